@@ -958,6 +958,12 @@ func (a *Aggregator) handleMonitoredTxResult(result ethtxmanager.MonitoredTxResu
 	if result.Status == ethtxmanager.MonitoredTxStatusFailed {
 		resLog.Fatal("failed to send batch verification, TODO: review this fatal and define what to do in this case")
 	}
+	if result.Status == ethtxmanager.MonitoredTxStatusCanceled {
+		// the proof is kept locked until the locked proofs cleanup releases it
+		// to be verified again
+		resLog.Warn("batch verification canceled by an operator")
+		return
+	}
 
 	// monitoredIDFormat: "proof-from-%v-to-%v"
 	idSlice := strings.Split(result.ID, "-")
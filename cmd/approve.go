@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/0xPolygonHermez/zkevm-node/config"
+	ethman "github.com/0xPolygonHermez/zkevm-node/etherman"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli/v2"
 )
 
@@ -20,6 +22,7 @@ func approveTokens(ctx *cli.Context) error {
 
 	addrKeyStorePath := ctx.String(config.FlagKeyStorePath)
 	addrPassword := ctx.String(config.FlagPassword)
+	addrRemote := ctx.String(config.FlagAddress)
 
 	c, err := config.Load(ctx)
 	if err != nil {
@@ -48,18 +51,35 @@ func approveTokens(ctx *cli.Context) error {
 		return err
 	}
 
-	// load auth from keystore file
-	auth, err := etherman.LoadAuthFromKeyStore(addrKeyStorePath, addrPassword)
-	if err != nil {
-		log.Fatal(err)
-		return err
+	var from common.Address
+	if addrKeyStorePath != "" {
+		// load auth from keystore file
+		auth, err := etherman.LoadAuthFromKeyStore(addrKeyStorePath, addrPassword)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+		from = auth.From
+	} else {
+		// load signer from the remote signer
+		if c.Etherman.RemoteSigner.URL == "" || !common.IsHexAddress(addrRemote) {
+			fmt.Println("Please, provide a key store file or an address signed by the remote signer configured in Etherman.RemoteSigner")
+			return nil
+		}
+		from = common.HexToAddress(addrRemote)
+		signer, err := ethman.NewRemoteSigner(ctx.Context, c.Etherman.RemoteSigner, c.Etherman.L1ChainID, from)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+		etherman.AddOrReplaceSigner(signer)
 	}
 
 	const decimals = 1000000000000000000
 	amountInWei := new(big.Float).Mul(amount, big.NewFloat(decimals))
 	amountB := new(big.Int)
 	amountInWei.Int(amountB)
-	tx, err := etherman.ApproveMatic(ctx.Context, auth.From, amountB, c.Etherman.PoEAddr)
+	tx, err := etherman.ApproveMatic(ctx.Context, from, amountB, c.Etherman.PoEAddr)
 	if err != nil {
 		return err
 	}
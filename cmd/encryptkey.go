@@ -4,15 +4,18 @@ import (
 	"log"
 	"strings"
 
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/urfave/cli/v2"
 )
 
 const (
-	encryptKeyFlagPrivateKey = "privateKey"
-	encryptKeyFlagPassword   = "password"
-	encryptKeyFlagOutput     = "output"
+	encryptKeyFlagPrivateKey         = "privateKey"
+	encryptKeyFlagPassword           = "password"
+	encryptKeyFlagOutput             = "output"
+	encryptKeyFlagRemoteSignerMethod = "remote-signer-method"
 )
 
 var encryptKeyFlags = []cli.Flag{
@@ -34,6 +37,16 @@ var encryptKeyFlags = []cli.Flag{
 		Usage:    "Output directory to save the encrypted private key file",
 		Required: true,
 	},
+	&cli.StringFlag{
+		Name:     config.FlagRemoteSignerURL,
+		Usage:    "JSON-RPC endpoint of the remote signer loading the keys of the output directory, used to check it provides the encrypted account",
+		Required: false,
+	},
+	&cli.StringFlag{
+		Name:     encryptKeyFlagRemoteSignerMethod,
+		Usage:    "Signing method of the remote signer, eth_signTransaction (Web3Signer) or account_signTransaction (Clef)",
+		Required: false,
+	},
 }
 
 func encryptKey(ctx *cli.Context) error {
@@ -47,8 +60,29 @@ func encryptKey(ctx *cli.Context) error {
 	}
 
 	ks := keystore.NewKeyStore(outputDir, keystore.StandardScryptN, keystore.StandardScryptP)
-	if _, err := ks.ImportECDSA(privateKey, password); err != nil {
+	account, err := ks.ImportECDSA(privateKey, password)
+	if err != nil {
 		log.Fatal("Failed to encrypt private key: ", err)
 	}
+	log.Printf("Key of the account %v encrypted into %v", account.Address.String(), account.URL.Path)
+
+	remoteSignerURL := ctx.String(config.FlagRemoteSignerURL)
+	if remoteSignerURL == "" {
+		return nil
+	}
+	// the remote signer needs to load the key store files of the output
+	// directory, check it is able to sign for the encrypted account
+	remoteSignerCfg := etherman.RemoteSignerConfig{URL: remoteSignerURL, Method: ctx.String(encryptKeyFlagRemoteSignerMethod)}
+	accounts, err := etherman.RemoteSignerAccounts(ctx.Context, remoteSignerCfg)
+	if err != nil {
+		log.Fatal("Failed to get remote signer accounts: ", err)
+	}
+	for _, remoteAccount := range accounts {
+		if remoteAccount == account.Address {
+			log.Printf("Account %v is provided by the remote signer", account.Address.String())
+			return nil
+		}
+	}
+	log.Printf("Account %v is not provided by the remote signer yet, its key store files may need to be reloaded", account.Address.String())
 	return nil
 }
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager/pb"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ethTxManFlagAdminURI  = "admin-uri"
+	ethTxManFlagOwner     = "owner"
+	ethTxManFlagID        = "id"
+	ethTxManFlagStatus    = "status"
+	ethTxManFlagGasPrice  = "gas-price"
+	ethTxManFlagGasTipCap = "gas-tip-cap"
+	ethTxManFlagGasFeeCap = "gas-fee-cap"
+
+	ethTxManRequestTimeout = time.Minute
+)
+
+var (
+	ethTxManAdminURIFlag = cli.StringFlag{
+		Name:     ethTxManFlagAdminURI,
+		Usage:    "Address of the eth tx manager admin server, defaults to the EthTxManager.Admin configuration",
+		Required: false,
+	}
+	ethTxManOwnerFlag = cli.StringFlag{
+		Name:     ethTxManFlagOwner,
+		Usage:    "Owner of the monitored tx, ex: aggregator",
+		Required: true,
+	}
+	ethTxManIDFlag = cli.StringFlag{
+		Name:     ethTxManFlagID,
+		Usage:    "Identifier of the monitored tx",
+		Required: true,
+	}
+)
+
+var ethTxManCommand = &cli.Command{
+	Name:  "ethtxman",
+	Usage: "Inspects and manages the L1 txs monitored by the eth tx manager",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "list",
+			Usage:  "Lists the monitored txs filtered by owner and statuses",
+			Action: ethTxManList,
+			Flags: []cli.Flag{
+				&configFileFlag,
+				&ethTxManAdminURIFlag,
+				&cli.StringFlag{
+					Name:  ethTxManFlagOwner,
+					Usage: "Owner of the monitored txs, all the owners if not provided",
+				},
+				&cli.StringSliceFlag{
+					Name:  ethTxManFlagStatus,
+					Usage: "Statuses of the monitored txs, all the statuses if not provided",
+				},
+			},
+		},
+		{
+			Name:   "show",
+			Usage:  "Shows a monitored tx with the hashes and receipts of all the txs sent for it",
+			Action: ethTxManShow,
+			Flags:  []cli.Flag{&configFileFlag, &ethTxManAdminURIFlag, &ethTxManOwnerFlag, &ethTxManIDFlag},
+		},
+		{
+			Name:   "resend",
+			Usage:  "Sends again a monitored tx that was not mined yet with new fees",
+			Action: ethTxManResend,
+			Flags: []cli.Flag{
+				&configFileFlag, &ethTxManAdminURIFlag, &ethTxManOwnerFlag, &ethTxManIDFlag,
+				&cli.StringFlag{
+					Name:  ethTxManFlagGasPrice,
+					Usage: "New gas price in wei, for legacy txs",
+				},
+				&cli.StringFlag{
+					Name:  ethTxManFlagGasTipCap,
+					Usage: "New gas tip cap in wei, for dynamic fee txs",
+				},
+				&cli.StringFlag{
+					Name:  ethTxManFlagGasFeeCap,
+					Usage: "New gas fee cap in wei, for dynamic fee txs",
+				},
+			},
+		},
+		{
+			Name:   "cancel",
+			Usage:  "Replaces a monitored tx that was not mined yet with a zero value tx using the same nonce",
+			Action: ethTxManCancel,
+			Flags:  []cli.Flag{&configFileFlag, &ethTxManAdminURIFlag, &ethTxManOwnerFlag, &ethTxManIDFlag, &yesFlag},
+		},
+		{
+			Name:   "fail",
+			Usage:  "Marks a monitored tx as failed, so the result handlers of its owner are triggered",
+			Action: ethTxManFail,
+			Flags:  []cli.Flag{&configFileFlag, &ethTxManAdminURIFlag, &ethTxManOwnerFlag, &ethTxManIDFlag, &yesFlag},
+		},
+	},
+}
+
+func ethTxManList(cliCtx *cli.Context) error {
+	return runEthTxManCmd(cliCtx, func(ctx context.Context, client pb.EthTxManagerAdminServiceClient) (proto.Message, error) {
+		return client.ListMonitoredTxs(ctx, &pb.ListMonitoredTxsRequest{
+			Owner:    cliCtx.String(ethTxManFlagOwner),
+			Statuses: cliCtx.StringSlice(ethTxManFlagStatus),
+		})
+	})
+}
+
+func ethTxManShow(cliCtx *cli.Context) error {
+	return runEthTxManCmd(cliCtx, func(ctx context.Context, client pb.EthTxManagerAdminServiceClient) (proto.Message, error) {
+		return client.GetMonitoredTx(ctx, ethTxManMonitoredTxRequest(cliCtx))
+	})
+}
+
+func ethTxManResend(cliCtx *cli.Context) error {
+	return runEthTxManCmd(cliCtx, func(ctx context.Context, client pb.EthTxManagerAdminServiceClient) (proto.Message, error) {
+		return client.ResendMonitoredTx(ctx, &pb.ResendMonitoredTxRequest{
+			Owner:     cliCtx.String(ethTxManFlagOwner),
+			Id:        cliCtx.String(ethTxManFlagID),
+			GasPrice:  cliCtx.String(ethTxManFlagGasPrice),
+			GasTipCap: cliCtx.String(ethTxManFlagGasTipCap),
+			GasFeeCap: cliCtx.String(ethTxManFlagGasFeeCap),
+		})
+	})
+}
+
+func ethTxManCancel(cliCtx *cli.Context) error {
+	if !confirmEthTxManOperation(cliCtx, "cancel") {
+		return nil
+	}
+	return runEthTxManCmd(cliCtx, func(ctx context.Context, client pb.EthTxManagerAdminServiceClient) (proto.Message, error) {
+		return client.CancelMonitoredTx(ctx, ethTxManMonitoredTxRequest(cliCtx))
+	})
+}
+
+func ethTxManFail(cliCtx *cli.Context) error {
+	if !confirmEthTxManOperation(cliCtx, "mark as failed") {
+		return nil
+	}
+	return runEthTxManCmd(cliCtx, func(ctx context.Context, client pb.EthTxManagerAdminServiceClient) (proto.Message, error) {
+		return client.FailMonitoredTx(ctx, ethTxManMonitoredTxRequest(cliCtx))
+	})
+}
+
+func ethTxManMonitoredTxRequest(cliCtx *cli.Context) *pb.MonitoredTxRequest {
+	return &pb.MonitoredTxRequest{
+		Owner: cliCtx.String(ethTxManFlagOwner),
+		Id:    cliCtx.String(ethTxManFlagID),
+	}
+}
+
+func confirmEthTxManOperation(cliCtx *cli.Context, operation string) bool {
+	if cliCtx.Bool(config.FlagYes) {
+		return true
+	}
+	fmt.Printf("*WARNING* Are you sure you want to %v the monitored tx %v of the owner %v? [y/N]: ",
+		operation, cliCtx.String(ethTxManFlagID), cliCtx.String(ethTxManFlagOwner))
+	var input string
+	if _, err := fmt.Scanln(&input); err != nil {
+		return false
+	}
+	return input == "y" || input == "yes"
+}
+
+// runEthTxManCmd connects to the eth tx manager admin server, executes
+// the request and prints the response as JSON
+func runEthTxManCmd(cliCtx *cli.Context, request func(context.Context, pb.EthTxManagerAdminServiceClient) (proto.Message, error)) error {
+	adminURI := cliCtx.String(ethTxManFlagAdminURI)
+	if adminURI == "" {
+		c, err := config.Load(cliCtx)
+		if err != nil {
+			return err
+		}
+		adminURI = fmt.Sprintf("%s:%d", c.EthTxManager.Admin.Host, c.EthTxManager.Admin.Port)
+	}
+
+	ctx, cancel := context.WithTimeout(cliCtx.Context, ethTxManRequestTimeout)
+	defer cancel()
+
+	client, conn, err := ethtxmanager.NewAdminClient(ctx, adminURI)
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint:errcheck
+
+	res, err := request(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	out, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(res)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
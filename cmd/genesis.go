@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/tools/genesis/genesistool"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	genesisFlagAlloc   = "alloc"
+	genesisFlagOutput  = "output"
+	genesisFlagActions = "actions"
+)
+
+var genesisCommand = &cli.Command{
+	Name:  "genesis",
+	Usage: "Builds, verifies and compares genesis files",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "build",
+			Usage:  "Builds a genesis file from a geth-style allocation, computing its root",
+			Action: genesisBuild,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     genesisFlagAlloc,
+					Usage:    "JSON `FILE` with the allocation, or a geth genesis file with an alloc field",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     genesisFlagOutput,
+					Aliases:  []string{"o"},
+					Usage:    "Genesis `FILE` to create",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     genesisFlagActions,
+					Usage:    "JSON `FILE` to create with the actions set on the tree, not created if not provided",
+					Required: false,
+				},
+			},
+		},
+		{
+			Name:   "verify",
+			Usage:  "Recomputes the root of a genesis file and checks it against the one of the file",
+			Action: genesisVerify,
+			Flags:  []cli.Flag{&genesisFlag},
+		},
+		{
+			Name:      "diff",
+			Usage:     "Compares two genesis files account by account",
+			ArgsUsage: "FILE_A FILE_B",
+			Action:    genesisDiff,
+		},
+	},
+}
+
+func genesisBuild(cliCtx *cli.Context) error {
+	alloc, err := genesistool.ReadAlloc(cliCtx.String(genesisFlagAlloc))
+	if err != nil {
+		return err
+	}
+	genesis, err := genesistool.Build(cliCtx.Context, alloc)
+	if err != nil {
+		return err
+	}
+	if err := genesistool.WriteJSON(cliCtx.String(genesisFlagOutput), genesis); err != nil {
+		return err
+	}
+	if actionsFile := cliCtx.String(genesisFlagActions); actionsFile != "" {
+		if err := genesistool.WriteJSON(actionsFile, genesistool.Actions(genesis)); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("genesis with %d accounts and root %s created\n", len(genesis.Genesis), genesis.Root)
+	return nil
+}
+
+func genesisVerify(cliCtx *cli.Context) error {
+	genesis, err := genesistool.ReadGenesis(cliCtx.String(genesisFlag.Name))
+	if err != nil {
+		return err
+	}
+	root, err := genesistool.Verify(cliCtx.Context, genesis)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("genesis root %s verified\n", root.String())
+	return nil
+}
+
+func genesisDiff(cliCtx *cli.Context) error {
+	if cliCtx.NArg() != 2 {
+		return errors.New("two genesis files must be provided")
+	}
+	a, err := genesistool.ReadGenesis(cliCtx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	b, err := genesistool.ReadGenesis(cliCtx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	diffs, err := genesistool.Diff(a, b)
+	if err != nil {
+		return err
+	}
+	for _, diff := range diffs {
+		fmt.Printf("%s %s: %q != %q\n", diff.Address.Hex(), diff.Field, diff.A, diff.B)
+	}
+	if a.Root != b.Root {
+		fmt.Printf("root: %s != %s\n", a.Root, b.Root)
+		return fmt.Errorf("%d differences found and the roots differ", len(diffs))
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("%d differences found", len(diffs))
+	}
+	return nil
+}
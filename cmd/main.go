@@ -105,13 +105,19 @@ func main() {
 					Name:     config.FlagKeyStorePath,
 					Aliases:  []string{""},
 					Usage:    "the path of the key store file containing the private key of the account going to sign and approve the tokens",
-					Required: true,
+					Required: false,
 				},
 				&cli.StringFlag{
 					Name:     config.FlagPassword,
 					Aliases:  []string{"pw"},
 					Usage:    "the password do decrypt the key store file",
-					Required: true,
+					Required: false,
+				},
+				&cli.StringFlag{
+					Name:     config.FlagAddress,
+					Aliases:  []string{"addr"},
+					Usage:    "the account going to sign and approve the tokens using the remote signer configured in Etherman.RemoteSigner, used when no key store file is provided",
+					Required: false,
 				},
 				&cli.StringFlag{
 					Name:     config.FlagAmount,
@@ -135,6 +141,16 @@ func main() {
 			Action:  dumpState,
 			Flags:   dumpStateFlags,
 		},
+		{
+			Name:    "verify-state",
+			Aliases: []string{},
+			Usage:   "Executes again the stored batches and compares the results with the stored ones",
+			Action:  verifyState,
+			Flags:   verifyStateFlags,
+		},
+		ethTxManCommand,
+		snapshotCommand,
+		genesisCommand,
 	}
 
 	err := app.Run(os.Args)
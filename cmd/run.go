@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node"
 	"github.com/0xPolygonHermez/zkevm-node/aggregator"
 	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/db"
 	"github.com/0xPolygonHermez/zkevm-node/etherman"
 	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
@@ -23,17 +25,18 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/pool/pgpoolstorage"
+	"github.com/0xPolygonHermez/zkevm-node/pruner"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast"
-	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast/pb"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/local"
+	executorpb "github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/urfave/cli/v2"
-	"google.golang.org/grpc"
 )
 
 const (
@@ -78,6 +81,7 @@ func start(cliCtx *cli.Context) error {
 	if err != nil {
 		log.Fatal(err)
 	}
+	cancelFuncs = append(cancelFuncs, etherman.Close)
 
 	// READ CHAIN ID FROM POE SC
 	l2ChainID, err := etherman.GetL2ChainID()
@@ -98,6 +102,26 @@ func start(cliCtx *cli.Context) error {
 
 	ctx := context.Background()
 	st := newState(ctx, c, l2ChainID, currentForkID, forkIDIntervals, stateSqlDB)
+	// The fork ids are stored by the synchronizer with the L1 blocks that
+	// activated them, so they are removed by the reorgs of those blocks. A db
+	// synced before they were stored gets them from L1
+	if err := st.BackfillForkIDs(ctx, forkIDIntervals, nil); err != nil {
+		log.Fatal("error backfilling forks: ", err)
+	}
+	if err := st.LoadForkIDIntervals(ctx, nil); err != nil {
+		log.Fatal("error loading forks: ", err)
+	}
+	// Without the synchronizer the fork ids it stores are reloaded every sync
+	// interval
+	runsSynchronizer := false
+	for _, component := range components {
+		if component == SYNCHRONIZER {
+			runsSynchronizer = true
+		}
+	}
+	if !runsSynchronizer {
+		go st.ReloadForkIDIntervals(ctx, c.Synchronizer.SyncInterval.Duration)
+	}
 
 	ethTxManagerStorage, err := ethtxmanager.NewPostgresStorage(c.StateDB)
 	if err != nil {
@@ -106,6 +130,11 @@ func start(cliCtx *cli.Context) error {
 
 	etm := ethtxmanager.New(c.EthTxManager, etherman, ethTxManagerStorage, st)
 
+	var (
+		seqInstance  *sequencer.Sequencer
+		syncInstance synchronizer.Synchronizer
+		broadcastSrv *broadcast.Server
+	)
 	for _, component := range components {
 		switch component {
 		case AGGREGATOR:
@@ -114,8 +143,10 @@ func start(cliCtx *cli.Context) error {
 		case SEQUENCER:
 			log.Info("Running sequencer")
 			poolInstance := createPool(c.Pool, c.NetworkConfig.L2BridgeAddr, l2ChainID, st)
-			seq := createSequencer(*c, poolInstance, ethTxManagerStorage, st)
+			seq, seqEtherman := createSequencer(*c, poolInstance, ethTxManagerStorage, st)
+			cancelFuncs = append(cancelFuncs, seqEtherman.Close)
 			go seq.Start(ctx)
+			seqInstance = seq
 		case RPC:
 			log.Info("Running JSON-RPC server")
 			poolInstance := createPool(c.Pool, c.NetworkConfig.L2BridgeAddr, l2ChainID, st)
@@ -126,14 +157,25 @@ func start(cliCtx *cli.Context) error {
 			go runJSONRPCServer(*c, poolInstance, st, apis)
 		case SYNCHRONIZER:
 			log.Info("Running synchronizer")
-			go runSynchronizer(*c, etherman, etm, st)
+			syncEtherman := etherman
+			if c.Etherman.RecordFile != "" {
+				syncEtherman = newRecordingEtherman(*c)
+				cancelFuncs = append(cancelFuncs, syncEtherman.Close)
+			}
+			syncInstance = createSynchronizer(*c, syncEtherman, etm, st)
 		case BROADCAST:
 			log.Info("Running broadcast service")
-			go runBroadcastServer(c.BroadcastServer, st)
+			broadcastSrv = broadcast.NewServer(&c.BroadcastServer, st)
+			go broadcastSrv.Start()
 		case ETHTXMANAGER:
 			log.Info("Running eth tx manager service")
-			etm := createEthTxManager(*c, ethTxManagerStorage, st)
+			etm, etmEtherman := createEthTxManager(*c, ethTxManagerStorage, st)
+			cancelFuncs = append(cancelFuncs, etmEtherman.Close)
 			go etm.Start()
+			if c.EthTxManager.Admin.Enabled {
+				log.Info("Running eth tx manager admin server")
+				go ethtxmanager.NewAdminServer(c.EthTxManager.Admin, etm).Start()
+			}
 		case L2GASPRICER:
 			log.Info("Running L2 gasPricer")
 			poolInstance := createPool(c.Pool, c.NetworkConfig.L2BridgeAddr, l2ChainID, st)
@@ -141,6 +183,37 @@ func start(cliCtx *cli.Context) error {
 		}
 	}
 
+	// The broadcast server streams the batches as they are closed by the
+	// sequencer running along with it and as the synchronizer stores them
+	if broadcastSrv != nil && (seqInstance != nil || syncInstance != nil) {
+		batchClosed := broadcastSrv.BatchClosedHandler()
+		if seqInstance != nil {
+			seqInstance.RegisterBatchClosedEventHandler(func(e sequencer.BatchClosedEvent) {
+				batchClosed(e.BatchNumber)
+			})
+		}
+		if syncInstance != nil {
+			syncInstance.RegisterBatchClosedEventHandler(batchClosed)
+		}
+	}
+	// The synchronizer is started once its handlers are registered
+	if syncInstance != nil {
+		go runSynchronizer(syncInstance)
+	}
+
+	switch c.Pruner.Mode {
+	case pruner.ModeArchive:
+	case pruner.ModeFull:
+		log.Info("Running pruner")
+		p, err := pruner.New(c.Pruner, st)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go p.Start(ctx)
+	default:
+		log.Fatalf("unknown pruner mode %q", c.Pruner.Mode)
+	}
+
 	if c.Metrics.Enabled {
 		go startMetricsHttpServer(c.Metrics)
 	}
@@ -181,6 +254,9 @@ func runMigrations(c db.Config, name string) {
 }
 
 func newEtherman(c config.Config) (*etherman.Client, error) {
+	// Only the etherman of the synchronizer records its responses, see
+	// newRecordingEtherman
+	c.Etherman.RecordFile = ""
 	etherman, err := etherman.NewClient(c.Etherman)
 	if err != nil {
 		return nil, err
@@ -188,11 +264,27 @@ func newEtherman(c config.Config) (*etherman.Client, error) {
 	return etherman, nil
 }
 
-func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManager *ethtxmanager.Client, st *state.State) {
+// newRecordingEtherman creates the etherman of the synchronizer recording the
+// L1 and HotShot query service responses in the record file, which must be
+// closed on shutdown. The synchronizer gets its own one so the fixture only
+// holds the requests of the sync
+func newRecordingEtherman(c config.Config) *etherman.Client {
+	etherman, err := etherman.NewClient(c.Etherman)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return etherman
+}
+
+func createSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManager *ethtxmanager.Client, st *state.State) synchronizer.Synchronizer {
 	sy, err := synchronizer.NewSynchronizer(cfg.IsTrustedSequencer, etherman, st, ethTxManager, cfg.NetworkConfig.Genesis, cfg.Synchronizer)
 	if err != nil {
 		log.Fatal(err)
 	}
+	return sy
+}
+
+func runSynchronizer(sy synchronizer.Synchronizer) {
 	if err := sy.Sync(); err != nil {
 		log.Fatal(err)
 	}
@@ -201,24 +293,22 @@ func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManager
 func runJSONRPCServer(c config.Config, pool *pool.Pool, st *state.State, apis map[string]bool) {
 	storage := jsonrpc.NewStorage()
 	c.RPC.MaxCumulativeGasUsed = c.Sequencer.MaxCumulativeGasUsed
+	c.RPC.ReadReplicas = len(c.StateDB.ReadReplicas) > 0
 
 	if err := jsonrpc.NewServer(c.RPC, pool, st, storage, apis).Start(); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func createSequencer(cfg config.Config, pool *pool.Pool, etmStorage *ethtxmanager.PostgresStorage, st *state.State) *sequencer.Sequencer {
+// createSequencer creates the sequencer along with the etherman it uses to
+// sign, which must be closed on shutdown
+func createSequencer(cfg config.Config, pool *pool.Pool, etmStorage *ethtxmanager.PostgresStorage, st *state.State) (*sequencer.Sequencer, *etherman.Client) {
 	etherman, err := newEtherman(cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	for _, privateKey := range cfg.Sequencer.Finalizer.PrivateKeys {
-		_, err := etherman.LoadAuthFromKeyStore(privateKey.Path, privateKey.Password)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
+	loadSigners(cfg, etherman, cfg.Sequencer.Finalizer.PrivateKeys)
 
 	ethTxManager := ethtxmanager.New(cfg.EthTxManager, etherman, etmStorage, st)
 
@@ -226,7 +316,7 @@ func createSequencer(cfg config.Config, pool *pool.Pool, etmStorage *ethtxmanage
 	if err != nil {
 		log.Fatal(err)
 	}
-	return seq
+	return seq, etherman
 }
 
 func runAggregator(ctx context.Context, c aggregator.Config, etherman *etherman.Client, ethTxManager *ethtxmanager.Client, st *state.State) {
@@ -240,15 +330,6 @@ func runAggregator(ctx context.Context, c aggregator.Config, etherman *etherman.
 	}
 }
 
-func runBroadcastServer(c broadcast.ServerConfig, st *state.State) {
-	s := grpc.NewServer()
-
-	broadcastSrv := broadcast.NewServer(&c, st)
-	pb.RegisterBroadcastServiceServer(s, broadcastSrv)
-
-	broadcastSrv.Start()
-}
-
 // runL2GasPriceSuggester init gas price gasPriceEstimator based on type in config.
 func runL2GasPriceSuggester(cfg gasprice.Config, state *state.State, pool *pool.Pool, etherman *etherman.Client) {
 	ctx := context.Background()
@@ -275,18 +356,64 @@ func waitSignal(cancelFuncs []context.CancelFunc) {
 
 func newState(ctx context.Context, c *config.Config, l2ChainID uint64, currentForkID uint64, forkIDIntervals []state.ForkIDInterval, sqlDB *pgxpool.Pool) *state.State {
 	stateDb := state.NewPostgresStorage(sqlDB)
-	executorClient, _, _ := executor.NewExecutorClient(ctx, c.Executor)
-	stateDBClient, _, _ := merkletree.NewMTDBServiceClient(ctx, c.MTClient)
-	stateTree := merkletree.NewStateTree(stateDBClient)
+	if len(c.StateDB.ReadReplicas) > 0 {
+		replicas, err := db.NewReadReplicaSQLDBs(c.StateDB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		stateDb = state.NewPostgresStorageWithReadReplicas(sqlDB, replicas, c.StateDB.MaxReplicaLag)
+		go stateDb.MonitorReadReplicas(ctx, c.StateDB.ReplicaLagCheckInterval.Duration)
+	}
+	// a node switched back to archive mode keeps the gaps of its pruned history
+	if c.Pruner.Mode == pruner.ModeFull {
+		stateDb.EnablePrunedHistoryCheck()
+	} else if prunedBatchNumber, err := stateDb.GetPrunedBatchNumber(ctx, nil); err != nil {
+		log.Fatal(err)
+	} else if prunedBatchNumber > 0 {
+		stateDb.EnablePrunedHistoryCheck()
+	}
+	var (
+		executorClient executorpb.ExecutorServiceClient
+		stateTree      *merkletree.StateTree
+	)
+	if c.Executor.Local {
+		log.Warn("using the local executor, the ZK counters are not computed")
+		stateTree = merkletree.NewStateTree(merkletree.NewLocalStateDBClient(merkletree.NewMemoryTree()))
+		executorClient = local.NewExecutor(stateTree, 0)
+	} else {
+		var err error
+		executorClient, err = executor.NewClientPool(ctx, c.Executor)
+		if err != nil {
+			log.Fatal(err)
+		}
+		stateDBClient, _, _ := merkletree.NewMTDBServiceClient(ctx, c.MTClient)
+		if c.StateCache.SMTSize > 0 {
+			stateTree = merkletree.NewStateTreeWithCache(stateDBClient, c.StateCache.SMTSize)
+		} else {
+			stateTree = merkletree.NewStateTree(stateDBClient)
+		}
+	}
 
 	stateCfg := state.Config{
 		MaxCumulativeGasUsed: c.Sequencer.MaxCumulativeGasUsed,
 		ChainID:              l2ChainID,
 		CurrentForkID:        currentForkID,
 		ForkIDIntervals:      forkIDIntervals,
+		Cache:                c.StateCache,
+		BatchConstraints:     c.Sequencer.BatchConstraintsCfg,
 	}
 
 	st := state.NewState(stateCfg, stateDb, executorClient, stateTree)
+	if c.Executor.Local {
+		// The state roots stored in the db point to nodes of the in-memory
+		// tree of a previous run, so the state must be synced from genesis
+		_, err := st.GetLastBlock(ctx, nil)
+		if err == nil {
+			log.Fatal("the local executor doesn't persist the state tree, it requires an empty state db")
+		} else if !errors.Is(err, state.ErrStateNotSynchronized) {
+			log.Fatal(err)
+		}
+	}
 	return st
 }
 
@@ -300,20 +427,36 @@ func createPool(cfgPool pool.Config, l2BridgeAddr common.Address, l2ChainID uint
 	return poolInstance
 }
 
-func createEthTxManager(cfg config.Config, etmStorage *ethtxmanager.PostgresStorage, st *state.State) *ethtxmanager.Client {
+// createEthTxManager creates the eth tx manager along with the etherman it uses
+// to sign, which must be closed on shutdown
+func createEthTxManager(cfg config.Config, etmStorage *ethtxmanager.PostgresStorage, st *state.State) (*ethtxmanager.Client, *etherman.Client) {
 	etherman, err := newEtherman(cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	for _, privateKey := range cfg.EthTxManager.PrivateKeys {
-		_, err := etherman.LoadAuthFromKeyStore(privateKey.Path, privateKey.Password)
+	loadSigners(cfg, etherman, cfg.EthTxManager.PrivateKeys)
+	etm := ethtxmanager.New(cfg.EthTxManager, etherman, etmStorage, st)
+	return etm, etherman
+}
+
+// loadSigners loads the signers of the accounts provided by the remote signer, if
+// configured, and the ones of the provided key store files
+func loadSigners(cfg config.Config, ethMan *etherman.Client, privateKeys []types.KeystoreFileConfig) {
+	if cfg.Etherman.RemoteSigner.URL != "" {
+		accounts, err := ethMan.LoadRemoteSigners(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Infof("loaded %v accounts from remote signer %v", len(accounts), cfg.Etherman.RemoteSigner.URL)
+	}
+
+	for _, privateKey := range privateKeys {
+		_, err := ethMan.LoadAuthFromKeyStore(privateKey.Path, privateKey.Password)
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
-	etm := ethtxmanager.New(cfg.EthTxManager, etherman, etmStorage, st)
-	return etm
 }
 
 func startProfilingHttpServer(c metrics.Config) {
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/snapshot"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	snapshotFlagDir   = "dir"
+	snapshotFlagBatch = "batch"
+)
+
+var snapshotDirFlag = cli.StringFlag{
+	Name:     snapshotFlagDir,
+	Aliases:  []string{"d"},
+	Usage:    "Directory of the snapshot",
+	Required: true,
+}
+
+var snapshotCommand = &cli.Command{
+	Name:  "snapshot",
+	Usage: "Exports and imports snapshots of the state to bootstrap new nodes",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "export",
+			Usage:  "Exports the state up to a batch verified on L1 and the tree of its state root",
+			Action: snapshotExport,
+			Flags: []cli.Flag{
+				&configFileFlag,
+				&snapshotDirFlag,
+				&cli.Uint64Flag{
+					Name:  snapshotFlagBatch,
+					Usage: "Verified batch of the snapshot, the last verified batch if not provided",
+				},
+			},
+		},
+		{
+			Name:   "import",
+			Usage:  "Imports a snapshot into an empty state, checking its state root against L1",
+			Action: snapshotImport,
+			Flags:  []cli.Flag{&configFileFlag, &snapshotDirFlag},
+		},
+	},
+}
+
+func snapshotExport(cliCtx *cli.Context) error {
+	c, err := config.Load(cliCtx)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+
+	stateSqlDB, err := db.NewSQLDB(c.StateDB)
+	if err != nil {
+		return err
+	}
+	hashSqlDB, err := db.NewSQLDB(c.MTClient.HashDB)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := snapshot.Export(cliCtx.Context, stateSqlDB, merkletree.NewPostgresNodeStore(hashSqlDB), cliCtx.String(snapshotFlagDir), cliCtx.Uint64(snapshotFlagBatch))
+	if err != nil {
+		return err
+	}
+	log.Infof("snapshot of batch %d with state root %s exported", manifest.BatchNumber, manifest.StateRoot.String())
+	return nil
+}
+
+func snapshotImport(cliCtx *cli.Context) error {
+	c, err := config.Load(cliCtx)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+
+	runStateMigrations(c.StateDB)
+	stateSqlDB, err := db.NewSQLDB(c.StateDB)
+	if err != nil {
+		return err
+	}
+	hashSqlDB, err := db.NewSQLDB(c.MTClient.HashDB)
+	if err != nil {
+		return err
+	}
+	etherman, err := newEtherman(*c)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := snapshot.Import(cliCtx.Context, stateSqlDB, merkletree.NewPostgresNodeStore(hashSqlDB), cliCtx.String(snapshotFlagDir), etherman)
+	if err != nil {
+		return err
+	}
+	log.Infof("snapshot of batch %d with state root %s imported, the synchronizer resumes from L1 block %d", manifest.BatchNumber, manifest.StateRoot.String(), manifest.BlockNumber)
+	return nil
+}
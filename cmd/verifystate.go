@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	verifyStateFlagFromBatch = "from-batch"
+	verifyStateFlagToBatch   = "to-batch"
+	verifyStateFlagWorkers   = "workers"
+)
+
+var verifyStateFlags = []cli.Flag{
+	&cli.Uint64Flag{
+		Name:     verifyStateFlagFromBatch,
+		Usage:    "First batch to verify",
+		Value:    1,
+		Required: false,
+	},
+	&cli.Uint64Flag{
+		Name:     verifyStateFlagToBatch,
+		Usage:    "Last batch to verify, the last closed batch of the state when not provided",
+		Required: false,
+	},
+	&cli.UintFlag{
+		Name:     verifyStateFlagWorkers,
+		Usage:    "Number of ranges of batches verified in parallel",
+		Value:    1,
+		Required: false,
+	},
+	&configFileFlag,
+}
+
+func verifyState(cliCtx *cli.Context) error {
+	c, err := config.Load(cliCtx)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+
+	fromBatch := cliCtx.Uint64(verifyStateFlagFromBatch)
+	if fromBatch == 0 {
+		return errors.New("the genesis batch can't be verified, the first batch must be greater than 0")
+	}
+	workers := uint64(cliCtx.Uint(verifyStateFlagWorkers))
+	if workers == 0 {
+		workers = 1
+	}
+
+	stateSqlDB, err := db.NewSQLDB(c.StateDB)
+	if err != nil {
+		return err
+	}
+	etherman, err := newEtherman(*c)
+	if err != nil {
+		return err
+	}
+	l2ChainID, err := etherman.GetL2ChainID()
+	if err != nil {
+		return err
+	}
+
+	ctx := cliCtx.Context
+	st := newState(ctx, c, l2ChainID, 0, nil, stateSqlDB)
+	if err := st.LoadForkIDIntervals(ctx, nil); err != nil {
+		return err
+	}
+
+	toBatch := cliCtx.Uint64(verifyStateFlagToBatch)
+	if toBatch == 0 {
+		// the open batch has no state root to verify yet
+		lastClosedBatch, err := st.GetLastClosedBatch(ctx, nil)
+		if err != nil {
+			return err
+		}
+		toBatch = lastClosedBatch.BatchNumber
+	}
+	if toBatch < fromBatch {
+		return fmt.Errorf("the last batch %d is lower than the first batch %d", toBatch, fromBatch)
+	}
+
+	// the batches are independent, each one is executed on top of the stored
+	// state root of the previous one, so the range is split between the workers
+	count := toBatch - fromBatch + 1
+	if workers > count {
+		workers = count
+	}
+	divergences := make([]*state.BatchDivergence, workers)
+	g, gCtx := errgroup.WithContext(ctx)
+	for w := uint64(0); w < workers; w++ {
+		w := w
+		from := fromBatch + w*count/workers
+		to := fromBatch + (w+1)*count/workers - 1
+		g.Go(func() error {
+			divergence, err := verifyBatchRange(gCtx, st, from, to)
+			divergences[w] = divergence
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	// the ranges are sorted, so the first divergence found is the lowest one
+	for _, divergence := range divergences {
+		if divergence != nil {
+			return fmt.Errorf("state divergence found: %s", divergence.String())
+		}
+	}
+	log.Infof("batches %d to %d verified, no divergence found", fromBatch, toBatch)
+	return nil
+}
+
+// verifyBatchRange verifies the batches of the range in order, stopping at the
+// first divergence
+func verifyBatchRange(ctx context.Context, st *state.State, from, to uint64) (*state.BatchDivergence, error) {
+	for batchNumber := from; batchNumber <= to; batchNumber++ {
+		dbTx, err := st.BeginStateTransaction(ctx)
+		if err != nil {
+			return nil, err
+		}
+		divergence, err := st.VerifyBatch(ctx, batchNumber, dbTx)
+		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+			log.Errorf("error rolling back state to verify batch %d: %v", batchNumber, rollbackErr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error verifying batch %d: %w", batchNumber, err)
+		}
+		if divergence != nil {
+			log.Errorf("divergence found: %s", divergence.String())
+			return divergence, nil
+		}
+		log.Debugf("batch %d verified", batchNumber)
+	}
+	return nil, nil
+}
@@ -16,8 +16,10 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/pricegetter"
+	"github.com/0xPolygonHermez/zkevm-node/pruner"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
 	"github.com/mitchellh/mapstructure"
@@ -44,6 +46,10 @@ const (
 	FlagKeyStorePath = "key-store-path"
 	// FlagPassword is the password needed to decrypt the key store
 	FlagPassword = "password"
+	// FlagAddress is the account going to sign and approve the tokens using the remote signer
+	FlagAddress = "address"
+	// FlagRemoteSignerURL is the flag for the remote signer url.
+	FlagRemoteSignerURL = "remote-signer-url"
 	// FlagMigrations is the flag for migrations.
 	FlagMigrations = "migrations"
 )
@@ -68,6 +74,8 @@ type Config struct {
 	MTClient            merkletree.Config
 	StateDB             db.Config
 	Metrics             metrics.Config
+	Pruner              pruner.Config
+	StateCache          state.CacheConfig
 }
 
 // Default parses the default configuration values.
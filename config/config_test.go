@@ -12,6 +12,8 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/aggregator"
 	"github.com/0xPolygonHermez/zkevm-node/config"
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/pricegetter"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer"
@@ -42,6 +44,66 @@ func Test_Defaults(t *testing.T) {
 			path:          "Synchronizer.SyncChunkSize",
 			expectedValue: uint64(100),
 		},
+		{
+			path:          "Synchronizer.SyncPipelineBufferSize",
+			expectedValue: uint64(10),
+		},
+		{
+			path:          "Synchronizer.SyncPipelineDownloadWorkers",
+			expectedValue: uint64(1),
+		},
+		{
+			path:          "Synchronizer.ResetChunkSize",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "Synchronizer.ForcedBatchTimeout",
+			expectedValue: uint64(64),
+		},
+		{
+			path:          "Synchronizer.L1Finality",
+			expectedValue: "latest",
+		},
+		{
+			path:          "Synchronizer.L1Confirmations",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "Synchronizer.Health.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Synchronizer.Health.Host",
+			expectedValue: "0.0.0.0",
+		},
+		{
+			path:          "Synchronizer.Health.Port",
+			expectedValue: 9093,
+		},
+		{
+			path:          "Synchronizer.Health.MaxL1BlockLag",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "Synchronizer.Health.MaxHotShotBatchLag",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "Synchronizer.Health.MaxSyncAge",
+			expectedValue: types.NewDuration(5 * time.Minute),
+		},
+		{
+			path:          "Synchronizer.Broadcast.TLS",
+			expectedValue: false,
+		},
+		{
+			path:          "Synchronizer.Broadcast.InitialBackoff",
+			expectedValue: types.NewDuration(time.Second),
+		},
+		{
+			path:          "Synchronizer.Broadcast.MaxBackoff",
+			expectedValue: types.NewDuration(time.Minute),
+		},
 		{
 			path:          "PriceGetter.Type",
 			expectedValue: pricegetter.DefaultType,
@@ -74,6 +136,10 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.FrequencyToCheckTxsForDelete",
 			expectedValue: types.NewDuration(12 * time.Hour),
 		},
+		{
+			path:          "Sequencer.FrequencyToCheckReorgs",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
 		{
 			path:          "Sequencer.MaxCumulativeGasUsed",
 			expectedValue: uint64(30000000),
@@ -146,6 +212,10 @@ func Test_Defaults(t *testing.T) {
 			path:          "Etherman.URL",
 			expectedValue: "http://localhost:8545",
 		},
+		{
+			path:          "Etherman.URLs",
+			expectedValue: []string{},
+		},
 		{
 			path:          "Etherman.L1ChainID",
 			expectedValue: uint64(1337),
@@ -166,6 +236,42 @@ func Test_Defaults(t *testing.T) {
 			path:          "Etherman.MultiGasProvider",
 			expectedValue: true,
 		},
+		{
+			path:          "Etherman.L1CacheSize",
+			expectedValue: 1000,
+		},
+		{
+			path:          "Etherman.RecordFile",
+			expectedValue: "",
+		},
+		{
+			path:          "Etherman.RemoteSigner.URL",
+			expectedValue: "",
+		},
+		{
+			path:          "Etherman.RemoteSigner.Method",
+			expectedValue: etherman.RemoteSignerMethodEth,
+		},
+		{
+			path:          "Etherman.LogRange.Adaptive",
+			expectedValue: true,
+		},
+		{
+			path:          "Etherman.LogRange.InitialSize",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "Etherman.LogRange.MinSize",
+			expectedValue: uint64(1),
+		},
+		{
+			path:          "Etherman.LogRange.MaxSize",
+			expectedValue: uint64(10000),
+		},
+		{
+			path:          "Etherman.LogRange.SparseLogs",
+			expectedValue: uint64(100),
+		},
 		{
 			path:          "EthTxManager.FrequencyToMonitorTxs",
 			expectedValue: types.NewDuration(1 * time.Second),
@@ -174,6 +280,58 @@ func Test_Defaults(t *testing.T) {
 			path:          "EthTxManager.WaitTxToBeMined",
 			expectedValue: types.NewDuration(2 * time.Minute),
 		},
+		{
+			path:          "EthTxManager.FeeStrategy.TxType",
+			expectedValue: ethtxmanager.TxTypeLegacy,
+		},
+		{
+			path:          "EthTxManager.FeeStrategy.PriorityFeeStrategy",
+			expectedValue: ethtxmanager.PriorityFeeStrategySuggested,
+		},
+		{
+			path:          "EthTxManager.FeeStrategy.BaseFeeMultiplier",
+			expectedValue: uint64(2),
+		},
+		{
+			path:          "EthTxManager.FeeStrategy.BumpPercentage",
+			expectedValue: uint64(10),
+		},
+		{
+			path:          "EthTxManager.FeeStrategy.EscalationInterval",
+			expectedValue: types.NewDuration(2 * time.Minute),
+		},
+		{
+			path:          "EthTxManager.FeeStrategy.DefaultMaxFeePerGas",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "EthTxManager.Admin.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "EthTxManager.Admin.Host",
+			expectedValue: "127.0.0.1",
+		},
+		{
+			path:          "EthTxManager.Admin.Port",
+			expectedValue: 61095,
+		},
+		{
+			path:          "EthTxManager.Admin.HTTPPort",
+			expectedValue: 61096,
+		},
+		{
+			path:          "EthTxManager.Balance.CheckInterval",
+			expectedValue: types.NewDuration(5 * time.Minute),
+		},
+		{
+			path:          "EthTxManager.Balance.MinBalance",
+			expectedValue: ethtxmanager.Wei{Int: new(big.Int).SetInt64(0)},
+		},
+		{
+			path:          "EthTxManager.Balance.TargetBalance",
+			expectedValue: ethtxmanager.Wei{Int: new(big.Int).SetInt64(0)},
+		},
 		{
 			path:          "PriceGetter.Type",
 			expectedValue: pricegetter.DefaultType,
@@ -190,6 +348,18 @@ func Test_Defaults(t *testing.T) {
 			path:          "MTClient.URI",
 			expectedValue: "127.0.0.1:50061",
 		},
+		{
+			path:          "MTClient.HashDB.User",
+			expectedValue: "prover_user",
+		},
+		{
+			path:          "MTClient.HashDB.Name",
+			expectedValue: "prover_db",
+		},
+		{
+			path:          "MTClient.HashDB.MaxConns",
+			expectedValue: 10,
+		},
 		{
 			path:          "StateDB.User",
 			expectedValue: "state_user",
@@ -218,6 +388,18 @@ func Test_Defaults(t *testing.T) {
 			path:          "StateDB.MaxConns",
 			expectedValue: 200,
 		},
+		{
+			path:          "StateDB.ReadReplicas",
+			expectedValue: []string{},
+		},
+		{
+			path:          "StateDB.MaxReplicaLag",
+			expectedValue: uint64(10),
+		},
+		{
+			path:          "StateDB.ReplicaLagCheckInterval",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
 		{
 			path:          "Pool.FreeClaimGasLimit",
 			expectedValue: uint64(150000),
@@ -294,6 +476,30 @@ func Test_Defaults(t *testing.T) {
 			path:          "Executor.URI",
 			expectedValue: "127.0.0.1:50071",
 		},
+		{
+			path:          "Executor.URIs",
+			expectedValue: []string{},
+		},
+		{
+			path:          "Executor.HealthCheckInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "Executor.MaxConsecutiveFailures",
+			expectedValue: uint32(5),
+		},
+		{
+			path:          "Executor.EjectionCooldown",
+			expectedValue: types.NewDuration(30 * time.Second),
+		},
+		{
+			path:          "Executor.MaxRetries",
+			expectedValue: uint32(2),
+		},
+		{
+			path:          "Executor.Local",
+			expectedValue: false,
+		},
 		{
 			path:          "BroadcastServer.Host",
 			expectedValue: "0.0.0.0",
@@ -302,6 +508,10 @@ func Test_Defaults(t *testing.T) {
 			path:          "BroadcastServer.Port",
 			expectedValue: 61090,
 		},
+		{
+			path:          "BroadcastServer.StreamPollInterval",
+			expectedValue: types.NewDuration(time.Second),
+		},
 		{
 			path:          "Metrics.Host",
 			expectedValue: "0.0.0.0",
@@ -314,6 +524,30 @@ func Test_Defaults(t *testing.T) {
 			path:          "Metrics.Enabled",
 			expectedValue: false,
 		},
+		{
+			path:          "StateCache.SMTSize",
+			expectedValue: 100000,
+		},
+		{
+			path:          "StateCache.BlockSize",
+			expectedValue: 1000,
+		},
+		{
+			path:          "Pruner.Mode",
+			expectedValue: "archive",
+		},
+		{
+			path:          "Pruner.RetainedBatches",
+			expectedValue: uint64(100000),
+		},
+		{
+			path:          "Pruner.Interval",
+			expectedValue: types.NewDuration(1 * time.Minute),
+		},
+		{
+			path:          "Pruner.ChunkSize",
+			expectedValue: uint64(100),
+		},
 		{
 			path:          "Aggregator.Host",
 			expectedValue: "0.0.0.0",
@@ -18,6 +18,9 @@ Host = "localhost"
 Port = "5432"
 EnableLog = false
 MaxConns = 200
+ReadReplicas = []
+MaxReplicaLag = 10
+ReplicaLagCheckInterval = "1s"
 
 [Pool]
 FreeClaimGasLimit = 150000
@@ -32,17 +35,48 @@ FreeClaimGasLimit = 150000
 
 [Etherman]
 URL = "http://localhost:8545"
+URLs = []
 L1ChainID = 1337
 PoEAddr = "0x8A791620dd6260079BF849Dc5567aDC3F2FdC318"
 MaticAddr = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
 GlobalExitRootManagerAddr = "0xa513E6E4b8f2a923D98304ec87F64353C4D5C853"
 MultiGasProvider = true
+L1CacheSize = 1000
+RecordFile = ""
 	[Etherman.Etherscan]
 		ApiKey = ""
+	[Etherman.RemoteSigner]
+		URL = ""
+		Method = "eth_signTransaction"
+		Addresses = []
+	[Etherman.LogRange]
+		Adaptive = true
+		InitialSize = 1000
+		MinSize = 1
+		MaxSize = 10000
+		SparseLogs = 100
 
 [EthTxManager]
 FrequencyToMonitorTxs = "1s"
 WaitTxToBeMined = "2m"
+	[EthTxManager.FeeStrategy]
+	TxType = "legacy"
+	PriorityFeeStrategy = "suggested"
+	FixedPriorityFee = 0
+	BaseFeeMultiplier = 2
+	BumpPercentage = 10
+	EscalationInterval = "2m"
+	DefaultMaxFeePerGas = 0
+	MaxFeePerGasByOwner = []
+	[EthTxManager.Admin]
+	Enabled = false
+	Host = "127.0.0.1"
+	Port = 61095
+	HTTPPort = 61096
+	[EthTxManager.Balance]
+	CheckInterval = "5m"
+	MinBalance = "0"
+	TargetBalance = "0"
 
 [RPC]
 Host = "0.0.0.0"
@@ -60,7 +94,25 @@ DefaultSenderAddress = "0x1111111111111111111111111111111111111111"
 [Synchronizer]
 SyncInterval = "0s"
 SyncChunkSize = 100
+SyncPipelineBufferSize = 10
+SyncPipelineDownloadWorkers = 1
+ResetChunkSize = 1000
+ForcedBatchTimeout = 64
 GenBlockNumber = 63
+L1Finality = "latest"
+L1Confirmations = 0
+	[Synchronizer.Health]
+	Enabled = false
+	Host = "0.0.0.0"
+	Port = 9093
+	MaxL1BlockLag = 100
+	MaxHotShotBatchLag = 100
+	MaxSyncAge = "5m"
+	[Synchronizer.Broadcast]
+	TLS = false
+	CACertFile = ""
+	InitialBackoff = "1s"
+	MaxBackoff = "1m"
 
 [Sequencer]
 MaxSequenceSize = "2000000"
@@ -69,6 +121,7 @@ WaitPeriodSendSequence = "5s"
 LastBatchVirtualizationTimeMaxWaitPeriod = "5s"
 BlocksAmountForTxsToBeDeleted = 100
 FrequencyToCheckTxsForDelete = "12h"
+FrequencyToCheckReorgs = "5s"
 MaxTxsPerBatch = 150
 MaxBatchBytesSize = 150000
 MaxCumulativeGasUsed = 30000000
@@ -129,13 +182,40 @@ StoreBackend = "PostgreSQL"
 
 [MTClient]
 URI = "127.0.0.1:50061"
+	[MTClient.HashDB]
+	User = "prover_user"
+	Password = "prover_pass"
+	Name = "prover_db"
+	Host = "localhost"
+	Port = "5432"
+	EnableLog = false
+	MaxConns = 10
 
 [Executor]
 URI = "127.0.0.1:50071"
+URIs = []
+HealthCheckInterval = "5s"
+MaxConsecutiveFailures = 5
+EjectionCooldown = "30s"
+MaxRetries = 2
+Local = false
 
 [BroadcastServer]
 Host = "0.0.0.0"
 Port = 61090
+TLSCertFile = ""
+TLSKeyFile = ""
+StreamPollInterval = "1s"
+
+[Pruner]
+Mode = "archive"
+RetainedBatches = 100000
+Interval = "1m"
+ChunkSize = 100
+
+[StateCache]
+SMTSize = 100000
+BlockSize = 1000
 
 [Metrics]
 Host = "0.0.0.0"
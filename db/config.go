@@ -1,5 +1,7 @@
 package db
 
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
 // Config provide fields to configure the pool
 type Config struct {
 	// Database name
@@ -22,4 +24,16 @@ type Config struct {
 
 	// MaxConns is the maximum number of connections in the pool.
 	MaxConns int `mapstructure:"MaxConns"`
+
+	// ReadReplicas are the DSNs of the read replicas of the database, the
+	// read only queries of the JSON RPC are sent to them when they are in sync
+	ReadReplicas []string `mapstructure:"ReadReplicas"`
+
+	// MaxReplicaLag is the number of L2 blocks a read replica can be behind
+	// the primary before it stops being used
+	MaxReplicaLag uint64 `mapstructure:"MaxReplicaLag"`
+
+	// ReplicaLagCheckInterval is the time between the checks of the lag of
+	// the read replicas
+	ReplicaLagCheckInterval types.Duration `mapstructure:"ReplicaLagCheckInterval"`
 }
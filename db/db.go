@@ -43,6 +43,32 @@ func NewSQLDB(cfg Config) (*pgxpool.Pool, error) {
 	return conn, nil
 }
 
+// NewReadReplicaSQLDBs creates a pool for each of the read replicas of the
+// given config
+func NewReadReplicaSQLDBs(cfg Config) ([]*pgxpool.Pool, error) {
+	pools := make([]*pgxpool.Pool, 0, len(cfg.ReadReplicas))
+	for i, dsn := range cfg.ReadReplicas {
+		config, err := pgxpool.ParseConfig(dsn)
+		if err != nil {
+			log.Errorf("Unable to parse DSN of read replica %d: %v\n", i, err)
+			return nil, err
+		}
+		if cfg.MaxConns > 0 {
+			config.MaxConns = int32(cfg.MaxConns)
+		}
+		if cfg.EnableLog {
+			config.ConnConfig.Logger = logger{}
+		}
+		conn, err := pgxpool.ConnectConfig(context.Background(), config)
+		if err != nil {
+			log.Errorf("Unable to connect to read replica %d: %v\n", i, err)
+			return nil, err
+		}
+		pools = append(pools, conn)
+	}
+	return pools, nil
+}
+
 // RunMigrationsUp runs migrate-up for the given config.
 func RunMigrationsUp(cfg Config, name string) error {
 	log.Info("running migrations up")
@@ -0,0 +1,14 @@
+-- +migrate Down
+DROP TABLE IF EXISTS pool.transaction_event;
+
+-- +migrate Up
+CREATE TABLE pool.transaction_event
+(
+    id         SERIAL PRIMARY KEY,
+    hash       VARCHAR                  NOT NULL REFERENCES pool.transaction (hash) ON DELETE CASCADE,
+    status     varchar(15)              NOT NULL,
+    reason     VARCHAR                  NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_transaction_event_hash ON pool.transaction_event (hash);
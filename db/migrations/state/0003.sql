@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE state.monitored_txs
+ADD COLUMN gas_tip_cap DECIMAL(78, 0);
+ALTER TABLE state.monitored_txs
+ADD COLUMN gas_fee_cap DECIMAL(78, 0);
+
+-- +migrate Down
+ALTER TABLE state.monitored_txs
+DROP COLUMN IF EXISTS gas_tip_cap;
+ALTER TABLE state.monitored_txs
+DROP COLUMN IF EXISTS gas_fee_cap;
@@ -0,0 +1,62 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the dynamic fee columns to the monitored txs
+type migrationTest0003 struct{}
+
+const insertLegacyMonitoredTx = `INSERT INTO state.monitored_txs (
+	owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, status, history, block_num, created_at, updated_at
+) VALUES (
+	'aggregator', $1, '0x70997970c51812dc3a010c7d01b50e0d17dc79c8', '0x8A791620dd6260079BF849Dc5567aDC3F2FdC318',
+	1, 0, 'abcd', 21000, 1000000000, 'sent', '{"0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f1"}', NULL, $2, $2
+);`
+
+const insertDynamicMonitoredTx = `INSERT INTO state.monitored_txs (
+	owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, gas_tip_cap, gas_fee_cap, status, history, block_num, created_at, updated_at
+) VALUES (
+	'aggregator', $1, '0x70997970c51812dc3a010c7d01b50e0d17dc79c8', '0x8A791620dd6260079BF849Dc5567aDC3F2FdC318',
+	2, 0, 'abcd', 21000, 3000000000, 1000000000, 3000000000, 'sent', '{}', NULL, $2, $2
+);`
+
+func (m migrationTest0003) InsertData(db *sql.DB) error {
+	_, err := db.Exec(insertLegacyMonitoredTx, "legacy_1", time.Now())
+	return err
+}
+
+func (m migrationTest0003) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var gasTipCap, gasFeeCap *uint64
+	row := db.QueryRow("SELECT gas_tip_cap, gas_fee_cap FROM state.monitored_txs WHERE id = 'legacy_1'")
+	assert.NoError(t, row.Scan(&gasTipCap, &gasFeeCap))
+	assert.Nil(t, gasTipCap)
+	assert.Nil(t, gasFeeCap)
+
+	_, err := db.Exec(insertDynamicMonitoredTx, "dynamic_1", time.Now())
+	assert.NoError(t, err)
+	row = db.QueryRow("SELECT gas_tip_cap, gas_fee_cap FROM state.monitored_txs WHERE id = 'dynamic_1'")
+	assert.NoError(t, row.Scan(&gasTipCap, &gasFeeCap))
+	assert.Equal(t, uint64(1000000000), *gasTipCap)
+	assert.Equal(t, uint64(3000000000), *gasFeeCap)
+}
+
+func (m migrationTest0003) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(insertDynamicMonitoredTx, "dynamic_2", time.Now())
+	assert.Error(t, err)
+	_, err = db.Exec(insertLegacyMonitoredTx, "legacy_2", time.Now())
+	assert.NoError(t, err)
+
+	var count int
+	row := db.QueryRow("SELECT count(*) FROM state.monitored_txs")
+	assert.NoError(t, row.Scan(&count))
+	assert.Equal(t, 3, count)
+}
+
+func TestMigration0003(t *testing.T) {
+	runMigrationTest(t, 3, migrationTest0003{})
+}
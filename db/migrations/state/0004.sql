@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE state.fork_id
+(
+    fork_id        BIGINT PRIMARY KEY,
+    from_batch_num NUMERIC NOT NULL,
+    version        VARCHAR NOT NULL DEFAULT '',
+    block_num      BIGINT REFERENCES state.block (block_num) ON DELETE CASCADE
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.fork_id;
@@ -0,0 +1,41 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the fork id table
+type migrationTest0004 struct{}
+
+const insertForkIDBlock = "INSERT INTO state.block (block_num, block_hash, parent_hash, received_at) VALUES ($1, '0x0', '0x0', $2)"
+
+func (m migrationTest0004) InsertData(db *sql.DB) error {
+	_, err := db.Exec(insertForkIDBlock, 1000, time.Now())
+	return err
+}
+
+func (m migrationTest0004) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("INSERT INTO state.fork_id (fork_id, from_batch_num, version, block_num) VALUES (1, 0, 'v1', NULL), (2, 100, 'v2', 1000)")
+	assert.NoError(t, err)
+
+	_, err = db.Exec("DELETE FROM state.block WHERE block_num = 1000")
+	assert.NoError(t, err)
+
+	var count int
+	row := db.QueryRow("SELECT count(*) FROM state.fork_id")
+	assert.NoError(t, row.Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func (m migrationTest0004) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT count(*) FROM state.fork_id")
+	assert.Error(t, err)
+}
+
+func TestMigration0004(t *testing.T) {
+	runMigrationTest(t, 4, migrationTest0004{})
+}
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE state.sync_info
+ADD COLUMN pruned_batch_num BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE state.sync_info
+DROP COLUMN IF EXISTS pruned_batch_num;
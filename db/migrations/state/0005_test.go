@@ -0,0 +1,38 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the last pruned batch to the sync info
+type migrationTest0005 struct{}
+
+func (m migrationTest0005) InsertData(db *sql.DB) error {
+	_, err := db.Exec("UPDATE state.sync_info SET last_batch_num_seen = 10")
+	return err
+}
+
+func (m migrationTest0005) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var lastBatchNumSeen, prunedBatchNum uint64
+	row := db.QueryRow("SELECT last_batch_num_seen, pruned_batch_num FROM state.sync_info")
+	assert.NoError(t, row.Scan(&lastBatchNumSeen, &prunedBatchNum))
+	assert.Equal(t, uint64(10), lastBatchNumSeen)
+	assert.Equal(t, uint64(0), prunedBatchNum)
+}
+
+func (m migrationTest0005) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT pruned_batch_num FROM state.sync_info")
+	assert.Error(t, err)
+
+	var lastBatchNumSeen uint64
+	row := db.QueryRow("SELECT last_batch_num_seen FROM state.sync_info")
+	assert.NoError(t, row.Scan(&lastBatchNumSeen))
+	assert.Equal(t, uint64(10), lastBatchNumSeen)
+}
+
+func TestMigration0005(t *testing.T) {
+	runMigrationTest(t, 5, migrationTest0005{})
+}
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE state.receipt
+ADD COLUMN zk_counters JSONB;
+
+-- +migrate Down
+ALTER TABLE state.receipt
+DROP COLUMN IF EXISTS zk_counters;
@@ -0,0 +1,29 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the ZK counters used by the txs to the receipts
+type migrationTest0006 struct{}
+
+func (m migrationTest0006) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0006) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT zk_counters FROM state.receipt")
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0006) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT zk_counters FROM state.receipt")
+	assert.Error(t, err)
+}
+
+func TestMigration0006(t *testing.T) {
+	runMigrationTest(t, 6, migrationTest0006{})
+}
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE state.virtual_batch
+ADD COLUMN l1_finality VARCHAR NOT NULL DEFAULT 'latest';
+
+-- +migrate Down
+ALTER TABLE state.virtual_batch
+DROP COLUMN IF EXISTS l1_finality;
@@ -0,0 +1,39 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the L1 finality level reached by the virtual batches
+type migrationTest0007 struct{}
+
+func (m migrationTest0007) InsertData(db *sql.DB) error {
+	if _, err := db.Exec("INSERT INTO state.block (block_num, block_hash, parent_hash, received_at) VALUES (1, '0x1', '0x0', $1)", time.Now()); err != nil {
+		return err
+	}
+	if _, err := db.Exec("INSERT INTO state.batch (batch_num, timestamp) VALUES (1, $1)", time.Now()); err != nil {
+		return err
+	}
+	_, err := db.Exec("INSERT INTO state.virtual_batch (batch_num, tx_hash, coinbase, block_num, seen_at) VALUES (1, '0x1', '0x1', 1, 1)")
+	return err
+}
+
+func (m migrationTest0007) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var l1Finality string
+	row := db.QueryRow("SELECT l1_finality FROM state.virtual_batch WHERE batch_num = 1")
+	assert.NoError(t, row.Scan(&l1Finality))
+	assert.Equal(t, "latest", l1Finality)
+}
+
+func (m migrationTest0007) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT l1_finality FROM state.virtual_batch")
+	assert.Error(t, err)
+}
+
+func TestMigration0007(t *testing.T) {
+	runMigrationTest(t, 7, migrationTest0007{})
+}
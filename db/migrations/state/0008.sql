@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE state.reorgs
+(
+    id               SERIAL PRIMARY KEY,
+    kind             VARCHAR                  NOT NULL,
+    depth            BIGINT                   NOT NULL,
+    first_block_num  BIGINT                   NOT NULL,
+    first_batch_num  BIGINT                   NOT NULL,
+    old_hash         VARCHAR                  NOT NULL,
+    new_hash         VARCHAR                  NOT NULL,
+    reason           VARCHAR                  NOT NULL DEFAULT '',
+    dropped_txs      JSONB                    NOT NULL DEFAULT '[]',
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_reorgs_created_at ON state.reorgs (created_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.reorgs;
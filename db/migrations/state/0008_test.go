@@ -0,0 +1,38 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the history of the reorgs of the state
+type migrationTest0008 struct{}
+
+func (m migrationTest0008) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0008) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const insertReorgSQL = `
+		INSERT INTO state.reorgs (kind, depth, first_block_num, first_batch_num, old_hash, new_hash, created_at)
+		VALUES ('l1', 1, 10, 5, '0x1', '0x2', $1)`
+	_, err := db.Exec(insertReorgSQL, time.Now())
+	assert.NoError(t, err)
+
+	var droppedTxs string
+	row := db.QueryRow("SELECT dropped_txs FROM state.reorgs WHERE first_block_num = 10")
+	assert.NoError(t, row.Scan(&droppedTxs))
+	assert.Equal(t, "[]", droppedTxs)
+}
+
+func (m migrationTest0008) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT * FROM state.reorgs")
+	assert.Error(t, err)
+}
+
+func TestMigration0008(t *testing.T) {
+	runMigrationTest(t, 8, migrationTest0008{})
+}
@@ -0,0 +1,9 @@
+-- +migrate Up
+CREATE TABLE state.reset_in_progress
+( --marker of a reset of the state to block_num that has not been completed yet
+    block_num  BIGINT                   NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.reset_in_progress;
@@ -0,0 +1,35 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the marker of the resets of the state in progress
+type migrationTest0009 struct{}
+
+func (m migrationTest0009) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0009) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("INSERT INTO state.reset_in_progress (block_num, started_at) VALUES (10, $1)", time.Now())
+	assert.NoError(t, err)
+
+	var blockNumber uint64
+	row := db.QueryRow("SELECT block_num FROM state.reset_in_progress")
+	assert.NoError(t, row.Scan(&blockNumber))
+	assert.Equal(t, uint64(10), blockNumber)
+}
+
+func (m migrationTest0009) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT * FROM state.reset_in_progress")
+	assert.Error(t, err)
+}
+
+func TestMigration0009(t *testing.T) {
+	runMigrationTest(t, 9, migrationTest0009{})
+}
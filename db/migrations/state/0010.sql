@@ -0,0 +1,9 @@
+-- +migrate Up
+-- the fork ids stored without an L1 block are attached to the first synced
+-- block, so they are removed by the reorgs like the rest of the fork ids
+UPDATE state.fork_id SET block_num = (SELECT MIN(block_num) FROM state.block) WHERE block_num IS NULL;
+DELETE FROM state.fork_id WHERE block_num IS NULL;
+ALTER TABLE state.fork_id ALTER COLUMN block_num SET NOT NULL;
+
+-- +migrate Down
+ALTER TABLE state.fork_id ALTER COLUMN block_num DROP NOT NULL;
@@ -0,0 +1,41 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration attaches the fork ids without an L1 block to the first block
+type migrationTest0010 struct{}
+
+func (m migrationTest0010) InsertData(db *sql.DB) error {
+	for _, blockNumber := range []uint64{2000, 2001} {
+		if _, err := db.Exec(insertForkIDBlock, blockNumber, time.Now()); err != nil {
+			return err
+		}
+	}
+	_, err := db.Exec("INSERT INTO state.fork_id (fork_id, from_batch_num, version, block_num) VALUES (10, 0, 'v10', NULL), (11, 100, 'v11', 2001)")
+	return err
+}
+
+func (m migrationTest0010) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var blockNumber uint64
+	row := db.QueryRow("SELECT block_num FROM state.fork_id WHERE fork_id = 10")
+	assert.NoError(t, row.Scan(&blockNumber))
+	assert.Equal(t, uint64(2000), blockNumber)
+
+	_, err := db.Exec("INSERT INTO state.fork_id (fork_id, from_batch_num, version, block_num) VALUES (12, 200, 'v12', NULL)")
+	assert.Error(t, err)
+}
+
+func (m migrationTest0010) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("INSERT INTO state.fork_id (fork_id, from_batch_num, version, block_num) VALUES (12, 200, 'v12', NULL)")
+	assert.NoError(t, err)
+}
+
+func TestMigration0010(t *testing.T) {
+	runMigrationTest(t, 10, migrationTest0010{})
+}
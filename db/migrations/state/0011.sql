@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS idx_virtual_batch_l1_finality_seen_at ON state.virtual_batch (l1_finality, seen_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.idx_virtual_batch_l1_finality_seen_at;
@@ -0,0 +1,33 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration indexes the virtual batches by finality level and L1 block
+type migrationTest0011 struct{}
+
+const getVirtualBatchFinalityIndex = "SELECT count(*) FROM pg_indexes WHERE indexname = 'idx_virtual_batch_l1_finality_seen_at'"
+
+func (m migrationTest0011) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0011) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(getVirtualBatchFinalityIndex).Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func (m migrationTest0011) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(getVirtualBatchFinalityIndex).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0011(t *testing.T) {
+	runMigrationTest(t, 11, migrationTest0011{})
+}
@@ -0,0 +1,8 @@
+-- +migrate Up
+CREATE TABLE state.processed_reorg
+( --last reorg whose dropped txs were resubmitted to the pool by the sequencer
+    reorg_id BIGINT NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.processed_reorg;
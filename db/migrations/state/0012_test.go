@@ -0,0 +1,34 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the last reorg processed by the sequencer
+type migrationTest0012 struct{}
+
+func (m migrationTest0012) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0012) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("INSERT INTO state.processed_reorg (reorg_id) VALUES (3)")
+	assert.NoError(t, err)
+
+	var reorgID uint64
+	row := db.QueryRow("SELECT reorg_id FROM state.processed_reorg")
+	assert.NoError(t, row.Scan(&reorgID))
+	assert.Equal(t, uint64(3), reorgID)
+}
+
+func (m migrationTest0012) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT * FROM state.processed_reorg")
+	assert.Error(t, err)
+}
+
+func TestMigration0012(t *testing.T) {
+	runMigrationTest(t, 12, migrationTest0012{})
+}
@@ -0,0 +1,12 @@
+-- +migrate Up
+ALTER TABLE state.virtual_batch
+ADD COLUMN last_forced_batch_num BIGINT NOT NULL DEFAULT 0; --last forced batch included up to the batch
+
+UPDATE state.virtual_batch AS v
+   SET last_forced_batch_num = b.last_forced_batch_num
+  FROM (SELECT batch_num, COALESCE(MAX(forced_batch_num) OVER (ORDER BY batch_num), 0) AS last_forced_batch_num FROM state.batch) AS b
+ WHERE b.batch_num = v.batch_num;
+
+-- +migrate Down
+ALTER TABLE state.virtual_batch
+DROP COLUMN IF EXISTS last_forced_batch_num;
@@ -0,0 +1,57 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the last forced batch included up to each virtual batch
+type migrationTest0013 struct{}
+
+func (m migrationTest0013) InsertData(db *sql.DB) error {
+	if _, err := db.Exec("INSERT INTO state.block (block_num, block_hash, parent_hash, received_at) VALUES (1, '0x1', '0x0', $1)", time.Now()); err != nil {
+		return err
+	}
+	if _, err := db.Exec("INSERT INTO state.forced_batch (forced_batch_num, timestamp, block_num) VALUES (1, $1, 1)", time.Now()); err != nil {
+		return err
+	}
+	if _, err := db.Exec("INSERT INTO state.batch (batch_num, timestamp) VALUES (1, $1), (3, $1)", time.Now()); err != nil {
+		return err
+	}
+	if _, err := db.Exec("INSERT INTO state.batch (batch_num, timestamp, forced_batch_num) VALUES (2, $1, 1)", time.Now()); err != nil {
+		return err
+	}
+	_, err := db.Exec("INSERT INTO state.virtual_batch (batch_num, tx_hash, coinbase, block_num, seen_at) VALUES (1, '0x1', '0x1', 1, 1), (2, '0x2', '0x1', 1, 1), (3, '0x3', '0x1', 1, 1)")
+	return err
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	rows, err := db.Query("SELECT last_forced_batch_num FROM state.virtual_batch ORDER BY batch_num")
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	var lastForcedBatchNums []uint64
+	for rows.Next() {
+		var lastForcedBatchNum uint64
+		assert.NoError(t, rows.Scan(&lastForcedBatchNum))
+		lastForcedBatchNums = append(lastForcedBatchNums, lastForcedBatchNum)
+	}
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, []uint64{0, 1, 1}, lastForcedBatchNums)
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT last_forced_batch_num FROM state.virtual_batch")
+	assert.Error(t, err)
+
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM state.virtual_batch").Scan(&count))
+	assert.Equal(t, 3, count)
+}
+
+func TestMigration0013(t *testing.T) {
+	runMigrationTest(t, 13, migrationTest0013{})
+}
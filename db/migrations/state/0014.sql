@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE state.reset_in_progress
+ADD COLUMN batch_num BIGINT; --first batch removed by the reset, NULL once the batches are removed
+
+-- +migrate Down
+ALTER TABLE state.reset_in_progress
+DROP COLUMN IF EXISTS batch_num;
@@ -0,0 +1,41 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the first batch removed by the resets of the state in
+// progress
+type migrationTest0014 struct{}
+
+func (m migrationTest0014) InsertData(db *sql.DB) error {
+	_, err := db.Exec("INSERT INTO state.reset_in_progress (block_num, started_at) VALUES (10, $1)", time.Now())
+	return err
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var batchNumber *uint64
+	row := db.QueryRow("SELECT batch_num FROM state.reset_in_progress")
+	assert.NoError(t, row.Scan(&batchNumber))
+	assert.Nil(t, batchNumber)
+
+	_, err := db.Exec("UPDATE state.reset_in_progress SET batch_num = 7")
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("SELECT batch_num FROM state.reset_in_progress")
+	assert.Error(t, err)
+
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM state.reset_in_progress").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestMigration0014(t *testing.T) {
+	runMigrationTest(t, 14, migrationTest0014{})
+}
@@ -2,13 +2,24 @@ package etherman
 
 import (
 	"github.com/0xPolygonHermez/zkevm-node/etherman/etherscan"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/l1client"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 // Config represents the configuration of the etherman
 type Config struct {
-	URL       string `mapstructure:"URL"`
-	L1ChainID uint64 `mapstructure:"L1ChainID"`
+	URL string `mapstructure:"URL"`
+	// URLs are additional L1 endpoints, the requests are sent to the healthiest
+	// of URL and URLs failing over to the others when it is unavailable
+	URLs      []string `mapstructure:"URLs"`
+	L1ChainID uint64   `mapstructure:"L1ChainID"`
+
+	// LogRange is the configuration of the block ranges of the L1 log queries
+	LogRange l1client.LogRangeConfig `mapstructure:"LogRange"`
+
+	// L1CacheSize is the number of L1 blocks whose header, global exit root
+	// and GlobalExitRootManager deployment are cached, 0 disables the cache
+	L1CacheSize int `mapstructure:"L1CacheSize"`
 
 	PoEAddr                   common.Address `mapstructure:"PoEAddr"`
 	MaticAddr                 common.Address `mapstructure:"MaticAddr"`
@@ -18,9 +29,33 @@ type Config struct {
 	PrivateKeyPath     string `mapstructure:"PrivateKeyPath"`
 	PrivateKeyPassword string `mapstructure:"PrivateKeyPassword"`
 
+	// RemoteSigner is the configuration of the remote signer used to sign the L1 txs
+	// instead of the key store files, it is disabled when the URL is empty
+	RemoteSigner RemoteSignerConfig `mapstructure:"RemoteSigner"`
+
 	MultiGasProvider bool `mapstructure:"MultiGasProvider"`
 	Etherscan        etherscan.Config
 
 	HotShotQueryServiceURL    string `mapstructure:"HotShotQueryServiceURL"`
 	GenesisHotShotBlockNumber uint64 `mapstructure:"GenesisHotShotBlockNumber"`
+
+	// RecordFile is the fixture file where the L1 and HotShot query service
+	// responses of the synchronizer are recorded, to replay the sync in tests
+	// with NewReplayClient. It must not exist, a fixture records a single
+	// sync. Recording is disabled when empty
+	RecordFile string `mapstructure:"RecordFile"`
+}
+
+// RemoteSignerConfig represents the configuration of a remote signer
+type RemoteSignerConfig struct {
+	// URL is the JSON-RPC endpoint of the remote signer
+	URL string `mapstructure:"URL"`
+
+	// Method is the JSON-RPC method used to sign the txs, "eth_signTransaction"
+	// for Web3Signer style signers or "account_signTransaction" for Clef style ones
+	Method string `mapstructure:"Method"`
+
+	// Addresses are the accounts signed by the remote signer, if empty all
+	// the accounts provided by the remote signer are used
+	Addresses []common.Address `mapstructure:"Addresses"`
 }
@@ -15,6 +15,10 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/etherman/etherscan"
 	"github.com/0xPolygonHermez/zkevm-node/etherman/ethgasstation"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/fixture"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/l1cache"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/l1client"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/ihotshot"
 	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/matic"
 	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevm"
@@ -31,7 +35,6 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -49,6 +52,7 @@ var (
 	emergencyStateDeactivatedSignatureHash      = crypto.Keccak256Hash([]byte("EmergencyStateDeactivated()"))
 	updateZkEVMVersionSignatureHash             = crypto.Keccak256Hash([]byte("UpdateZkEVMVersion(uint64,uint64,string)"))
 	newBlocksSignatureHash                      = crypto.Keccak256Hash([]byte("NewBlocks(uint256,uint256)"))
+	forceBatchSignatureHash                     = crypto.Keccak256Hash([]byte("ForceBatch(uint64,bytes32,address,bytes)"))
 
 	// Proxy events
 	initializedSignatureHash    = crypto.Keccak256Hash([]byte("Initialized(uint8)"))
@@ -83,6 +87,10 @@ const (
 	SequenceBatchesOrder EventOrder = "SequenceBatches"
 	// TrustedVerifyBatchOrder identifies a TrustedVerifyBatch event
 	TrustedVerifyBatchOrder EventOrder = "TrustedVerifyBatch"
+	// ForkIDsOrder identifies an UpdateZkEVMVersion event
+	ForkIDsOrder EventOrder = "ForkIDs"
+	// ForcedBatchesOrder identifies a ForceBatch event
+	ForcedBatchesOrder EventOrder = "ForcedBatches"
 )
 
 type ethereumClient interface {
@@ -96,6 +104,19 @@ type ethereumClient interface {
 	ethereum.TransactionSender
 
 	bind.DeployBackend
+
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// closer is implemented by the signers holding a connection
+type closer interface {
+	Close()
+}
+
+// blockHashVerifier is implemented by the L1 clients that check the block
+// hashes against all their providers
+type blockHashVerifier interface {
+	ConsistentBlockHash(ctx context.Context, number uint64) (common.Hash, error)
 }
 
 type externalGasProviders struct {
@@ -114,18 +135,55 @@ type Client struct {
 
 	GasProviders externalGasProviders
 
-	cfg  Config
-	auth map[common.Address]bind.TransactOpts // empty in case of read-only client
+	cfg      Config
+	logRange *l1client.LogRange
+	l1Cache  *l1cache.Client
+	// httpClient sends the requests to the HotShot query service
+	httpClient *http.Client
+	// recorder records the L1 and HotShot query service responses, nil if
+	// Config.RecordFile is not set
+	recorder *fixture.Recorder
+	auth     map[common.Address]bind.TransactOpts // empty in case of read-only client
+	signers  map[common.Address]Signer
 }
 
 // NewClient creates a new etherman.
 func NewClient(cfg Config) (*Client, error) {
-	// Connect to ethereum node
-	ethClient, err := ethclient.Dial(cfg.URL)
+	// Connect to ethereum nodes
+	l1, err := l1client.Dial(append([]string{cfg.URL}, cfg.URLs...))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RecordFile == "" {
+		return newClient(cfg, l1, http.DefaultClient)
+	}
+	recorder, err := fixture.NewRecorder(cfg.RecordFile)
 	if err != nil {
-		log.Errorf("error connecting to %s: %+v", cfg.URL, err)
 		return nil, err
 	}
+	log.Warnf("Recording the L1 and HotShot query service responses in %s", cfg.RecordFile)
+	etherMan, err := newClient(cfg, recorder.L1(l1), &http.Client{Transport: recorder.HotShot(http.DefaultTransport)})
+	if err != nil {
+		_ = recorder.Close()
+		return nil, err
+	}
+	etherMan.recorder = recorder
+	return etherMan, nil
+}
+
+// NewReplayClient creates an etherman serving the L1 and HotShot query service
+// responses recorded in the fixture file at path, see Config.RecordFile. It
+// syncs the recorded history deterministically, so it can be used to test the
+// synchronizer against real chains, but it can't send L1 txs.
+func NewReplayClient(cfg Config, path string) (*Client, error) {
+	replay, err := fixture.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(cfg, replay.L1(), &http.Client{Transport: replay.HotShot()})
+}
+
+func newClient(cfg Config, ethClient l1client.Backend, httpClient *http.Client) (*Client, error) {
 	// Create smc clients
 	poe, err := polygonzkevm.NewPolygonzkevm(cfg.PoEAddr, ethClient)
 	if err != nil {
@@ -158,6 +216,8 @@ func NewClient(cfg Config) (*Client, error) {
 		gProviders = append(gProviders, ethgasstation.NewEthGasStationService())
 	}
 
+	metrics.Register()
+
 	log.Infof("Hotshot address %s", cfg.HotShotAddr.String())
 	log.Infof("Genesis hotshot block number %d", cfg.GenesisHotShotBlockNumber)
 
@@ -172,8 +232,12 @@ func NewClient(cfg Config) (*Client, error) {
 			MultiGasProvider: cfg.MultiGasProvider,
 			Providers:        gProviders,
 		},
-		cfg:  cfg,
-		auth: map[common.Address]bind.TransactOpts{},
+		cfg:        cfg,
+		logRange:   l1client.NewLogRange(cfg.LogRange),
+		l1Cache:    l1cache.NewClient(cfg.L1CacheSize, ethClient, globalExitRoot, cfg.GlobalExitRootManagerAddr),
+		httpClient: httpClient,
+		auth:       map[common.Address]bind.TransactOpts{},
+		signers:    map[common.Address]Signer{},
 	}, nil
 }
 
@@ -231,6 +295,7 @@ func (etherMan *Client) GetForks(ctx context.Context) ([]state.ForkIDInterval, e
 				ToBatchNumber:   math.MaxUint64,
 				ForkId:          zkevmVersion.ForkID,
 				Version:         zkevmVersion.Version,
+				BlockNumber:     l.BlockNumber,
 			}
 		} else {
 			forks[len(forks)-1].ToBatchNumber = zkevmVersion.NumBatch - 1
@@ -239,6 +304,7 @@ func (etherMan *Client) GetForks(ctx context.Context) ([]state.ForkIDInterval, e
 				ToBatchNumber:   math.MaxUint64,
 				ForkId:          zkevmVersion.ForkID,
 				Version:         zkevmVersion.Version,
+				BlockNumber:     l.BlockNumber,
 			}
 		}
 		forks = append(forks, fork)
@@ -272,7 +338,7 @@ type Order struct {
 }
 
 func (etherMan *Client) readEvents(ctx context.Context, prevBatch state.L2BatchInfo, query ethereum.FilterQuery, usePreconfirmations bool) ([]Block, map[common.Hash][]Order, error) {
-	logs, err := etherMan.EthClient.FilterLogs(ctx, query)
+	logs, err := etherMan.logRange.FilterLogs(ctx, etherMan.EthClient, query)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -301,6 +367,8 @@ func (etherMan *Client) processEvent(ctx context.Context, prevBatch *state.L2Bat
 		}
 	case updateGlobalExitRootSignatureHash:
 		return etherMan.updateGlobalExitRootEvent(ctx, vLog, blocks, blocksOrder)
+	case forceBatchSignatureHash:
+		return etherMan.forcedBatchEvent(ctx, vLog, blocks, blocksOrder)
 	case verifyBatchesTrustedAggregatorSignatureHash:
 		return etherMan.verifyBatchesTrustedAggregatorEvent(ctx, vLog, blocks, blocksOrder)
 	case verifyBatchesSignatureHash:
@@ -340,8 +408,7 @@ func (etherMan *Client) processEvent(ctx context.Context, prevBatch *state.L2Bat
 		log.Debug("EmergencyStateDeactivated event detected")
 		return nil
 	case updateZkEVMVersionSignatureHash:
-		log.Debug("UpdateZkEVMVersion event detected")
-		return nil
+		return etherMan.updateZkEVMVersionEvent(ctx, vLog, blocks, blocksOrder)
 	}
 	log.Warn("Event not registered: ", vLog)
 	return nil
@@ -384,6 +451,80 @@ func (etherMan *Client) updateGlobalExitRootEvent(ctx context.Context, vLog type
 	return nil
 }
 
+func (etherMan *Client) forcedBatchEvent(ctx context.Context, vLog types.Log, blocks *[]Block, blocksOrder *map[common.Hash][]Order) error {
+	log.Debug("ForceBatch event detected")
+	fb, err := etherMan.PoE.ParseForceBatch(vLog)
+	if err != nil {
+		return err
+	}
+	fullBlock, err := etherMan.EthClient.BlockByHash(ctx, vLog.BlockHash)
+	if err != nil {
+		return fmt.Errorf("error getting hashParent. BlockNumber: %d. Error: %w", vLog.BlockNumber, err)
+	}
+	forcedBatch := ForcedBatch{
+		BlockNumber:       vLog.BlockNumber,
+		ForcedBatchNumber: fb.ForceBatchNum,
+		Sequencer:         fb.Sequencer,
+		GlobalExitRoot:    fb.LastGlobalExitRoot,
+		RawTxsData:        fb.Transactions,
+		ForcedAt:          time.Unix(int64(fullBlock.Time()), 0),
+	}
+
+	if len(*blocks) == 0 || ((*blocks)[len(*blocks)-1].BlockHash != vLog.BlockHash || (*blocks)[len(*blocks)-1].BlockNumber != vLog.BlockNumber) {
+		block, err := prepareBlock(&vLog, fullBlock)
+		if err != nil {
+			return err
+		}
+		block.ForcedBatches = append(block.ForcedBatches, forcedBatch)
+		*blocks = append(*blocks, block)
+	} else if (*blocks)[len(*blocks)-1].BlockHash == vLog.BlockHash && (*blocks)[len(*blocks)-1].BlockNumber == vLog.BlockNumber {
+		(*blocks)[len(*blocks)-1].ForcedBatches = append((*blocks)[len(*blocks)-1].ForcedBatches, forcedBatch)
+	} else {
+		log.Error("Error processing ForceBatch event. BlockHash:", vLog.BlockHash, ". BlockNumber: ", vLog.BlockNumber)
+		return fmt.Errorf("error processing ForceBatch event")
+	}
+	or := Order{
+		Name: ForcedBatchesOrder,
+		Pos:  len((*blocks)[len(*blocks)-1].ForcedBatches) - 1,
+	}
+	(*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash] = append((*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash], or)
+	return nil
+}
+
+func (etherMan *Client) updateZkEVMVersionEvent(ctx context.Context, vLog types.Log, blocks *[]Block, blocksOrder *map[common.Hash][]Order) error {
+	log.Debug("UpdateZkEVMVersion event detected")
+	zkevmVersion, err := etherMan.PoE.ParseUpdateZkEVMVersion(vLog)
+	if err != nil {
+		return err
+	}
+	forkID := ForkID{
+		BatchNumber: zkevmVersion.NumBatch,
+		ForkID:      zkevmVersion.ForkID,
+		Version:     zkevmVersion.Version,
+	}
+
+	if len(*blocks) == 0 || ((*blocks)[len(*blocks)-1].BlockHash != vLog.BlockHash || (*blocks)[len(*blocks)-1].BlockNumber != vLog.BlockNumber) {
+		fullBlock, err := etherMan.EthClient.BlockByHash(ctx, vLog.BlockHash)
+		if err != nil {
+			return fmt.Errorf("error getting hashParent. BlockNumber: %d. Error: %w", vLog.BlockNumber, err)
+		}
+		block, err := prepareBlock(&vLog, fullBlock)
+		if err != nil {
+			return err
+		}
+		block.ForkIDs = append(block.ForkIDs, forkID)
+		*blocks = append(*blocks, block)
+	} else {
+		(*blocks)[len(*blocks)-1].ForkIDs = append((*blocks)[len(*blocks)-1].ForkIDs, forkID)
+	}
+	or := Order{
+		Name: ForkIDsOrder,
+		Pos:  len((*blocks)[len(*blocks)-1].ForkIDs) - 1,
+	}
+	(*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash] = append((*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash], or)
+	return nil
+}
+
 // WaitTxToBeMined waits for an L1 tx to be mined. It will return error if the tx is reverted or timeout is exceeded
 func (etherMan *Client) WaitTxToBeMined(ctx context.Context, tx *types.Transaction, timeout time.Duration) (bool, error) {
 	err := operations.WaitTxToBeMined(ctx, etherMan.EthClient, tx, timeout)
@@ -483,7 +624,7 @@ func (etherMan *Client) TrustedSequencer() (common.Address, error) {
 
 func (etherMan *Client) getMaxPreconfirmation() (uint64, error) {
 	url := etherMan.cfg.HotShotQueryServiceURL + "/availability/block-height"
-	response, err := http.Get(url)
+	response, err := etherMan.httpClient.Get(url)
 	if err != nil {
 		// Usually this means the hotshot query service is not yet running.
 		// Returning the error here will cause the processing of the batch to be
@@ -503,6 +644,23 @@ func (etherMan *Client) getMaxPreconfirmation() (uint64, error) {
 	return blockHeight, nil
 }
 
+// GetLatestHotShotBatchNumber returns the number of the last L2 batch
+// sequenced in HotShot, that is the last one available as a preconfirmation.
+// The number doesn't count the forced batches, compare it with
+// state.L2BatchInfo.HotShotBatchNumber
+func (etherMan *Client) GetLatestHotShotBatchNumber() (uint64, error) {
+	hotShotBlockHeight, err := etherMan.getMaxPreconfirmation()
+	if err != nil {
+		return 0, err
+	}
+	// The HotShot blocks below the height are available, the batch numbers are
+	// offset by the HotShot block height at L2 genesis
+	if hotShotBlockHeight <= etherMan.cfg.GenesisHotShotBlockNumber+1 {
+		return 0, nil
+	}
+	return hotShotBlockHeight - 1 - etherMan.cfg.GenesisHotShotBlockNumber, nil
+}
+
 func (etherMan *Client) GetPreconfirmations(ctx context.Context, prevBatch state.L2BatchInfo) ([]Block, map[common.Hash][]Order, error) {
 	hotShotBlockHeight, err := etherMan.getMaxPreconfirmation()
 	if err != nil {
@@ -513,8 +671,9 @@ func (etherMan *Client) GetPreconfirmations(ctx context.Context, prevBatch state
 	order := make(map[common.Hash][]Order)
 
 	// Start fetching from the next L2 batch (prevBatch.Number + 1), adjusting batch numbers to
-	// HotShot block numbers by offsetting by the HotShot block height at L2 genesis time.
-	fromHotShotBlock := prevBatch.Number + 1 + etherMan.cfg.GenesisHotShotBlockNumber
+	// HotShot block numbers by offsetting by the HotShot block height at L2 genesis time and
+	// discounting the forced batches, which are not derived from a HotShot block.
+	fromHotShotBlock := prevBatch.Number + 1 - prevBatch.ForcedBatchNum + etherMan.cfg.GenesisHotShotBlockNumber
 	log.Infof("Getting HotShot blocks in range %d - %d", fromHotShotBlock, hotShotBlockHeight)
 	for hotShotBlockNum := fromHotShotBlock; hotShotBlockNum < hotShotBlockHeight; hotShotBlockNum++ {
 		var batch SequencedBatch
@@ -638,7 +797,7 @@ func (etherMan *Client) fetchL2Block(ctx context.Context, hotShotBlockNum uint64
 	var err error
 
 	for i := 0; i < maxRetries; i++ {
-		response, err = http.Get(url)
+		response, err = etherMan.httpClient.Get(url)
 		success := err == nil && response.StatusCode == 200
 
 		// If there's no error we should close the response.
@@ -674,7 +833,8 @@ func (etherMan *Client) fetchL2Block(ctx context.Context, hotShotBlockNum uint64
 		panic(err)
 	}
 
-	batchNum := hotShotBlockNum - etherMan.cfg.GenesisHotShotBlockNumber
+	// The forced batches included before this block take a batch number too
+	batchNum := hotShotBlockNum - etherMan.cfg.GenesisHotShotBlockNumber + prevBatch.ForcedBatchNum
 	log.Infof("Creating batch number %d", batchNum)
 
 	// Check that we got the expected batch.
@@ -713,9 +873,10 @@ func (etherMan *Client) fetchL2Block(ctx context.Context, hotShotBlockNum uint64
 		l2Block.Timestamp = prevBatch.Timestamp
 	}
 	*prevBatch = state.L2BatchInfo{
-		Number:    batchNum,
-		L1Block:   l2Block.L1Block,
-		Timestamp: l2Block.Timestamp,
+		Number:         batchNum,
+		L1Block:        l2Block.L1Block,
+		Timestamp:      l2Block.Timestamp,
+		ForcedBatchNum: prevBatch.ForcedBatchNum,
 	}
 
 	log.Infof(
@@ -732,11 +893,11 @@ func (etherMan *Client) fetchL2Block(ctx context.Context, hotShotBlockNum uint64
 	}
 
 	var ger [32]byte
-	code, err := etherMan.EthClient.CodeAt(ctx, etherMan.cfg.GlobalExitRootManagerAddr, big.NewInt(int64(l2Block.L1Block)))
+	deployed, err := etherMan.globalExitRootManagerDeployedAt(ctx, l2Block.L1Block)
 	if err != nil {
 		return err
 	}
-	if len(code) == 0 {
+	if !deployed {
 		// Since this L2 is deployed onto an already-running HotShot sequencer, there may be HotShot
 		// blocks from before the global exit root manager contract was deployed. These blocks
 		// should not contain any transactions for this L2, since they were created before the L2
@@ -745,7 +906,7 @@ func (etherMan *Client) fetchL2Block(ctx context.Context, hotShotBlockNum uint64
 			return fmt.Errorf("block %v (L1 block %v) contains L2 transactions from before GlobalExitRootManager was deployed", hotShotBlockNum, l2Block.L1Block)
 		}
 	} else {
-		ger, err = etherMan.GlobalExitRootManager.GetLastGlobalExitRoot(&bind.CallOpts{BlockNumber: big.NewInt(int64(l2Block.L1Block))})
+		ger, err = etherMan.globalExitRootAt(l2Block.L1Block)
 		if err != nil {
 			return err
 		}
@@ -776,6 +937,18 @@ func (etherMan *Client) fetchL2Block(ctx context.Context, hotShotBlockNum uint64
 	return nil
 }
 
+// globalExitRootManagerDeployedAt returns whether the GlobalExitRootManager
+// contract was deployed at the L1 block
+func (etherMan *Client) globalExitRootManagerDeployedAt(ctx context.Context, blockNumber uint64) (bool, error) {
+	return etherMan.l1Cache.GlobalExitRootManagerDeployedAt(ctx, blockNumber)
+}
+
+// globalExitRootAt returns the last global exit root of the
+// GlobalExitRootManager contract at the L1 block
+func (etherMan *Client) globalExitRootAt(blockNumber uint64) (common.Hash, error) {
+	return etherMan.l1Cache.GlobalExitRootAt(blockNumber)
+}
+
 func (etherMan *Client) verifyBatchesTrustedAggregatorEvent(ctx context.Context, vLog types.Log, blocks *[]Block, blocksOrder *map[common.Hash][]Order) error {
 	log.Debug("TrustedVerifyBatches event detected")
 	vb, err := etherMan.PoE.ParseVerifyBatchesTrustedAggregator(vLog)
@@ -848,7 +1021,30 @@ func hash(data ...[32]byte) [32]byte {
 // HeaderByNumber returns a block header from the current canonical chain. If number is
 // nil, the latest known header is returned.
 func (etherMan *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
-	return etherMan.EthClient.HeaderByNumber(ctx, number)
+	if number == nil || !number.IsUint64() {
+		return etherMan.EthClient.HeaderByNumber(ctx, number)
+	}
+	return etherMan.l1Cache.HeaderByNumber(ctx, number.Uint64())
+}
+
+// ResetL1Cache removes the cached data of the L1 blocks after blockNumber,
+// which may be replaced by a reorg
+func (etherMan *Client) ResetL1Cache(blockNumber uint64) {
+	etherMan.l1Cache.Reset(blockNumber)
+}
+
+// VerifyBlockHash returns the hash of an ethereum block checking that a
+// majority of the L1 providers agree on it, so a reorg reported by a single
+// provider is not acted on
+func (etherMan *Client) VerifyBlockHash(ctx context.Context, blockNumber uint64) (common.Hash, error) {
+	if l1, ok := etherMan.EthClient.(blockHashVerifier); ok {
+		return l1.ConsistentBlockHash(ctx, blockNumber)
+	}
+	header, err := etherMan.EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
 }
 
 // EthBlockByNumber function retrieves the ethereum block information by ethereum block number.
@@ -896,6 +1092,13 @@ func (etherMan *Client) GetLatestVerifiedBatchNum() (uint64, error) {
 	return etherMan.PoE.LastVerifiedBatch(&bind.CallOpts{Pending: false})
 }
 
+// GetVerifiedBatchStateRoot returns the state root stored in the smart contract
+// for a verified batch, it is zero when the batch wasn't the last one of a
+// verification
+func (etherMan *Client) GetVerifiedBatchStateRoot(batchNum uint64) (common.Hash, error) {
+	return etherMan.PoE.BatchNumToStateRoot(&bind.CallOpts{Pending: false}, batchNum)
+}
+
 // GetTx function get ethereum tx
 func (etherMan *Client) GetTx(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
 	return etherMan.EthClient.TransactionByHash(ctx, txHash)
@@ -936,16 +1139,27 @@ func (etherMan *Client) GetL2ChainID() (uint64, error) {
 	return etherMan.PoE.ChainID(&bind.CallOpts{Pending: false})
 }
 
-// GetL2ForkID returns current L2 Fork ID
+// GetL2ForkID returns current L2 Fork ID, the one of the last
+// UpdateZkEVMVersion event
 func (etherMan *Client) GetL2ForkID() (uint64, error) {
-	// TODO: implement this
-	return 1, nil
+	intervals, err := etherMan.GetL2ForkIDIntervals()
+	if err != nil {
+		return 0, err
+	}
+	return intervals[len(intervals)-1].ForkId, nil
 }
 
-// GetL2ForkIDIntervals return L2 Fork ID intervals
+// GetL2ForkIDIntervals return L2 Fork ID intervals read from the
+// UpdateZkEVMVersion events
 func (etherMan *Client) GetL2ForkIDIntervals() ([]state.ForkIDInterval, error) {
-	// TODO: implement this
-	return []state.ForkIDInterval{{FromBatchNumber: 0, ToBatchNumber: math.MaxUint64, ForkId: 1}}, nil
+	intervals, err := etherMan.GetForks(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if len(intervals) == 0 {
+		return nil, errors.New("no UpdateZkEVMVersion events found")
+	}
+	return intervals, nil
 }
 
 // GetL1GasPrice gets the l1 gas price
@@ -974,6 +1188,11 @@ func (etherMan *Client) CurrentNonce(ctx context.Context, account common.Address
 	return etherMan.EthClient.NonceAt(ctx, account, nil)
 }
 
+// CurrentBalance returns the current balance in wei of the provided account
+func (etherMan *Client) CurrentBalance(ctx context.Context, account common.Address) (*big.Int, error) {
+	return etherMan.EthClient.BalanceAt(ctx, account, nil)
+}
+
 // SuggestedGasPrice returns the suggest nonce for the network at the moment
 func (etherMan *Client) SuggestedGasPrice(ctx context.Context) (*big.Int, error) {
 	suggestedGasPrice := etherMan.GetL1GasPrice(ctx)
@@ -983,6 +1202,24 @@ func (etherMan *Client) SuggestedGasPrice(ctx context.Context) (*big.Int, error)
 	return suggestedGasPrice, nil
 }
 
+// SuggestedGasTipCap returns the priority fee per gas suggested by the network
+// to get a dynamic fee tx included in a timely manner
+func (etherMan *Client) SuggestedGasTipCap(ctx context.Context) (*big.Int, error) {
+	return etherMan.EthClient.SuggestGasTipCap(ctx)
+}
+
+// GetLatestBaseFee returns the base fee per gas of the latest L1 block
+func (etherMan *Client) GetLatestBaseFee(ctx context.Context) (*big.Int, error) {
+	header, err := etherMan.EthClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if header.BaseFee == nil {
+		return nil, errors.New("latest block has no base fee, network is not london compatible")
+	}
+	return header.BaseFee, nil
+}
+
 // EstimateGas returns the estimated gas for the tx
 func (etherMan *Client) EstimateGas(ctx context.Context, from common.Address, to *common.Address, value *big.Int, data []byte) (uint64, error) {
 	return etherMan.EthClient.EstimateGas(ctx, ethereum.CallMsg{
@@ -1007,11 +1244,11 @@ func (etherMan *Client) CheckTxWasMined(ctx context.Context, txHash common.Hash)
 
 // SignTx tries to sign a transaction accordingly to the provided sender
 func (etherMan *Client) SignTx(ctx context.Context, sender common.Address, tx *types.Transaction) (*types.Transaction, error) {
-	auth, err := etherMan.getAuthByAddress(sender)
-	if err == ErrNotFound {
+	signer, found := etherMan.signers[sender]
+	if !found {
 		return nil, ErrPrivateKeyNotFound
 	}
-	signedTx, err := auth.Signer(auth.From, tx)
+	signedTx, err := signer.SignTx(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
@@ -1043,9 +1280,58 @@ func (etherMan *Client) GetRevertMessage(ctx context.Context, tx *types.Transact
 func (etherMan *Client) AddOrReplaceAuth(auth bind.TransactOpts) error {
 	log.Infof("added or replaced authorization for address: %v", auth.From.String())
 	etherMan.auth[auth.From] = auth
+	etherMan.replaceSigner(&keystoreSigner{auth: auth})
 	return nil
 }
 
+// AddOrReplaceSigner adds a signer or replace an existent one to the same account,
+// the authorization used by the smart contract calls of the account is replaced too
+func (etherMan *Client) AddOrReplaceSigner(signer Signer) {
+	address := signer.Address()
+	log.Infof("added or replaced signer for address: %v", address.String())
+	etherMan.replaceSigner(signer)
+	etherMan.auth[address] = bind.TransactOpts{
+		From: address,
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(context.Background(), tx)
+		},
+	}
+}
+
+// LoadRemoteSigners loads the signers of the accounts provided by the remote signer
+// configured in RemoteSigner, or only the ones defined in its Addresses if any
+func (etherMan *Client) LoadRemoteSigners(ctx context.Context) ([]common.Address, error) {
+	cfg := etherMan.cfg.RemoteSigner
+	accounts, err := RemoteSignerAccounts(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Addresses) > 0 {
+		available := make(map[common.Address]bool, len(accounts))
+		for _, account := range accounts {
+			available[account] = true
+		}
+		for _, address := range cfg.Addresses {
+			if !available[address] {
+				return nil, fmt.Errorf("remote signer does not provide the account %v", address.String())
+			}
+		}
+		accounts = cfg.Addresses
+	}
+
+	for _, account := range accounts {
+		signer, err := NewRemoteSigner(ctx, cfg, etherMan.cfg.L1ChainID, account)
+		if err != nil {
+			return nil, err
+		}
+		etherMan.AddOrReplaceSigner(signer)
+	}
+	return accounts, nil
+}
+
 // LoadAuthFromKeyStore loads an authorization from a key store file
 func (etherMan *Client) LoadAuthFromKeyStore(path, password string) (*bind.TransactOpts, error) {
 	auth, err := newAuthFromKeystore(path, password, etherMan.cfg.L1ChainID)
@@ -1055,9 +1341,33 @@ func (etherMan *Client) LoadAuthFromKeyStore(path, password string) (*bind.Trans
 
 	log.Infof("loaded authorization for address: %v", auth.From.String())
 	etherMan.auth[auth.From] = auth
+	etherMan.replaceSigner(&keystoreSigner{auth: auth})
 	return &auth, nil
 }
 
+// replaceSigner sets the signer of its account, closing the replaced one
+func (etherMan *Client) replaceSigner(signer Signer) {
+	if old, ok := etherMan.signers[signer.Address()].(closer); ok {
+		old.Close()
+	}
+	etherMan.signers[signer.Address()] = signer
+}
+
+// Close releases the connections held by the signers and closes the fixture
+// file the responses are recorded in
+func (etherMan *Client) Close() {
+	for _, signer := range etherMan.signers {
+		if c, ok := signer.(closer); ok {
+			c.Close()
+		}
+	}
+	if etherMan.recorder != nil {
+		if err := etherMan.recorder.Close(); err != nil {
+			log.Errorf("error closing the fixture file %s: %v", etherMan.cfg.RecordFile, err)
+		}
+	}
+}
+
 // newKeyFromKeystore creates an instance of a keystore key from a keystore file
 func newKeyFromKeystore(path, password string) (*keystore.Key, error) {
 	if path == "" && password == "" {
@@ -0,0 +1,189 @@
+// Package fixture records the L1 and HotShot query service responses seen by
+// the etherman during a sync into a fixture file, and replays them so the
+// synchronizer can be run deterministically against a recorded history.
+package fixture
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// ErrNotRecorded is returned by the replay backends when a request was never
+// seen while recording the fixture
+var ErrNotRecorded = errors.New("request not recorded in the fixture")
+
+// Entry is a response recorded in a fixture. The fixture files hold one JSON
+// encoded entry per line, in the order the requests were answered.
+type Entry struct {
+	// Method is the L1 client method, or hotShotMethod for the requests to the
+	// HotShot query service
+	Method string `json:"method"`
+	// Params are the JSON encoded parameters of the request
+	Params json.RawMessage `json:"params"`
+	// Result is the JSON encoded response, empty when the request failed
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error is the message of the error returned by the request
+	Error string `json:"error,omitempty"`
+}
+
+func (e Entry) key() string {
+	return e.Method + string(e.Params)
+}
+
+// err returns the error recorded in the entry. The not found errors are
+// restored as ethereum.NotFound as callers check for them, and the deadline
+// errors as context.DeadlineExceeded so the log queries that timed out shrink
+// the adaptive log range as they did while recording.
+func (e Entry) err() error {
+	switch e.Error {
+	case "":
+		return nil
+	case ethereum.NotFound.Error():
+		return ethereum.NotFound
+	case context.DeadlineExceeded.Error():
+		return context.DeadlineExceeded
+	default:
+		return errors.New(e.Error)
+	}
+}
+
+func newEntry(method string, params interface{}, result interface{}, err error) (Entry, error) {
+	encodedParams, marshalErr := json.Marshal(params)
+	if marshalErr != nil {
+		return Entry{}, marshalErr
+	}
+	entry := Entry{Method: method, Params: encodedParams}
+	if err != nil {
+		entry.Error = err.Error()
+		return entry, nil
+	}
+	entry.Result, marshalErr = json.Marshal(result)
+	if marshalErr != nil {
+		return Entry{}, marshalErr
+	}
+	return entry, nil
+}
+
+// Recorder writes the recorded responses to a fixture file. Each entry is
+// written as soon as it is recorded, so the fixture is usable even if the
+// node is not stopped cleanly.
+type Recorder struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewRecorder creates a Recorder writing to a new fixture file at path. A
+// fixture holds a single sync, as the responses to the same request are
+// replayed in order, so the file must not exist yet.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644) //nolint:gosec
+	if errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("fixture %s already exists, a fixture records a single sync: %w", path, err)
+	} else if err != nil {
+		return nil, err
+	}
+	return &Recorder{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// record writes the response of a request to the fixture. Recording never
+// fails the request, the errors are only returned to be logged.
+func (r *Recorder) record(method string, params interface{}, result interface{}, err error) error {
+	entry, encodeErr := newEntry(method, params, result, err)
+	if encodeErr != nil {
+		return fmt.Errorf("error encoding the %s response: %w", method, encodeErr)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.encoder.Encode(entry)
+}
+
+// Close closes the fixture file
+func (r *Recorder) Close() error {
+	return r.file.Close()
+}
+
+// Replay serves the responses recorded in a fixture. The responses to the
+// same request are served in the order they were recorded, which replays the
+// reorgs seen while recording, and the last one is served again once they
+// run out.
+//
+// The requests are matched by their params, so the sync replayed must make
+// the same requests as the recorded one. The block ranges of the log queries
+// depend on the SyncChunkSize of the synchronizer and the LogRange of the
+// etherman, they must be configured as they were while recording. The
+// adaptive log ranges shrink on the recorded errors as they did then.
+type Replay struct {
+	mutex     sync.Mutex
+	responses map[string][]Entry
+}
+
+// Load reads the fixture file at path
+func Load(path string) (*Replay, error) {
+	file, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	replay := &Replay{responses: make(map[string][]Entry)}
+	scanner := bufio.NewScanner(file)
+	// The recorded L1 blocks and HotShot blocks may be larger than the default
+	// line limit of the scanner
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("error decoding line %d of fixture %s: %w", line, path, err)
+		}
+		replay.Add(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
+// Add appends a response to the ones served by the replay, it allows tests to
+// build fixtures in code or to extend the recorded ones
+func (r *Replay) Add(entry Entry) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.responses[entry.key()] = append(r.responses[entry.key()], entry)
+}
+
+// next decodes into result the next response to the request
+func (r *Replay) next(method string, params interface{}, result interface{}) error {
+	encodedParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	key := Entry{Method: method, Params: encodedParams}.key()
+
+	r.mutex.Lock()
+	responses := r.responses[key]
+	if len(responses) == 0 {
+		r.mutex.Unlock()
+		return fmt.Errorf("%w: %s %s", ErrNotRecorded, method, encodedParams)
+	}
+	entry := responses[0]
+	if len(responses) > 1 {
+		r.responses[key] = responses[1:]
+	}
+	r.mutex.Unlock()
+
+	if err := entry.err(); err != nil {
+		return err
+	}
+	return json.Unmarshal(entry.Result, result)
+}
@@ -0,0 +1,238 @@
+package fixture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/l1client"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backendMock serves a chain whose block 2 is reorged after being requested
+// once
+type backendMock struct {
+	l1client.Backend
+	blocks  []*types.Block
+	reorged *types.Block
+	calls   int
+}
+
+func (b *backendMock) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	n := number.Uint64()
+	if n >= uint64(len(b.blocks)) {
+		return nil, ethereum.NotFound
+	}
+	if n == 2 {
+		b.calls++
+		if b.calls > 1 {
+			return b.reorged, nil
+		}
+	}
+	return b.blocks[n], nil
+}
+
+func (b *backendMock) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return []types.Log{{
+		Address:     common.HexToAddress("0x1"),
+		Topics:      []common.Hash{common.HexToHash("0x2")},
+		Data:        []byte{3},
+		BlockNumber: q.FromBlock.Uint64(),
+		TxHash:      b.blocks[1].Transactions()[0].Hash(),
+		BlockHash:   b.blocks[1].Hash(),
+	}}, nil
+}
+
+func (b *backendMock) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	return b.blocks[1].Transactions()[0], false, nil
+}
+
+func (b *backendMock) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, errors.New("execution reverted")
+}
+
+func newBackendMock(t *testing.T) *backendMock {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.NewLondonSigner(big.NewInt(1337))
+	tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		Data:      []byte{1, 2, 3},
+	})
+	require.NoError(t, err)
+
+	var blocks []*types.Block
+	parentHash := common.Hash{}
+	for n := int64(0); n < 3; n++ {
+		header := &types.Header{Number: big.NewInt(n), ParentHash: parentHash, Time: uint64(n), BaseFee: big.NewInt(1)}
+		var txs []*types.Transaction
+		if n == 1 {
+			txs = []*types.Transaction{tx}
+		}
+		block := types.NewBlockWithHeader(header).WithBody(txs, nil)
+		blocks = append(blocks, block)
+		parentHash = block.Hash()
+	}
+	reorged := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), ParentHash: blocks[1].Hash(), Extra: []byte("reorg")})
+	return &backendMock{blocks: blocks, reorged: reorged}
+}
+
+func TestRecordAndReplayL1(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "fixture.jsonl")
+	backend := newBackendMock(t)
+
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	l1 := recorder.L1(backend)
+	var recorded []*types.Block
+	for _, n := range []int64{0, 1, 2, 2} {
+		block, err := l1.BlockByNumber(ctx, big.NewInt(n))
+		require.NoError(t, err)
+		recorded = append(recorded, block)
+	}
+	_, err = l1.BlockByNumber(ctx, big.NewInt(3))
+	require.ErrorIs(t, err, ethereum.NotFound)
+	query := ethereum.FilterQuery{FromBlock: big.NewInt(1), ToBlock: big.NewInt(2), Addresses: []common.Address{common.HexToAddress("0x1")}}
+	logs, err := l1.FilterLogs(ctx, query)
+	require.NoError(t, err)
+	tx, _, err := l1.TransactionByHash(ctx, logs[0].TxHash)
+	require.NoError(t, err)
+	_, err = l1.CallContract(ctx, ethereum.CallMsg{Data: []byte{1}}, nil)
+	require.Error(t, err)
+	require.NoError(t, recorder.Close())
+
+	replay, err := Load(path)
+	require.NoError(t, err)
+	l1Replay := replay.L1()
+
+	// The block 2 is served as first seen, then reorged, and the last response
+	// is served again once the recorded ones run out
+	for i, n := range []int64{0, 1, 2, 2, 2} {
+		block, err := l1Replay.BlockByNumber(ctx, big.NewInt(n))
+		require.NoError(t, err)
+		expected := recorded[len(recorded)-1]
+		if i < len(recorded) {
+			expected = recorded[i]
+		}
+		assert.Equal(t, expected.Hash(), block.Hash())
+		assert.Equal(t, len(expected.Transactions()), len(block.Transactions()))
+	}
+	_, err = l1Replay.BlockByNumber(ctx, big.NewInt(3))
+	assert.ErrorIs(t, err, ethereum.NotFound)
+
+	replayedLogs, err := l1Replay.FilterLogs(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, logs, replayedLogs)
+	replayedTx, isPending, err := l1Replay.TransactionByHash(ctx, logs[0].TxHash)
+	require.NoError(t, err)
+	assert.False(t, isPending)
+	assert.Equal(t, tx.Hash(), replayedTx.Hash())
+	_, err = l1Replay.CallContract(ctx, ethereum.CallMsg{Data: []byte{1}}, nil)
+	assert.EqualError(t, err, "execution reverted")
+
+	_, err = l1Replay.BlockByNumber(ctx, big.NewInt(4))
+	assert.ErrorIs(t, err, ErrNotRecorded)
+	_, err = l1Replay.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(2)})
+	assert.ErrorIs(t, err, ErrNotRecorded)
+	err = l1Replay.SendTransaction(ctx, tx)
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestRecordAndReplayHotShot(t *testing.T) {
+	height := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/availability/block-height":
+			height++
+			fmt.Fprint(w, height)
+		case "/availability/block/1":
+			fmt.Fprint(w, `{"height":1,"transactions":"0x"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	get := func(client *http.Client, url string) (int, string) {
+		response, err := client.Get(url)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		return response.StatusCode, string(body)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	client := &http.Client{Transport: recorder.HotShot(http.DefaultTransport)}
+	for _, p := range []string{"/availability/block-height", "/availability/block-height", "/availability/block/1", "/availability/block/2"} {
+		get(client, server.URL+p)
+	}
+	require.NoError(t, recorder.Close())
+
+	replay, err := Load(path)
+	require.NoError(t, err)
+	client = &http.Client{Transport: replay.HotShot()}
+	// The replay doesn't depend on the URL of the query service
+	url := "http://hotshot.invalid"
+	for _, expected := range []string{"1", "2", "2"} {
+		status, body := get(client, url+"/availability/block-height")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, expected, body)
+	}
+	status, body := get(client, url+"/availability/block/1")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"height":1,"transactions":"0x"}`, body)
+	status, _ = get(client, url+"/availability/block/2")
+	assert.Equal(t, http.StatusNotFound, status)
+	_, err = client.Get(url + "/availability/block/3")
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestRecorderSingleSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Close())
+
+	// The fixture of a previous sync is not extended
+	_, err = NewRecorder(path)
+	assert.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestReplayErrors(t *testing.T) {
+	ctx := context.Background()
+	replay := &Replay{responses: make(map[string][]Entry)}
+	for _, err := range []error{context.DeadlineExceeded, ethereum.NotFound, errors.New("execution reverted")} {
+		entry, encodeErr := newEntry("HeaderByNumber", []interface{}{big.NewInt(1)}, nil, err)
+		require.NoError(t, encodeErr)
+		replay.Add(entry)
+	}
+
+	// The errors checked by the callers are restored
+	l1 := replay.L1()
+	_, err := l1.HeaderByNumber(ctx, big.NewInt(1))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = l1.HeaderByNumber(ctx, big.NewInt(1))
+	assert.ErrorIs(t, err, ethereum.NotFound)
+	_, err = l1.HeaderByNumber(ctx, big.NewInt(1))
+	assert.EqualError(t, err, "execution reverted")
+}
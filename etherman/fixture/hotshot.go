@@ -0,0 +1,90 @@
+package fixture
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// hotShotMethod is the method of the entries recording the responses of the
+// HotShot query service, whose params are the path of the request
+const hotShotMethod = "HotShotQueryService"
+
+// hotShotResponse is a recorded response of the HotShot query service
+type hotShotResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"`
+}
+
+// hotShotRecorder is an http.RoundTripper that records the responses to the
+// requests sent to the HotShot query service. The requests are identified by
+// their path, so the replay works with any query service URL without a path.
+type hotShotRecorder struct {
+	transport http.RoundTripper
+	recorder  *Recorder
+}
+
+// HotShot wraps the transport of the HotShot query service client to record
+// its responses
+func (r *Recorder) HotShot(transport http.RoundTripper) http.RoundTripper {
+	return &hotShotRecorder{transport: transport, recorder: r}
+}
+
+// RoundTrip sends the request and records its response
+func (t *hotShotRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := req.URL.RequestURI()
+	response, err := t.transport.RoundTrip(req)
+	if err != nil {
+		t.record(path, nil, err)
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		t.record(path, nil, err)
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	t.record(path, hotShotResponse{StatusCode: response.StatusCode, Body: string(body)}, nil)
+	return response, nil
+}
+
+func (t *hotShotRecorder) record(path string, response interface{}, err error) {
+	if recordErr := t.recorder.record(hotShotMethod, path, response, err); recordErr != nil {
+		log.Warnf("error recording the HotShot query service response to %s: %v", path, recordErr)
+	}
+}
+
+// hotShotReplay is an http.RoundTripper serving the recorded responses of the
+// HotShot query service
+type hotShotReplay struct {
+	replay *Replay
+}
+
+// HotShot returns a transport for the HotShot query service client serving
+// the recorded responses
+func (r *Replay) HotShot() http.RoundTripper {
+	return &hotShotReplay{replay: r}
+}
+
+// RoundTrip serves the next recorded response to the request
+func (t *hotShotReplay) RoundTrip(req *http.Request) (*http.Response, error) {
+	var response hotShotResponse
+	if err := t.replay.next(hotShotMethod, req.URL.RequestURI(), &response); err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        http.StatusText(response.StatusCode),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
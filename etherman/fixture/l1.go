@@ -0,0 +1,342 @@
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/l1client"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// rlpValue JSON encodes the blocks, headers and txs as their hex RLP encoding,
+// which unlike their JSON encoding always preserves their hash
+type rlpValue struct {
+	val interface{}
+}
+
+// MarshalJSON encodes the value as its hex RLP encoding
+func (v rlpValue) MarshalJSON() ([]byte, error) {
+	encoded, err := rlp.EncodeToBytes(v.val)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(hexutil.Bytes(encoded))
+}
+
+// UnmarshalJSON decodes the hex RLP encoding into the value, which must be a
+// pointer
+func (v *rlpValue) UnmarshalJSON(data []byte) error {
+	var encoded hexutil.Bytes
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	return rlp.DecodeBytes(encoded, v.val)
+}
+
+// txResult is the recorded response of TransactionByHash
+type txResult struct {
+	Tx        rlpValue `json:"tx"`
+	IsPending bool     `json:"isPending"`
+}
+
+// L1Recorder is an L1 backend that records in a fixture the responses of the
+// wrapped backend to the requests made while syncing. The other requests,
+// like sending txs, are passed through without being recorded.
+type L1Recorder struct {
+	l1client.Backend
+	recorder *Recorder
+}
+
+// L1 wraps the backend to record its responses
+func (r *Recorder) L1(backend l1client.Backend) *L1Recorder {
+	return &L1Recorder{Backend: backend, recorder: r}
+}
+
+func (r *L1Recorder) record(method string, params []interface{}, result interface{}, err error) {
+	if recordErr := r.recorder.record(method, params, result, err); recordErr != nil {
+		log.Warnf("error recording the L1 response to %s: %v", method, recordErr)
+	}
+}
+
+// BlockByHash returns the given full block
+func (r *L1Recorder) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	block, err := r.Backend.BlockByHash(ctx, hash)
+	r.record("BlockByHash", []interface{}{hash}, rlpValue{block}, err)
+	return block, err
+}
+
+// BlockByNumber returns a block from the current canonical chain, if number is
+// nil the latest known block is returned
+func (r *L1Recorder) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	block, err := r.Backend.BlockByNumber(ctx, number)
+	r.record("BlockByNumber", []interface{}{number}, rlpValue{block}, err)
+	return block, err
+}
+
+// HeaderByHash returns the block header with the given hash
+func (r *L1Recorder) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	header, err := r.Backend.HeaderByHash(ctx, hash)
+	r.record("HeaderByHash", []interface{}{hash}, rlpValue{header}, err)
+	return header, err
+}
+
+// HeaderByNumber returns a block header from the current canonical chain, if
+// number is nil the latest known header is returned
+func (r *L1Recorder) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := r.Backend.HeaderByNumber(ctx, number)
+	r.record("HeaderByNumber", []interface{}{number}, rlpValue{header}, err)
+	return header, err
+}
+
+// TransactionByHash returns the tx with the given hash
+func (r *L1Recorder) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	tx, isPending, err := r.Backend.TransactionByHash(ctx, hash)
+	r.record("TransactionByHash", []interface{}{hash}, txResult{Tx: rlpValue{tx}, IsPending: isPending}, err)
+	return tx, isPending, err
+}
+
+// TransactionReceipt returns the receipt of a mined tx
+func (r *L1Recorder) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := r.Backend.TransactionReceipt(ctx, txHash)
+	r.record("TransactionReceipt", []interface{}{txHash}, receipt, err)
+	return receipt, err
+}
+
+// CodeAt returns the contract code of the given account
+func (r *L1Recorder) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	code, err := r.Backend.CodeAt(ctx, account, blockNumber)
+	r.record("CodeAt", []interface{}{account, blockNumber}, hexutil.Bytes(code), err)
+	return code, err
+}
+
+// CallContract executes a message call transaction
+func (r *L1Recorder) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	result, err := r.Backend.CallContract(ctx, call, blockNumber)
+	r.record("CallContract", []interface{}{call, blockNumber}, hexutil.Bytes(result), err)
+	return result, err
+}
+
+// FilterLogs executes a filter query
+func (r *L1Recorder) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	logs, err := r.Backend.FilterLogs(ctx, q)
+	r.record("FilterLogs", []interface{}{q}, logs, err)
+	return logs, err
+}
+
+// ConsistentBlockHash returns the hash of the block agreed by a majority of
+// the providers when the wrapped backend has several of them, or the hash of
+// the block otherwise
+func (r *L1Recorder) ConsistentBlockHash(ctx context.Context, number uint64) (common.Hash, error) {
+	var (
+		hash common.Hash
+		err  error
+	)
+	if l1, ok := r.Backend.(*l1client.Client); ok {
+		hash, err = l1.ConsistentBlockHash(ctx, number)
+	} else {
+		var header *types.Header
+		header, err = r.Backend.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err == nil {
+			hash = header.Hash()
+		}
+	}
+	r.record("ConsistentBlockHash", []interface{}{number}, hash, err)
+	return hash, err
+}
+
+// L1Replay is an L1 backend serving the responses recorded by L1Recorder, the
+// requests that are not recorded fail with ErrNotRecorded
+type L1Replay struct {
+	replay *Replay
+}
+
+var _ l1client.Backend = (*L1Replay)(nil)
+
+// L1 returns an L1 backend serving the recorded L1 responses
+func (r *Replay) L1() *L1Replay {
+	return &L1Replay{replay: r}
+}
+
+func notRecorded(method string) error {
+	return fmt.Errorf("%w: %s is never recorded", ErrNotRecorded, method)
+}
+
+// BlockByHash returns the given full block
+func (r *L1Replay) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	block := new(types.Block)
+	if err := r.replay.next("BlockByHash", []interface{}{hash}, &rlpValue{block}); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// BlockByNumber returns a block from the current canonical chain, if number is
+// nil the latest known block is returned
+func (r *L1Replay) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	block := new(types.Block)
+	if err := r.replay.next("BlockByNumber", []interface{}{number}, &rlpValue{block}); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// HeaderByHash returns the block header with the given hash
+func (r *L1Replay) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	header := new(types.Header)
+	if err := r.replay.next("HeaderByHash", []interface{}{hash}, &rlpValue{header}); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// HeaderByNumber returns a block header from the current canonical chain, if
+// number is nil the latest known header is returned
+func (r *L1Replay) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header := new(types.Header)
+	if err := r.replay.next("HeaderByNumber", []interface{}{number}, &rlpValue{header}); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// TransactionByHash returns the tx with the given hash
+func (r *L1Replay) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	tx := new(types.Transaction)
+	result := txResult{Tx: rlpValue{tx}}
+	if err := r.replay.next("TransactionByHash", []interface{}{hash}, &result); err != nil {
+		return nil, false, err
+	}
+	return tx, result.IsPending, nil
+}
+
+// TransactionReceipt returns the receipt of a mined tx
+func (r *L1Replay) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt := new(types.Receipt)
+	if err := r.replay.next("TransactionReceipt", []interface{}{txHash}, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// CodeAt returns the contract code of the given account
+func (r *L1Replay) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	var code hexutil.Bytes
+	if err := r.replay.next("CodeAt", []interface{}{account, blockNumber}, &code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// CallContract executes a message call transaction
+func (r *L1Replay) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := r.replay.next("CallContract", []interface{}{call, blockNumber}, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FilterLogs executes a filter query
+func (r *L1Replay) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	if err := r.replay.next("FilterLogs", []interface{}{q}, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// ConsistentBlockHash returns the recorded hash of the block
+func (r *L1Replay) ConsistentBlockHash(ctx context.Context, number uint64) (common.Hash, error) {
+	var hash common.Hash
+	if err := r.replay.next("ConsistentBlockHash", []interface{}{number}, &hash); err != nil {
+		return common.Hash{}, err
+	}
+	return hash, nil
+}
+
+// TransactionCount is not recorded
+func (r *L1Replay) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return 0, notRecorded("TransactionCount")
+}
+
+// TransactionInBlock is not recorded
+func (r *L1Replay) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	return nil, notRecorded("TransactionInBlock")
+}
+
+// SubscribeNewHead is not recorded
+func (r *L1Replay) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, notRecorded("SubscribeNewHead")
+}
+
+// BalanceAt is not recorded
+func (r *L1Replay) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return nil, notRecorded("BalanceAt")
+}
+
+// StorageAt is not recorded
+func (r *L1Replay) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return nil, notRecorded("StorageAt")
+}
+
+// NonceAt is not recorded
+func (r *L1Replay) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return 0, notRecorded("NonceAt")
+}
+
+// PendingBalanceAt is not recorded
+func (r *L1Replay) PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	return nil, notRecorded("PendingBalanceAt")
+}
+
+// PendingStorageAt is not recorded
+func (r *L1Replay) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error) {
+	return nil, notRecorded("PendingStorageAt")
+}
+
+// PendingCodeAt is not recorded
+func (r *L1Replay) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return nil, notRecorded("PendingCodeAt")
+}
+
+// PendingNonceAt is not recorded
+func (r *L1Replay) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 0, notRecorded("PendingNonceAt")
+}
+
+// PendingTransactionCount is not recorded
+func (r *L1Replay) PendingTransactionCount(ctx context.Context) (uint, error) {
+	return 0, notRecorded("PendingTransactionCount")
+}
+
+// EstimateGas is not recorded
+func (r *L1Replay) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 0, notRecorded("EstimateGas")
+}
+
+// SuggestGasPrice is not recorded
+func (r *L1Replay) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return nil, notRecorded("SuggestGasPrice")
+}
+
+// SuggestGasTipCap is not recorded
+func (r *L1Replay) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return nil, notRecorded("SuggestGasTipCap")
+}
+
+// SubscribeFilterLogs is not recorded
+func (r *L1Replay) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, notRecorded("SubscribeFilterLogs")
+}
+
+// SendTransaction is not recorded
+func (r *L1Replay) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return notRecorded("SendTransaction")
+}
@@ -0,0 +1,173 @@
+package l1cache
+
+import (
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	dataHeader         = "header"
+	dataGlobalExitRoot = "global_exit_root"
+	dataCode           = "code"
+)
+
+// blockData is the data of an L1 block kept by the cache, the fields not
+// fetched yet are nil
+type blockData struct {
+	header         *types.Header
+	globalExitRoot *common.Hash
+	hasCode        *bool
+}
+
+// cache keeps the data of the L1 blocks assigned as L1 origin of the HotShot
+// blocks, which are mostly shared by consecutive blocks. When it is full the
+// blocks with the lowest numbers are evicted, as the L1 origins only advance.
+// The data of a block is removed along with its header when a header fetched
+// later doesn't link to it, and on the resets of the synchronizer.
+type cache struct {
+	size   int
+	mutex  sync.Mutex
+	blocks map[uint64]*blockData
+}
+
+// newCache creates a new cache holding the data of up to size blocks, it
+// returns nil, which caches nothing, when size is 0
+func newCache(size int) *cache {
+	if size <= 0 {
+		return nil
+	}
+	return &cache{
+		size:   size,
+		blocks: make(map[uint64]*blockData, size),
+	}
+}
+
+func (c *cache) header(blockNumber uint64) (*types.Header, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if b, ok := c.blocks[blockNumber]; ok && b.header != nil {
+		metrics.L1CacheHit(dataHeader)
+		return b.header, true
+	}
+	metrics.L1CacheMiss(dataHeader)
+	return nil, false
+}
+
+// addHeader caches the header, removing the cached blocks it doesn't link to
+func (c *cache) addHeader(header *types.Header) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	number := header.Number.Uint64()
+	if b, ok := c.blocks[number]; ok && b.header != nil && b.header.Hash() != header.Hash() {
+		c.removeFrom(number)
+	}
+	if number > 0 {
+		if parent, ok := c.blocks[number-1]; ok && parent.header != nil && parent.header.Hash() != header.ParentHash {
+			c.removeFrom(number - 1)
+		}
+	}
+	if child, ok := c.blocks[number+1]; ok && child.header != nil && child.header.ParentHash != header.Hash() {
+		c.removeFrom(number + 1)
+	}
+	c.block(number).header = header
+}
+
+func (c *cache) globalExitRoot(blockNumber uint64) (common.Hash, bool) {
+	if c == nil {
+		return common.Hash{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if b, ok := c.blocks[blockNumber]; ok && b.globalExitRoot != nil {
+		metrics.L1CacheHit(dataGlobalExitRoot)
+		return *b.globalExitRoot, true
+	}
+	metrics.L1CacheMiss(dataGlobalExitRoot)
+	return common.Hash{}, false
+}
+
+func (c *cache) addGlobalExitRoot(blockNumber uint64, globalExitRoot common.Hash) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.block(blockNumber).globalExitRoot = &globalExitRoot
+}
+
+func (c *cache) hasCode(blockNumber uint64) (bool, bool) {
+	if c == nil {
+		return false, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if b, ok := c.blocks[blockNumber]; ok && b.hasCode != nil {
+		metrics.L1CacheHit(dataCode)
+		return *b.hasCode, true
+	}
+	metrics.L1CacheMiss(dataCode)
+	return false, false
+}
+
+func (c *cache) addHasCode(blockNumber uint64, hasCode bool) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.block(blockNumber).hasCode = &hasCode
+}
+
+// reset removes the cached data of the blocks after blockNumber
+func (c *cache) reset(blockNumber uint64) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.removeFrom(blockNumber + 1)
+}
+
+// block returns the data of the block, adding it if it is not cached. The
+// mutex must be held
+func (c *cache) block(blockNumber uint64) *blockData {
+	if b, ok := c.blocks[blockNumber]; ok {
+		return b
+	}
+	b := &blockData{}
+	c.blocks[blockNumber] = b
+	for len(c.blocks) > c.size {
+		lowest := blockNumber
+		for number := range c.blocks {
+			if number < lowest {
+				lowest = number
+			}
+		}
+		if lowest == blockNumber {
+			// The block is below every cached block, it is not kept
+			delete(c.blocks, blockNumber)
+			break
+		}
+		delete(c.blocks, lowest)
+	}
+	return b
+}
+
+// removeFrom removes the cached data of the blocks from blockNumber on. The
+// mutex must be held
+func (c *cache) removeFrom(blockNumber uint64) {
+	for number := range c.blocks {
+		if number >= blockNumber {
+			delete(c.blocks, number)
+		}
+	}
+}
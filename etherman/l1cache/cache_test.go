@@ -0,0 +1,66 @@
+package l1cache
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	header := func(number int64, parentHash common.Hash) *types.Header {
+		return &types.Header{Number: big.NewInt(number), ParentHash: parentHash}
+	}
+
+	c := newCache(2)
+	h1 := header(1, common.Hash{})
+	h2 := header(2, h1.Hash())
+	c.addHeader(h1)
+	c.addHeader(h2)
+	c.addGlobalExitRoot(2, common.HexToHash("0x2"))
+	c.addHasCode(2, true)
+
+	cached, ok := c.header(1)
+	assert.True(t, ok)
+	assert.Equal(t, h1.Hash(), cached.Hash())
+	ger, ok := c.globalExitRoot(2)
+	assert.True(t, ok)
+	assert.Equal(t, common.HexToHash("0x2"), ger)
+	hasCode, ok := c.hasCode(2)
+	assert.True(t, ok)
+	assert.True(t, hasCode)
+	_, ok = c.globalExitRoot(1)
+	assert.False(t, ok)
+
+	// The lowest block is evicted when the cache is full
+	c.addHeader(header(3, h2.Hash()))
+	_, ok = c.header(1)
+	assert.False(t, ok)
+	_, ok = c.header(3)
+	assert.True(t, ok)
+
+	// A header that doesn't link to the cached blocks removes them
+	c.addHeader(header(3, common.HexToHash("0xdead")))
+	_, ok = c.header(2)
+	assert.False(t, ok)
+	_, ok = c.globalExitRoot(2)
+	assert.False(t, ok)
+	_, ok = c.header(3)
+	assert.True(t, ok)
+
+	// The blocks after a reset are removed
+	c.addGlobalExitRoot(4, common.HexToHash("0x4"))
+	c.reset(3)
+	_, ok = c.header(3)
+	assert.True(t, ok)
+	_, ok = c.globalExitRoot(4)
+	assert.False(t, ok)
+
+	// A disabled cache caches nothing
+	disabled := newCache(0)
+	disabled.addHeader(h1)
+	_, ok = disabled.header(1)
+	assert.False(t, ok)
+}
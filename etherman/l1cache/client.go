@@ -0,0 +1,89 @@
+package l1cache
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// L1Reader is the L1 client the data not cached is read from
+type L1Reader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// GlobalExitRootReader is the GlobalExitRootManager contract the global exit
+// roots not cached are read from
+type GlobalExitRootReader interface {
+	GetLastGlobalExitRoot(opts *bind.CallOpts) ([32]byte, error)
+}
+
+// Client reads the data of the L1 blocks assigned as L1 origin of the HotShot
+// blocks through the cache
+type Client struct {
+	cache                     *cache
+	l1                        L1Reader
+	globalExitRootManager     GlobalExitRootReader
+	globalExitRootManagerAddr common.Address
+}
+
+// NewClient creates a new Client caching the data of up to size blocks, 0
+// disables the cache
+func NewClient(size int, l1 L1Reader, globalExitRootManager GlobalExitRootReader, globalExitRootManagerAddr common.Address) *Client {
+	return &Client{
+		cache:                     newCache(size),
+		l1:                        l1,
+		globalExitRootManager:     globalExitRootManager,
+		globalExitRootManagerAddr: globalExitRootManagerAddr,
+	}
+}
+
+// HeaderByNumber returns the header of the L1 block
+func (c *Client) HeaderByNumber(ctx context.Context, blockNumber uint64) (*types.Header, error) {
+	if header, ok := c.cache.header(blockNumber); ok {
+		return header, nil
+	}
+	header, err := c.l1.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	c.cache.addHeader(header)
+	return header, nil
+}
+
+// GlobalExitRootManagerDeployedAt returns whether the GlobalExitRootManager
+// contract was deployed at the L1 block
+func (c *Client) GlobalExitRootManagerDeployedAt(ctx context.Context, blockNumber uint64) (bool, error) {
+	if hasCode, ok := c.cache.hasCode(blockNumber); ok {
+		return hasCode, nil
+	}
+	code, err := c.l1.CodeAt(ctx, c.globalExitRootManagerAddr, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return false, err
+	}
+	c.cache.addHasCode(blockNumber, len(code) != 0)
+	return len(code) != 0, nil
+}
+
+// GlobalExitRootAt returns the last global exit root of the
+// GlobalExitRootManager contract at the L1 block
+func (c *Client) GlobalExitRootAt(blockNumber uint64) (common.Hash, error) {
+	if ger, ok := c.cache.globalExitRoot(blockNumber); ok {
+		return ger, nil
+	}
+	ger, err := c.globalExitRootManager.GetLastGlobalExitRoot(&bind.CallOpts{BlockNumber: new(big.Int).SetUint64(blockNumber)})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	c.cache.addGlobalExitRoot(blockNumber, ger)
+	return ger, nil
+}
+
+// Reset removes the cached data of the L1 blocks after blockNumber, which may
+// be replaced by a reorg
+func (c *Client) Reset(blockNumber uint64) {
+	c.cache.reset(blockNumber)
+}
@@ -0,0 +1,184 @@
+package l1cache
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var globalExitRootManagerAddr = common.HexToAddress("0x1")
+
+// l1Mock serves the headers, the code of the GlobalExitRootManager and its
+// global exit roots at each block, failing with err if set
+type l1Mock struct {
+	headers         map[uint64]*types.Header
+	codes           map[uint64][]byte
+	globalExitRoots map[uint64]common.Hash
+	err             error
+	calls           int
+}
+
+func (l *l1Mock) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	l.calls++
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.headers[number.Uint64()], nil
+}
+
+func (l *l1Mock) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	l.calls++
+	if l.err != nil {
+		return nil, l.err
+	}
+	if account != globalExitRootManagerAddr {
+		return nil, nil
+	}
+	return l.codes[blockNumber.Uint64()], nil
+}
+
+func (l *l1Mock) GetLastGlobalExitRoot(opts *bind.CallOpts) ([32]byte, error) {
+	l.calls++
+	if l.err != nil {
+		return [32]byte{}, l.err
+	}
+	return l.globalExitRoots[opts.BlockNumber.Uint64()], nil
+}
+
+func newL1Mock() *l1Mock {
+	h1 := &types.Header{Number: big.NewInt(1)}
+	h2 := &types.Header{Number: big.NewInt(2), ParentHash: h1.Hash()}
+	return &l1Mock{
+		headers:         map[uint64]*types.Header{1: h1, 2: h2},
+		codes:           map[uint64][]byte{2: {1}},
+		globalExitRoots: map[uint64]common.Hash{1: common.HexToHash("0x1"), 2: common.HexToHash("0x2")},
+	}
+}
+
+func TestClientHeaderByNumber(t *testing.T) {
+	ctx := context.Background()
+	l1 := newL1Mock()
+	c := NewClient(10, l1, l1, globalExitRootManagerAddr)
+
+	header, err := c.HeaderByNumber(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, l1.headers[2].Hash(), header.Hash())
+	header, err = c.HeaderByNumber(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, l1.headers[2].Hash(), header.Hash())
+	assert.Equal(t, 1, l1.calls)
+
+	// A header that doesn't link to the cached one replaces it
+	l1.headers[1] = &types.Header{Number: big.NewInt(1), Extra: []byte{1}}
+	_, err = c.HeaderByNumber(ctx, 1)
+	require.NoError(t, err)
+	l1.headers[2] = &types.Header{Number: big.NewInt(2), ParentHash: l1.headers[1].Hash()}
+	header, err = c.HeaderByNumber(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, l1.headers[2].Hash(), header.Hash())
+	assert.Equal(t, 3, l1.calls)
+
+	// The errors are not cached
+	l1.err = errors.New("unavailable")
+	_, err = c.HeaderByNumber(ctx, 3)
+	require.Error(t, err)
+	l1.err = nil
+	l1.headers[3] = &types.Header{Number: big.NewInt(3), ParentHash: l1.headers[2].Hash()}
+	header, err = c.HeaderByNumber(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, l1.headers[3].Hash(), header.Hash())
+}
+
+func TestClientGlobalExitRootAt(t *testing.T) {
+	l1 := newL1Mock()
+	c := NewClient(10, l1, l1, globalExitRootManagerAddr)
+
+	for i := 0; i < 2; i++ {
+		ger, err := c.GlobalExitRootAt(1)
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0x1"), ger)
+		ger, err = c.GlobalExitRootAt(2)
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0x2"), ger)
+	}
+	assert.Equal(t, 2, l1.calls)
+
+	l1.err = errors.New("unavailable")
+	_, err := c.GlobalExitRootAt(3)
+	require.Error(t, err)
+}
+
+func TestClientGlobalExitRootManagerDeployedAt(t *testing.T) {
+	ctx := context.Background()
+	l1 := newL1Mock()
+	c := NewClient(10, l1, l1, globalExitRootManagerAddr)
+
+	for i := 0; i < 2; i++ {
+		deployed, err := c.GlobalExitRootManagerDeployedAt(ctx, 1)
+		require.NoError(t, err)
+		assert.False(t, deployed)
+		deployed, err = c.GlobalExitRootManagerDeployedAt(ctx, 2)
+		require.NoError(t, err)
+		assert.True(t, deployed)
+	}
+	assert.Equal(t, 2, l1.calls)
+
+	l1.err = errors.New("unavailable")
+	_, err := c.GlobalExitRootManagerDeployedAt(ctx, 3)
+	require.Error(t, err)
+}
+
+func TestClientReset(t *testing.T) {
+	ctx := context.Background()
+	l1 := newL1Mock()
+	c := NewClient(10, l1, l1, globalExitRootManagerAddr)
+
+	for _, blockNumber := range []uint64{1, 2} {
+		_, err := c.HeaderByNumber(ctx, blockNumber)
+		require.NoError(t, err)
+		_, err = c.GlobalExitRootAt(blockNumber)
+		require.NoError(t, err)
+		_, err = c.GlobalExitRootManagerDeployedAt(ctx, blockNumber)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 6, l1.calls)
+
+	// The data of the blocks after the reset is read again from L1, the one
+	// of the blocks before it is kept
+	c.Reset(1)
+	l1.globalExitRoots[2] = common.HexToHash("0x3")
+	_, err := c.HeaderByNumber(ctx, 1)
+	require.NoError(t, err)
+	_, err = c.HeaderByNumber(ctx, 2)
+	require.NoError(t, err)
+	ger, err := c.GlobalExitRootAt(2)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x3"), ger)
+	_, err = c.GlobalExitRootManagerDeployedAt(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 9, l1.calls)
+}
+
+func TestClientDisabled(t *testing.T) {
+	ctx := context.Background()
+	l1 := newL1Mock()
+	c := NewClient(0, l1, l1, globalExitRootManagerAddr)
+
+	for i := 0; i < 2; i++ {
+		_, err := c.HeaderByNumber(ctx, 1)
+		require.NoError(t, err)
+		_, err = c.GlobalExitRootAt(1)
+		require.NoError(t, err)
+		_, err = c.GlobalExitRootManagerDeployedAt(ctx, 1)
+		require.NoError(t, err)
+	}
+	c.Reset(0)
+	assert.Equal(t, 6, l1.calls)
+}
@@ -0,0 +1,550 @@
+package l1client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// scoreWeight is the weight of the last request in the health score of a
+	// provider
+	scoreWeight = 0.2
+	// scoreRecovery is the time it takes to a provider to recover its health
+	// score after its last failure
+	scoreRecovery = time.Minute
+	// minVoterHealth is the health score under which a provider doesn't vote
+	// on the hash of a block
+	minVoterHealth = 0.5
+)
+
+// ErrInconsistentBlockHash is returned when there is no majority of the L1
+// providers that agree on the hash of a block
+var ErrInconsistentBlockHash = errors.New("L1 providers return different hashes for the same block")
+
+// Backend is the API of an L1 provider
+type Backend interface {
+	ethereum.ChainReader
+	ethereum.ChainStateReader
+	ethereum.ContractCaller
+	ethereum.GasEstimator
+	ethereum.GasPricer
+	ethereum.LogFilterer
+	ethereum.PendingStateReader
+	ethereum.TransactionReader
+	ethereum.TransactionSender
+
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// provider is an L1 provider along with its health score, the score is
+// a moving average of the results of its requests that recovers over time
+type provider struct {
+	index   int
+	backend Backend
+
+	mu          sync.Mutex
+	score       float64
+	lastFailure time.Time
+}
+
+func (p *provider) health(now time.Time) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.healthAt(now)
+}
+
+func (p *provider) healthAt(now time.Time) float64 {
+	elapsed := now.Sub(p.lastFailure)
+	if elapsed >= scoreRecovery {
+		return 1
+	}
+	return p.score + (1-p.score)*float64(elapsed)/float64(scoreRecovery)
+}
+
+func (p *provider) record(ok bool, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	result := 0.0
+	if ok {
+		result = 1
+	}
+	p.score = (1-scoreWeight)*p.healthAt(now) + scoreWeight*result
+	if !ok {
+		p.lastFailure = now
+	}
+}
+
+// Client is an L1 client that sends the requests to the healthiest of several
+// providers, failing over to the others when a provider is unavailable
+type Client struct {
+	providers []*provider
+}
+
+// Dial connects to the L1 providers of the urls, the first one is preferred
+// while all of them are healthy
+func Dial(urls []string) (*Client, error) {
+	backends := make([]Backend, 0, len(urls))
+	for _, url := range urls {
+		backend, err := ethclient.Dial(url)
+		if err != nil {
+			log.Errorf("error connecting to %s: %+v", url, err)
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+	return New(backends...), nil
+}
+
+// New creates a new Client for the backends, the first one is preferred while
+// all of them are healthy
+func New(backends ...Backend) *Client {
+	c := &Client{providers: make([]*provider, 0, len(backends))}
+	for i, backend := range backends {
+		c.providers = append(c.providers, &provider{index: i, backend: backend, score: 1})
+	}
+	return c
+}
+
+// Health returns the health score of each provider, in the order they were
+// given, from 0 to 1
+func (c *Client) Health() []float64 {
+	now := time.Now()
+	health := make([]float64, 0, len(c.providers))
+	for _, p := range c.providers {
+		health = append(health, p.health(now))
+	}
+	return health
+}
+
+// ranked returns the providers sorted by health score
+func (c *Client) ranked() []*provider {
+	now := time.Now()
+	health := make(map[*provider]float64, len(c.providers))
+	providers := make([]*provider, len(c.providers))
+	for i, p := range c.providers {
+		health[p] = p.health(now)
+		providers[i] = p
+	}
+	sort.SliceStable(providers, func(i, j int) bool {
+		return health[providers[i]] > health[providers[j]]
+	})
+	return providers
+}
+
+// do runs the call on the healthiest provider, failing over to the next ones
+// while the providers are unavailable
+func (c *Client) do(ctx context.Context, call func(Backend) error) error {
+	return c.doWith(ctx, isProviderError, call)
+}
+
+// doWith runs the call like do, failing over to the next provider on the
+// errors for which isProviderErr is true
+func (c *Client) doWith(ctx context.Context, isProviderErr func(context.Context, error) bool, call func(Backend) error) error {
+	var err error
+	for _, p := range c.ranked() {
+		err = call(p.backend)
+		if err == nil || !isProviderErr(ctx, err) {
+			p.record(true, time.Now())
+			return err
+		}
+		p.record(false, time.Now())
+		log.Warnf("L1 provider %d failed, failing over to the next one. Error: %v", p.index, err)
+	}
+	return err
+}
+
+// isProviderError checks if the error means that the provider is unavailable,
+// the errors returned by the node itself, like reverts or missing blocks, are
+// answers that another provider would also return
+func isProviderError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// isLogsProviderError checks if the error of a log query means that the
+// provider is unavailable. A log query that times out is too large rather than
+// sent to an unavailable provider, so it is left to the LogRange to shrink it
+func isLogsProviderError(ctx context.Context, err error) bool {
+	return isProviderError(ctx, err) && !isTimeout(err)
+}
+
+// blockHashVote is the hash of a block returned by a provider
+type blockHashVote struct {
+	p    *provider
+	hash common.Hash
+}
+
+// ConsistentBlockHash returns the hash of the block agreed by a majority of
+// the healthy providers that know it. Without a majority, the votes of the
+// providers whose head is behind the others are discarded, as they may not
+// have seen the last reorg yet, and ErrInconsistentBlockHash is returned if
+// the rest still disagree. The providers outvoted lose health, so a provider
+// that keeps disagreeing stops voting
+func (c *Client) ConsistentBlockHash(ctx context.Context, number uint64) (common.Hash, error) {
+	var (
+		votes   []blockHashVote
+		lastErr error
+	)
+	for _, p := range c.voters() {
+		header, err := p.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			if isProviderError(ctx, err) {
+				p.record(false, time.Now())
+			}
+			if ctx.Err() != nil {
+				return common.Hash{}, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		votes = append(votes, blockHashVote{p: p, hash: header.Hash()})
+	}
+	if len(votes) == 0 {
+		return common.Hash{}, lastErr
+	}
+	if hash, ok := majority(number, votes); ok {
+		return hash, nil
+	}
+
+	votes = discardBehind(ctx, votes)
+	if ctx.Err() != nil {
+		return common.Hash{}, ctx.Err()
+	}
+	if hash, ok := majority(number, votes); ok {
+		return hash, nil
+	}
+	return common.Hash{}, fmt.Errorf("%w: block %d is %s", ErrInconsistentBlockHash, number, describeVotes(votes))
+}
+
+// voters returns the providers healthy enough to vote on the hash of a block,
+// or all of them if none is
+func (c *Client) voters() []*provider {
+	now := time.Now()
+	var voters []*provider
+	for _, p := range c.providers {
+		if p.health(now) >= minVoterHealth {
+			voters = append(voters, p)
+		}
+	}
+	if len(voters) == 0 {
+		return c.providers
+	}
+	return voters
+}
+
+// discardBehind returns the votes of the providers at the highest head, the
+// providers that fail to return their head are discarded too
+func discardBehind(ctx context.Context, votes []blockHashVote) []blockHashVote {
+	heads := make([]*uint64, len(votes))
+	var maxHead uint64
+	for i, vote := range votes {
+		header, err := vote.p.backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			if isProviderError(ctx, err) {
+				vote.p.record(false, time.Now())
+			}
+			continue
+		}
+		head := header.Number.Uint64()
+		heads[i] = &head
+		if head > maxHead {
+			maxHead = head
+		}
+	}
+	var current []blockHashVote
+	for i, vote := range votes {
+		if heads[i] == nil {
+			continue
+		}
+		if *heads[i] < maxHead {
+			log.Debugf("L1 provider %d is behind at block %d, its vote is discarded", vote.p.index, *heads[i])
+			continue
+		}
+		current = append(current, vote)
+	}
+	return current
+}
+
+// majority returns the hash voted by more than half of the votes, the
+// providers that voted it are recorded as healthy and the rest as failed
+func majority(number uint64, votes []blockHashVote) (common.Hash, bool) {
+	counts := make(map[common.Hash]int, len(votes))
+	for _, vote := range votes {
+		counts[vote.hash]++
+	}
+	for hash, count := range counts {
+		if 2*count <= len(votes) { //nolint:gomnd
+			continue
+		}
+		for _, vote := range votes {
+			if vote.hash == hash {
+				vote.p.record(true, time.Now())
+			} else {
+				vote.p.record(false, time.Now())
+				log.Warnf("L1 provider %d returned hash %s for block %d, outvoted by hash %s", vote.p.index, vote.hash, number, hash)
+			}
+		}
+		return hash, true
+	}
+	return common.Hash{}, false
+}
+
+// describeVotes lists the providers that voted each hash
+func describeVotes(votes []blockHashVote) string {
+	var hashes []common.Hash
+	providers := make(map[common.Hash][]int)
+	for _, vote := range votes {
+		if _, ok := providers[vote.hash]; !ok {
+			hashes = append(hashes, vote.hash)
+		}
+		providers[vote.hash] = append(providers[vote.hash], vote.p.index)
+	}
+	descriptions := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		descriptions = append(descriptions, fmt.Sprintf("%s on providers %v", hash, providers[hash]))
+	}
+	return strings.Join(descriptions, " and ")
+}
+
+// BlockByHash returns the given full block
+func (c *Client) BlockByHash(ctx context.Context, hash common.Hash) (block *types.Block, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		block, err = b.BlockByHash(ctx, hash)
+		return err
+	})
+	return block, err
+}
+
+// BlockByNumber returns a block from the current canonical chain, if number is
+// nil the latest known block is returned
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (block *types.Block, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		block, err = b.BlockByNumber(ctx, number)
+		return err
+	})
+	return block, err
+}
+
+// HeaderByHash returns the block header with the given hash
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (header *types.Header, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		header, err = b.HeaderByHash(ctx, hash)
+		return err
+	})
+	return header, err
+}
+
+// HeaderByNumber returns a block header from the current canonical chain, if
+// number is nil the latest known header is returned
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		header, err = b.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+// TransactionCount returns the total number of transactions in the given block
+func (c *Client) TransactionCount(ctx context.Context, blockHash common.Hash) (count uint, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		count, err = b.TransactionCount(ctx, blockHash)
+		return err
+	})
+	return count, err
+}
+
+// TransactionInBlock returns a single transaction at index in the given block
+func (c *Client) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (tx *types.Transaction, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		tx, err = b.TransactionInBlock(ctx, blockHash, index)
+		return err
+	})
+	return tx, err
+}
+
+// SubscribeNewHead subscribes to notifications about the current blockchain head
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (sub ethereum.Subscription, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		sub, err = b.SubscribeNewHead(ctx, ch)
+		return err
+	})
+	return sub, err
+}
+
+// BalanceAt returns the wei balance of the given account
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (balance *big.Int, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		balance, err = b.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return balance, err
+}
+
+// StorageAt returns the value of key in the contract storage of the given account
+func (c *Client) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) (value []byte, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		value, err = b.StorageAt(ctx, account, key, blockNumber)
+		return err
+	})
+	return value, err
+}
+
+// CodeAt returns the contract code of the given account
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		code, err = b.CodeAt(ctx, account, blockNumber)
+		return err
+	})
+	return code, err
+}
+
+// NonceAt returns the account nonce of the given account
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (nonce uint64, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		nonce, err = b.NonceAt(ctx, account, blockNumber)
+		return err
+	})
+	return nonce, err
+}
+
+// PendingBalanceAt returns the wei balance of the given account in the pending state
+func (c *Client) PendingBalanceAt(ctx context.Context, account common.Address) (balance *big.Int, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		balance, err = b.PendingBalanceAt(ctx, account)
+		return err
+	})
+	return balance, err
+}
+
+// PendingStorageAt returns the value of key in the contract storage of the given
+// account in the pending state
+func (c *Client) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) (value []byte, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		value, err = b.PendingStorageAt(ctx, account, key)
+		return err
+	})
+	return value, err
+}
+
+// PendingCodeAt returns the contract code of the given account in the pending state
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		code, err = b.PendingCodeAt(ctx, account)
+		return err
+	})
+	return code, err
+}
+
+// PendingNonceAt returns the account nonce of the given account in the pending state
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		nonce, err = b.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+// PendingTransactionCount returns the total number of transactions in the pending state
+func (c *Client) PendingTransactionCount(ctx context.Context) (count uint, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		count, err = b.PendingTransactionCount(ctx)
+		return err
+	})
+	return count, err
+}
+
+// CallContract executes a message call transaction
+func (c *Client) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		result, err = b.CallContract(ctx, call, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction
+func (c *Client) EstimateGas(ctx context.Context, call ethereum.CallMsg) (gas uint64, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		gas, err = b.EstimateGas(ctx, call)
+		return err
+	})
+	return gas, err
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price
+func (c *Client) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		price, err = b.SuggestGasPrice(ctx)
+		return err
+	})
+	return price, err
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap
+func (c *Client) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		tip, err = b.SuggestGasTipCap(ctx)
+		return err
+	})
+	return tip, err
+}
+
+// FilterLogs executes a filter query, a query that times out is not failed
+// over so the LogRange can shrink it
+func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = c.doWith(ctx, isLogsProviderError, func(b Backend) error {
+		logs, err = b.FilterLogs(ctx, query)
+		return err
+	})
+	return logs, err
+}
+
+// SubscribeFilterLogs subscribes to the results of a streaming filter query
+func (c *Client) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (sub ethereum.Subscription, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		sub, err = b.SubscribeFilterLogs(ctx, query, ch)
+		return err
+	})
+	return sub, err
+}
+
+// TransactionByHash returns the transaction with the given hash
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		tx, isPending, err = b.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, isPending, err
+}
+
+// TransactionReceipt returns the receipt of a mined transaction
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
+	err = c.do(ctx, func(b Backend) error {
+		receipt, err = b.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return receipt, err
+}
+
+// SendTransaction injects a signed transaction into the pending pool for execution
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.do(ctx, func(b Backend) error {
+		return b.SendTransaction(ctx, tx)
+	})
+}
@@ -0,0 +1,189 @@
+package l1client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcError is an error returned by the L1 node itself
+type rpcError struct{ msg string }
+
+func (e rpcError) Error() string  { return e.msg }
+func (e rpcError) ErrorCode() int { return -32000 }
+
+// backendMock serves the headers of its chain, failing with err if set
+type backendMock struct {
+	Backend
+	headers map[uint64]*types.Header
+	err     error
+	calls   int
+}
+
+func (b *backendMock) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	b.calls++
+	if b.err != nil {
+		return nil, b.err
+	}
+	if number == nil {
+		var head *types.Header
+		for _, header := range b.headers {
+			if head == nil || header.Number.Cmp(head.Number) > 0 {
+				head = header
+			}
+		}
+		return head, nil
+	}
+	header, ok := b.headers[number.Uint64()]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return header, nil
+}
+
+func (b *backendMock) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	b.calls++
+	return nil, b.err
+}
+
+func chain(extra []byte, numbers ...uint64) map[uint64]*types.Header {
+	headers := map[uint64]*types.Header{}
+	for _, n := range numbers {
+		headers[n] = &types.Header{Number: new(big.Int).SetUint64(n), Extra: extra}
+	}
+	return headers
+}
+
+func TestClientFailover(t *testing.T) {
+	ctx := context.Background()
+	primary := &backendMock{headers: chain(nil, 1), err: errors.New("connection refused")}
+	secondary := &backendMock{headers: chain(nil, 1)}
+	c := New(primary, secondary)
+
+	header, err := c.HeaderByNumber(ctx, big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), header.Number.Uint64())
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, secondary.calls)
+
+	health := c.Health()
+	assert.Less(t, health[0], health[1])
+
+	// The healthiest provider is tried first
+	_, err = c.HeaderByNumber(ctx, big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 2, secondary.calls)
+}
+
+func TestClientDoesNotFailOverOnNodeErrors(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{"not found", ethereum.NotFound},
+		{"rpc error", rpcError{"execution reverted"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			primary := &backendMock{err: tc.err}
+			secondary := &backendMock{headers: chain(nil, 1)}
+			c := New(primary, secondary)
+
+			_, err := c.HeaderByNumber(ctx, big.NewInt(1))
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, 0, secondary.calls)
+			assert.Equal(t, []float64{1, 1}, c.Health())
+		})
+	}
+}
+
+func TestClientAllProvidersFail(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	c := New(&backendMock{err: errA}, &backendMock{err: errB})
+	_, err := c.HeaderByNumber(context.Background(), big.NewInt(1))
+	assert.ErrorIs(t, err, errB)
+}
+
+func TestConsistentBlockHash(t *testing.T) {
+	ctx := context.Background()
+	a := &backendMock{headers: chain(nil, 1, 2)}
+	b := &backendMock{headers: chain(nil, 1)}
+	forked := &backendMock{headers: chain([]byte{1}, 1, 2)}
+	down := &backendMock{err: errors.New("connection refused")}
+
+	// The providers that don't know the block or are down are ignored
+	hash, err := New(a, b, down).ConsistentBlockHash(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, a.headers[2].Hash(), hash)
+
+	hash, err = New(a, b).ConsistentBlockHash(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, b.headers[1].Hash(), hash)
+
+	_, err = New(a, forked).ConsistentBlockHash(ctx, 2)
+	assert.ErrorIs(t, err, ErrInconsistentBlockHash)
+
+	_, err = New(down, b).ConsistentBlockHash(ctx, 2)
+	assert.ErrorIs(t, err, ethereum.NotFound)
+}
+
+func TestConsistentBlockHashMajority(t *testing.T) {
+	ctx := context.Background()
+	a := &backendMock{headers: chain(nil, 1, 2)}
+	b := &backendMock{headers: chain(nil, 1, 2)}
+	forked := &backendMock{headers: chain([]byte{1}, 1, 2)}
+
+	// The provider outvoted loses health until it stops voting
+	c := New(forked, a, b)
+	for i := 0; i < 10 && c.Health()[0] >= minVoterHealth; i++ {
+		hash, err := c.ConsistentBlockHash(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, a.headers[2].Hash(), hash)
+	}
+	assert.Less(t, c.Health()[0], minVoterHealth)
+	calls := forked.calls
+	hash, err := c.ConsistentBlockHash(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, a.headers[2].Hash(), hash)
+	assert.Equal(t, calls, forked.calls)
+
+	// Without a majority, the providers behind are discarded
+	reorged := &backendMock{headers: chain([]byte{1}, 1, 2, 3)}
+	hash, err = New(a, reorged).ConsistentBlockHash(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, reorged.headers[2].Hash(), hash)
+
+	_, err = New(a, b, forked, reorged).ConsistentBlockHash(ctx, 2)
+	require.NoError(t, err)
+
+	// The providers at the same head that disagree stay inconsistent
+	_, err = New(a, b, forked, &backendMock{headers: chain([]byte{1}, 1, 2)}).ConsistentBlockHash(ctx, 2)
+	assert.ErrorIs(t, err, ErrInconsistentBlockHash)
+}
+
+func TestClientFilterLogsTimeout(t *testing.T) {
+	ctx := context.Background()
+	primary := &backendMock{err: context.DeadlineExceeded}
+	secondary := &backendMock{}
+	c := New(primary, secondary)
+
+	// A log query that times out is returned to be shrunk, not failed over
+	_, err := c.FilterLogs(ctx, ethereum.FilterQuery{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 0, secondary.calls)
+	assert.Equal(t, []float64{1, 1}, c.Health())
+
+	primary.err = errors.New("connection refused")
+	_, err = c.FilterLogs(ctx, ethereum.FilterQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, secondary.calls)
+}
@@ -0,0 +1,174 @@
+package l1client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LogRangeConfig represents the configuration of the block ranges of the L1
+// log queries
+type LogRangeConfig struct {
+	// Adaptive splits the block range of the log queries in windows that shrink
+	// when the provider rejects them and grow when they are sparse
+	Adaptive bool `mapstructure:"Adaptive"`
+
+	// InitialSize is the number of blocks of the first window
+	InitialSize uint64 `mapstructure:"InitialSize"`
+
+	// MinSize is the minimum number of blocks of a window
+	MinSize uint64 `mapstructure:"MinSize"`
+
+	// MaxSize is the maximum number of blocks of a window
+	MaxSize uint64 `mapstructure:"MaxSize"`
+
+	// SparseLogs is the number of logs under which a window is considered
+	// sparse, so the next one is doubled
+	SparseLogs uint64 `mapstructure:"SparseLogs"`
+}
+
+// rangeErrors are the messages returned by the providers when a log query
+// covers too many blocks or results
+var rangeErrors = []string{
+	"query returned more than",
+	"too many",
+	"limit exceeded",
+	"block range",
+	"range too large",
+	"is limited to",
+	"response size",
+	"timeout",
+	"timed out",
+}
+
+// LogRange runs the log queries splitting their block range in windows sized
+// from the responses of the provider, the size is kept between queries
+type LogRange struct {
+	cfg LogRangeConfig
+
+	mu   sync.Mutex
+	size uint64
+}
+
+// NewLogRange creates a new LogRange
+func NewLogRange(cfg LogRangeConfig) *LogRange {
+	if cfg.MinSize == 0 {
+		cfg.MinSize = 1
+	}
+	if cfg.MaxSize < cfg.MinSize {
+		cfg.MaxSize = cfg.MinSize
+	}
+	r := &LogRange{cfg: cfg, size: cfg.InitialSize}
+	r.size = r.clamp(r.size)
+	return r
+}
+
+// Size returns the number of blocks of the next window
+func (r *LogRange) Size() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.size
+}
+
+// FilterLogs runs the query on the filterer. If the adaptive ranges are
+// enabled and the query has both bounds, its block range is queried in windows
+// that are halved each time the provider rejects one and doubled after a
+// sparse one
+func (r *LogRange) FilterLogs(ctx context.Context, filterer ethereum.LogFilterer, query ethereum.FilterQuery) ([]types.Log, error) {
+	if r == nil || !r.cfg.Adaptive || query.BlockHash != nil || query.FromBlock == nil || query.ToBlock == nil {
+		return filterer.FilterLogs(ctx, query)
+	}
+
+	from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+	var logs []types.Log
+	for from <= to {
+		size := r.Size()
+		end := to
+		if to-from >= size {
+			end = from + size - 1
+		}
+		window := query
+		window.FromBlock = new(big.Int).SetUint64(from)
+		window.ToBlock = new(big.Int).SetUint64(end)
+		windowLogs, err := filterer.FilterLogs(ctx, window)
+		if err != nil {
+			if isRangeError(ctx, err) && r.shrink(size) {
+				log.Debugf("log query from L1 block %d to %d rejected, shrinking the window to %d blocks: %v", from, end, r.Size(), err)
+				continue
+			}
+			return nil, err
+		}
+		if end-from+1 == size && uint64(len(windowLogs)) < r.cfg.SparseLogs {
+			r.grow(size)
+		}
+		logs = append(logs, windowLogs...)
+		from = end + 1
+	}
+	return logs, nil
+}
+
+// shrink halves the window if it is still the size used by the rejected query,
+// it returns false if the window can't be shrunk anymore
+func (r *LogRange) shrink(size uint64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size != size {
+		// Another query already resized the window
+		return true
+	}
+	if r.size <= r.cfg.MinSize {
+		return false
+	}
+	r.size = r.clamp(r.size / 2) //nolint:gomnd
+	return true
+}
+
+// grow doubles the window if it is still the size used by the sparse query
+func (r *LogRange) grow(size uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size == size {
+		r.size = r.clamp(r.size * 2) //nolint:gomnd
+	}
+}
+
+func (r *LogRange) clamp(size uint64) uint64 {
+	if size < r.cfg.MinSize {
+		return r.cfg.MinSize
+	}
+	if size > r.cfg.MaxSize {
+		return r.cfg.MaxSize
+	}
+	return size
+}
+
+// isRangeError checks if the error is caused by the size of the log query
+// rather than by the provider being unavailable or the context being done
+func isRangeError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if isTimeout(err) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, rangeErr := range rangeErrors {
+		if strings.Contains(msg, rangeErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTimeout checks if the request timed out
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,115 @@
+package l1client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filtererMock returns a log per block and rejects the queries of more than
+// maxRange blocks
+type filtererMock struct {
+	ethereum.LogFilterer
+	maxRange uint64
+	err      error
+	queries  [][2]uint64
+}
+
+func (f *filtererMock) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+	f.queries = append(f.queries, [2]uint64{from, to})
+	if f.maxRange > 0 && to-from+1 > f.maxRange {
+		return nil, f.err
+	}
+	var logs []types.Log
+	for n := from; n <= to; n++ {
+		logs = append(logs, types.Log{BlockNumber: n})
+	}
+	return logs, nil
+}
+
+func query(from, to uint64) ethereum.FilterQuery {
+	return ethereum.FilterQuery{FromBlock: new(big.Int).SetUint64(from), ToBlock: new(big.Int).SetUint64(to)}
+}
+
+func TestLogRangeShrinksOnRangeErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{"too many results", errors.New("query returned more than 10000 results")},
+		{"block range", errors.New("exceed maximum block range: 50")},
+		{"response size", errors.New("Log response size exceeded")},
+		{"timeout", context.DeadlineExceeded},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filterer := &filtererMock{maxRange: 25, err: tc.err}
+			r := NewLogRange(LogRangeConfig{Adaptive: true, InitialSize: 100, MinSize: 1, MaxSize: 1000, SparseLogs: 0})
+
+			logs, err := r.FilterLogs(context.Background(), filterer, query(1, 100))
+			require.NoError(t, err)
+			require.Len(t, logs, 100)
+			for i, l := range logs {
+				assert.Equal(t, uint64(i+1), l.BlockNumber)
+			}
+			assert.Equal(t, uint64(25), r.Size())
+			assert.Equal(t, [2]uint64{1, 100}, filterer.queries[0])
+			assert.Equal(t, [2]uint64{1, 50}, filterer.queries[1])
+			assert.Equal(t, [2]uint64{1, 25}, filterer.queries[2])
+		})
+	}
+}
+
+func TestLogRangeGrowsOnSparseRanges(t *testing.T) {
+	filterer := &filtererMock{}
+	r := NewLogRange(LogRangeConfig{Adaptive: true, InitialSize: 10, MinSize: 1, MaxSize: 40, SparseLogs: 100})
+
+	logs, err := r.FilterLogs(context.Background(), filterer, query(1, 100))
+	require.NoError(t, err)
+	require.Len(t, logs, 100)
+	assert.Equal(t, [][2]uint64{{1, 10}, {11, 30}, {31, 70}, {71, 100}}, filterer.queries)
+	// The last window was not full, so it is not grown
+	assert.Equal(t, uint64(40), r.Size())
+}
+
+func TestLogRangeKeepsSizeOnDenseRanges(t *testing.T) {
+	filterer := &filtererMock{}
+	r := NewLogRange(LogRangeConfig{Adaptive: true, InitialSize: 10, MinSize: 1, MaxSize: 40, SparseLogs: 5})
+
+	_, err := r.FilterLogs(context.Background(), filterer, query(1, 30))
+	require.NoError(t, err)
+	assert.Equal(t, [][2]uint64{{1, 10}, {11, 20}, {21, 30}}, filterer.queries)
+	assert.Equal(t, uint64(10), r.Size())
+}
+
+func TestLogRangeErrors(t *testing.T) {
+	// Errors not caused by the range are returned as they are
+	otherErr := errors.New("connection refused")
+	r := NewLogRange(LogRangeConfig{Adaptive: true, InitialSize: 100, MinSize: 1, MaxSize: 1000})
+	_, err := r.FilterLogs(context.Background(), &filtererMock{maxRange: 25, err: otherErr}, query(1, 100))
+	assert.ErrorIs(t, err, otherErr)
+	assert.Equal(t, uint64(100), r.Size())
+
+	// The range errors are returned once the window can't be shrunk anymore
+	rangeErr := errors.New("query returned more than 10000 results")
+	r = NewLogRange(LogRangeConfig{Adaptive: true, InitialSize: 100, MinSize: 50, MaxSize: 1000})
+	_, err = r.FilterLogs(context.Background(), &filtererMock{maxRange: 25, err: rangeErr}, query(1, 100))
+	assert.ErrorIs(t, err, rangeErr)
+	assert.Equal(t, uint64(50), r.Size())
+}
+
+func TestLogRangeDisabled(t *testing.T) {
+	filterer := &filtererMock{}
+	r := NewLogRange(LogRangeConfig{Adaptive: false, InitialSize: 10, MinSize: 1, MaxSize: 40})
+	logs, err := r.FilterLogs(context.Background(), filterer, query(1, 100))
+	require.NoError(t, err)
+	assert.Len(t, logs, 100)
+	assert.Equal(t, [][2]uint64{{1, 100}}, filterer.queries)
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"github.com/0xPolygonHermez/zkevm-node/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Prefix for the metrics of the etherman package.
+	Prefix = "etherman_"
+	// L1CacheHitsName is the name of the metric that counts the hits of the L1 data cache.
+	L1CacheHitsName = Prefix + "l1_cache_hits"
+	// L1CacheMissesName is the name of the metric that counts the misses of the L1 data cache.
+	L1CacheMissesName = Prefix + "l1_cache_misses"
+	// L1CacheLabelName is the name of the label for the kind of L1 data cached.
+	L1CacheLabelName = "data"
+)
+
+// Register the metrics for the etherman package.
+func Register() {
+	counterVecs := []metrics.CounterVecOpts{
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: L1CacheHitsName,
+				Help: "[ETHERMAN] number of hits of the L1 data cache",
+			},
+			Labels: []string{L1CacheLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: L1CacheMissesName,
+				Help: "[ETHERMAN] number of misses of the L1 data cache",
+			},
+			Labels: []string{L1CacheLabelName},
+		},
+	}
+
+	metrics.RegisterCounterVecs(counterVecs...)
+}
+
+// L1CacheHit increments the hits of the L1 data cache for the given kind of data.
+func L1CacheHit(data string) {
+	metrics.CounterVecInc(L1CacheHitsName, data)
+}
+
+// L1CacheMiss increments the misses of the L1 data cache for the given kind of data.
+func L1CacheMiss(data string) {
+	metrics.CounterVecInc(L1CacheMissesName, data)
+}
@@ -0,0 +1,195 @@
+package etherman
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// RemoteSignerMethodEth is the signing method of the Web3Signer style remote signers
+	RemoteSignerMethodEth = "eth_signTransaction"
+	// RemoteSignerMethodAccount is the signing method of the Clef style remote signers
+	RemoteSignerMethodAccount = "account_signTransaction"
+
+	remoteSignerAccountsMethodEth     = "eth_accounts"
+	remoteSignerAccountsMethodAccount = "account_list"
+)
+
+var (
+	// ErrInvalidRemoteSignature is used when the tx returned by the remote signer
+	// is not the requested one or it is not signed by the requested account
+	ErrInvalidRemoteSignature = errors.New("remote signer returned an invalid signed tx")
+)
+
+// Signer signs the L1 txs of an account
+type Signer interface {
+	// Address returns the account of the signer
+	Address() common.Address
+	// SignTx signs the provided tx with the account of the signer
+	SignTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error)
+}
+
+// keystoreSigner signs the txs with a private key decrypted from a key store file
+type keystoreSigner struct {
+	auth bind.TransactOpts
+}
+
+// Address returns the account of the signer
+func (s *keystoreSigner) Address() common.Address {
+	return s.auth.From
+}
+
+// SignTx signs the provided tx with the private key of the signer
+func (s *keystoreSigner) SignTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	return s.auth.Signer(s.auth.From, tx)
+}
+
+// RemoteSigner signs the txs of an account using a remote signer that
+// implements the Web3Signer or Clef JSON-RPC signing API, so the private
+// key never leaves the signer host
+type RemoteSigner struct {
+	client  *rpc.Client
+	method  string
+	address common.Address
+	chainID *big.Int
+}
+
+// remoteSignTxArgs are the arguments of the remote signing method
+type remoteSignTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                hexutil.Big     `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// NewRemoteSigner creates a signer for the account using the remote signer
+func NewRemoteSigner(ctx context.Context, cfg RemoteSignerConfig, chainID uint64, address common.Address) (*RemoteSigner, error) {
+	client, err := rpc.DialContext(ctx, cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer %v: %w", cfg.URL, err)
+	}
+	return &RemoteSigner{
+		client:  client,
+		method:  remoteSignerMethod(cfg),
+		address: address,
+		chainID: new(big.Int).SetUint64(chainID),
+	}, nil
+}
+
+// RemoteSignerAccounts returns the accounts the remote signer is able to sign for
+func RemoteSignerAccounts(ctx context.Context, cfg RemoteSignerConfig) ([]common.Address, error) {
+	client, err := rpc.DialContext(ctx, cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer %v: %w", cfg.URL, err)
+	}
+	defer client.Close()
+
+	accountsMethod := remoteSignerAccountsMethodEth
+	if remoteSignerMethod(cfg) == RemoteSignerMethodAccount {
+		accountsMethod = remoteSignerAccountsMethodAccount
+	}
+
+	var accounts []common.Address
+	err = client.CallContext(ctx, &accounts, accountsMethod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote signer accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// Address returns the account of the signer
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// Close closes the connection to the remote signer
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+// SignTx requests the remote signer to sign the provided tx and checks the
+// returned tx is the requested one signed by the account of the signer
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	args := remoteSignTxArgs{
+		From:    s.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(s.chainID),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var result json.RawMessage
+	err := s.client.CallContext(ctx, &result, s.method, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx with remote signer: %w", err)
+	}
+	raw, err := decodeRemoteSignResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode tx signed by remote signer: %w", err)
+	}
+
+	signer := types.LatestSignerForChainID(s.chainID)
+	if signer.Hash(signedTx) != signer.Hash(tx) {
+		return nil, ErrInvalidRemoteSignature
+	}
+	sender, err := types.Sender(signer, signedTx)
+	if err != nil || sender != s.address {
+		return nil, ErrInvalidRemoteSignature
+	}
+	return signedTx, nil
+}
+
+// decodeRemoteSignResult gets the raw signed tx from the result of the signing
+// method, Web3Signer returns it directly and Clef returns it in the raw field
+func decodeRemoteSignResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+
+	var clefResult struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &clefResult); err != nil || len(clefResult.Raw) == 0 {
+		return nil, fmt.Errorf("failed to decode remote signer result: %s", string(result))
+	}
+	return clefResult.Raw, nil
+}
+
+// remoteSignerMethod returns the signing method of the remote signer,
+// the Web3Signer one is used by default
+func remoteSignerMethod(cfg RemoteSignerConfig) string {
+	if strings.TrimSpace(cfg.Method) == "" {
+		return RemoteSignerMethodEth
+	}
+	return cfg.Method
+}
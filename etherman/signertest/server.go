@@ -0,0 +1,161 @@
+// Package signertest provides a local stand-in of a remote signer implementing
+// the Web3Signer and Clef JSON-RPC signing API, to be used by the tests
+package signertest
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrUnknownAccount is returned when the signer does not have the key of the requested account
+var ErrUnknownAccount = errors.New("unknown account")
+
+// Server is a remote signer holding the private keys in memory
+type Server struct {
+	keys    map[common.Address]*ecdsa.PrivateKey
+	chainID *big.Int
+
+	rpcServer  *rpc.Server
+	httpServer *httptest.Server
+}
+
+// SignTxArgs are the arguments of the signing methods
+type SignTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                hexutil.Big     `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// ClefSignTxResult is the result of the Clef signing method
+type ClefSignTxResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// NewServer creates and starts a remote signer for the provided keys
+func NewServer(chainID uint64, keys ...*ecdsa.PrivateKey) (*Server, error) {
+	s := &Server{
+		keys:      make(map[common.Address]*ecdsa.PrivateKey, len(keys)),
+		chainID:   new(big.Int).SetUint64(chainID),
+		rpcServer: rpc.NewServer(),
+	}
+	for _, key := range keys {
+		s.keys[crypto.PubkeyToAddress(key.PublicKey)] = key
+	}
+
+	if err := s.rpcServer.RegisterName("eth", &ethService{s}); err != nil {
+		return nil, err
+	}
+	if err := s.rpcServer.RegisterName("account", &accountService{s}); err != nil {
+		return nil, err
+	}
+	s.httpServer = httptest.NewServer(s.rpcServer)
+	return s, nil
+}
+
+// URL returns the JSON-RPC endpoint of the signer
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// Close stops the signer
+func (s *Server) Close() {
+	s.httpServer.Close()
+	s.rpcServer.Stop()
+}
+
+func (s *Server) accounts() []common.Address {
+	accounts := make([]common.Address, 0, len(s.keys))
+	for account := range s.keys {
+		accounts = append(accounts, account)
+	}
+	return accounts
+}
+
+func (s *Server) signTx(args SignTxArgs) (*types.Transaction, error) {
+	key, found := s.keys[args.From]
+	if !found {
+		return nil, ErrUnknownAccount
+	}
+
+	var txData types.TxData
+	if args.MaxFeePerGas != nil {
+		txData = &types.DynamicFeeTx{
+			ChainID:   s.chainID,
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      args.Data,
+		}
+	} else {
+		txData = &types.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    args.Value.ToInt(),
+			Data:     args.Data,
+		}
+	}
+	return types.SignNewTx(key, types.LatestSignerForChainID(s.chainID), txData)
+}
+
+// ethService implements the Web3Signer methods
+type ethService struct {
+	s *Server
+}
+
+// Accounts returns the accounts of the signer
+func (e *ethService) Accounts() []common.Address {
+	return e.s.accounts()
+}
+
+// SignTransaction signs the tx and returns it encoded
+func (e *ethService) SignTransaction(args SignTxArgs) (hexutil.Bytes, error) {
+	tx, err := e.s.signTx(args)
+	if err != nil {
+		return nil, err
+	}
+	return tx.MarshalBinary()
+}
+
+// accountService implements the Clef methods
+type accountService struct {
+	s *Server
+}
+
+// List returns the accounts of the signer
+func (a *accountService) List() []common.Address {
+	return a.s.accounts()
+}
+
+// SignTransaction signs the tx and returns it both encoded and decoded
+func (a *accountService) SignTransaction(args SignTxArgs) (*ClefSignTxResult, error) {
+	tx, err := a.s.signTx(args)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &ClefSignTxResult{Raw: raw, Tx: tx}, nil
+}
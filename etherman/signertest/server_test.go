@@ -0,0 +1,96 @@
+package signertest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chainID = 1337
+
+func TestRemoteSigner(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	account := crypto.PubkeyToAddress(key.PublicKey)
+
+	server, err := NewServer(chainID, key)
+	require.NoError(t, err)
+	defer server.Close()
+
+	to := common.HexToAddress("0x1")
+	txs := map[string]*types.Transaction{
+		"legacy": types.NewTx(&types.LegacyTx{
+			Nonce: 1, GasPrice: big.NewInt(10), Gas: 21000, To: &to, Value: big.NewInt(5), Data: []byte{1, 2},
+		}),
+		"dynamic": types.NewTx(&types.DynamicFeeTx{
+			ChainID: big.NewInt(chainID), Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(20),
+			Gas: 21000, To: &to, Value: big.NewInt(5), Data: []byte{3},
+		}),
+	}
+
+	for _, method := range []string{etherman.RemoteSignerMethodEth, etherman.RemoteSignerMethodAccount} {
+		cfg := etherman.RemoteSignerConfig{URL: server.URL(), Method: method}
+
+		accounts, err := etherman.RemoteSignerAccounts(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []common.Address{account}, accounts)
+
+		signer, err := etherman.NewRemoteSigner(ctx, cfg, chainID, account)
+		require.NoError(t, err)
+
+		for name, tx := range txs {
+			signedTx, err := signer.SignTx(ctx, tx)
+			require.NoError(t, err, method, name)
+
+			sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(chainID)), signedTx)
+			require.NoError(t, err)
+			assert.Equal(t, account, sender)
+			assert.Equal(t, tx.Type(), signedTx.Type())
+			assert.Equal(t, tx.Nonce(), signedTx.Nonce())
+			assert.Equal(t, tx.Data(), signedTx.Data())
+		}
+	}
+}
+
+func TestRemoteSignerUnknownAccount(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	server, err := NewServer(chainID, key)
+	require.NoError(t, err)
+	defer server.Close()
+
+	signer, err := etherman.NewRemoteSigner(ctx, etherman.RemoteSignerConfig{URL: server.URL()}, chainID, common.HexToAddress("0x2"))
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x1")
+	_, err = signer.SignTx(ctx, types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(0)}))
+	assert.ErrorContains(t, err, ErrUnknownAccount.Error())
+}
+
+func TestRemoteSignerWrongChainID(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	account := crypto.PubkeyToAddress(key.PublicKey)
+
+	server, err := NewServer(chainID+1, key)
+	require.NoError(t, err)
+	defer server.Close()
+
+	signer, err := etherman.NewRemoteSigner(ctx, etherman.RemoteSignerConfig{URL: server.URL()}, chainID, account)
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x1")
+	_, err = signer.SignTx(ctx, types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(0)}))
+	assert.ErrorIs(t, err, etherman.ErrInvalidRemoteSignature)
+}
@@ -24,6 +24,7 @@ type Block struct {
 	ForcedBatches         []ForcedBatch
 	SequencedBatches      [][]SequencedBatch
 	VerifiedBatches       []VerifiedBatch
+	ForkIDs               []ForkID
 	ReceivedAt            time.Time
 }
 
@@ -56,6 +57,13 @@ type ForcedBatch struct {
 	ForcedAt          time.Time
 }
 
+// ForkID represents a fork id activated on L1 by an UpdateZkEVMVersion event
+type ForkID struct {
+	BatchNumber uint64
+	ForkID      uint64
+	Version     string
+}
+
 // VerifiedBatch represents a VerifiedBatch
 type VerifiedBatch struct {
 	BlockNumber uint64
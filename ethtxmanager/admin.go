@@ -0,0 +1,210 @@
+package ethtxmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// cancelTxGas is the gas used by the zero value tx sent to cancel a monitored tx
+	cancelTxGas = 21000
+
+	// minReplacementBumpPercentage is the min fee increase required by
+	// the L1 nodes to accept a tx replacing another one with the same nonce
+	minReplacementBumpPercentage = 10
+)
+
+// forceResend updates the fees of a monitored tx that was not mined yet,
+// then signs and sends the new tx to the network without waiting for the
+// next monitoring cycle.
+func (c *Client) forceResend(ctx context.Context, owner, id string, newFees fees, dbTx pgx.Tx) (monitoredTx, error) {
+	mTx, unlock, err := c.getLocked(ctx, owner, id, dbTx)
+	if err != nil {
+		return mTx, err
+	}
+	defer unlock()
+
+	if mTx.status != MonitoredTxStatusCreated && mTx.status != MonitoredTxStatusSent {
+		return mTx, ErrInvalidStatus
+	}
+
+	currentFees := mTx.fees()
+	if newFees.isDynamic() != currentFees.isDynamic() || newFees.gasPrice.Cmp(currentFees.gasPrice) <= 0 ||
+		(newFees.isDynamic() && newFees.gasTipCap.Cmp(currentFees.gasTipCap) < 0) {
+		return mTx, ErrInvalidFees
+	}
+
+	mTxLog := log.WithFields("owner", owner, "monitoredTx", id)
+	mTxLog.Infof("forcing resend with fees updated from %v to %v", currentFees.String(), newFees.String())
+	mTx.setFees(newFees)
+
+	err = c.signAndSend(ctx, &mTx, dbTx)
+	if err != nil {
+		return mTx, err
+	}
+	return mTx, nil
+}
+
+// cancelTx replaces a monitored tx that was not mined yet with a zero value tx to
+// the sender using the same nonce and higher fees, so the nonce is consumed and
+// the data of the monitored tx is not executed.
+//
+// The owner fee caps are not applied to the cancel tx, since it needs to pay
+// more than the tx it replaces to be accepted by the L1 nodes.
+func (c *Client) cancelTx(ctx context.Context, owner, id string, dbTx pgx.Tx) (monitoredTx, error) {
+	mTx, unlock, err := c.getLocked(ctx, owner, id, dbTx)
+	if err != nil {
+		return mTx, err
+	}
+	defer unlock()
+
+	if mTx.status != MonitoredTxStatusCreated && mTx.status != MonitoredTxStatusSent {
+		return mTx, ErrInvalidStatus
+	}
+
+	suggestedFees, err := c.suggestFees(ctx, "", mTx.txType())
+	if err != nil {
+		return mTx, err
+	}
+
+	bumpPercentage := c.cfg.FeeStrategy.BumpPercentage
+	if bumpPercentage < minReplacementBumpPercentage {
+		bumpPercentage = minReplacementBumpPercentage
+	}
+	currentFees := mTx.fees()
+	cancelFees := fees{
+		gasPrice: maxBigInt(suggestedFees.gasPrice, bumpByPercentage(currentFees.gasPrice, bumpPercentage, 1)),
+	}
+	if currentFees.isDynamic() {
+		cancelFees.gasTipCap = maxBigInt(suggestedFees.gasTipCap, bumpByPercentage(currentFees.gasTipCap, bumpPercentage, 1))
+		cancelFees.gasFeeCap = cancelFees.gasPrice
+	}
+
+	mTxLog := log.WithFields("owner", owner, "monitoredTx", id)
+	mTxLog.Infof("canceling with a zero value tx using nonce %v and fees %v", mTx.nonce, cancelFees.String())
+
+	from := mTx.from
+	mTx.to = &from
+	mTx.value = big.NewInt(0)
+	mTx.data = nil
+	mTx.gas = cancelTxGas
+	mTx.setFees(cancelFees)
+
+	err = c.signAndSend(ctx, &mTx, dbTx)
+	if err != nil {
+		return mTx, err
+	}
+
+	// the cancel tx is monitored like the replaced one until any of them is mined
+	mTx.status = MonitoredTxStatusCanceling
+	err = c.storage.Update(ctx, mTx, dbTx)
+	if err != nil {
+		return mTx, fmt.Errorf("failed to update monitored tx: %w", err)
+	}
+	return mTx, nil
+}
+
+// setStatusFailed sets the status of a monitored tx that is not
+// confirmed yet to failed, so the next time the owner processes its
+// pending monitored txs, the result handler is triggered for it.
+func (c *Client) setStatusFailed(ctx context.Context, owner, id string, dbTx pgx.Tx) (monitoredTx, error) {
+	mTx, unlock, err := c.getLocked(ctx, owner, id, dbTx)
+	if err != nil {
+		return mTx, err
+	}
+	defer unlock()
+
+	if mTx.status == MonitoredTxStatusConfirmed || mTx.status == MonitoredTxStatusDone ||
+		mTx.status == MonitoredTxStatusCanceled {
+		return mTx, ErrInvalidStatus
+	}
+
+	log.WithFields("owner", owner, "monitoredTx", id).Infof("status changed from %v to %v", mTx.status, MonitoredTxStatusFailed)
+	mTx.status = MonitoredTxStatusFailed
+
+	err = c.storage.Update(ctx, mTx, dbTx)
+	if err != nil {
+		return mTx, fmt.Errorf("failed to update monitored tx: %w", err)
+	}
+	return mTx, nil
+}
+
+// getLocked returns the monitored tx once the changes to the monitored txs of
+// its sender are locked, along with the function that unlocks them. The admin
+// changes wait for the monitoring of the txs of the sender in progress, so they
+// are not overwritten by it.
+func (c *Client) getLocked(ctx context.Context, owner, id string, dbTx pgx.Tx) (monitoredTx, func(), error) {
+	mTx, err := c.storage.Get(ctx, owner, id, dbTx)
+	if err != nil {
+		return mTx, nil, err
+	}
+	unlock := c.lockSender(mTx.from)
+	// the sender of a monitored tx never changes, but the rest of it may have
+	// changed while waiting for the lock
+	mTx, err = c.storage.Get(ctx, owner, id, dbTx)
+	if err != nil {
+		unlock()
+		return mTx, nil, err
+	}
+	return mTx, unlock, nil
+}
+
+// isCancel checks if the monitored tx was replaced by a cancel tx
+func (mTx monitoredTx) isCancel() bool {
+	return mTx.to != nil && *mTx.to == mTx.from && len(mTx.data) == 0 &&
+		mTx.value != nil && mTx.value.Sign() == 0 && mTx.gas == cancelTxGas
+}
+
+// minedCancelTx checks if the tx mined for a canceled monitored tx is the
+// cancel tx rather than one of the txs it replaced
+func (c *Client) minedCancelTx(ctx context.Context, mTx monitoredTx, receipt *types.Receipt) (bool, error) {
+	tx, _, err := c.etherman.GetTx(ctx, receipt.TxHash)
+	if err != nil {
+		return false, err
+	}
+	return tx.To() != nil && *tx.To() == mTx.from && len(tx.Data()) == 0, nil
+}
+
+func containsStatus(statuses []MonitoredTxStatus, status MonitoredTxStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// signAndSend signs the tx built from the monitored tx, adds it to
+// the history and sends it to the network, then the monitored tx
+// is stored with the sent status.
+func (c *Client) signAndSend(ctx context.Context, mTx *monitoredTx, dbTx pgx.Tx) error {
+	signedTx, err := c.etherman.SignTx(ctx, mTx.from, mTx.Tx())
+	if err != nil {
+		return fmt.Errorf("failed to sign tx: %w", err)
+	}
+
+	err = mTx.AddHistory(signedTx)
+	if err != nil && !errors.Is(err, ErrAlreadyExists) {
+		return fmt.Errorf("failed to add signed tx to the history: %w", err)
+	}
+
+	err = c.etherman.SendTx(ctx, signedTx)
+	if err != nil {
+		return fmt.Errorf("failed to send tx %v to network: %w", signedTx.Hash().String(), err)
+	}
+	metrics.AccountTxSent(mTx.from.String())
+
+	mTx.status = MonitoredTxStatusSent
+	err = c.storage.Update(ctx, *mTx, dbTx)
+	if err != nil {
+		return fmt.Errorf("failed to update monitored tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package ethtxmanager
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSentMonitoredTxForTests() monitoredTx {
+	to := common.HexToAddress("0x2")
+	return monitoredTx{
+		owner: "owner", id: "id",
+		from: common.HexToAddress("0x1"), to: &to,
+		nonce: 7, value: big.NewInt(0), data: []byte{1, 2, 3},
+		gas: 100000, gasPrice: big.NewInt(100),
+		status:  MonitoredTxStatusSent,
+		history: map[common.Hash]bool{common.HexToHash("0x3"): true},
+	}
+}
+
+func TestCancelTx(t *testing.T) {
+	ctx := context.Background()
+	etherman := newEthermanMock(t)
+	storage := newStorageMock(t)
+	c := New(Config{}, etherman, storage, nil)
+
+	mTx := newSentMonitoredTxForTests()
+	storage.On("Get", ctx, mTx.owner, mTx.id, nil).Return(mTx, nil).Twice()
+	etherman.On("SuggestedGasPrice", ctx).Return(big.NewInt(50), nil).Once()
+	etherman.
+		On("SignTx", ctx, mTx.from, mock.IsType(&types.Transaction{})).
+		Return(func(ctx context.Context, sender common.Address, tx *types.Transaction) *types.Transaction { return tx }, nil).
+		Once()
+	etherman.On("SendTx", ctx, mock.IsType(&types.Transaction{})).Return(nil).Once()
+	storage.On("Update", ctx, mock.IsType(monitoredTx{}), nil).Return(nil).Twice()
+
+	canceled, err := c.cancelTx(ctx, mTx.owner, mTx.id, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, MonitoredTxStatusCanceling, canceled.status)
+	assert.True(t, canceled.isCancel())
+	assert.Equal(t, mTx.nonce, canceled.nonce)
+	assert.Equal(t, mTx.from, *canceled.to)
+	assert.Equal(t, big.NewInt(0), canceled.value)
+	assert.Nil(t, canceled.data)
+	assert.Equal(t, uint64(cancelTxGas), canceled.gas)
+	assert.Equal(t, big.NewInt(110), canceled.gasPrice)
+	assert.Len(t, canceled.history, 2)
+}
+
+func TestCancelTxInvalidStatus(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorageMock(t)
+	c := New(Config{}, newEthermanMock(t), storage, nil)
+
+	mTx := newSentMonitoredTxForTests()
+	mTx.status = MonitoredTxStatusConfirmed
+	storage.On("Get", ctx, mTx.owner, mTx.id, nil).Return(mTx, nil).Twice()
+
+	_, err := c.cancelTx(ctx, mTx.owner, mTx.id, nil)
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
+
+func TestForceResendInvalidFees(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorageMock(t)
+	c := New(Config{}, newEthermanMock(t), storage, nil)
+
+	mTx := newSentMonitoredTxForTests()
+	storage.On("Get", ctx, mTx.owner, mTx.id, nil).Return(mTx, nil).Times(4)
+
+	_, err := c.forceResend(ctx, mTx.owner, mTx.id, fees{gasPrice: big.NewInt(90)}, nil)
+	assert.ErrorIs(t, err, ErrInvalidFees)
+
+	dynamicFees := fees{gasTipCap: big.NewInt(10), gasFeeCap: big.NewInt(200), gasPrice: big.NewInt(200)}
+	_, err = c.forceResend(ctx, mTx.owner, mTx.id, dynamicFees, nil)
+	assert.ErrorIs(t, err, ErrInvalidFees)
+}
+
+func TestSetStatusFailed(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorageMock(t)
+	c := New(Config{}, newEthermanMock(t), storage, nil)
+
+	mTx := newSentMonitoredTxForTests()
+	storage.On("Get", ctx, mTx.owner, mTx.id, nil).Return(mTx, nil).Twice()
+	storage.
+		On("Update", ctx, mock.MatchedBy(func(m monitoredTx) bool { return m.status == MonitoredTxStatusFailed }), nil).
+		Return(nil).
+		Once()
+
+	failed, err := c.setStatusFailed(ctx, mTx.owner, mTx.id, nil)
+	require.NoError(t, err)
+	assert.Equal(t, MonitoredTxStatusFailed, failed.status)
+}
+
+func TestMonitorCanceledTx(t *testing.T) {
+	ctx := context.Background()
+	mTx := newSentMonitoredTxForTests()
+	mTx.status = MonitoredTxStatusCanceling
+	mTx.to = &mTx.from
+	mTx.data = nil
+	mTx.gas = cancelTxGas
+
+	testCases := []struct {
+		name     string
+		minedTx  *types.Transaction
+		expected MonitoredTxStatus
+	}{
+		{
+			name:     "cancel tx mined",
+			minedTx:  mTx.Tx(),
+			expected: MonitoredTxStatusCanceled,
+		},
+		{
+			name:     "replaced tx mined",
+			minedTx:  newSentMonitoredTxForTests().Tx(),
+			expected: MonitoredTxStatusConfirmed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			etherman := newEthermanMock(t)
+			storage := newStorageMock(t)
+			st := newStateMock(t)
+			c := New(Config{}, etherman, storage, st)
+
+			receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tc.minedTx.Hash(), BlockNumber: big.NewInt(10)}
+			storage.On("Get", ctx, mTx.owner, mTx.id, nil).Return(mTx, nil).Once()
+			etherman.On("CheckTxWasMined", ctx, common.HexToHash("0x3")).Return(true, receipt, nil).Once()
+			st.On("GetLastBlock", ctx, nil).Return(&state.Block{BlockNumber: 10}, nil).Once()
+			etherman.On("GetTx", ctx, tc.minedTx.Hash()).Return(tc.minedTx, false, nil).Once()
+			storage.
+				On("Update", ctx, mock.MatchedBy(func(m monitoredTx) bool { return m.status == tc.expected }), nil).
+				Return(nil).
+				Once()
+
+			c.monitorTx(ctx, mTx)
+		})
+	}
+}
+
+func TestMonitorTxChangedByAdmin(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorageMock(t)
+	c := New(Config{}, newEthermanMock(t), storage, nil)
+
+	// the monitored tx was set as failed after the monitoring cycle loaded it
+	mTx := newSentMonitoredTxForTests()
+	failed := mTx
+	failed.status = MonitoredTxStatusFailed
+	storage.On("Get", ctx, mTx.owner, mTx.id, nil).Return(failed, nil).Once()
+
+	c.monitorTx(ctx, mTx)
+}
+
+func TestAdminHTTPHandler(t *testing.T) {
+	storage := newStorageMock(t)
+	s := NewAdminServer(AdminServerConfig{}, New(Config{}, newEthermanMock(t), storage, nil))
+	handler := s.httpHandler()
+
+	mTx := newSentMonitoredTxForTests()
+	owner := mTx.owner
+	storage.
+		On("GetByStatus", mock.Anything, &owner, []MonitoredTxStatus{MonitoredTxStatusSent}, nil).
+		Return([]monitoredTx{mTx}, nil).
+		Once()
+	storage.
+		On("Get", mock.Anything, "owner", "unknown", nil).
+		Return(monitoredTx{}, ErrNotFound).
+		Once()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/monitoredtxs?owner=owner&status=sent", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.Contains(rec.Body.String(), `"nonce":"7"`), rec.Body.String())
+	assert.True(t, strings.Contains(rec.Body.String(), common.HexToHash("0x3").String()), rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/monitoredtxs/owner/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/monitoredtxs/owner/id/cancel", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/monitoredtxs/owner/id/resend", strings.NewReader(`{"gasPrice":"abc"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,389 @@
+package ethtxmanager
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/encoding"
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager/pb"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	adminHTTPPath        = "/monitoredtxs"
+	adminHTTPReadTimeout = 10 * time.Second
+	adminHTTPMaxBodySize = 1024 * 1024
+)
+
+// AdminServer provides the gRPC and HTTP admin APIs used by the
+// operators to inspect and manage the monitored txs.
+type AdminServer struct {
+	cfg AdminServerConfig
+
+	srv     *grpc.Server
+	httpSrv *http.Server
+	pb.UnimplementedEthTxManagerAdminServiceServer
+	client *Client
+}
+
+// NewAdminServer is the admin server constructor.
+func NewAdminServer(cfg AdminServerConfig, client *Client) *AdminServer {
+	return &AdminServer{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+// Start sets up the server to process requests.
+func (s *AdminServer) Start() {
+	if s.cfg.HTTPPort != 0 {
+		go s.startHTTP()
+	}
+
+	address := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	s.srv = grpc.NewServer()
+	pb.RegisterEthTxManagerAdminServiceServer(s.srv, s)
+
+	log.Infof("eth tx manager admin server listening in %q", address)
+	if err := s.srv.Serve(lis); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
+
+// Stop stops the server.
+func (s *AdminServer) Stop() {
+	s.srv.Stop()
+	if s.httpSrv != nil {
+		if err := s.httpSrv.Close(); err != nil {
+			log.Errorf("failed to stop eth tx manager admin http server: %v", err)
+		}
+	}
+}
+
+// Implementation of pb.EthTxManagerAdminServiceServer interface methods.
+
+// ListMonitoredTxs returns the monitored txs matching the provided owner and
+// statuses, if the owner or the statuses are empty, they are not filtered.
+func (s *AdminServer) ListMonitoredTxs(ctx context.Context, in *pb.ListMonitoredTxsRequest) (*pb.ListMonitoredTxsResponse, error) {
+	var owner *string
+	if in.Owner != "" {
+		owner = &in.Owner
+	}
+	statuses := make([]MonitoredTxStatus, 0, len(in.Statuses))
+	for _, s := range in.Statuses {
+		statuses = append(statuses, MonitoredTxStatus(s))
+	}
+
+	mTxs, err := s.client.storage.GetByStatus(ctx, owner, statuses, nil)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	res := &pb.ListMonitoredTxsResponse{MonitoredTxs: make([]*pb.MonitoredTx, 0, len(mTxs))}
+	for _, mTx := range mTxs {
+		res.MonitoredTxs = append(res.MonitoredTxs, toPBMonitoredTx(mTx, nil))
+	}
+	return res, nil
+}
+
+// GetMonitoredTx returns a monitored tx with the receipts of all the txs in its history.
+func (s *AdminServer) GetMonitoredTx(ctx context.Context, in *pb.MonitoredTxRequest) (*pb.MonitoredTx, error) {
+	mTx, err := s.client.storage.Get(ctx, in.Owner, in.Id, nil)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return s.toPBMonitoredTxWithResults(ctx, mTx)
+}
+
+// ResendMonitoredTx sends again a monitored tx that was not mined yet using the provided fees.
+func (s *AdminServer) ResendMonitoredTx(ctx context.Context, in *pb.ResendMonitoredTxRequest) (*pb.MonitoredTx, error) {
+	newFees := fees{}
+	var err error
+	if in.GasFeeCap != "" || in.GasTipCap != "" {
+		if newFees.gasTipCap, err = parseBigInt("gas_tip_cap", in.GasTipCap); err != nil {
+			return nil, err
+		}
+		if newFees.gasFeeCap, err = parseBigInt("gas_fee_cap", in.GasFeeCap); err != nil {
+			return nil, err
+		}
+		newFees.gasPrice = newFees.gasFeeCap
+	} else if newFees.gasPrice, err = parseBigInt("gas_price", in.GasPrice); err != nil {
+		return nil, err
+	}
+
+	mTx, err := s.client.forceResend(ctx, in.Owner, in.Id, newFees, nil)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toPBMonitoredTx(mTx, nil), nil
+}
+
+// CancelMonitoredTx replaces a monitored tx that was not mined yet with a zero value tx using the same nonce.
+func (s *AdminServer) CancelMonitoredTx(ctx context.Context, in *pb.MonitoredTxRequest) (*pb.MonitoredTx, error) {
+	mTx, err := s.client.cancelTx(ctx, in.Owner, in.Id, nil)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toPBMonitoredTx(mTx, nil), nil
+}
+
+// FailMonitoredTx marks a monitored tx as failed, so the result handlers of its owner are triggered.
+func (s *AdminServer) FailMonitoredTx(ctx context.Context, in *pb.MonitoredTxRequest) (*pb.MonitoredTx, error) {
+	mTx, err := s.client.setStatusFailed(ctx, in.Owner, in.Id, nil)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toPBMonitoredTx(mTx, nil), nil
+}
+
+func (s *AdminServer) toPBMonitoredTxWithResults(ctx context.Context, mTx monitoredTx) (*pb.MonitoredTx, error) {
+	result, err := s.client.buildResult(ctx, mTx)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toPBMonitoredTx(mTx, result.Txs), nil
+}
+
+// startHTTP exposes the admin API over HTTP with JSON bodies:
+//
+//	GET  /monitoredtxs?owner={owner}&status={status}
+//	GET  /monitoredtxs/{owner}/{id}
+//	POST /monitoredtxs/{owner}/{id}/resend
+//	POST /monitoredtxs/{owner}/{id}/cancel
+//	POST /monitoredtxs/{owner}/{id}/fail
+func (s *AdminServer) startHTTP() {
+	address := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.HTTPPort)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("failed to create tcp listener for eth tx manager admin http server: %v", err)
+		return
+	}
+
+	s.httpSrv = &http.Server{
+		Handler:     s.httpHandler(),
+		ReadTimeout: adminHTTPReadTimeout,
+	}
+	log.Infof("eth tx manager admin http server listening in %q", address)
+	if err := s.httpSrv.Serve(lis); err != nil {
+		if err == http.ErrServerClosed {
+			log.Warnf("eth tx manager admin http server stopped")
+			return
+		}
+		log.Errorf("closed http connection for eth tx manager admin server: %v", err)
+	}
+}
+
+func (s *AdminServer) httpHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(adminHTTPPath, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeHTTPError(w, status.Error(codes.Unimplemented, "method not allowed"))
+			return
+		}
+		query := req.URL.Query()
+		res, err := s.ListMonitoredTxs(req.Context(), &pb.ListMonitoredTxsRequest{
+			Owner:    query.Get("owner"),
+			Statuses: query["status"],
+		})
+		writeHTTPResponse(w, res, err)
+	})
+	mux.HandleFunc(adminHTTPPath+"/", func(w http.ResponseWriter, req *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(req.URL.Path, adminHTTPPath+"/"), "/")
+		const (
+			monitoredTxPathParts = 2
+			operationPathParts   = 3
+		)
+		if len(parts) < monitoredTxPathParts || len(parts) > operationPathParts {
+			writeHTTPError(w, status.Error(codes.NotFound, "path not found"))
+			return
+		}
+		in := &pb.MonitoredTxRequest{Owner: parts[0], Id: parts[1]}
+
+		if len(parts) == monitoredTxPathParts {
+			if req.Method != http.MethodGet {
+				writeHTTPError(w, status.Error(codes.Unimplemented, "method not allowed"))
+				return
+			}
+			res, err := s.GetMonitoredTx(req.Context(), in)
+			writeHTTPResponse(w, res, err)
+			return
+		}
+
+		if req.Method != http.MethodPost {
+			writeHTTPError(w, status.Error(codes.Unimplemented, "method not allowed"))
+			return
+		}
+		var res *pb.MonitoredTx
+		var err error
+		switch parts[2] {
+		case "resend":
+			resendReq := &pb.ResendMonitoredTxRequest{}
+			body, err := io.ReadAll(io.LimitReader(req.Body, adminHTTPMaxBodySize))
+			if err != nil {
+				writeHTTPError(w, status.Error(codes.InvalidArgument, err.Error()))
+				return
+			}
+			if err := protojson.Unmarshal(body, resendReq); err != nil {
+				writeHTTPError(w, status.Error(codes.InvalidArgument, err.Error()))
+				return
+			}
+			resendReq.Owner, resendReq.Id = in.Owner, in.Id
+			res, err = s.ResendMonitoredTx(req.Context(), resendReq)
+			writeHTTPResponse(w, res, err)
+			return
+		case "cancel":
+			res, err = s.CancelMonitoredTx(req.Context(), in)
+		case "fail":
+			res, err = s.FailMonitoredTx(req.Context(), in)
+		default:
+			err = status.Error(codes.NotFound, "path not found")
+		}
+		writeHTTPResponse(w, res, err)
+	})
+	return mux
+}
+
+func writeHTTPResponse(w http.ResponseWriter, res proto.Message, err error) {
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	body, err := protojson.Marshal(res)
+	if err != nil {
+		writeHTTPError(w, status.Error(codes.Internal, err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(body); err != nil {
+		log.Errorf("failed to write eth tx manager admin http response: %v", err)
+	}
+}
+
+func writeHTTPError(w http.ResponseWriter, err error) {
+	httpStatus := http.StatusInternalServerError
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.NotFound:
+		httpStatus = http.StatusNotFound
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		httpStatus = http.StatusBadRequest
+	case codes.Unimplemented:
+		httpStatus = http.StatusMethodNotAllowed
+	}
+	http.Error(w, st.Message(), httpStatus)
+}
+
+// toStatusError converts the errors returned by the eth tx manager into gRPC status errors
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrInvalidStatus):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrInvalidFees):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func parseBigInt(field, value string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(value, encoding.Base10)
+	if !ok || v.Sign() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %v: %q", field, value)
+	}
+	return v, nil
+}
+
+// toPBMonitoredTx converts a monitored tx into its protobuf representation,
+// the receipts are added to the history when the results are provided
+func toPBMonitoredTx(mTx monitoredTx, results map[common.Hash]TxResult) *pb.MonitoredTx {
+	res := &pb.MonitoredTx{
+		Owner:     mTx.owner,
+		Id:        mTx.id,
+		From:      mTx.from.String(),
+		Nonce:     mTx.nonce,
+		Gas:       mTx.gas,
+		Status:    mTx.status.String(),
+		CreatedAt: uint64(mTx.createdAt.Unix()),
+		UpdatedAt: uint64(mTx.updatedAt.Unix()),
+	}
+	if mTx.to != nil {
+		res.To = mTx.to.String()
+	}
+	if mTx.value != nil {
+		res.Value = mTx.value.String()
+	}
+	if mTx.data != nil {
+		res.Data = "0x" + hex.EncodeToString(mTx.data)
+	}
+	if mTx.gasPrice != nil {
+		res.GasPrice = mTx.gasPrice.String()
+	}
+	if mTx.gasTipCap != nil {
+		res.GasTipCap = mTx.gasTipCap.String()
+	}
+	if mTx.gasFeeCap != nil {
+		res.GasFeeCap = mTx.gasFeeCap.String()
+	}
+	if mTx.blockNumber != nil {
+		res.BlockNumber = mTx.blockNumber.Uint64()
+	}
+
+	history := mTx.historyHashSlice()
+	sort.Slice(history, func(i, j int) bool { return history[i].String() < history[j].String() })
+	for _, txHash := range history {
+		historyTx := &pb.HistoryTx{Hash: txHash.String()}
+		if result, found := results[txHash]; found {
+			historyTx.RevertMessage = result.RevertMessage
+			if result.Receipt != nil {
+				historyTx.Receipt = &pb.Receipt{
+					Status:    result.Receipt.Status,
+					BlockHash: result.Receipt.BlockHash.String(),
+					GasUsed:   result.Receipt.GasUsed,
+				}
+				if result.Receipt.BlockNumber != nil {
+					historyTx.Receipt.BlockNumber = result.Receipt.BlockNumber.Uint64()
+				}
+				if result.Receipt.EffectiveGasPrice != nil {
+					historyTx.Receipt.EffectiveGasPrice = result.Receipt.EffectiveGasPrice.String()
+				}
+			}
+		}
+		res.History = append(res.History, historyTx)
+	}
+	return res
+}
+
+// NewAdminClient creates a grpc client to communicate with the eth tx manager admin server
+func NewAdminClient(ctx context.Context, serverAddress string) (pb.EthTxManagerAdminServiceClient, *grpc.ClientConn, error) {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	conn, err := grpc.DialContext(ctx, serverAddress, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to eth tx manager admin server: %w", err)
+	}
+	return pb.NewEthTxManagerAdminServiceClient(conn), conn, nil
+}
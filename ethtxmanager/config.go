@@ -1,6 +1,13 @@
 package ethtxmanager
 
-import "github.com/0xPolygonHermez/zkevm-node/config/types"
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/encoding"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 // Config is configuration for ethereum transaction manager
 type Config struct {
@@ -15,4 +22,117 @@ type Config struct {
 
 	// How often to poll for transaction receipts when monitoring.
 	PollingInterval types.Duration `mapstructure:"PollingInterval"`
+
+	// FeeStrategy defines how the fees of the L1 txs are computed and
+	// how they are escalated while the txs are not mined
+	FeeStrategy FeeStrategyConfig `mapstructure:"FeeStrategy"`
+
+	// Admin is the configuration of the admin server used by the operators
+	// to inspect and manage the monitored txs
+	Admin AdminServerConfig `mapstructure:"Admin"`
+
+	// SenderAccounts defines the pools of accounts the owners use to send their L1 txs.
+	// The monitored txs of an owner with a pool are assigned to the least loaded account
+	// of the pool instead of the sender provided by the owner, so a stuck tx only blocks
+	// the txs of its own account. The private keys of the accounts must be in PrivateKeys
+	SenderAccounts []OwnerSenderAccounts `mapstructure:"SenderAccounts"`
+
+	// Balance defines how the balances of the sender accounts are checked and rebalanced
+	Balance BalanceConfig `mapstructure:"Balance"`
+}
+
+// OwnerSenderAccounts defines the pool of sender accounts of an owner
+type OwnerSenderAccounts struct {
+	// Owner is the owner of the monitored txs, ex: aggregator
+	Owner string `mapstructure:"Owner"`
+
+	// Addresses are the accounts used to send the txs of the owner
+	Addresses []common.Address `mapstructure:"Addresses"`
+}
+
+// BalanceConfig is the configuration of the balance checks of the sender accounts
+type BalanceConfig struct {
+	// CheckInterval is the frequency the balances of the sender accounts are checked, 0 disables the checks
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
+
+	// MinBalance is the balance in wei below which a warning is logged and
+	// funds are moved to the account from the richest account of its pool
+	MinBalance Wei `mapstructure:"MinBalance"`
+
+	// TargetBalance is the balance in wei a low balance account is refilled to,
+	// the funding account must keep at least this balance after the transfer.
+	// 0 disables the rebalance and only the warnings are logged
+	TargetBalance Wei `mapstructure:"TargetBalance"`
+}
+
+// Wei is a wrapper type that parses an amount in wei to big int
+type Wei struct {
+	*big.Int
+}
+
+// UnmarshalText unmarshal an amount in wei from a string to big int
+func (w *Wei) UnmarshalText(data []byte) error {
+	amount, ok := new(big.Int).SetString(string(data), encoding.Base10)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal string to wei")
+	}
+	w.Int = amount
+	return nil
+}
+
+// AdminServerConfig represents the configuration of the admin server
+type AdminServerConfig struct {
+	// Enabled defines if the admin server is started together with the eth tx manager
+	Enabled bool `mapstructure:"Enabled"`
+	// Host is the address the admin server binds to
+	Host string `mapstructure:"Host"`
+	// Port is the port of the gRPC admin API
+	Port int `mapstructure:"Port"`
+	// HTTPPort is the port of the HTTP admin API, 0 disables it
+	HTTPPort int `mapstructure:"HTTPPort"`
+}
+
+// FeeStrategyConfig is the configuration of the fees used to build the L1 txs
+type FeeStrategyConfig struct {
+	// TxType defines the type of the txs sent to L1, "legacy" or "dynamic" (EIP-1559).
+	// If not set, legacy txs are used
+	TxType TxType `mapstructure:"TxType"`
+
+	// PriorityFeeStrategy defines how the priority fee (tip) of dynamic fee txs is computed:
+	//   - "suggested": the priority fee suggested by the L1 node
+	//   - "fixed": the value defined in FixedPriorityFee
+	//   - "max": the max value between the suggested and the fixed ones
+	PriorityFeeStrategy PriorityFeeStrategy `mapstructure:"PriorityFeeStrategy"`
+
+	// FixedPriorityFee is the priority fee in wei used by the "fixed" and "max" strategies
+	FixedPriorityFee uint64 `mapstructure:"FixedPriorityFee"`
+
+	// BaseFeeMultiplier is applied to the latest L1 base fee to compute the max fee
+	// per gas of dynamic fee txs, so they remain valid if the base fee increases
+	BaseFeeMultiplier uint64 `mapstructure:"BaseFeeMultiplier"`
+
+	// BumpPercentage is the percentage the fees are increased every time a tx
+	// is resent, it must be at least 10 to be accepted as a replacement by L1 nodes
+	BumpPercentage uint64 `mapstructure:"BumpPercentage"`
+
+	// EscalationInterval is the time a tx can stay not mined before its fees are bumped,
+	// the bump is applied once per elapsed interval since the monitored tx was created
+	EscalationInterval types.Duration `mapstructure:"EscalationInterval"`
+
+	// DefaultMaxFeePerGas is the max gas price or max fee per gas in wei that can be
+	// paid by the owners without a specific cap, 0 means there is no cap
+	DefaultMaxFeePerGas uint64 `mapstructure:"DefaultMaxFeePerGas"`
+
+	// MaxFeePerGasByOwner defines the max gas price or max fee per gas in wei that
+	// can be paid by the txs of a specific owner, ex: aggregator
+	MaxFeePerGasByOwner []OwnerFeeCap `mapstructure:"MaxFeePerGasByOwner"`
+}
+
+// OwnerFeeCap defines the max fee per gas that can be paid by the txs of an owner
+type OwnerFeeCap struct {
+	// Owner is the owner of the monitored txs, ex: aggregator
+	Owner string `mapstructure:"Owner"`
+
+	// MaxFeePerGas is the max gas price or max fee per gas in wei, 0 means there is no cap
+	MaxFeePerGas uint64 `mapstructure:"MaxFeePerGas"`
 }
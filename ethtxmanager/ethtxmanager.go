@@ -9,8 +9,10 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum"
@@ -33,6 +35,12 @@ var (
 	// ErrExecutionReverted returned when trying to get the revert message
 	// but the call fails without revealing the revert reason
 	ErrExecutionReverted = errors.New("execution reverted")
+
+	// ErrInvalidStatus when the operation is not allowed for the current status of the monitored tx
+	ErrInvalidStatus = errors.New("operation not allowed for the current monitored tx status")
+
+	// ErrInvalidFees when the provided fees are not valid to replace the current tx
+	ErrInvalidFees = errors.New("fees must match the tx type and be higher than the current ones")
 )
 
 // Client for eth tx manager
@@ -44,26 +52,48 @@ type Client struct {
 	etherman ethermanInterface
 	storage  storageInterface
 	state    stateInterface
+
+	// nonceMutex serializes the assignment of senders and nonces to the new monitored txs
+	nonceMutex       sync.Mutex
+	lastBalanceCheck time.Time
+
+	// senderMutexes serialize the changes to the monitored txs of each sender
+	// made by the monitoring cycle and by the admin API
+	senderMutexes      map[common.Address]*sync.Mutex
+	senderMutexesMutex sync.Mutex
 }
 
 // New creates new eth tx manager
 func New(cfg Config, ethMan ethermanInterface, storage storageInterface, state stateInterface) *Client {
 	c := &Client{
-		cfg:      cfg,
-		etherman: ethMan,
-		storage:  storage,
-		state:    state,
+		cfg:           cfg,
+		etherman:      ethMan,
+		storage:       storage,
+		state:         state,
+		senderMutexes: make(map[common.Address]*sync.Mutex),
 	}
 
 	return c
 }
 
-// Add a transaction to be sent and monitored
+// Add a transaction to be sent and monitored.
+//
+// If the owner has a pool of sender accounts, the tx is assigned to the least
+// loaded account of the pool and the provided sender is ignored
 func (c *Client) Add(ctx context.Context, owner, id string, from common.Address, to *common.Address, value *big.Int, data []byte, dbTx pgx.Tx) error {
+	c.nonceMutex.Lock()
+	defer c.nonceMutex.Unlock()
+
+	pending, err := c.pendingBySender(ctx, dbTx)
+	if err != nil {
+		log.Errorf(err.Error())
+		return err
+	}
+	// get sender
+	from = c.selectSender(owner, from, pending)
 	// get next nonce
-	nonce, err := c.etherman.CurrentNonce(ctx, from)
+	nonce, err := c.nextNonce(ctx, from, pending[from])
 	if err != nil {
-		err := fmt.Errorf("failed to get current nonce: %w", err)
 		log.Errorf(err.Error())
 		return err
 	}
@@ -74,10 +104,9 @@ func (c *Client) Add(ctx context.Context, owner, id string, from common.Address,
 		log.Errorf(err.Error())
 		return err
 	}
-	// get gas price
-	gasPrice, err := c.etherman.SuggestedGasPrice(ctx)
+	// get fees
+	fees, err := c.suggestFees(ctx, owner, c.cfg.FeeStrategy.TxType)
 	if err != nil {
-		err := fmt.Errorf("failed to get suggested gas price: %w", err)
 		log.Errorf(err.Error())
 		return err
 	}
@@ -85,10 +114,10 @@ func (c *Client) Add(ctx context.Context, owner, id string, from common.Address,
 	// create monitored tx
 	mTx := monitoredTx{
 		owner: owner, id: id, from: from, to: to,
-		nonce: nonce, value: value, data: data,
-		gas: gas, gasPrice: gasPrice,
+		nonce: nonce, value: value, data: data, gas: gas,
 		status: MonitoredTxStatusCreated,
 	}
+	mTx.setFees(fees)
 
 	// add to storage
 	err = c.storage.Add(ctx, mTx, dbTx)
@@ -190,6 +219,7 @@ func (c *Client) buildResult(ctx context.Context, mTx monitoredTx) (MonitoredTxR
 func (c *Client) Start() {
 	// infinite loop to manage txs as they arrive
 	c.ctx, c.cancel = context.WithCancel(context.Background())
+	metrics.Register()
 
 	for {
 		select {
@@ -200,6 +230,10 @@ func (c *Client) Start() {
 			if err != nil {
 				c.logErrorAndWait("failed to monitor txs: %v", err)
 			}
+			if c.cfg.Balance.CheckInterval.Duration > 0 && time.Since(c.lastBalanceCheck) >= c.cfg.Balance.CheckInterval.Duration {
+				c.checkBalances(c.ctx)
+				c.lastBalanceCheck = time.Now()
+			}
 		}
 	}
 }
@@ -234,227 +268,298 @@ func (c *Client) Reorg(ctx context.Context, fromBlockNumber uint64, dbTx pgx.Tx)
 	return nil
 }
 
+// monitoredStatuses are the statuses of the monitored txs processed by the
+// monitoring cycle
+var monitoredStatuses = []MonitoredTxStatus{
+	MonitoredTxStatusCreated,
+	MonitoredTxStatusSent,
+	MonitoredTxStatusReorged,
+	MonitoredTxStatusCanceling,
+}
+
+// lockSender locks the changes to the monitored txs of the sender, it returns
+// the function that unlocks them
+func (c *Client) lockSender(from common.Address) func() {
+	c.senderMutexesMutex.Lock()
+	mutex, found := c.senderMutexes[from]
+	if !found {
+		mutex = &sync.Mutex{}
+		c.senderMutexes[from] = mutex
+	}
+	c.senderMutexesMutex.Unlock()
+
+	mutex.Lock()
+	return mutex.Unlock
+}
+
 // monitorTxs process all pending monitored tx
 func (c *Client) monitorTxs(ctx context.Context) error {
-	statusesFilter := []MonitoredTxStatus{MonitoredTxStatusCreated, MonitoredTxStatusSent, MonitoredTxStatusReorged}
-	mTxs, err := c.storage.GetByStatus(ctx, nil, statusesFilter, nil)
+	mTxs, err := c.storage.GetByStatus(ctx, nil, monitoredStatuses, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get created monitored txs: %v", err)
 	}
 
 	log.Infof("found %v monitored tx to process", len(mTxs))
 
-	for _, mTx := range mTxs {
-		mTx := mTx // force variable shadowing to avoid pointer conflicts
-		mTxLog := log.WithFields("monitoredTx", mTx.id)
-		mTxLog.Info("processing")
-
-		// check if any of the txs in the history was mined
-		mined := false
-		var receipt *types.Receipt
-		hasFailedReceipts := false
-		allHistoryTxMined := true
-		for txHash := range mTx.history {
-			mined, receipt, err = c.etherman.CheckTxWasMined(ctx, txHash)
-			if err != nil {
-				mTxLog.Errorf("failed to check if tx %v was mined: %v", txHash.String(), err)
-				continue
+	// monitored txs are grouped in lanes by sender account and the lanes are processed
+	// concurrently, so a tx that is not mined only delays the txs sharing its nonce sequence
+	lanes := groupBySender(mTxs)
+	c.updatePendingTxsMetrics(lanes)
+
+	var wg sync.WaitGroup
+	wg.Add(len(lanes))
+	for _, lane := range lanes {
+		go func(lane []monitoredTx) {
+			defer wg.Done()
+			for _, mTx := range lane {
+				c.monitorTx(ctx, mTx)
 			}
+		}(lane)
+	}
+	wg.Wait()
 
-			// if the tx is not mined yet, check that not all the tx were mined and go to the next
-			if !mined {
-				allHistoryTxMined = false
-				continue
-			}
+	return nil
+}
 
-			// if the tx was mined successfully we can break the loop and proceed
-			if receipt.Status == types.ReceiptStatusSuccessful {
-				break
-			}
+// monitorTx sends the monitored tx to the network if needed, waits for it to be
+// mined and updates its status accordingly to the receipt
+func (c *Client) monitorTx(ctx context.Context, mTx monitoredTx) {
+	mTxLog := log.WithFields("monitoredTx", mTx.id, "from", mTx.from.String())
+	mTxLog.Info("processing")
+
+	// the monitored tx is reloaded once its sender is locked, since it may have
+	// been changed by the admin API after the monitoring cycle loaded it
+	unlock := c.lockSender(mTx.from)
+	defer unlock()
+	mTx, err := c.storage.Get(ctx, mTx.owner, mTx.id, nil)
+	if err != nil {
+		mTxLog.Errorf("failed to reload monitored tx: %v", err)
+		return
+	}
+	if !containsStatus(monitoredStatuses, mTx.status) {
+		mTxLog.Infof("not monitored anymore, status: %v", mTx.status)
+		return
+	}
+
+	// check if any of the txs in the history was mined
+	mined := false
+	var receipt *types.Receipt
+	hasFailedReceipts := false
+	allHistoryTxMined := true
+	for txHash := range mTx.history {
+		mined, receipt, err = c.etherman.CheckTxWasMined(ctx, txHash)
+		if err != nil {
+			mTxLog.Errorf("failed to check if tx %v was mined: %v", txHash.String(), err)
+			continue
+		}
+
+		// if the tx is not mined yet, check that not all the tx were mined and go to the next
+		if !mined {
+			allHistoryTxMined = false
+			continue
+		}
 
-			// if the tx was mined but failed, we continue to consider it was not mined
-			// and store the failed receipt to be used to check if nonce needs to be reviewed
-			mined = false
-			hasFailedReceipts = true
+		// if the tx was mined successfully we can break the loop and proceed
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			break
 		}
 
-		// we need to check if we need to review the nonce carefully, to avoid sending
-		// duplicated data to the block chain.
-		//
-		// if we have failed receipts, this means at least one of the generated txs was mined
-		// so maybe the current nonce was already consumed, then we need to check if there are
-		// tx that were not mined yet, if so, we just need to wait, because maybe one of them
-		// will get mined successfully
-		//
-		// in case of all tx were mined and none of them were mined successfully, we need to
-		// review the nonce
-		if hasFailedReceipts && allHistoryTxMined {
-			mTxLog.Infof("nonce needs to be updated")
-			err := c.ReviewMonitoredTxNonce(ctx, &mTx)
+		// if the tx was mined but failed, we continue to consider it was not mined
+		// and store the failed receipt to be used to check if nonce needs to be reviewed
+		mined = false
+		hasFailedReceipts = true
+	}
+
+	// we need to check if we need to review the nonce carefully, to avoid sending
+	// duplicated data to the block chain.
+	//
+	// if we have failed receipts, this means at least one of the generated txs was mined
+	// so maybe the current nonce was already consumed, then we need to check if there are
+	// tx that were not mined yet, if so, we just need to wait, because maybe one of them
+	// will get mined successfully
+	//
+	// in case of all tx were mined and none of them were mined successfully, we need to
+	// review the nonce
+	if hasFailedReceipts && allHistoryTxMined {
+		mTxLog.Infof("nonce needs to be updated")
+		err := c.ReviewMonitoredTxNonce(ctx, &mTx)
+		if err != nil {
+			mTxLog.Errorf("failed to review monitored tx nonce: %v", err)
+			return
+		}
+		err = c.storage.Update(ctx, mTx, nil)
+		if err != nil {
+			mTxLog.Errorf("failed to update monitored tx nonce change: %v", err)
+			return
+		}
+	}
+
+	// if the history size reaches the max history size, this means something is really wrong with
+	// this Tx and we are not able to identify automatically, so we mark this as failed to let the
+	// caller know something is not right and needs to be review and to avoid to monitor this
+	// tx infinitely
+	// if len(mTx.history) == maxHistorySize {
+	// 	mTx.status = MonitoredTxStatusFailed
+	// 	mTxLog.Infof("marked as failed because reached the history size limit: %v", err)
+	// 	// update monitored tx changes into storage
+	// 	err = c.storage.Update(ctx, mTx, nil)
+	// 	if err != nil {
+	// 		mTxLog.Errorf("failed to update monitored tx when max history size limit reached: %v", err)
+	// 		return
+	// 	}
+	// }
+
+	var signedTx *types.Transaction
+	if !mined {
+		// if is a reorged, move to the next
+		if mTx.status == MonitoredTxStatusReorged {
+			return
+		}
+
+		// review tx and increase gas and gas price if needed
+		if mTx.status == MonitoredTxStatusSent || mTx.status == MonitoredTxStatusCanceling {
+			err := c.ReviewMonitoredTx(ctx, &mTx)
 			if err != nil {
-				mTxLog.Errorf("failed to review monitored tx nonce: %v", err)
-				continue
+				mTxLog.Errorf("failed to review monitored tx: %v", err)
+				return
 			}
 			err = c.storage.Update(ctx, mTx, nil)
 			if err != nil {
-				mTxLog.Errorf("failed to update monitored tx nonce change: %v", err)
-				continue
+				mTxLog.Errorf("failed to update monitored tx review change: %v", err)
+				return
 			}
 		}
 
-		// if the history size reaches the max history size, this means something is really wrong with
-		// this Tx and we are not able to identify automatically, so we mark this as failed to let the
-		// caller know something is not right and needs to be review and to avoid to monitor this
-		// tx infinitely
-		// if len(mTx.history) == maxHistorySize {
-		// 	mTx.status = MonitoredTxStatusFailed
-		// 	mTxLog.Infof("marked as failed because reached the history size limit: %v", err)
-		// 	// update monitored tx changes into storage
-		// 	err = c.storage.Update(ctx, mTx, nil)
-		// 	if err != nil {
-		// 		mTxLog.Errorf("failed to update monitored tx when max history size limit reached: %v", err)
-		// 		continue
-		// 	}
-		// }
-
-		var signedTx *types.Transaction
-		if !mined {
-			// if is a reorged, move to the next
-			if mTx.status == MonitoredTxStatusReorged {
-				continue
-			}
+		// rebuild transaction
+		tx := mTx.Tx()
+		mTxLog.Debugf("unsigned tx %v created", tx.Hash().String(), mTx.id)
 
-			// review tx and increase gas and gas price if needed
-			if mTx.status == MonitoredTxStatusSent {
-				err := c.ReviewMonitoredTx(ctx, &mTx)
-				if err != nil {
-					mTxLog.Errorf("failed to review monitored tx: %v", err)
-					continue
-				}
-				err = c.storage.Update(ctx, mTx, nil)
-				if err != nil {
-					mTxLog.Errorf("failed to update monitored tx review change: %v", err)
-					continue
-				}
+		// sign tx
+		signedTx, err = c.etherman.SignTx(ctx, mTx.from, tx)
+		if err != nil {
+			mTxLog.Errorf("failed to sign tx %v created from monitored tx %v: %v", tx.Hash().String(), mTx.id, err)
+			return
+		}
+		mTxLog.Debugf("signed tx %v created", signedTx.Hash().String())
+
+		// add tx to monitored tx history
+		err = mTx.AddHistory(signedTx)
+		if errors.Is(err, ErrAlreadyExists) {
+			mTxLog.Infof("signed tx already existed in the history")
+		} else if err != nil {
+			mTxLog.Errorf("failed to add signed tx to monitored tx %v history: %v", mTx.id, err)
+			return
+		} else {
+			// update monitored tx changes into storage
+			err = c.storage.Update(ctx, mTx, nil)
+			if err != nil {
+				mTxLog.Errorf("failed to update monitored tx: %v", err)
+				return
 			}
+			mTxLog.Debugf("signed tx added to the monitored tx history")
+		}
 
-			// rebuild transaction
-			tx := mTx.Tx()
-			mTxLog.Debugf("unsigned tx %v created", tx.Hash().String(), mTx.id)
-
-			// sign tx
-			signedTx, err = c.etherman.SignTx(ctx, mTx.from, tx)
+		// check if the tx is already in the network, if not, send it
+		_, _, err = c.etherman.GetTx(ctx, signedTx.Hash())
+		// if not found, send it tx to the network
+		if errors.Is(err, ethereum.NotFound) {
+			mTxLog.Debugf("signed tx not found in the network")
+			err := c.etherman.SendTx(ctx, signedTx)
 			if err != nil {
-				mTxLog.Errorf("failed to sign tx %v created from monitored tx %v: %v", tx.Hash().String(), mTx.id, err)
-				continue
+				mTxLog.Errorf("failed to send tx %v to network: %v", signedTx.Hash().String(), err)
+				return
 			}
-			mTxLog.Debugf("signed tx %v created", signedTx.Hash().String())
-
-			// add tx to monitored tx history
-			err = mTx.AddHistory(signedTx)
-			if errors.Is(err, ErrAlreadyExists) {
-				mTxLog.Infof("signed tx already existed in the history")
-			} else if err != nil {
-				mTxLog.Errorf("failed to add signed tx to monitored tx %v history: %v", mTx.id, err)
-				continue
-			} else {
+			mTxLog.Infof("signed tx sent to the network: %v", signedTx.Hash().String())
+			metrics.AccountTxSent(mTx.from.String())
+			if mTx.status == MonitoredTxStatusCreated {
+				// update tx status to sent
+				mTx.status = MonitoredTxStatusSent
+				mTxLog.Debugf("status changed to %v", string(mTx.status))
 				// update monitored tx changes into storage
 				err = c.storage.Update(ctx, mTx, nil)
 				if err != nil {
-					mTxLog.Errorf("failed to update monitored tx: %v", err)
-					continue
+					mTxLog.Errorf("failed to update monitored tx changes: %v", err)
+					return
 				}
-				mTxLog.Debugf("signed tx added to the monitored tx history")
-			}
-
-			// check if the tx is already in the network, if not, send it
-			_, _, err = c.etherman.GetTx(ctx, signedTx.Hash())
-			// if not found, send it tx to the network
-			if errors.Is(err, ethereum.NotFound) {
-				mTxLog.Debugf("signed tx not found in the network")
-				err := c.etherman.SendTx(ctx, signedTx)
-				if err != nil {
-					mTxLog.Errorf("failed to send tx %v to network: %v", signedTx.Hash().String(), err)
-					continue
-				}
-				mTxLog.Infof("signed tx sent to the network: %v", signedTx.Hash().String())
-				if mTx.status == MonitoredTxStatusCreated {
-					// update tx status to sent
-					mTx.status = MonitoredTxStatusSent
-					mTxLog.Debugf("status changed to %v", string(mTx.status))
-					// update monitored tx changes into storage
-					err = c.storage.Update(ctx, mTx, nil)
-					if err != nil {
-						mTxLog.Errorf("failed to update monitored tx changes: %v", err)
-						continue
-					}
-				}
-			} else {
-				mTxLog.Infof("signed tx already found in the network")
 			}
+		} else {
+			mTxLog.Infof("signed tx already found in the network")
+		}
 
-			log.Infof("waiting signedTx to be mined...")
+		log.Infof("waiting signedTx to be mined...")
 
-			// wait tx to get mined
-			mined, err = c.etherman.WaitTxToBeMined(ctx, signedTx, c.cfg.WaitTxToBeMined.Duration)
-			if err != nil {
-				mTxLog.Errorf("failed to wait tx to be mined: %v", err)
-				continue
-			}
-			if !mined {
-				log.Infof("signedTx not mined yet and timeout has been reached")
-				continue
-			}
+		// wait tx to get mined
+		mined, err = c.etherman.WaitTxToBeMined(ctx, signedTx, c.cfg.WaitTxToBeMined.Duration)
+		if err != nil {
+			mTxLog.Errorf("failed to wait tx to be mined: %v", err)
+			return
+		}
+		if !mined {
+			log.Infof("signedTx not mined yet and timeout has been reached")
+			return
+		}
 
-			// get tx receipt
-			receipt, err = c.etherman.GetTxReceipt(ctx, signedTx.Hash())
-			if err != nil {
-				mTxLog.Errorf("failed to get tx receipt for tx %v: %v", signedTx.Hash().String(), err)
-				continue
-			}
+		// get tx receipt
+		receipt, err = c.etherman.GetTxReceipt(ctx, signedTx.Hash())
+		if err != nil {
+			mTxLog.Errorf("failed to get tx receipt for tx %v: %v", signedTx.Hash().String(), err)
+			return
 		}
+	}
 
-		mTx.blockNumber = receipt.BlockNumber
+	mTx.blockNumber = receipt.BlockNumber
 
-		// if mined, check receipt and mark as Failed or Confirmed
-		if receipt.Status == types.ReceiptStatusSuccessful {
-			receiptBlockNum := receipt.BlockNumber.Uint64()
+	// if mined, check receipt and mark as Failed or Confirmed
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		receiptBlockNum := receipt.BlockNumber.Uint64()
 
-			// check block synced
-			block, err := c.state.GetLastBlock(ctx, nil)
-			if errors.Is(err, state.ErrStateNotSynchronized) {
-				mTxLog.Debugf("state not synchronized yet, waiting for L1 block %v to be synced", receiptBlockNum)
-				continue
-			} else if err != nil {
-				mTxLog.Errorf("failed to check if L1 block %v is already synced: %v", receiptBlockNum, err)
-				continue
-			} else if block.BlockNumber < receiptBlockNum {
-				mTxLog.Debugf("L1 block %v not synchronized yet, waiting for L1 block to be synced in order to confirm monitored tx", receiptBlockNum)
-				continue
+		// check block synced
+		block, err := c.state.GetLastBlock(ctx, nil)
+		if errors.Is(err, state.ErrStateNotSynchronized) {
+			mTxLog.Debugf("state not synchronized yet, waiting for L1 block %v to be synced", receiptBlockNum)
+			return
+		} else if err != nil {
+			mTxLog.Errorf("failed to check if L1 block %v is already synced: %v", receiptBlockNum, err)
+			return
+		} else if block.BlockNumber < receiptBlockNum {
+			mTxLog.Debugf("L1 block %v not synchronized yet, waiting for L1 block to be synced in order to confirm monitored tx", receiptBlockNum)
+			return
+		} else if mTx.isCancel() {
+			// the monitored tx was replaced by a cancel tx, but any of the txs
+			// sent before may have been mined instead
+			canceled, err := c.minedCancelTx(ctx, mTx, receipt)
+			if err != nil {
+				mTxLog.Errorf("failed to check if the cancel tx was mined: %v", err)
+				return
+			}
+			if canceled {
+				mTxLog.Info("canceled")
+				mTx.status = MonitoredTxStatusCanceled
 			} else {
-				mTxLog.Info("confirmed")
+				mTxLog.Info("confirmed before being canceled")
 				mTx.status = MonitoredTxStatusConfirmed
 			}
 		} else {
-			// if we should continue to monitor, we move to the next one and this will
-			// be reviewed in the next monitoring cycle
-			if c.shouldContinueToMonitorThisTx(ctx, receipt) {
-				continue
-			}
-			mTxLog.Info("failed")
-			// otherwise we understand this monitored tx has failed
-			mTx.status = MonitoredTxStatusFailed
+			mTxLog.Info("confirmed")
+			mTx.status = MonitoredTxStatusConfirmed
 		}
-
-		// update monitored tx changes into storage
-		err = c.storage.Update(ctx, mTx, nil)
-		if err != nil {
-			mTxLog.Errorf("failed to update monitored tx: %v", err)
-			continue
+	} else {
+		// if the tx has to keep being monitored, its receipt will be reviewed
+		// again in the next monitoring cycle
+		if c.shouldContinueToMonitorThisTx(ctx, receipt) {
+			return
 		}
+		mTxLog.Info("failed")
+		// otherwise we understand this monitored tx has failed
+		mTx.status = MonitoredTxStatusFailed
 	}
 
-	return nil
+	// update monitored tx changes into storage
+	err = c.storage.Update(ctx, mTx, nil)
+	if err != nil {
+		mTxLog.Errorf("failed to update monitored tx: %v", err)
+		return
+	}
 }
 
 // shouldContinueToMonitorThisTx checks the the tx receipt and decides if it should
@@ -503,18 +608,26 @@ func (c *Client) ReviewMonitoredTx(ctx context.Context, mTx *monitoredTx) error
 		mTx.gas = gas
 	}
 
-	// get gas price
-	gasPrice, err := c.etherman.SuggestedGasPrice(ctx)
+	// get fees
+	suggestedFees, err := c.suggestFees(ctx, mTx.owner, mTx.txType())
 	if err != nil {
-		err := fmt.Errorf("failed to get suggested gas price: %w", err)
 		mTxLog.Errorf(err.Error())
 		return err
 	}
 
-	// check gas price
-	if gasPrice.Cmp(mTx.gasPrice) == 1 {
-		mTxLog.Infof("monitored tx gas price updated from %v to %v", mTx.gasPrice.String(), gasPrice.String())
-		mTx.gasPrice = gasPrice
+	// check fees, when bumped the tx will be rebuilt with the new
+	// fees, signed and added to the history before being sent
+	currentFees := mTx.fees()
+	maxFee := c.cfg.FeeStrategy.maxFeePerGas(mTx.owner)
+	bumpedFees, bumped, capped := c.cfg.FeeStrategy.escalateFees(currentFees, suggestedFees, time.Since(mTx.createdAt), maxFee)
+	if bumped {
+		mTxLog.Infof("monitored tx fees bumped from %v to %v", currentFees.String(), bumpedFees.String())
+		mTx.setFees(bumpedFees)
+		if maxFee != nil && mTx.gasPrice.Cmp(maxFee) >= 0 {
+			mTxLog.Warnf("monitored tx fees reached the max fee per gas allowed for the owner %v: %v", mTx.owner, maxFee.String())
+		}
+	} else if capped {
+		mTxLog.Warnf("monitored tx fees %v not bumped, the max fee per gas allowed for the owner %v: %v prevents a replacement, waiting for the tx to be mined", currentFees.String(), mTx.owner, maxFee.String())
 	}
 	return nil
 }
@@ -554,9 +667,10 @@ func (c *Client) logErrorAndWait(msg string, err error) {
 type ResultHandler func(MonitoredTxResult, pgx.Tx)
 
 // ProcessPendingMonitoredTxs will check all monitored txs of this owner
-// and wait until all of them are either confirmed or failed before continuing
+// and wait until all of them are either confirmed, failed or canceled before
+// continuing
 //
-// for the confirmed and failed ones, the resultHandler will be triggered
+// for the confirmed, failed and canceled ones, the resultHandler will be triggered
 func (c *Client) ProcessPendingMonitoredTxs(ctx context.Context, owner string, resultHandler ResultHandler, dbTx pgx.Tx) {
 	statusesFilter := []MonitoredTxStatus{
 		MonitoredTxStatusCreated,
@@ -564,6 +678,8 @@ func (c *Client) ProcessPendingMonitoredTxs(ctx context.Context, owner string, r
 		MonitoredTxStatusFailed,
 		MonitoredTxStatusConfirmed,
 		MonitoredTxStatusReorged,
+		MonitoredTxStatusCanceling,
+		MonitoredTxStatusCanceled,
 	}
 	// keep running until there are pending monitored txs
 	for {
@@ -580,13 +696,13 @@ func (c *Client) ProcessPendingMonitoredTxs(ctx context.Context, owner string, r
 		for _, result := range results {
 			resultLog := log.WithFields("owner", owner, "id", result.ID)
 
-			// if the result is confirmed, we set it as done do stop looking into this monitored tx
-			if result.Status == MonitoredTxStatusConfirmed {
+			// if the result is confirmed or canceled, we set it as done do stop looking into this monitored tx
+			if result.Status == MonitoredTxStatusConfirmed || result.Status == MonitoredTxStatusCanceled {
 				err := c.setStatusDone(ctx, owner, result.ID, dbTx)
 				if err != nil {
 					resultLog.Errorf("failed to set monitored tx as done, err: %v", err)
 				} else {
-					resultLog.Info("monitored tx confirmed")
+					resultLog.Infof("monitored tx %v", result.Status)
 				}
 				resultHandler(result, dbTx)
 				continue
@@ -610,8 +726,9 @@ func (c *Client) ProcessPendingMonitoredTxs(ctx context.Context, owner string, r
 					continue
 				}
 
-				// if the result status is confirmed or failed, breaks the wait loop
-				if result.Status == MonitoredTxStatusConfirmed || result.Status == MonitoredTxStatusFailed {
+				// if the result status is confirmed, failed or canceled, breaks the wait loop
+				if result.Status == MonitoredTxStatusConfirmed || result.Status == MonitoredTxStatusFailed ||
+					result.Status == MonitoredTxStatusCanceled {
 					break
 				}
 
@@ -0,0 +1,207 @@
+package ethtxmanager
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	// TxTypeLegacy is used to send legacy txs with a single gas price
+	TxTypeLegacy = TxType("legacy")
+
+	// TxTypeDynamic is used to send EIP-1559 dynamic fee txs
+	TxTypeDynamic = TxType("dynamic")
+
+	// PriorityFeeStrategySuggested uses the priority fee suggested by the L1 node
+	PriorityFeeStrategySuggested = PriorityFeeStrategy("suggested")
+
+	// PriorityFeeStrategyFixed uses the configured fixed priority fee
+	PriorityFeeStrategyFixed = PriorityFeeStrategy("fixed")
+
+	// PriorityFeeStrategyMax uses the max value between the suggested
+	// and the configured fixed priority fee
+	PriorityFeeStrategyMax = PriorityFeeStrategy("max")
+
+	defaultBaseFeeMultiplier = 2
+
+	// maxEscalationSteps limits the number of bumps computed by the
+	// escalation schedule, the fee caps are expected to be reached before
+	maxEscalationSteps = 64
+
+	percentageBase = 100
+)
+
+// TxType represents the type of the txs sent to L1
+type TxType string
+
+// PriorityFeeStrategy represents how the priority fee of dynamic fee txs is computed
+type PriorityFeeStrategy string
+
+// fees represents the fee fields of a tx, gasPrice is used by
+// legacy txs and gasTipCap and gasFeeCap by dynamic fee txs
+type fees struct {
+	gasPrice  *big.Int
+	gasTipCap *big.Int
+	gasFeeCap *big.Int
+}
+
+// String returns a string representation of the fees
+func (f fees) String() string {
+	if f.gasFeeCap != nil {
+		return fmt.Sprintf("tip cap: %v, fee cap: %v", f.gasTipCap.String(), f.gasFeeCap.String())
+	}
+	return fmt.Sprintf("gas price: %v", f.gasPrice.String())
+}
+
+// isDynamic returns true if the fees belong to a dynamic fee tx
+func (f fees) isDynamic() bool {
+	return f.gasFeeCap != nil
+}
+
+// suggestFees computes the fees for a tx of the provided type accordingly to the
+// current state of the network and the configured strategy, capped by the owner max fee
+func (c *Client) suggestFees(ctx context.Context, owner string, txType TxType) (fees, error) {
+	cfg := c.cfg.FeeStrategy
+	maxFee := cfg.maxFeePerGas(owner)
+
+	if txType != TxTypeDynamic {
+		gasPrice, err := c.etherman.SuggestedGasPrice(ctx)
+		if err != nil {
+			return fees{}, fmt.Errorf("failed to get suggested gas price: %w", err)
+		}
+		return capFees(fees{gasPrice: gasPrice}, maxFee), nil
+	}
+
+	gasTipCap := new(big.Int).SetUint64(cfg.FixedPriorityFee)
+	if cfg.PriorityFeeStrategy != PriorityFeeStrategyFixed {
+		suggestedTipCap, err := c.etherman.SuggestedGasTipCap(ctx)
+		if err != nil {
+			return fees{}, fmt.Errorf("failed to get suggested gas tip cap: %w", err)
+		}
+		if cfg.PriorityFeeStrategy != PriorityFeeStrategyMax || suggestedTipCap.Cmp(gasTipCap) > 0 {
+			gasTipCap = suggestedTipCap
+		}
+	}
+
+	baseFee, err := c.etherman.GetLatestBaseFee(ctx)
+	if err != nil {
+		return fees{}, fmt.Errorf("failed to get latest base fee: %w", err)
+	}
+
+	multiplier := cfg.BaseFeeMultiplier
+	if multiplier == 0 {
+		multiplier = defaultBaseFeeMultiplier
+	}
+	gasFeeCap := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(multiplier))
+	gasFeeCap.Add(gasFeeCap, gasTipCap)
+
+	return capFees(fees{gasTipCap: gasTipCap, gasFeeCap: gasFeeCap, gasPrice: gasFeeCap}, maxFee), nil
+}
+
+// escalateFees computes the fees a tx must be resent with, given the fees
+// it was sent with, the fees currently suggested for the network and the
+// time elapsed since the monitored tx was created.
+//
+// The suggested fees are increased by BumpPercentage once per EscalationInterval
+// elapsed, if the result is higher than the current fees, the current fees are
+// increased at least by BumpPercentage so the new tx is accepted as a replacement
+// by the L1 nodes. The result never exceeds maxFee, if provided, when it
+// prevents a bump of at least minReplacementBumpPercentage the current fees are
+// kept, as the L1 nodes would reject the replacement.
+//
+// The first returned bool indicates if the fees were bumped, the second one if
+// the bump was prevented by maxFee.
+func (cfg FeeStrategyConfig) escalateFees(current, suggested fees, elapsed time.Duration, maxFee *big.Int) (fees, bool, bool) {
+	steps := uint64(0)
+	if cfg.EscalationInterval.Duration > 0 {
+		steps = uint64(elapsed / cfg.EscalationInterval.Duration)
+	}
+	if steps > maxEscalationSteps {
+		steps = maxEscalationSteps
+	}
+
+	if !current.isDynamic() {
+		target := bumpByPercentage(suggested.gasPrice, cfg.BumpPercentage, steps)
+		if target.Cmp(current.gasPrice) <= 0 {
+			return current, false, false
+		}
+		gasPrice := maxBigInt(target, bumpByPercentage(current.gasPrice, cfg.BumpPercentage, 1))
+		bumped := capFees(fees{gasPrice: gasPrice}, maxFee)
+		if bumped.gasPrice.Cmp(bumpByPercentage(current.gasPrice, minReplacementBumpPercentage, 1)) < 0 {
+			return current, false, true
+		}
+		return bumped, true, false
+	}
+
+	targetTipCap := bumpByPercentage(suggested.gasTipCap, cfg.BumpPercentage, steps)
+	targetFeeCap := bumpByPercentage(suggested.gasFeeCap, cfg.BumpPercentage, steps)
+	if targetTipCap.Cmp(current.gasTipCap) <= 0 && targetFeeCap.Cmp(current.gasFeeCap) <= 0 {
+		return current, false, false
+	}
+	gasTipCap := maxBigInt(targetTipCap, bumpByPercentage(current.gasTipCap, cfg.BumpPercentage, 1))
+	gasFeeCap := maxBigInt(targetFeeCap, bumpByPercentage(current.gasFeeCap, cfg.BumpPercentage, 1))
+	bumped := capFees(fees{gasTipCap: gasTipCap, gasFeeCap: gasFeeCap, gasPrice: gasFeeCap}, maxFee)
+	// the L1 nodes require both the tip cap and the fee cap to be bumped
+	if bumped.gasTipCap.Cmp(bumpByPercentage(current.gasTipCap, minReplacementBumpPercentage, 1)) < 0 ||
+		bumped.gasFeeCap.Cmp(bumpByPercentage(current.gasFeeCap, minReplacementBumpPercentage, 1)) < 0 {
+		return current, false, true
+	}
+	return bumped, true, false
+}
+
+// maxFeePerGas returns the max gas price or max fee per gas the provided
+// owner can pay, nil is returned if there is no cap
+func (cfg FeeStrategyConfig) maxFeePerGas(owner string) *big.Int {
+	maxFee := cfg.DefaultMaxFeePerGas
+	for _, ownerCap := range cfg.MaxFeePerGasByOwner {
+		if ownerCap.Owner == owner {
+			maxFee = ownerCap.MaxFeePerGas
+			break
+		}
+	}
+	if maxFee == 0 {
+		return nil
+	}
+	return new(big.Int).SetUint64(maxFee)
+}
+
+// capFees limits the provided fees to maxFee, the tip cap of dynamic
+// fee txs is also limited to the fee cap
+func capFees(f fees, maxFee *big.Int) fees {
+	if maxFee == nil {
+		return f
+	}
+	if !f.isDynamic() {
+		return fees{gasPrice: minBigInt(f.gasPrice, maxFee)}
+	}
+	gasFeeCap := minBigInt(f.gasFeeCap, maxFee)
+	return fees{gasTipCap: minBigInt(f.gasTipCap, gasFeeCap), gasFeeCap: gasFeeCap, gasPrice: gasFeeCap}
+}
+
+// bumpByPercentage increases the provided value by the provided percentage as many times as requested
+func bumpByPercentage(value *big.Int, percentage, times uint64) *big.Int {
+	result := new(big.Int).Set(value)
+	factor := new(big.Int).SetUint64(percentageBase + percentage)
+	base := new(big.Int).SetUint64(percentageBase)
+	for i := uint64(0); i < times; i++ {
+		result.Mul(result, factor)
+		result.Div(result, base)
+	}
+	return result
+}
+
+func maxBigInt(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+func minBigInt(a, b *big.Int) *big.Int {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
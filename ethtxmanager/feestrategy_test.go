@@ -0,0 +1,196 @@
+package ethtxmanager
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestFees(t *testing.T) {
+	ctx := context.Background()
+
+	tcs := []struct {
+		description  string
+		cfg          FeeStrategyConfig
+		owner        string
+		txType       TxType
+		suggestedTip *big.Int
+		expected     fees
+	}{
+		{
+			description: "legacy",
+			cfg:         FeeStrategyConfig{},
+			txType:      TxTypeLegacy,
+			expected:    fees{gasPrice: big.NewInt(30)},
+		},
+		{
+			description: "legacy capped by owner",
+			cfg: FeeStrategyConfig{
+				DefaultMaxFeePerGas: 100,
+				MaxFeePerGasByOwner: []OwnerFeeCap{{Owner: "aggregator", MaxFeePerGas: 20}},
+			},
+			owner:    "aggregator",
+			txType:   TxTypeLegacy,
+			expected: fees{gasPrice: big.NewInt(20)},
+		},
+		{
+			description:  "dynamic with suggested priority fee",
+			cfg:          FeeStrategyConfig{PriorityFeeStrategy: PriorityFeeStrategySuggested},
+			txType:       TxTypeDynamic,
+			suggestedTip: big.NewInt(2),
+			expected:     fees{gasTipCap: big.NewInt(2), gasFeeCap: big.NewInt(22), gasPrice: big.NewInt(22)},
+		},
+		{
+			description: "dynamic with fixed priority fee",
+			cfg:         FeeStrategyConfig{PriorityFeeStrategy: PriorityFeeStrategyFixed, FixedPriorityFee: 5, BaseFeeMultiplier: 3},
+			txType:      TxTypeDynamic,
+			expected:    fees{gasTipCap: big.NewInt(5), gasFeeCap: big.NewInt(35), gasPrice: big.NewInt(35)},
+		},
+		{
+			description:  "dynamic with max priority fee",
+			cfg:          FeeStrategyConfig{PriorityFeeStrategy: PriorityFeeStrategyMax, FixedPriorityFee: 5},
+			txType:       TxTypeDynamic,
+			suggestedTip: big.NewInt(7),
+			expected:     fees{gasTipCap: big.NewInt(7), gasFeeCap: big.NewInt(27), gasPrice: big.NewInt(27)},
+		},
+		{
+			description:  "dynamic capped by default max fee",
+			cfg:          FeeStrategyConfig{DefaultMaxFeePerGas: 15},
+			owner:        "sequencer",
+			txType:       TxTypeDynamic,
+			suggestedTip: big.NewInt(2),
+			expected:     fees{gasTipCap: big.NewInt(2), gasFeeCap: big.NewInt(15), gasPrice: big.NewInt(15)},
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			etherman := newEthermanMock(t)
+			c := New(Config{FeeStrategy: tc.cfg}, etherman, nil, nil)
+
+			if tc.txType == TxTypeLegacy {
+				etherman.On("SuggestedGasPrice", ctx).Return(big.NewInt(30), nil).Once()
+			} else {
+				if tc.suggestedTip != nil {
+					etherman.On("SuggestedGasTipCap", ctx).Return(tc.suggestedTip, nil).Once()
+				}
+				etherman.On("GetLatestBaseFee", ctx).Return(big.NewInt(10), nil).Once()
+			}
+
+			f, err := c.suggestFees(ctx, tc.owner, tc.txType)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, f)
+		})
+	}
+}
+
+func TestEscalateFees(t *testing.T) {
+	cfg := FeeStrategyConfig{
+		BumpPercentage:     10,
+		EscalationInterval: types.NewDuration(time.Minute),
+	}
+
+	tcs := []struct {
+		description    string
+		current        fees
+		suggested      fees
+		elapsed        time.Duration
+		maxFee         *big.Int
+		expected       fees
+		expectedBumped bool
+		expectedCapped bool
+	}{
+		{
+			description:    "legacy not bumped when suggested is lower",
+			current:        fees{gasPrice: big.NewInt(100)},
+			suggested:      fees{gasPrice: big.NewInt(90)},
+			expected:       fees{gasPrice: big.NewInt(100)},
+			expectedBumped: false,
+		},
+		{
+			description:    "legacy bumped at least by the bump percentage",
+			current:        fees{gasPrice: big.NewInt(100)},
+			suggested:      fees{gasPrice: big.NewInt(101)},
+			expected:       fees{gasPrice: big.NewInt(110)},
+			expectedBumped: true,
+		},
+		{
+			description:    "legacy escalated by elapsed intervals",
+			current:        fees{gasPrice: big.NewInt(100)},
+			suggested:      fees{gasPrice: big.NewInt(100)},
+			elapsed:        2*time.Minute + time.Second,
+			expected:       fees{gasPrice: big.NewInt(121)},
+			expectedBumped: true,
+		},
+		{
+			description:    "legacy capped",
+			current:        fees{gasPrice: big.NewInt(100)},
+			suggested:      fees{gasPrice: big.NewInt(100)},
+			elapsed:        10 * time.Minute,
+			maxFee:         big.NewInt(150),
+			expected:       fees{gasPrice: big.NewInt(150)},
+			expectedBumped: true,
+		},
+		{
+			description:    "legacy already at cap",
+			current:        fees{gasPrice: big.NewInt(150)},
+			suggested:      fees{gasPrice: big.NewInt(150)},
+			elapsed:        10 * time.Minute,
+			maxFee:         big.NewInt(150),
+			expected:       fees{gasPrice: big.NewInt(150)},
+			expectedBumped: false,
+			expectedCapped: true,
+		},
+		{
+			description:    "legacy not bumped when the cap prevents a replacement",
+			current:        fees{gasPrice: big.NewInt(140)},
+			suggested:      fees{gasPrice: big.NewInt(150)},
+			elapsed:        10 * time.Minute,
+			maxFee:         big.NewInt(150),
+			expected:       fees{gasPrice: big.NewInt(140)},
+			expectedBumped: false,
+			expectedCapped: true,
+		},
+		{
+			description:    "dynamic escalated by elapsed intervals",
+			current:        fees{gasTipCap: big.NewInt(10), gasFeeCap: big.NewInt(100), gasPrice: big.NewInt(100)},
+			suggested:      fees{gasTipCap: big.NewInt(10), gasFeeCap: big.NewInt(100), gasPrice: big.NewInt(100)},
+			elapsed:        time.Minute,
+			expected:       fees{gasTipCap: big.NewInt(11), gasFeeCap: big.NewInt(110), gasPrice: big.NewInt(110)},
+			expectedBumped: true,
+		},
+		{
+			description:    "dynamic tip cap limited by capped fee cap",
+			current:        fees{gasTipCap: big.NewInt(100), gasFeeCap: big.NewInt(100), gasPrice: big.NewInt(100)},
+			suggested:      fees{gasTipCap: big.NewInt(200), gasFeeCap: big.NewInt(200), gasPrice: big.NewInt(200)},
+			maxFee:         big.NewInt(150),
+			expected:       fees{gasTipCap: big.NewInt(150), gasFeeCap: big.NewInt(150), gasPrice: big.NewInt(150)},
+			expectedBumped: true,
+		},
+		{
+			description:    "dynamic not bumped when the cap prevents a replacement",
+			current:        fees{gasTipCap: big.NewInt(10), gasFeeCap: big.NewInt(140), gasPrice: big.NewInt(140)},
+			suggested:      fees{gasTipCap: big.NewInt(20), gasFeeCap: big.NewInt(200), gasPrice: big.NewInt(200)},
+			maxFee:         big.NewInt(150),
+			expected:       fees{gasTipCap: big.NewInt(10), gasFeeCap: big.NewInt(140), gasPrice: big.NewInt(140)},
+			expectedBumped: false,
+			expectedCapped: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			f, bumped, capped := cfg.escalateFees(tc.current, tc.suggested, tc.elapsed, tc.maxFee)
+			assert.Equal(t, tc.expectedBumped, bumped)
+			assert.Equal(t, tc.expectedCapped, capped)
+			assert.Equal(t, tc.expected, f)
+		})
+	}
+}
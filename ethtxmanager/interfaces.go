@@ -17,7 +17,10 @@ type ethermanInterface interface {
 	WaitTxToBeMined(ctx context.Context, tx *types.Transaction, timeout time.Duration) (bool, error)
 	SendTx(ctx context.Context, tx *types.Transaction) error
 	CurrentNonce(ctx context.Context, account common.Address) (uint64, error)
+	CurrentBalance(ctx context.Context, account common.Address) (*big.Int, error)
 	SuggestedGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestedGasTipCap(ctx context.Context) (*big.Int, error)
+	GetLatestBaseFee(ctx context.Context) (*big.Int, error)
 	EstimateGas(ctx context.Context, from common.Address, to *common.Address, value *big.Int, data []byte) (uint64, error)
 	CheckTxWasMined(ctx context.Context, txHash common.Hash) (bool, *types.Receipt, error)
 	SignTx(ctx context.Context, sender common.Address, tx *types.Transaction) (*types.Transaction, error)
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"github.com/0xPolygonHermez/zkevm-node/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Prefix for the metrics of the ethtxmanager package.
+	Prefix = "ethtxmanager_"
+	// AccountPrefix is the prefix for the metrics of the sender accounts.
+	AccountPrefix = Prefix + "account_"
+	// AccountBalanceName is the name of the metric that shows the balance in wei of a sender account.
+	AccountBalanceName = AccountPrefix + "balance"
+	// AccountNonceName is the name of the metric that shows the L1 nonce of a sender account.
+	AccountNonceName = AccountPrefix + "nonce"
+	// AccountPendingTxsName is the name of the metric that shows the monitored txs not mined yet of a sender account.
+	AccountPendingTxsName = AccountPrefix + "pending_txs"
+	// AccountTxsSentName is the name of the metric that counts the txs sent to L1 by a sender account.
+	AccountTxsSentName = AccountPrefix + "txs_sent"
+	// AccountLabelName is the name of the label for the sender account address.
+	AccountLabelName = "account"
+)
+
+// Register the metrics for the ethtxmanager package.
+func Register() {
+	var (
+		counterVecs []metrics.CounterVecOpts
+		gaugeVecs   []metrics.GaugeVecOpts
+	)
+
+	counterVecs = []metrics.CounterVecOpts{
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: AccountTxsSentName,
+				Help: "[ETHTXMANAGER] number of txs sent to L1 by the sender account",
+			},
+			Labels: []string{AccountLabelName},
+		},
+	}
+
+	gaugeVecs = []metrics.GaugeVecOpts{
+		{
+			GaugeOpts: prometheus.GaugeOpts{
+				Name: AccountBalanceName,
+				Help: "[ETHTXMANAGER] balance in wei of the sender account",
+			},
+			Labels: []string{AccountLabelName},
+		},
+		{
+			GaugeOpts: prometheus.GaugeOpts{
+				Name: AccountNonceName,
+				Help: "[ETHTXMANAGER] L1 nonce of the sender account",
+			},
+			Labels: []string{AccountLabelName},
+		},
+		{
+			GaugeOpts: prometheus.GaugeOpts{
+				Name: AccountPendingTxsName,
+				Help: "[ETHTXMANAGER] number of monitored txs not mined yet of the sender account",
+			},
+			Labels: []string{AccountLabelName},
+		},
+	}
+
+	metrics.RegisterCounterVecs(counterVecs...)
+	metrics.RegisterGaugeVecs(gaugeVecs...)
+}
+
+// AccountBalance sets the gauge to the given balance of the sender account.
+func AccountBalance(account string, balance float64) {
+	metrics.GaugeVecSet(AccountBalanceName, account, balance)
+}
+
+// AccountNonce sets the gauge to the given L1 nonce of the sender account.
+func AccountNonce(account string, nonce uint64) {
+	metrics.GaugeVecSet(AccountNonceName, account, float64(nonce))
+}
+
+// AccountPendingTxs sets the gauge to the given number of pending monitored
+// txs of the sender account.
+func AccountPendingTxs(account string, count int) {
+	metrics.GaugeVecSet(AccountPendingTxsName, account, float64(count))
+}
+
+// AccountTxSent increases the counter of txs sent to L1 by the sender account.
+func AccountTxSent(account string) {
+	metrics.CounterVecInc(AccountTxsSentName, account)
+}
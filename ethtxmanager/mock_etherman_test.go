@@ -50,6 +50,29 @@ func (_m *ethermanMock) CheckTxWasMined(ctx context.Context, txHash common.Hash)
 	return r0, r1, r2
 }
 
+// CurrentBalance provides a mock function with given fields: ctx, account
+func (_m *ethermanMock) CurrentBalance(ctx context.Context, account common.Address) (*big.Int, error) {
+	ret := _m.Called(ctx, account)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address) *big.Int); ok {
+		r0 = rf(ctx, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CurrentNonce provides a mock function with given fields: ctx, account
 func (_m *ethermanMock) CurrentNonce(ctx context.Context, account common.Address) (uint64, error) {
 	ret := _m.Called(ctx, account)
@@ -92,6 +115,29 @@ func (_m *ethermanMock) EstimateGas(ctx context.Context, from common.Address, to
 	return r0, r1
 }
 
+// GetLatestBaseFee provides a mock function with given fields: ctx
+func (_m *ethermanMock) GetLatestBaseFee(ctx context.Context) (*big.Int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(context.Context) *big.Int); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRevertMessage provides a mock function with given fields: ctx, tx
 func (_m *ethermanMock) GetRevertMessage(ctx context.Context, tx *types.Transaction) (string, error) {
 	ret := _m.Called(ctx, tx)
@@ -226,6 +272,29 @@ func (_m *ethermanMock) SuggestedGasPrice(ctx context.Context) (*big.Int, error)
 	return r0, r1
 }
 
+// SuggestedGasTipCap provides a mock function with given fields: ctx
+func (_m *ethermanMock) SuggestedGasTipCap(ctx context.Context) (*big.Int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(context.Context) *big.Int); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WaitTxToBeMined provides a mock function with given fields: ctx, tx, timeout
 func (_m *ethermanMock) WaitTxToBeMined(ctx context.Context, tx *types.Transaction, timeout time.Duration) (bool, error) {
 	ret := _m.Called(ctx, tx, timeout)
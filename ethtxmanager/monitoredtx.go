@@ -33,6 +33,15 @@ const (
 
 	// MonitoredTxStatusDone means the tx was set by the owner as done
 	MonitoredTxStatusDone = MonitoredTxStatus("done")
+
+	// MonitoredTxStatusCanceling means the tx is being replaced on request of an
+	// operator by a zero value tx to the sender using the same nonce, it keeps
+	// being monitored until either the replacement or a previous tx is mined
+	MonitoredTxStatusCanceling = MonitoredTxStatus("canceling")
+
+	// MonitoredTxStatusCanceled means the zero value tx replacing the tx was mined,
+	// so the data of the monitored tx was not executed
+	MonitoredTxStatusCanceled = MonitoredTxStatus("canceled")
 )
 
 // MonitoredTxStatus represents the status of a monitored tx
@@ -73,9 +82,15 @@ type monitoredTx struct {
 	// tx gas
 	gas uint64
 
-	// tx gas price
+	// tx gas price, for dynamic fee txs it's the same as the gasFeeCap
 	gasPrice *big.Int
 
+	// tx gas tip cap, only set for dynamic fee txs
+	gasTipCap *big.Int
+
+	// tx gas fee cap, only set for dynamic fee txs
+	gasFeeCap *big.Int
+
 	// status of this monitoring
 	status MonitoredTxStatus
 
@@ -98,6 +113,18 @@ type monitoredTx struct {
 
 // Tx uses the current information to build a tx
 func (mTx monitoredTx) Tx() *types.Transaction {
+	if mTx.gasFeeCap != nil {
+		return types.NewTx(&types.DynamicFeeTx{
+			To:        mTx.to,
+			Nonce:     mTx.nonce,
+			Value:     mTx.value,
+			Data:      mTx.data,
+			Gas:       mTx.gas,
+			GasTipCap: mTx.gasTipCap,
+			GasFeeCap: mTx.gasFeeCap,
+		})
+	}
+
 	tx := types.NewTx(&types.LegacyTx{
 		To:       mTx.to,
 		Nonce:    mTx.nonce,
@@ -110,6 +137,26 @@ func (mTx monitoredTx) Tx() *types.Transaction {
 	return tx
 }
 
+// fees returns the current fee fields
+func (mTx monitoredTx) fees() fees {
+	return fees{gasPrice: mTx.gasPrice, gasTipCap: mTx.gasTipCap, gasFeeCap: mTx.gasFeeCap}
+}
+
+// setFees updates the fee fields
+func (mTx *monitoredTx) setFees(f fees) {
+	mTx.gasPrice = f.gasPrice
+	mTx.gasTipCap = f.gasTipCap
+	mTx.gasFeeCap = f.gasFeeCap
+}
+
+// txType returns the type of the tx built by this monitored tx
+func (mTx monitoredTx) txType() TxType {
+	if mTx.gasFeeCap != nil {
+		return TxTypeDynamic
+	}
+	return TxTypeLegacy
+}
+
 // AddHistory adds a transaction to the monitoring history
 func (mTx monitoredTx) AddHistory(tx *types.Transaction) error {
 	if _, found := mTx.history[tx.Hash()]; found {
@@ -139,6 +186,26 @@ func (mTx *monitoredTx) valueU64Ptr() *uint64 {
 	return value
 }
 
+// gasTipCapU64Ptr returns the current gasTipCap field as a uint64 pointer
+func (mTx *monitoredTx) gasTipCapU64Ptr() *uint64 {
+	var gasTipCap *uint64
+	if mTx.gasTipCap != nil {
+		tmp := mTx.gasTipCap.Uint64()
+		gasTipCap = &tmp
+	}
+	return gasTipCap
+}
+
+// gasFeeCapU64Ptr returns the current gasFeeCap field as a uint64 pointer
+func (mTx *monitoredTx) gasFeeCapU64Ptr() *uint64 {
+	var gasFeeCap *uint64
+	if mTx.gasFeeCap != nil {
+		tmp := mTx.gasFeeCap.Uint64()
+		gasFeeCap = &tmp
+	}
+	return gasFeeCap
+}
+
 // dataStringPtr returns the current data field as a string pointer
 func (mTx *monitoredTx) dataStringPtr() *string {
 	var data *string
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,3 +35,27 @@ func TestTx(t *testing.T) {
 	assert.Equal(t, gas, tx.Gas())
 	assert.Equal(t, gasPrice, tx.GasPrice())
 }
+
+func TestDynamicFeeTx(t *testing.T) {
+	to := common.HexToAddress("0x2")
+	gasTipCap := big.NewInt(4)
+	gasFeeCap := big.NewInt(5)
+
+	mTx := monitoredTx{
+		to:        &to,
+		nonce:     1,
+		value:     big.NewInt(2),
+		data:      []byte("data"),
+		gas:       3,
+		gasPrice:  gasFeeCap,
+		gasTipCap: gasTipCap,
+		gasFeeCap: gasFeeCap,
+	}
+
+	tx := mTx.Tx()
+
+	assert.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+	assert.Equal(t, gasTipCap, tx.GasTipCap())
+	assert.Equal(t, gasFeeCap, tx.GasFeeCap())
+	assert.Equal(t, TxTypeDynamic, mTx.txType())
+}
@@ -36,15 +36,15 @@ func NewPostgresStorage(dbCfg db.Config) (*PostgresStorage, error) {
 func (s *PostgresStorage) Add(ctx context.Context, mTx monitoredTx, dbTx pgx.Tx) error {
 	conn := s.dbConn(dbTx)
 	cmd := `
-        INSERT INTO state.monitored_txs (owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, status, block_num, history, created_at, updated_at)
-                                 VALUES (   $1, $2,        $3,      $4,    $5,    $6,   $7,  $8,        $9,    $10,       $11,     $12,        $13,        $14)`
+        INSERT INTO state.monitored_txs (owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, gas_tip_cap, gas_fee_cap, status, block_num, history, created_at, updated_at)
+                                 VALUES (   $1, $2,        $3,      $4,    $5,    $6,   $7,  $8,        $9,         $10,         $11,    $12,       $13,     $14,        $15,        $16)`
 
 	_, err := conn.Exec(ctx, cmd, mTx.owner,
 		mTx.id, mTx.from.String(), mTx.toStringPtr(),
 		mTx.nonce, mTx.valueU64Ptr(), mTx.dataStringPtr(),
-		mTx.gas, mTx.gasPrice.Uint64(), string(mTx.status), mTx.blockNumberU64Ptr(),
-		mTx.historyStringSlice(), time.Now().UTC().Round(time.Microsecond),
-		time.Now().UTC().Round(time.Microsecond))
+		mTx.gas, mTx.gasPrice.Uint64(), mTx.gasTipCapU64Ptr(), mTx.gasFeeCapU64Ptr(),
+		string(mTx.status), mTx.blockNumberU64Ptr(), mTx.historyStringSlice(),
+		time.Now().UTC().Round(time.Microsecond), time.Now().UTC().Round(time.Microsecond))
 
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.ConstraintName == "monitored_txs_pkey" {
@@ -61,7 +61,7 @@ func (s *PostgresStorage) Add(ctx context.Context, mTx monitoredTx, dbTx pgx.Tx)
 func (s *PostgresStorage) Get(ctx context.Context, owner, id string, dbTx pgx.Tx) (monitoredTx, error) {
 	conn := s.dbConn(dbTx)
 	cmd := `
-        SELECT owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, status, block_num, history, created_at, updated_at
+        SELECT owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, gas_tip_cap, gas_fee_cap, status, block_num, history, created_at, updated_at
           FROM state.monitored_txs
          WHERE owner = $1 
            AND id = $2`
@@ -85,7 +85,7 @@ func (s *PostgresStorage) GetByStatus(ctx context.Context, owner *string, status
 
 	conn := s.dbConn(dbTx)
 	cmd := `
-        SELECT owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, status, block_num, history, created_at, updated_at
+        SELECT owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, gas_tip_cap, gas_fee_cap, status, block_num, history, created_at, updated_at
           FROM state.monitored_txs
          WHERE (owner = $1 OR $1 IS NULL)`
 	if hasStatusToFilter {
@@ -128,7 +128,7 @@ func (s *PostgresStorage) GetByStatus(ctx context.Context, owner *string, status
 func (s *PostgresStorage) GetByBlock(ctx context.Context, fromBlock, toBlock *uint64, dbTx pgx.Tx) ([]monitoredTx, error) {
 	conn := s.dbConn(dbTx)
 	cmd := `
-        SELECT owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, status, block_num, history, created_at, updated_at
+        SELECT owner, id, from_addr, to_addr, nonce, value, data, gas, gas_price, gas_tip_cap, gas_fee_cap, status, block_num, history, created_at, updated_at
           FROM state.monitored_txs
          WHERE (block_num >= $1 OR $1 IS NULL)
            AND (block_num <= $2 OR $2 IS NULL)
@@ -183,10 +183,12 @@ func (s *PostgresStorage) Update(ctx context.Context, mTx monitoredTx, dbTx pgx.
              , data = $7
              , gas = $8
              , gas_price = $9
-             , status = $10
-             , block_num = $11
-             , history = $12
-             , updated_at = $13
+             , gas_tip_cap = $10
+             , gas_fee_cap = $11
+             , status = $12
+             , block_num = $13
+             , history = $14
+             , updated_at = $15
          WHERE owner = $1
            AND id = $2`
 
@@ -199,8 +201,8 @@ func (s *PostgresStorage) Update(ctx context.Context, mTx monitoredTx, dbTx pgx.
 	_, err := conn.Exec(ctx, cmd, mTx.owner,
 		mTx.id, mTx.from.String(), mTx.toStringPtr(),
 		mTx.nonce, mTx.valueU64Ptr(), mTx.dataStringPtr(),
-		mTx.gas, mTx.gasPrice.Uint64(), string(mTx.status), bn,
-		mTx.historyStringSlice(), time.Now().UTC().Round(time.Microsecond))
+		mTx.gas, mTx.gasPrice.Uint64(), mTx.gasTipCapU64Ptr(), mTx.gasFeeCapU64Ptr(),
+		string(mTx.status), bn, mTx.historyStringSlice(), time.Now().UTC().Round(time.Microsecond))
 
 	if err != nil {
 		return err
@@ -212,16 +214,16 @@ func (s *PostgresStorage) Update(ctx context.Context, mTx monitoredTx, dbTx pgx.
 // scanMtx scans a row and fill the provided instance of monitoredTx with
 // the row data
 func (s *PostgresStorage) scanMtx(row pgx.Row, mTx *monitoredTx) error {
-	// id, from, to, nonce, value, data, gas, gas_price, status, history, created_at, updated_at
+	// id, from, to, nonce, value, data, gas, gas_price, gas_tip_cap, gas_fee_cap, status, history, created_at, updated_at
 	var from, status string
 	var to, data *string
 	var history []string
-	var value, blockNumber *uint64
+	var value, gasTipCap, gasFeeCap, blockNumber *uint64
 	var gasPrice uint64
 
 	err := row.Scan(&mTx.owner, &mTx.id, &from, &to, &mTx.nonce, &value,
-		&data, &mTx.gas, &gasPrice, &status, &blockNumber, &history,
-		&mTx.createdAt, &mTx.updatedAt)
+		&data, &mTx.gas, &gasPrice, &gasTipCap, &gasFeeCap, &status, &blockNumber,
+		&history, &mTx.createdAt, &mTx.updatedAt)
 	if err != nil {
 		return err
 	}
@@ -238,6 +240,12 @@ func (s *PostgresStorage) scanMtx(row pgx.Row, mTx *monitoredTx) error {
 		tmp := *value
 		mTx.value = big.NewInt(0).SetUint64(tmp)
 	}
+	if gasTipCap != nil {
+		mTx.gasTipCap = big.NewInt(0).SetUint64(*gasTipCap)
+	}
+	if gasFeeCap != nil {
+		mTx.gasFeeCap = big.NewInt(0).SetUint64(*gasFeeCap)
+	}
 	if data != nil {
 		tmp := *data
 		bytes, err := hex.DecodeString(tmp)
@@ -0,0 +1,211 @@
+package ethtxmanager
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// rebalanceOwner is the owner of the monitored txs created by the eth tx
+// manager to move funds between the sender accounts of a pool
+const rebalanceOwner = "ethtxmanager"
+
+// pendingStatuses are the statuses of the monitored txs that were not mined yet,
+// the ones being canceled keep their nonce until the cancel tx is mined
+var pendingStatuses = []MonitoredTxStatus{MonitoredTxStatusCreated, MonitoredTxStatusSent, MonitoredTxStatusReorged, MonitoredTxStatusCanceling}
+
+// senderAccounts returns the pool of sender accounts configured for the owner
+func (c *Client) senderAccounts(owner string) []common.Address {
+	for _, pool := range c.cfg.SenderAccounts {
+		if pool.Owner == owner {
+			return pool.Addresses
+		}
+	}
+	return nil
+}
+
+// pendingBySender returns the monitored txs that were not mined yet grouped by sender account
+func (c *Client) pendingBySender(ctx context.Context, dbTx pgx.Tx) (map[common.Address][]monitoredTx, error) {
+	mTxs, err := c.storage.GetByStatus(ctx, nil, pendingStatuses, dbTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending monitored txs: %w", err)
+	}
+	return groupBySender(mTxs), nil
+}
+
+// selectSender returns the account of the owner pool with less pending monitored txs,
+// the first one of the pool wins the ties. If the owner has no pool, the provided
+// sender is returned
+func (c *Client) selectSender(owner string, from common.Address, pending map[common.Address][]monitoredTx) common.Address {
+	accounts := c.senderAccounts(owner)
+	if len(accounts) == 0 {
+		return from
+	}
+
+	selected := accounts[0]
+	for _, account := range accounts[1:] {
+		if len(pending[account]) < len(pending[selected]) {
+			selected = account
+		}
+	}
+	return selected
+}
+
+// nextNonce returns the nonce to be used by the next monitored tx of the sender account,
+// which is the next one after the pending monitored txs of the account or the current
+// L1 nonce of the account if it is higher
+func (c *Client) nextNonce(ctx context.Context, from common.Address, pending []monitoredTx) (uint64, error) {
+	nonce, err := c.etherman.CurrentNonce(ctx, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current nonce: %w", err)
+	}
+
+	for _, mTx := range pending {
+		if mTx.nonce >= nonce {
+			nonce = mTx.nonce + 1
+		}
+	}
+	return nonce, nil
+}
+
+// groupBySender groups the monitored txs by sender account keeping their order
+func groupBySender(mTxs []monitoredTx) map[common.Address][]monitoredTx {
+	lanes := make(map[common.Address][]monitoredTx)
+	for _, mTx := range mTxs {
+		lanes[mTx.from] = append(lanes[mTx.from], mTx)
+	}
+	return lanes
+}
+
+// updatePendingTxsMetrics sets the number of pending monitored txs of every
+// account with pending monitored txs or belonging to a pool
+func (c *Client) updatePendingTxsMetrics(lanes map[common.Address][]monitoredTx) {
+	for _, pool := range c.cfg.SenderAccounts {
+		for _, account := range pool.Addresses {
+			metrics.AccountPendingTxs(account.String(), len(lanes[account]))
+		}
+	}
+	for account, lane := range lanes {
+		metrics.AccountPendingTxs(account.String(), len(lane))
+	}
+}
+
+// checkBalances checks the balances of the accounts of every pool, logging a warning
+// for the accounts below the min balance and moving funds to them from the richest
+// account of the pool when the rebalance is enabled
+func (c *Client) checkBalances(ctx context.Context) {
+	c.processRebalanceResults(ctx)
+
+	pending, err := c.pendingBySender(ctx, nil)
+	if err != nil {
+		log.Errorf("failed to check sender accounts balances: %v", err)
+		return
+	}
+
+	for _, pool := range c.cfg.SenderAccounts {
+		balances := make(map[common.Address]*big.Int, len(pool.Addresses))
+		for _, account := range pool.Addresses {
+			balance, err := c.etherman.CurrentBalance(ctx, account)
+			if err != nil {
+				log.Errorf("failed to get balance of sender account %v: %v", account.String(), err)
+				continue
+			}
+			balances[account] = balance
+			balanceFloat, _ := new(big.Float).SetInt(balance).Float64()
+			metrics.AccountBalance(account.String(), balanceFloat)
+
+			nonce, err := c.etherman.CurrentNonce(ctx, account)
+			if err != nil {
+				log.Errorf("failed to get nonce of sender account %v: %v", account.String(), err)
+				continue
+			}
+			metrics.AccountNonce(account.String(), nonce)
+		}
+
+		for _, account := range pool.Addresses {
+			balance, found := balances[account]
+			if !found || c.cfg.Balance.MinBalance.Int == nil || balance.Cmp(c.cfg.Balance.MinBalance.Int) >= 0 {
+				continue
+			}
+			log.Warnf("sender account %v of the owner %v has a low balance: %v wei", account.String(), pool.Owner, balance.String())
+
+			if c.cfg.Balance.TargetBalance.Int == nil || c.cfg.Balance.TargetBalance.Sign() == 0 {
+				continue
+			}
+			if hasPendingRebalance(account, pending) {
+				log.Infof("sender account %v already has a pending rebalance", account.String())
+				continue
+			}
+			err := c.rebalance(ctx, account, balances)
+			if err != nil {
+				log.Warnf("failed to rebalance sender account %v of the owner %v: %v", account.String(), pool.Owner, err)
+			}
+		}
+	}
+}
+
+// rebalance adds a monitored tx moving funds from the richest account of the pool
+// to the provided account, so its balance reaches the target balance
+func (c *Client) rebalance(ctx context.Context, account common.Address, balances map[common.Address]*big.Int) error {
+	target := c.cfg.Balance.TargetBalance.Int
+	amount := new(big.Int).Sub(target, balances[account])
+
+	var richest common.Address
+	for candidate, balance := range balances {
+		if candidate != account && (balances[richest] == nil || balance.Cmp(balances[richest]) > 0) {
+			richest = candidate
+		}
+	}
+	if balances[richest] == nil || new(big.Int).Sub(balances[richest], amount).Cmp(target) < 0 {
+		return fmt.Errorf("no account of the pool has enough funds to transfer %v wei keeping the target balance", amount.String())
+	}
+
+	id := fmt.Sprintf("rebalance-%v-%v", account.String(), time.Now().UnixNano())
+	err := c.Add(ctx, rebalanceOwner, id, richest, &account, amount, nil, nil)
+	if err != nil {
+		return err
+	}
+	log.Infof("moving %v wei from sender account %v to %v, monitored tx: %v", amount.String(), richest.String(), account.String(), id)
+
+	balances[richest] = new(big.Int).Sub(balances[richest], amount)
+	balances[account] = new(big.Int).Set(target)
+	return nil
+}
+
+// processRebalanceResults sets as done the rebalance monitored txs that were confirmed
+// or failed, so they stop being monitored
+func (c *Client) processRebalanceResults(ctx context.Context) {
+	owner := rebalanceOwner
+	mTxs, err := c.storage.GetByStatus(ctx, &owner, []MonitoredTxStatus{MonitoredTxStatusConfirmed, MonitoredTxStatusFailed}, nil)
+	if err != nil {
+		log.Errorf("failed to get rebalance monitored txs: %v", err)
+		return
+	}
+	for _, mTx := range mTxs {
+		if mTx.status == MonitoredTxStatusFailed {
+			log.Warnf("rebalance monitored tx %v to sender account %v failed", mTx.id, mTx.to.String())
+		}
+		err := c.setStatusDone(ctx, owner, mTx.id, nil)
+		if err != nil {
+			log.Errorf("failed to set rebalance monitored tx %v as done: %v", mTx.id, err)
+		}
+	}
+}
+
+// hasPendingRebalance checks if there is a rebalance monitored tx not mined yet to the account
+func hasPendingRebalance(account common.Address, pending map[common.Address][]monitoredTx) bool {
+	for _, lane := range pending {
+		for _, mTx := range lane {
+			if mTx.owner == rebalanceOwner && mTx.to != nil && *mTx.to == account {
+				return true
+			}
+		}
+	}
+	return false
+}
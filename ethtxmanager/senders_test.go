@@ -0,0 +1,140 @@
+package ethtxmanager
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	senderA = common.HexToAddress("0xA")
+	senderB = common.HexToAddress("0xB")
+	senderC = common.HexToAddress("0xC")
+)
+
+func TestSelectSender(t *testing.T) {
+	c := New(Config{
+		SenderAccounts: []OwnerSenderAccounts{{Owner: "aggregator", Addresses: []common.Address{senderA, senderB}}},
+	}, nil, nil, nil)
+
+	pending := map[common.Address][]monitoredTx{
+		senderA: {{from: senderA, nonce: 1}, {from: senderA, nonce: 2}},
+		senderB: {{from: senderB, nonce: 5}},
+	}
+
+	assert.Equal(t, senderB, c.selectSender("aggregator", senderC, pending))
+	assert.Equal(t, senderA, c.selectSender("aggregator", senderC, map[common.Address][]monitoredTx{}))
+	assert.Equal(t, senderC, c.selectSender("sequencer", senderC, pending))
+}
+
+func TestNextNonce(t *testing.T) {
+	ctx := context.Background()
+	etherman := newEthermanMock(t)
+	c := New(Config{}, etherman, nil, nil)
+
+	etherman.On("CurrentNonce", ctx, senderA).Return(uint64(3), nil).Twice()
+
+	nonce, err := c.nextNonce(ctx, senderA, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+
+	nonce, err = c.nextNonce(ctx, senderA, []monitoredTx{{nonce: 3}, {nonce: 4}})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), nonce)
+}
+
+func TestAddAssignsLeastLoadedSender(t *testing.T) {
+	ctx := context.Background()
+	etherman := newEthermanMock(t)
+	storage := newStorageMock(t)
+	c := New(Config{
+		FeeStrategy:    FeeStrategyConfig{TxType: TxTypeLegacy},
+		SenderAccounts: []OwnerSenderAccounts{{Owner: "aggregator", Addresses: []common.Address{senderA, senderB}}},
+	}, etherman, storage, nil)
+
+	to := common.HexToAddress("0x1")
+	storage.
+		On("GetByStatus", ctx, (*string)(nil), pendingStatuses, nil).
+		Return([]monitoredTx{{owner: "aggregator", from: senderA, nonce: 10}}, nil).
+		Once()
+	etherman.On("CurrentNonce", ctx, senderB).Return(uint64(7), nil).Once()
+	etherman.On("EstimateGas", ctx, senderB, &to, big.NewInt(0), []byte{1}).Return(uint64(21000), nil).Once()
+	etherman.On("SuggestedGasPrice", ctx).Return(big.NewInt(10), nil).Once()
+	storage.
+		On("Add", ctx, mock.MatchedBy(func(mTx monitoredTx) bool {
+			return mTx.from == senderB && mTx.nonce == 7
+		}), nil).
+		Return(nil).
+		Once()
+
+	err := c.Add(ctx, "aggregator", "id", senderA, &to, big.NewInt(0), []byte{1}, nil)
+	require.NoError(t, err)
+}
+
+func TestAddSkipsNonceOfCancelingTx(t *testing.T) {
+	ctx := context.Background()
+	etherman := newEthermanMock(t)
+	storage := newStorageMock(t)
+	c := New(Config{
+		FeeStrategy: FeeStrategyConfig{TxType: TxTypeLegacy},
+	}, etherman, storage, nil)
+
+	to := common.HexToAddress("0x1")
+	storage.
+		On("GetByStatus", ctx, (*string)(nil), pendingStatuses, nil).
+		Return([]monitoredTx{{owner: "aggregator", from: senderA, nonce: 4, status: MonitoredTxStatusCanceling}}, nil).
+		Once()
+	etherman.On("CurrentNonce", ctx, senderA).Return(uint64(4), nil).Once()
+	etherman.On("EstimateGas", ctx, senderA, &to, big.NewInt(0), []byte{1}).Return(uint64(21000), nil).Once()
+	etherman.On("SuggestedGasPrice", ctx).Return(big.NewInt(10), nil).Once()
+	storage.
+		On("Add", ctx, mock.MatchedBy(func(mTx monitoredTx) bool {
+			return mTx.from == senderA && mTx.nonce == 5
+		}), nil).
+		Return(nil).
+		Once()
+
+	err := c.Add(ctx, "aggregator", "id", senderA, &to, big.NewInt(0), []byte{1}, nil)
+	require.NoError(t, err)
+}
+
+func TestCheckBalancesRebalance(t *testing.T) {
+	ctx := context.Background()
+	etherman := newEthermanMock(t)
+	storage := newStorageMock(t)
+	c := New(Config{
+		FeeStrategy:    FeeStrategyConfig{TxType: TxTypeLegacy},
+		SenderAccounts: []OwnerSenderAccounts{{Owner: "aggregator", Addresses: []common.Address{senderA, senderB, senderC}}},
+		Balance: BalanceConfig{
+			MinBalance:    Wei{big.NewInt(100)},
+			TargetBalance: Wei{big.NewInt(500)},
+		},
+	}, etherman, storage, nil)
+
+	owner := rebalanceOwner
+	storage.
+		On("GetByStatus", ctx, &owner, []MonitoredTxStatus{MonitoredTxStatusConfirmed, MonitoredTxStatusFailed}, nil).
+		Return([]monitoredTx{}, nil).
+		Once()
+	storage.On("GetByStatus", ctx, (*string)(nil), pendingStatuses, nil).Return([]monitoredTx{}, nil).Twice()
+
+	etherman.On("CurrentBalance", ctx, senderA).Return(big.NewInt(50), nil).Once()
+	etherman.On("CurrentBalance", ctx, senderB).Return(big.NewInt(2000), nil).Once()
+	etherman.On("CurrentBalance", ctx, senderC).Return(big.NewInt(800), nil).Once()
+	etherman.On("CurrentNonce", ctx, mock.Anything).Return(uint64(1), nil).Times(4)
+	etherman.On("EstimateGas", ctx, senderB, &senderA, big.NewInt(450), []byte(nil)).Return(uint64(21000), nil).Once()
+	etherman.On("SuggestedGasPrice", ctx).Return(big.NewInt(10), nil).Once()
+	storage.
+		On("Add", ctx, mock.MatchedBy(func(mTx monitoredTx) bool {
+			return mTx.owner == rebalanceOwner && mTx.from == senderB && *mTx.to == senderA && mTx.value.Cmp(big.NewInt(450)) == 0
+		}), nil).
+		Return(nil).
+		Once()
+
+	c.checkBalances(ctx)
+}
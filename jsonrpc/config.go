@@ -31,6 +31,10 @@ type Config struct {
 	// ChainID is the L2 ChainID provided by the Network Config
 	ChainID uint64
 
+	// ReadReplicas is set when the state has read replicas, the read only
+	// queries run without a dbTx so they can be sent to them
+	ReadReplicas bool
+
 	// Websockets
 	WebSockets WebSocketsConfig `mapstructure:"WebSockets"`
 }
@@ -3,15 +3,23 @@ package jsonrpc
 import (
 	"context"
 
+	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/jackc/pgx/v4"
 )
 
-type dbTxManager struct{}
+type dbTxManager struct {
+	// readReplicas runs the scoped functions without a dbTx, so the state
+	// can send their queries to the read replicas
+	readReplicas bool
+}
 
 type dbTxScopedFn func(ctx context.Context, dbTx pgx.Tx) (interface{}, rpcError)
 
 func (f *dbTxManager) NewDbTxScope(st stateInterface, scopedFn dbTxScopedFn) (interface{}, rpcError) {
 	ctx := context.Background()
+	if f.readReplicas {
+		return scopedFn(state.WithReadReplica(ctx), nil)
+	}
 	dbTx, err := st.BeginStateTransaction(ctx)
 	if err != nil {
 		return rpcErrorResponse(defaultErrorCode, "failed to connect to the state", err)
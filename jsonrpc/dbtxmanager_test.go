@@ -95,3 +95,17 @@ func TestNewDbTxScope(t *testing.T) {
 		})
 	}
 }
+
+func TestNewDbTxScopeReadReplicas(t *testing.T) {
+	dbTxManager := dbTxManager{readReplicas: true}
+	s := newStateMock(t)
+
+	result, err := dbTxManager.NewDbTxScope(s, func(ctx context.Context, dbTx pgx.Tx) (interface{}, rpcError) {
+		assert.Nil(t, dbTx)
+		assert.NotEqual(t, context.Background(), ctx)
+		return 1, nil
+	})
+	assert.Equal(t, 1, result)
+	assert.Nil(t, err)
+	s.AssertNotCalled(t, "BeginStateTransaction", context.Background())
+}
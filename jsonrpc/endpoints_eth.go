@@ -28,7 +28,7 @@ type EthEndpoints struct {
 
 // newEthEndpoints creates an new instance of Eth
 func newEthEndpoints(cfg Config, p jsonRPCTxPool, s stateInterface, storage storageInterface) *EthEndpoints {
-	e := &EthEndpoints{cfg: cfg, pool: p, state: s, storage: storage}
+	e := &EthEndpoints{cfg: cfg, pool: p, state: s, storage: storage, txMan: dbTxManager{readReplicas: cfg.ReadReplicas}}
 	s.RegisterNewL2BlockEventHandler(e.onNewL2Block)
 
 	return e
@@ -192,6 +192,9 @@ func (e *EthEndpoints) GetBlockByNumber(number BlockNumber, fullTx bool) (interf
 			return nil, rpcErr
 		}
 
+		if e.cfg.ReadReplicas && dbTx == nil {
+			ctx = state.WithReadReplicaBlock(ctx, blockNumber)
+		}
 		block, err := e.state.GetL2BlockByNumber(ctx, blockNumber, dbTx)
 		if errors.Is(err, state.ErrNotFound) {
 			return nil, nil
@@ -334,6 +337,11 @@ func (e *EthEndpoints) internalGetLogs(ctx context.Context, dbTx pgx.Tx, filter
 		return nil, rpcErr
 	}
 
+	if e.cfg.ReadReplicas && dbTx == nil && filter.BlockHash == nil {
+		// the logs of the blocks already synced by a lagging read replica
+		// can still be read from it
+		ctx = state.WithReadReplicaBlock(ctx, toBlock)
+	}
 	logs, err := e.state.GetLogs(ctx, fromBlock, toBlock, filter.Addresses, filter.Topics, filter.BlockHash, filter.Since, dbTx)
 	if err != nil {
 		return rpcErrorResponse(defaultErrorCode, "failed to get logs from state", err)
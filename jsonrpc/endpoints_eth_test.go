@@ -2551,6 +2551,28 @@ func TestGetTransactionReceipt(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			Name:           "Get TX receipt of pruned history",
+			Hash:           common.HexToHash("0x123"),
+			ExpectedResult: nil,
+			ExpectedError:  newRPCError(prunedHistoryErrorCode, "pruned history unavailable, the requested data is older than the retention window of the node"),
+			SetupMocks: func(m *mocks, tc testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetTransactionByHash", context.Background(), tc.Hash, m.DbTx).
+					Return(nil, state.ErrPrunedHistory).
+					Once()
+			},
+		},
 		{
 			Name:           "TX receipt Not Found",
 			Hash:           common.HexToHash("0x123"),
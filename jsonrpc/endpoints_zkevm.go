@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool/pgpoolstorage"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 )
@@ -15,6 +18,7 @@ import (
 // ZKEVMEndpoints contains implementations for the "zkevm" RPC endpoints
 type ZKEVMEndpoints struct {
 	config Config
+	pool   jsonRPCTxPool
 	state  stateInterface
 	txMan  dbTxManager
 }
@@ -158,8 +162,102 @@ func (z *ZKEVMEndpoints) GetBatchByNumber(batchNumber BatchNumber, fullTx bool)
 	})
 }
 
+// EstimateCounters executes the transaction alone on top of the given block
+// and returns the ZK counters it uses and the percentage of the limits of a
+// batch they represent, so the txs can be adjusted to fit in a batch
+func (z *ZKEVMEndpoints) EstimateCounters(arg *txnArgs, number *BlockNumber) (interface{}, rpcError) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, rpcError) {
+		blockNumber, rpcErr := number.getNumericBlockNumber(ctx, z.state, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		sender, tx, err := arg.ToUnsignedTransaction(ctx, z.state, blockNumber, z.config, dbTx)
+		if err != nil {
+			return rpcErrorResponse(defaultErrorCode, "failed to convert arguments into an unsigned transaction", err)
+		}
+
+		var blockNumberToProcessTx *uint64
+		if number != nil && *number != LatestBlockNumber && *number != PendingBlockNumber {
+			blockNumberToProcessTx = &blockNumber
+		}
+
+		estimation, err := z.state.EstimateCounters(ctx, tx, sender, blockNumberToProcessTx, dbTx)
+		if err != nil {
+			return rpcErrorResponse(defaultErrorCode, "failed to estimate the counters of the transaction", err)
+		}
+		return countersEstimationToRPCCountersEstimation(estimation), nil
+	})
+}
+
+// GetTransactionZKCounters returns the ZK counters used by a mined tx and the
+// percentage of the limits of a batch they represent, null if the tx is not
+// mined. The counters of the synced txs are computed executing them again
+func (z *ZKEVMEndpoints) GetTransactionZKCounters(hash common.Hash) (interface{}, rpcError) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, rpcError) {
+		usage, err := z.state.GetTransactionZKCounters(ctx, hash, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return rpcErrorResponse(defaultErrorCode, "failed to load the transaction zk counters from state", err)
+		}
+		return zkCounterUsageToRPCZKCounters(usage), nil
+	})
+}
+
 // GetBroadcastURI returns the IP:PORT of the broadcast service provided
 // by the Trusted Sequencer JSON RPC server
 func (z *ZKEVMEndpoints) GetBroadcastURI() (interface{}, rpcError) {
 	return z.config.BroadcastURI, nil
 }
+
+// GetTransactionStatus returns the status of a tx in the pool along with the
+// history of its status changes, if the tx is not in the pool but it was
+// mined, the status is mined
+func (z *ZKEVMEndpoints) GetTransactionStatus(hash common.Hash) (interface{}, rpcError) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, rpcError) {
+		var blockNumber *argUint64
+		receipt, err := z.state.GetTransactionReceipt(ctx, hash, dbTx)
+		if err != nil && !errors.Is(err, state.ErrNotFound) {
+			return rpcErrorResponse(defaultErrorCode, "failed to load transaction receipt from state", err)
+		}
+		if receipt != nil {
+			number := argUint64(receipt.BlockNumber.Uint64())
+			blockNumber = &number
+		}
+
+		poolTx, err := z.pool.GetTxByHash(ctx, hash)
+		if errors.Is(err, pgpoolstorage.ErrNotFound) {
+			if blockNumber == nil {
+				return nil, nil
+			}
+			return rpcTxStatus{Hash: hash, Status: txStatusMined, BlockNumber: blockNumber, Events: []rpcTxEvent{}}, nil
+		} else if err != nil {
+			return rpcErrorResponse(defaultErrorCode, "failed to load transaction by hash from pool", err)
+		}
+
+		events, err := z.pool.GetTxEvents(ctx, hash)
+		if err != nil {
+			return rpcErrorResponse(defaultErrorCode, "failed to load transaction events from pool", err)
+		}
+
+		return rpcTxStatus{
+			Hash:        hash,
+			Status:      poolTx.Status.String(),
+			BlockNumber: blockNumber,
+			Events:      poolTxEventsToRPCTxEvents(events),
+		}, nil
+	})
+}
+
+// GetReorgs returns the reorgs of the state recorded since the given unix
+// time, oldest first
+func (z *ZKEVMEndpoints) GetReorgs(fromTime argUint64) (interface{}, rpcError) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, rpcError) {
+		reorgs, err := z.state.GetReorgs(ctx, time.Unix(int64(fromTime), 0), dbTx)
+		if err != nil {
+			return rpcErrorResponse(defaultErrorCode, "failed to load reorgs from state", err)
+		}
+		return reorgsToRPCReorgs(reorgs), nil
+	})
+}
@@ -10,12 +10,15 @@ import (
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/pool/pgpoolstorage"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1084,3 +1087,388 @@ func signTx(tx *types.Transaction, chainID uint64) *types.Transaction {
 	signedTx, _ := auth.Signer(auth.From, tx)
 	return signedTx
 }
+
+func TestGetTransactionStatus(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		Name           string
+		Hash           common.Hash
+		ExpectedResult *rpcTxStatus
+		ExpectedError  rpcError
+		SetupMocks     func(m *mocks, tc testCase)
+	}
+
+	receivedAt := time.Unix(1000, 0)
+	events := []pool.TxEvent{
+		{Status: pool.TxStatusPending, Timestamp: receivedAt},
+		{Status: pool.TxStatusWIP, Timestamp: receivedAt.Add(time.Second)},
+		{Status: pool.TxStatusInvalid, Reason: "OOCS", Timestamp: receivedAt.Add(2 * time.Second)},
+	}
+	blockNumber := argUint64(5)
+
+	testCases := []testCase{
+		{
+			Name: "tx in the pool with events",
+			Hash: common.HexToHash("0x1"),
+			ExpectedResult: &rpcTxStatus{
+				Hash:   common.HexToHash("0x1"),
+				Status: pool.TxStatusInvalid.String(),
+				Events: []rpcTxEvent{
+					{Status: "pending", Timestamp: 1000},
+					{Status: "wip", Timestamp: 1001},
+					{Status: "invalid", Reason: "OOCS", Timestamp: 1002},
+				},
+			},
+			SetupMocks: func(m *mocks, tc testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("GetTransactionReceipt", context.Background(), tc.Hash, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+				m.Pool.
+					On("GetTxByHash", context.Background(), tc.Hash).
+					Return(&pool.Transaction{Status: pool.TxStatusInvalid}, nil).
+					Once()
+				m.Pool.
+					On("GetTxEvents", context.Background(), tc.Hash).
+					Return(events, nil).
+					Once()
+			},
+		},
+		{
+			Name: "tx mined and no longer in the pool",
+			Hash: common.HexToHash("0x2"),
+			ExpectedResult: &rpcTxStatus{
+				Hash:        common.HexToHash("0x2"),
+				Status:      txStatusMined,
+				BlockNumber: &blockNumber,
+				Events:      []rpcTxEvent{},
+			},
+			SetupMocks: func(m *mocks, tc testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("GetTransactionReceipt", context.Background(), tc.Hash, m.DbTx).
+					Return(&types.Receipt{BlockNumber: big.NewInt(5)}, nil).
+					Once()
+				m.Pool.
+					On("GetTxByHash", context.Background(), tc.Hash).
+					Return(nil, pgpoolstorage.ErrNotFound).
+					Once()
+			},
+		},
+		{
+			Name:           "tx not found",
+			Hash:           common.HexToHash("0x3"),
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocks, tc testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("GetTransactionReceipt", context.Background(), tc.Hash, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+				m.Pool.
+					On("GetTxByHash", context.Background(), tc.Hash).
+					Return(nil, pgpoolstorage.ErrNotFound).
+					Once()
+			},
+		},
+		{
+			Name:          "failed to get tx events",
+			Hash:          common.HexToHash("0x4"),
+			ExpectedError: newRPCError(defaultErrorCode, "failed to load transaction events from pool"),
+			SetupMocks: func(m *mocks, tc testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("GetTransactionReceipt", context.Background(), tc.Hash, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+				m.Pool.
+					On("GetTxByHash", context.Background(), tc.Hash).
+					Return(&pool.Transaction{Status: pool.TxStatusPending}, nil).
+					Once()
+				m.Pool.
+					On("GetTxEvents", context.Background(), tc.Hash).
+					Return(nil, errors.New("failed to get events")).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m, tc)
+
+			res, err := s.JSONRPCCall("zkevm_getTransactionStatus", tc.Hash.String())
+			require.NoError(t, err)
+
+			if tc.ExpectedResult != nil {
+				var result rpcTxStatus
+				err = json.Unmarshal(res.Result, &result)
+				require.NoError(t, err)
+				assert.Equal(t, *tc.ExpectedResult, result)
+			} else if tc.ExpectedError == nil {
+				assert.Equal(t, "null", string(res.Result))
+			}
+
+			if res.Error != nil || tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
+func TestEstimateCounters(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		Name           string
+		ExpectedResult *rpcCountersEstimation
+		ExpectedError  rpcError
+		SetupMocks     func(m *mocks)
+	}
+
+	to := common.HexToAddress("0x2")
+	blockNumber := uint64(1)
+
+	testCases := []testCase{
+		{
+			Name: "estimate counters successfully",
+			ExpectedResult: &rpcCountersEstimation{
+				Counters: []rpcZKCounter{
+					{Name: state.CounterSteps, Used: 100, Limit: 400, Percentage: 25},
+				},
+				OutOfCounters: state.CounterSteps,
+				Error:         "out of counters at node level (steps)",
+			},
+			SetupMocks: func(m *mocks) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("EstimateCounters", context.Background(), mock.IsType(&types.Transaction{}), common.HexToAddress(s.Config.DefaultSenderAddress), &blockNumber, m.DbTx).
+					Return(&state.CountersEstimation{
+						Usage:         []state.ZKCounterUsage{{Name: state.CounterSteps, Used: 100, Limit: 400, Percentage: 25}},
+						OutOfCounters: state.CounterSteps,
+						Err:           errors.New("out of counters at node level (steps)"),
+					}, nil).
+					Once()
+			},
+		},
+		{
+			Name:          "failed to estimate counters",
+			ExpectedError: newRPCError(defaultErrorCode, "failed to estimate the counters of the transaction"),
+			SetupMocks: func(m *mocks) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("EstimateCounters", context.Background(), mock.IsType(&types.Transaction{}), common.HexToAddress(s.Config.DefaultSenderAddress), &blockNumber, m.DbTx).
+					Return(nil, errors.New("failed to execute")).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_estimateCounters", map[string]interface{}{"to": to.String()}, "0x1")
+			require.NoError(t, err)
+
+			if tc.ExpectedResult != nil {
+				require.Nil(t, res.Error)
+				var result rpcCountersEstimation
+				err = json.Unmarshal(res.Result, &result)
+				require.NoError(t, err)
+				assert.Equal(t, *tc.ExpectedResult, result)
+			}
+
+			if res.Error != nil || tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
+func TestGetTransactionZKCounters(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		Name           string
+		ExpectedResult []rpcZKCounter
+		ExpectedError  rpcError
+		SetupMocks     func(m *mocks)
+	}
+
+	hash := common.HexToHash("0x1")
+
+	testCases := []testCase{
+		{
+			Name: "get counters successfully",
+			ExpectedResult: []rpcZKCounter{
+				{Name: state.CounterKeccakHashes, Used: 10, Limit: 40, Percentage: 25},
+				{Name: state.CounterSteps, Used: 100, Limit: 400, Percentage: 25},
+			},
+			SetupMocks: func(m *mocks) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("GetTransactionZKCounters", context.Background(), hash, m.DbTx).
+					Return([]state.ZKCounterUsage{
+						{Name: state.CounterKeccakHashes, Used: 10, Limit: 40, Percentage: 25},
+						{Name: state.CounterSteps, Used: 100, Limit: 400, Percentage: 25},
+					}, nil).
+					Once()
+			},
+		},
+		{
+			Name:           "counters not found",
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocks) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("GetTransactionZKCounters", context.Background(), hash, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+			},
+		},
+		{
+			Name:          "failed to get counters",
+			ExpectedError: newRPCError(defaultErrorCode, "failed to load the transaction zk counters from state"),
+			SetupMocks: func(m *mocks) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("GetTransactionZKCounters", context.Background(), hash, m.DbTx).
+					Return(nil, errors.New("failed to load")).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getTransactionZKCounters", hash.String())
+			require.NoError(t, err)
+
+			if tc.ExpectedError == nil {
+				require.Nil(t, res.Error)
+				var result []rpcZKCounter
+				err = json.Unmarshal(res.Result, &result)
+				require.NoError(t, err)
+				assert.Equal(t, tc.ExpectedResult, result)
+			}
+
+			if res.Error != nil || tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
+func TestGetReorgs(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		Name           string
+		ExpectedResult []rpcReorg
+		ExpectedError  rpcError
+		SetupMocks     func(m *mocks)
+	}
+
+	fromTime := time.Unix(1000, 0)
+	droppedTx := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil)
+
+	testCases := []testCase{
+		{
+			Name: "reorgs found",
+			ExpectedResult: []rpcReorg{
+				{
+					ID:               1,
+					Kind:             "trusted",
+					Depth:            2,
+					FirstBlockNumber: 10,
+					FirstBatchNumber: 5,
+					OldHash:          common.HexToHash("0x1"),
+					NewHash:          common.HexToHash("0x2"),
+					Reason:           "Different field StateRoot",
+					DroppedTxs:       []common.Hash{droppedTx.Hash()},
+					Timestamp:        1001,
+				},
+			},
+			SetupMocks: func(m *mocks) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("GetReorgs", context.Background(), fromTime, m.DbTx).
+					Return([]state.Reorg{
+						{
+							ID:               1,
+							Kind:             state.ReorgKindTrusted,
+							Depth:            2,
+							FirstBlockNumber: 10,
+							FirstBatchNumber: 5,
+							OldHash:          common.HexToHash("0x1"),
+							NewHash:          common.HexToHash("0x2"),
+							Reason:           "Different field StateRoot",
+							DroppedTxs:       []types.Transaction{*droppedTx},
+							CreatedAt:        time.Unix(1001, 0),
+						},
+					}, nil).
+					Once()
+			},
+		},
+		{
+			Name:          "failed to get reorgs",
+			ExpectedError: newRPCError(defaultErrorCode, "failed to load reorgs from state"),
+			SetupMocks: func(m *mocks) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.
+					On("GetReorgs", context.Background(), fromTime, m.DbTx).
+					Return(nil, errors.New("failed to query")).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getReorgs", hex.EncodeUint64(uint64(fromTime.Unix())))
+			require.NoError(t, err)
+
+			if tc.ExpectedResult != nil {
+				require.Nil(t, res.Error)
+				var result []rpcReorg
+				err = json.Unmarshal(res.Result, &result)
+				require.NoError(t, err)
+				assert.Equal(t, tc.ExpectedResult, result)
+			}
+
+			if res.Error != nil || tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
@@ -8,6 +8,9 @@ const (
 	notFoundErrorCode       = -32601
 	invalidParamsErrorCode  = -32602
 	parserErrorCode         = -32700
+	// prunedHistoryErrorCode is the code used by geth for the history removed
+	// from the node
+	prunedHistoryErrorCode = 4444
 )
 
 type rpcError interface {
@@ -93,6 +93,29 @@ func (_m *stateMock) DebugTransaction(ctx context.Context, transactionHash commo
 	return r0, r1
 }
 
+// EstimateCounters provides a mock function with given fields: ctx, tx, senderAddress, l2BlockNumber, dbTx
+func (_m *stateMock) EstimateCounters(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (*state.CountersEstimation, error) {
+	ret := _m.Called(ctx, tx, senderAddress, l2BlockNumber, dbTx)
+
+	var r0 *state.CountersEstimation
+	if rf, ok := ret.Get(0).(func(context.Context, *types.Transaction, common.Address, *uint64, pgx.Tx) *state.CountersEstimation); ok {
+		r0 = rf(ctx, tx, senderAddress, l2BlockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.CountersEstimation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *types.Transaction, common.Address, *uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, tx, senderAddress, l2BlockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EstimateGas provides a mock function with given fields: transaction, senderAddress, l2BlockNumber, dbTx
 func (_m *stateMock) EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (uint64, error) {
 	ret := _m.Called(transaction, senderAddress, l2BlockNumber, dbTx)
@@ -491,6 +514,29 @@ func (_m *stateMock) GetNonce(ctx context.Context, address common.Address, block
 	return r0, r1
 }
 
+// GetReorgs provides a mock function with given fields: ctx, fromTime, dbTx
+func (_m *stateMock) GetReorgs(ctx context.Context, fromTime time.Time, dbTx pgx.Tx) ([]state.Reorg, error) {
+	ret := _m.Called(ctx, fromTime, dbTx)
+
+	var r0 []state.Reorg
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, pgx.Tx) []state.Reorg); ok {
+		r0 = rf(ctx, fromTime, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.Reorg)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, pgx.Tx) error); ok {
+		r1 = rf(ctx, fromTime, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStorageAt provides a mock function with given fields: ctx, address, position, blockNumber, dbTx
 func (_m *stateMock) GetStorageAt(ctx context.Context, address common.Address, position *big.Int, blockNumber uint64, dbTx pgx.Tx) (*big.Int, error) {
 	ret := _m.Called(ctx, address, position, blockNumber, dbTx)
@@ -627,6 +673,29 @@ func (_m *stateMock) GetTransactionReceipt(ctx context.Context, transactionHash
 	return r0, r1
 }
 
+// GetTransactionZKCounters provides a mock function with given fields: ctx, txHash, dbTx
+func (_m *stateMock) GetTransactionZKCounters(ctx context.Context, txHash common.Hash, dbTx pgx.Tx) ([]state.ZKCounterUsage, error) {
+	ret := _m.Called(ctx, txHash, dbTx)
+
+	var r0 []state.ZKCounterUsage
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) []state.ZKCounterUsage); ok {
+		r0 = rf(ctx, txHash, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.ZKCounterUsage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, txHash, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionsByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *stateMock) GetTransactionsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]types.Transaction, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/didip/tollbooth/v6"
 	"github.com/gorilla/websocket"
 )
@@ -65,7 +66,7 @@ func NewServer(
 	}
 
 	if _, ok := apis[APIZKEVM]; ok {
-		zkEVMEndpoints := &ZKEVMEndpoints{state: s, config: cfg}
+		zkEVMEndpoints := &ZKEVMEndpoints{state: s, config: cfg, pool: p, txMan: dbTxManager{readReplicas: cfg.ReadReplicas}}
 		handler.registerService(APIZKEVM, zkEVMEndpoints)
 	}
 
@@ -75,7 +76,7 @@ func NewServer(
 	}
 
 	if _, ok := apis[APIDebug]; ok {
-		debugEndpoints := &DebugEndpoints{state: s}
+		debugEndpoints := &DebugEndpoints{state: s, txMan: dbTxManager{readReplicas: cfg.ReadReplicas}}
 		handler.registerService(APIDebug, debugEndpoints)
 	}
 
@@ -392,6 +393,9 @@ func handleError(w http.ResponseWriter, err error) {
 }
 
 func rpcErrorResponse(code int, errorMessage string, err error) (interface{}, rpcError) {
+	if errors.Is(err, state.ErrPrunedHistory) {
+		return nil, newRPCError(prunedHistoryErrorCode, "pruned history unavailable, the requested data is older than the retention window of the node")
+	}
 	if err != nil {
 		log.Errorf("%v:%v", errorMessage, err.Error())
 	} else {
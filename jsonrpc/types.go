@@ -8,6 +8,7 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -467,3 +468,104 @@ func logToRPCLog(l types.Log) rpcLog {
 		Removed:     l.Removed,
 	}
 }
+
+// txStatusMined is the status of the txs that are not in the pool but were mined
+const txStatusMined = "mined"
+
+type rpcTxEvent struct {
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason"`
+	Timestamp argUint64 `json:"timestamp"`
+}
+
+type rpcTxStatus struct {
+	Hash        common.Hash  `json:"hash"`
+	Status      string       `json:"status"`
+	BlockNumber *argUint64   `json:"blockNumber"`
+	Events      []rpcTxEvent `json:"events"`
+}
+
+func poolTxEventsToRPCTxEvents(events []pool.TxEvent) []rpcTxEvent {
+	res := make([]rpcTxEvent, 0, len(events))
+	for _, event := range events {
+		res = append(res, rpcTxEvent{
+			Status:    event.Status.String(),
+			Reason:    event.Reason,
+			Timestamp: argUint64(event.Timestamp.Unix()),
+		})
+	}
+	return res
+}
+
+type rpcZKCounter struct {
+	Name       string    `json:"name"`
+	Used       argUint64 `json:"used"`
+	Limit      argUint64 `json:"limit"`
+	Percentage float64   `json:"percentage"`
+}
+
+type rpcCountersEstimation struct {
+	Counters      []rpcZKCounter `json:"counters"`
+	OutOfCounters string         `json:"outOfCounters,omitempty"`
+	Error         string         `json:"error,omitempty"`
+}
+
+func zkCounterUsageToRPCZKCounters(usage []state.ZKCounterUsage) []rpcZKCounter {
+	res := make([]rpcZKCounter, 0, len(usage))
+	for _, u := range usage {
+		res = append(res, rpcZKCounter{
+			Name:       u.Name,
+			Used:       argUint64(u.Used),
+			Limit:      argUint64(u.Limit),
+			Percentage: u.Percentage,
+		})
+	}
+	return res
+}
+
+func countersEstimationToRPCCountersEstimation(estimation *state.CountersEstimation) rpcCountersEstimation {
+	res := rpcCountersEstimation{
+		Counters:      zkCounterUsageToRPCZKCounters(estimation.Usage),
+		OutOfCounters: estimation.OutOfCounters,
+	}
+	if estimation.Err != nil {
+		res.Error = estimation.Err.Error()
+	}
+	return res
+}
+
+type rpcReorg struct {
+	ID               argUint64     `json:"id"`
+	Kind             string        `json:"kind"`
+	Depth            argUint64     `json:"depth"`
+	FirstBlockNumber argUint64     `json:"firstBlockNumber"`
+	FirstBatchNumber argUint64     `json:"firstBatchNumber"`
+	OldHash          common.Hash   `json:"oldHash"`
+	NewHash          common.Hash   `json:"newHash"`
+	Reason           string        `json:"reason"`
+	DroppedTxs       []common.Hash `json:"droppedTransactions"`
+	Timestamp        argUint64     `json:"timestamp"`
+}
+
+func reorgsToRPCReorgs(reorgs []state.Reorg) []rpcReorg {
+	res := make([]rpcReorg, 0, len(reorgs))
+	for _, reorg := range reorgs {
+		droppedTxs := make([]common.Hash, 0, len(reorg.DroppedTxs))
+		for _, tx := range reorg.DroppedTxs {
+			droppedTxs = append(droppedTxs, tx.Hash())
+		}
+		res = append(res, rpcReorg{
+			ID:               argUint64(reorg.ID),
+			Kind:             string(reorg.Kind),
+			Depth:            argUint64(reorg.Depth),
+			FirstBlockNumber: argUint64(reorg.FirstBlockNumber),
+			FirstBatchNumber: argUint64(reorg.FirstBatchNumber),
+			OldHash:          reorg.OldHash,
+			NewHash:          reorg.NewHash,
+			Reason:           reorg.Reason,
+			DroppedTxs:       droppedTxs,
+			Timestamp:        argUint64(reorg.CreatedAt.Unix()),
+		})
+	}
+	return res
+}
@@ -1,7 +1,12 @@
 package merkletree
 
+import "github.com/0xPolygonHermez/zkevm-node/db"
+
 // Config represents the configuration of the merkletree server.
 type Config struct {
 	// URI is the server URI.
 	URI string `mapstructure:"URI"`
+	// HashDB is the database where the server keeps the nodes of the tree, it is
+	// only accessed to export and import the tree in the state snapshots
+	HashDB db.Config `mapstructure:"HashDB"`
 }
@@ -0,0 +1,99 @@
+package merkletree
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// LocalStateDBClient implements pb.StateDBServiceClient on top of a MemoryTree,
+// serving the StateDB requests in-process instead of through gRPC
+type LocalStateDBClient struct {
+	tree *MemoryTree
+}
+
+// NewLocalStateDBClient creates a new LocalStateDBClient backed by the given tree
+func NewLocalStateDBClient(tree *MemoryTree) *LocalStateDBClient {
+	return &LocalStateDBClient{tree: tree}
+}
+
+// Set sets the value of a key in the tree.
+func (c *LocalStateDBClient) Set(ctx context.Context, in *pb.SetRequest, opts ...grpc.CallOption) (*pb.SetResponse, error) {
+	value := big.NewInt(0)
+	if v := strings.TrimPrefix(in.Value, "0x"); v != "" {
+		var ok bool
+		value, ok = new(big.Int).SetString(v, hex.Base)
+		if !ok {
+			return nil, fmt.Errorf("invalid value %q", in.Value)
+		}
+	}
+
+	oldRoot, key := feaToH4(in.OldRoot), feaToH4(in.Key)
+	oldValue, err := c.tree.Get(oldRoot, key)
+	if err != nil {
+		return nil, err
+	}
+	newRoot, err := c.tree.Set(oldRoot, key, value)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetResponse{
+		OldRoot:  in.OldRoot,
+		NewRoot:  h4ToFea(newRoot),
+		Key:      in.Key,
+		OldValue: oldValue.Text(hex.Base),
+		NewValue: value.Text(hex.Base),
+		Result:   &pb.ResultCode{Code: pb.ResultCode_CODE_SUCCESS},
+	}, nil
+}
+
+// Get gets the value of a key in the tree.
+func (c *LocalStateDBClient) Get(ctx context.Context, in *pb.GetRequest, opts ...grpc.CallOption) (*pb.GetResponse, error) {
+	value, err := c.tree.Get(feaToH4(in.Root), feaToH4(in.Key))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetResponse{
+		Root:   in.Root,
+		Key:    in.Key,
+		Value:  value.Text(hex.Base),
+		Result: &pb.ResultCode{Code: pb.ResultCode_CODE_SUCCESS},
+	}, nil
+}
+
+// SetProgram stores a smart contract bytecode by its hash.
+func (c *LocalStateDBClient) SetProgram(ctx context.Context, in *pb.SetProgramRequest, opts ...grpc.CallOption) (*pb.SetProgramResponse, error) {
+	c.tree.SetProgram(feaToH4(in.Key), in.Data)
+	return &pb.SetProgramResponse{Result: &pb.ResultCode{Code: pb.ResultCode_CODE_SUCCESS}}, nil
+}
+
+// GetProgram gets a smart contract bytecode by its hash.
+func (c *LocalStateDBClient) GetProgram(ctx context.Context, in *pb.GetProgramRequest, opts ...grpc.CallOption) (*pb.GetProgramResponse, error) {
+	data, err := c.tree.GetProgram(feaToH4(in.Key))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetProgramResponse{Data: data, Result: &pb.ResultCode{Code: pb.ResultCode_CODE_SUCCESS}}, nil
+}
+
+// Flush is a no-op, the tree is kept in memory.
+func (c *LocalStateDBClient) Flush(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func feaToH4(fea *pb.Fea) [4]uint64 {
+	if fea == nil {
+		return zeroH4
+	}
+	return [4]uint64{fea.Fe0, fea.Fe1, fea.Fe2, fea.Fe3}
+}
+
+func h4ToFea(h4 [4]uint64) *pb.Fea {
+	return &pb.Fea{Fe0: h4[0], Fe1: h4[1], Fe2: h4[2], Fe3: h4[3]}
+}
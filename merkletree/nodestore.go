@@ -0,0 +1,144 @@
+package merkletree
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	poseidon "github.com/iden3/go-iden3-crypto/goldenposeidon"
+)
+
+// NodeStore gives raw access to the nodes and the programs of the tree by
+// their hash, it is used to copy the tree of a state root between databases
+type NodeStore interface {
+	GetNode(ctx context.Context, hash [4]uint64) ([12]uint64, error)
+	SetNode(ctx context.Context, hash [4]uint64, data [12]uint64) error
+	GetProgram(ctx context.Context, hash [4]uint64) ([]byte, error)
+	SetProgram(ctx context.Context, hash [4]uint64, data []byte) error
+}
+
+// CheckNodeHash checks that the hash of a node matches its preimage
+func CheckNodeHash(hash [4]uint64, data [12]uint64) error {
+	var in [8]uint64
+	var capIn [4]uint64
+	copy(in[:], data[:8])
+	copy(capIn[:], data[8:])
+	h, err := poseidon.Hash(in, capIn)
+	if err != nil {
+		return err
+	}
+	if h != hash {
+		return fmt.Errorf("invalid node %s, its data hashes to %s", H4ToString(hash[:]), H4ToString(h[:]))
+	}
+	return nil
+}
+
+// WalkTree visits all the nodes of the tree with the given root, including the
+// value nodes of the leaves, and the programs whose hash is the value of a
+// leaf. The hash of every node is checked
+func WalkTree(ctx context.Context, store NodeStore, root [4]uint64, onNode func(hash [4]uint64, data [12]uint64) error, onProgram func(hash [4]uint64, data []byte) error) error {
+	programs := make(map[[4]uint64]struct{})
+	pending := [][4]uint64{}
+	if root != zeroH4 {
+		pending = append(pending, root)
+	}
+	for len(pending) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hash := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		data, err := store.GetNode(ctx, hash)
+		if err != nil {
+			return fmt.Errorf("error getting node %s: %w", H4ToString(hash[:]), err)
+		}
+		if err := CheckNodeHash(hash, data); err != nil {
+			return err
+		}
+		if err := onNode(hash, data); err != nil {
+			return err
+		}
+
+		n := node(data)
+		if !n.isLeaf() {
+			for side := uint64(0); side < 2; side++ {
+				if child := n.child(side); child != zeroH4 {
+					pending = append(pending, child)
+				}
+			}
+			continue
+		}
+
+		valH := n.child(1)
+		value, err := store.GetNode(ctx, valH)
+		if err != nil {
+			return fmt.Errorf("error getting value node %s: %w", H4ToString(valH[:]), err)
+		}
+		if err := CheckNodeHash(valH, value); err != nil {
+			return err
+		}
+		if err := onNode(valH, value); err != nil {
+			return err
+		}
+
+		// the leaves don't keep their type, so any value may be the hash of a
+		// program
+		var programH [4]uint64
+		copy(programH[:], scalarToh4(fea2scalar(value[:8])))
+		if _, ok := programs[programH]; ok {
+			continue
+		}
+		program, err := store.GetProgram(ctx, programH)
+		if errors.Is(err, ErrProgramNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("error getting program %s: %w", H4ToString(programH[:]), err)
+		}
+		programs[programH] = struct{}{}
+		if err := onProgram(programH, program); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetNode returns the preimage of the node with the given hash
+func (t *MemoryTree) GetNode(ctx context.Context, hash [4]uint64) ([12]uint64, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	n, ok := t.nodes[hash]
+	if !ok {
+		return [12]uint64{}, ErrNodeNotFound
+	}
+	return n, nil
+}
+
+// SetNode stores the preimage of the node with the given hash
+func (t *MemoryTree) SetNode(ctx context.Context, hash [4]uint64, data [12]uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nodes[hash] = data
+	return nil
+}
+
+// memoryNodeStore adapts the program accessors of the MemoryTree to NodeStore
+type memoryNodeStore struct {
+	*MemoryTree
+}
+
+// NewMemoryNodeStore returns the NodeStore of the MemoryTree
+func NewMemoryNodeStore(tree *MemoryTree) NodeStore {
+	return &memoryNodeStore{MemoryTree: tree}
+}
+
+// GetProgram returns the bytecode stored by its hash
+func (s *memoryNodeStore) GetProgram(ctx context.Context, hash [4]uint64) ([]byte, error) {
+	return s.MemoryTree.GetProgram(hash)
+}
+
+// SetProgram stores the bytecode by its hash
+func (s *memoryNodeStore) SetProgram(ctx context.Context, hash [4]uint64, data []byte) error {
+	s.MemoryTree.SetProgram(hash, data)
+	return nil
+}
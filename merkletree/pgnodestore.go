@@ -0,0 +1,79 @@
+package merkletree
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	getNodeSQL    = "SELECT data FROM state.nodes WHERE hash = $1"
+	setNodeSQL    = "INSERT INTO state.nodes (hash, data) VALUES ($1, $2) ON CONFLICT (hash) DO NOTHING"
+	getProgramSQL = "SELECT data FROM state.program WHERE hash = $1"
+	setProgramSQL = "INSERT INTO state.program (hash, data) VALUES ($1, $2) ON CONFLICT (hash) DO NOTHING"
+
+	// nodeDataLen is the length of the preimage of a node stored by the
+	// StateDB service, 12 elements of 8 bytes
+	nodeDataLen = 96
+)
+
+// PostgresNodeStore implements NodeStore on top of the database of the
+// StateDB service, where each hash and each element of a preimage are stored
+// as big endian bytes
+type PostgresNodeStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresNodeStore creates a new PostgresNodeStore
+func NewPostgresNodeStore(db *pgxpool.Pool) *PostgresNodeStore {
+	return &PostgresNodeStore{db: db}
+}
+
+// GetNode returns the preimage of the node with the given hash
+func (s *PostgresNodeStore) GetNode(ctx context.Context, hash [4]uint64) ([12]uint64, error) {
+	var node [12]uint64
+	var data []byte
+	err := s.db.QueryRow(ctx, getNodeSQL, h4ToFilledByteSlice(hash[:])).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return node, ErrNodeNotFound
+	} else if err != nil {
+		return node, err
+	}
+	if len(data) != nodeDataLen {
+		return node, fmt.Errorf("invalid length %d of node %s", len(data), H4ToString(hash[:]))
+	}
+	for i := range node {
+		node[i] = binary.BigEndian.Uint64(data[i*8 : i*8+8]) //nolint:gomnd
+	}
+	return node, nil
+}
+
+// SetNode stores the preimage of the node with the given hash
+func (s *PostgresNodeStore) SetNode(ctx context.Context, hash [4]uint64, node [12]uint64) error {
+	data := make([]byte, nodeDataLen)
+	for i, e := range node {
+		binary.BigEndian.PutUint64(data[i*8:], e) //nolint:gomnd
+	}
+	_, err := s.db.Exec(ctx, setNodeSQL, h4ToFilledByteSlice(hash[:]), data)
+	return err
+}
+
+// GetProgram returns the bytecode stored by its hash
+func (s *PostgresNodeStore) GetProgram(ctx context.Context, hash [4]uint64) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRow(ctx, getProgramSQL, h4ToFilledByteSlice(hash[:])).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProgramNotFound
+	}
+	return data, err
+}
+
+// SetProgram stores the bytecode by its hash
+func (s *PostgresNodeStore) SetProgram(ctx context.Context, hash [4]uint64, data []byte) error {
+	_, err := s.db.Exec(ctx, setProgramSQL, h4ToFilledByteSlice(hash[:]), data)
+	return err
+}
@@ -0,0 +1,341 @@
+package merkletree
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	poseidon "github.com/iden3/go-iden3-crypto/goldenposeidon"
+)
+
+// maxLevels is the max depth of the sparse merkle tree
+const maxLevels = 256
+
+// node is the preimage of a node hash, 8 input elements followed by the 4
+// capacity elements
+type node [12]uint64
+
+var (
+	// ErrNodeNotFound is returned when a node of the tree is not stored
+	ErrNodeNotFound = errors.New("merkletree node not found")
+	// ErrProgramNotFound is returned when the bytecode for a hash is not stored
+	ErrProgramNotFound = errors.New("merkletree program not found")
+
+	zeroH4    = [4]uint64{}
+	leafCapIn = [4]uint64{1, 0, 0, 0}
+)
+
+// isLeaf checks if the node is a leaf, leaves are hashed with a capacity of [1, 0, 0, 0]
+func (n node) isLeaf() bool {
+	return n[8] == 1
+}
+
+// child returns the hash of the child in the given side of an intermediate node
+func (n node) child(side uint64) [4]uint64 {
+	return [4]uint64{n[side*4], n[side*4+1], n[side*4+2], n[side*4+3]}
+}
+
+func (n *node) setChild(side uint64, h [4]uint64) {
+	copy(n[side*4:side*4+4], h[:])
+}
+
+// MemoryTree is an in-memory implementation of the sparse merkle tree kept by
+// the StateDB service, it computes the same roots so it can replace the
+// service for tests and nodes not needing to persist the tree
+type MemoryTree struct {
+	mu       sync.RWMutex
+	nodes    map[[4]uint64]node
+	programs map[[4]uint64][]byte
+}
+
+// NewMemoryTree creates an empty MemoryTree
+func NewMemoryTree() *MemoryTree {
+	return &MemoryTree{
+		nodes:    make(map[[4]uint64]node),
+		programs: make(map[[4]uint64][]byte),
+	}
+}
+
+// Get returns the value stored for the key in the tree with the given root,
+// zero is returned when the key is not in the tree
+func (t *MemoryTree) Get(root, key [4]uint64) (*big.Int, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	keys := splitKey(key)
+	r := root
+	for level := 0; r != zeroH4 && level < maxLevels; level++ {
+		n, ok := t.nodes[r]
+		if !ok {
+			return nil, ErrNodeNotFound
+		}
+		if n.isLeaf() {
+			foundKey := joinKey(keys[:level], [4]uint64{n[0], n[1], n[2], n[3]})
+			if foundKey != key {
+				return big.NewInt(0), nil
+			}
+			return t.value(n.child(1))
+		}
+		r = n.child(keys[level])
+	}
+	return big.NewInt(0), nil
+}
+
+// Set stores the value for the key in the tree with the given root, returning
+// the new root. Setting a zero value removes the key from the tree
+func (t *MemoryTree) Set(oldRoot, key [4]uint64, value *big.Int) ([4]uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := splitKey(key)
+	var (
+		siblings  []node
+		accKey    []uint64
+		foundKey  *[4]uint64
+		foundValH [4]uint64
+		level     int
+	)
+	newRoot := oldRoot
+
+	// walk down the tree until finding a leaf or an empty node
+	for r := oldRoot; r != zeroH4 && foundKey == nil; {
+		n, ok := t.nodes[r]
+		if !ok {
+			return zeroH4, ErrNodeNotFound
+		}
+		siblings = append(siblings, n)
+		if n.isLeaf() {
+			k := joinKey(accKey, [4]uint64{n[0], n[1], n[2], n[3]})
+			foundKey = &k
+			foundValH = n.child(1)
+		} else {
+			r = n.child(keys[level])
+			accKey = append(accKey, keys[level])
+			level++
+		}
+	}
+	level--
+	if len(accKey) > 0 {
+		accKey = accKey[:len(accKey)-1]
+	}
+
+	if value.Sign() != 0 {
+		valH, err := t.hashSave(scalar2fea8(value), zeroH4)
+		if err != nil {
+			return zeroH4, err
+		}
+		var leafH [4]uint64
+		switch {
+		case foundKey != nil && *foundKey == key:
+			// update the value of an existing leaf
+			leafH, err = t.hashLeaf(removeKeyBits(key, level+1), valH)
+			if err != nil {
+				return zeroH4, err
+			}
+		case foundKey != nil:
+			// insert the key pushing down the leaf found in its path
+			level2 := level + 1
+			foundKeys := splitKey(*foundKey)
+			for level2 < maxLevels-1 && keys[level2] == foundKeys[level2] {
+				level2++
+			}
+			oldLeafH, err := t.hashLeaf(removeKeyBits(*foundKey, level2+1), foundValH)
+			if err != nil {
+				return zeroH4, err
+			}
+			newLeafH, err := t.hashLeaf(removeKeyBits(key, level2+1), valH)
+			if err != nil {
+				return zeroH4, err
+			}
+			var n node
+			n.setChild(keys[level2], newLeafH)
+			n.setChild(foundKeys[level2], oldLeafH)
+			leafH, err = t.hashNode(n)
+			if err != nil {
+				return zeroH4, err
+			}
+			for level2--; level2 != level; level2-- {
+				n = node{}
+				n.setChild(keys[level2], leafH)
+				leafH, err = t.hashNode(n)
+				if err != nil {
+					return zeroH4, err
+				}
+			}
+		default:
+			// insert the key in an empty node
+			leafH, err = t.hashLeaf(removeKeyBits(key, level+1), valH)
+			if err != nil {
+				return zeroH4, err
+			}
+		}
+		if level >= 0 {
+			siblings[level].setChild(keys[level], leafH)
+		} else {
+			newRoot = leafH
+		}
+	} else if foundKey != nil && *foundKey == key {
+		// delete the leaf, moving up its sibling when it is a leaf left alone
+		if level >= 0 {
+			siblings[level].setChild(keys[level], zeroH4)
+			uKey := uniqueSibling(siblings[level])
+			if uKey >= 0 {
+				sibling, ok := t.nodes[siblings[level].child(uint64(uKey))]
+				if !ok {
+					return zeroH4, ErrNodeNotFound
+				}
+				if sibling.isLeaf() {
+					valH := sibling.child(1)
+					insKey := joinKey(append(accKey[:level:level], uint64(uKey)), [4]uint64{sibling[0], sibling[1], sibling[2], sibling[3]})
+					for uKey >= 0 && level >= 0 {
+						level--
+						if level >= 0 {
+							uKey = uniqueSibling(siblings[level])
+						}
+					}
+					leafH, err := t.hashLeaf(removeKeyBits(insKey, level+1), valH)
+					if err != nil {
+						return zeroH4, err
+					}
+					if level >= 0 {
+						siblings[level].setChild(keys[level], leafH)
+					} else {
+						newRoot = leafH
+					}
+				}
+			}
+		} else {
+			newRoot = zeroH4
+		}
+	} else {
+		// setting a zero value for a missing key doesn't change the tree
+		return oldRoot, nil
+	}
+
+	// hash the modified path up to the root
+	for ; level >= 0; level-- {
+		h, err := t.hashNode(siblings[level])
+		if err != nil {
+			return zeroH4, err
+		}
+		newRoot = h
+		if level > 0 {
+			siblings[level-1].setChild(keys[level-1], h)
+		}
+	}
+	return newRoot, nil
+}
+
+// SetProgram stores the bytecode by its hash
+func (t *MemoryTree) SetProgram(key [4]uint64, data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.programs[key] = append([]byte{}, data...)
+}
+
+// GetProgram returns the bytecode stored by its hash
+func (t *MemoryTree) GetProgram(key [4]uint64) ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	data, ok := t.programs[key]
+	if !ok {
+		return nil, ErrProgramNotFound
+	}
+	return data, nil
+}
+
+// value returns the value whose hash is stored in a leaf
+func (t *MemoryTree) value(valH [4]uint64) (*big.Int, error) {
+	n, ok := t.nodes[valH]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+	return fea2scalar(n[:8]), nil
+}
+
+func (t *MemoryTree) hashLeaf(rKey, valH [4]uint64) ([4]uint64, error) {
+	return t.hashSave([8]uint64{rKey[0], rKey[1], rKey[2], rKey[3], valH[0], valH[1], valH[2], valH[3]}, leafCapIn)
+}
+
+func (t *MemoryTree) hashNode(n node) ([4]uint64, error) {
+	var in [8]uint64
+	copy(in[:], n[:8])
+	return t.hashSave(in, [4]uint64{n[8], n[9], n[10], n[11]})
+}
+
+// hashSave hashes the input storing the preimage by its hash
+func (t *MemoryTree) hashSave(in [8]uint64, capIn [4]uint64) ([4]uint64, error) {
+	h, err := poseidon.Hash(in, capIn)
+	if err != nil {
+		return zeroH4, err
+	}
+	var n node
+	copy(n[:8], in[:])
+	copy(n[8:], capIn[:])
+	t.nodes[h] = n
+	return h, nil
+}
+
+// splitKey returns the path of the key, the bit of each level is taken
+// alternately from each of the key elements
+func splitKey(key [4]uint64) [maxLevels]uint64 {
+	var res [maxLevels]uint64
+	for i := 0; i < maxLevels; i++ {
+		res[i] = (key[i%4] >> (i / 4)) & 1
+	}
+	return res
+}
+
+// removeKeyBits removes the bits of the first levels from the key, returning
+// the remaining key stored in the leaf
+func removeKeyBits(key [4]uint64, nBits int) [4]uint64 {
+	fullLevels := nBits / 4
+	var res [4]uint64
+	for i := 0; i < 4; i++ {
+		n := fullLevels
+		if fullLevels*4+i < nBits {
+			n++
+		}
+		res[i] = key[i] >> n
+	}
+	return res
+}
+
+// joinKey rebuilds a key from the bits of its path and the remaining key
+func joinKey(bits []uint64, rKey [4]uint64) [4]uint64 {
+	var n, accs [4]uint64
+	for i, bit := range bits {
+		if bit == 1 {
+			accs[i%4] |= 1 << n[i%4]
+		}
+		n[i%4]++
+	}
+	var res [4]uint64
+	for i := 0; i < 4; i++ {
+		res[i] = rKey[i]<<n[i] | accs[i]
+	}
+	return res
+}
+
+// uniqueSibling returns the side of the only non-empty child of the node, or -1
+// if the node has both children
+func uniqueSibling(n node) int {
+	found, side := 0, -1
+	for i := uint64(0); i < 2; i++ {
+		if n.child(i) != zeroH4 {
+			found++
+			side = int(i)
+		}
+	}
+	if found == 1 {
+		return side
+	}
+	return -1
+}
+
+// scalar2fea8 splits a 256 bits value into 8 elements of 32 bits
+func scalar2fea8(value *big.Int) [8]uint64 {
+	var res [8]uint64
+	copy(res[:], scalar2fea(value))
+	return res
+}
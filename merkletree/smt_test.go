@@ -0,0 +1,142 @@
+package merkletree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/encoding"
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testVectorRaw struct {
+	Keys         []string `json:"keys"`
+	Values       []string `json:"values"`
+	ExpectedRoot string   `json:"expectedRoot"`
+}
+
+type testVectorGenesis struct {
+	Addresses []struct {
+		Address  string            `json:"address"`
+		Balance  string            `json:"balance"`
+		Nonce    string            `json:"nonce"`
+		Bytecode string            `json:"bytecode"`
+		Storage  map[string]string `json:"storage"`
+	} `json:"addresses"`
+	ExpectedRoot string `json:"expectedRoot"`
+}
+
+func bigFromString(t *testing.T, s string, base int) *big.Int {
+	v, ok := new(big.Int).SetString(s, base)
+	require.True(t, ok, "invalid number %q", s)
+	return v
+}
+
+func TestMemoryTreeRaw(t *testing.T) {
+	data, err := os.ReadFile("test/vectors/src/merkle-tree/smt-raw.json")
+	require.NoError(t, err)
+	var testVectors []testVectorRaw
+	require.NoError(t, json.Unmarshal(data, &testVectors))
+
+	for ti, testVector := range testVectors {
+		t.Run(fmt.Sprintf("Test vector %d", ti), func(t *testing.T) {
+			tree := NewMemoryTree()
+			root := zeroH4
+			for i := range testVector.Keys {
+				var key [4]uint64
+				copy(key[:], scalarToh4(bigFromString(t, testVector.Keys[i], encoding.Base10)))
+				value := bigFromString(t, testVector.Values[i], encoding.Base10)
+				root, err = tree.Set(root, key, value)
+				require.NoError(t, err)
+
+				stored, err := tree.Get(root, key)
+				require.NoError(t, err)
+				assert.Equal(t, 0, value.Cmp(stored))
+			}
+			assert.Equal(t, testVector.ExpectedRoot, H4ToString(root[:]))
+		})
+	}
+}
+
+func TestMemoryTreeGenesis(t *testing.T) {
+	var testVectors []testVectorGenesis
+	for _, f := range []string{
+		"test/vectors/src/merkle-tree/smt-genesis.json",
+		"test/vectors/src/merkle-tree/smt-full-genesis.json",
+	} {
+		data, err := os.ReadFile(f)
+		require.NoError(t, err)
+		var tv []testVectorGenesis
+		require.NoError(t, json.Unmarshal(data, &tv))
+		testVectors = append(testVectors, tv...)
+	}
+
+	ctx := context.Background()
+	for ti, testVector := range testVectors {
+		t.Run(fmt.Sprintf("Test vector %d", ti), func(t *testing.T) {
+			tree := NewStateTree(NewLocalStateDBClient(NewMemoryTree()))
+			root := []byte{}
+			var err error
+			for _, acc := range testVector.Addresses {
+				address := common.HexToAddress(acc.Address)
+				root, _, err = tree.SetBalance(ctx, address, bigFromString(t, acc.Balance, encoding.Base10), root)
+				require.NoError(t, err)
+				root, _, err = tree.SetNonce(ctx, address, bigFromString(t, acc.Nonce, encoding.Base10), root)
+				require.NoError(t, err)
+				if acc.Bytecode != "" {
+					code, err := hex.DecodeHex(acc.Bytecode)
+					require.NoError(t, err)
+					root, _, err = tree.SetCode(ctx, address, code, root)
+					require.NoError(t, err)
+
+					stored, err := tree.GetCode(ctx, address, root)
+					require.NoError(t, err)
+					assert.Equal(t, code, stored)
+				}
+				for position, value := range acc.Storage {
+					root, _, err = tree.SetStorageAt(ctx, address, bigFromString(t, position, encoding.Base10), bigFromString(t, value, encoding.Base10), root)
+					require.NoError(t, err)
+				}
+			}
+			assert.Equal(t, testVector.ExpectedRoot, new(big.Int).SetBytes(root).String())
+
+			// the last value set for an address is the one kept
+			balances := make(map[common.Address]string)
+			for _, acc := range testVector.Addresses {
+				balances[common.HexToAddress(acc.Address)] = acc.Balance
+			}
+			for address, expected := range balances {
+				balance, err := tree.GetBalance(ctx, address, root)
+				require.NoError(t, err)
+				assert.Equal(t, expected, balance.String())
+			}
+		})
+	}
+}
+
+func TestMemoryTreeDelete(t *testing.T) {
+	tree := NewMemoryTree()
+	keys := [][4]uint64{{1, 0, 0, 0}, {2, 0, 0, 0}, {3, 0, 0, 0}, {17, 0, 0, 0}}
+
+	roots := []([4]uint64){zeroH4}
+	root := zeroH4
+	var err error
+	for _, key := range keys {
+		root, err = tree.Set(root, key, big.NewInt(int64(key[0])))
+		require.NoError(t, err)
+		roots = append(roots, root)
+	}
+
+	// removing the keys in reverse order goes back through the same roots
+	for i := len(keys) - 1; i >= 0; i-- {
+		root, err = tree.Set(root, keys[i], big.NewInt(0))
+		require.NoError(t, err)
+		assert.Equal(t, roots[i], root)
+	}
+}
@@ -8,14 +8,27 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/merkletree/pb"
+	"github.com/0xPolygonHermez/zkevm-node/state/cache"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 // StateTree provides methods to access and modify state in merkletree
 type StateTree struct {
 	grpcClient pb.StateDBServiceClient
+	cache      *cache.LRU
 }
 
+// valueCacheKey is the key of the values read from the tree, the value of a
+// key under a root never changes so they can be cached forever
+type valueCacheKey struct {
+	root [4]uint64
+	key  [4]uint64
+}
+
+// programCacheKey is the key of the programs read from the tree, which is
+// the hash of the program
+type programCacheKey [4]uint64
+
 // NewStateTree creates new StateTree.
 func NewStateTree(client pb.StateDBServiceClient) *StateTree {
 	return &StateTree{
@@ -23,6 +36,15 @@ func NewStateTree(client pb.StateDBServiceClient) *StateTree {
 	}
 }
 
+// NewStateTreeWithCache creates new StateTree that caches up to cacheSize
+// values and programs read from the tree.
+func NewStateTreeWithCache(client pb.StateDBServiceClient, cacheSize int) *StateTree {
+	return &StateTree{
+		grpcClient: client,
+		cache:      cache.NewLRU("smt", cacheSize),
+	}
+}
+
 // GetBalance returns balance.
 func (tree *StateTree) GetBalance(ctx context.Context, address common.Address, root []byte) (*big.Int, error) {
 	r := new(big.Int).SetBytes(root)
@@ -241,6 +263,19 @@ func (tree *StateTree) SetStorageAt(ctx context.Context, address common.Address,
 }
 
 func (tree *StateTree) get(ctx context.Context, root, key []uint64) (*Proof, error) {
+	var cacheKey valueCacheKey
+	if tree.cache != nil {
+		copy(cacheKey.root[:], root)
+		copy(cacheKey.key[:], key)
+		if value, ok := tree.cache.Get(cacheKey); ok {
+			return &Proof{
+				Root:  []uint64{root[0], root[1], root[2], root[3]},
+				Key:   key,
+				Value: append([]uint64{}, value.([]uint64)...),
+			}, nil
+		}
+	}
+
 	result, err := tree.grpcClient.Get(ctx, &pb.GetRequest{
 		Root: &pb.Fea{Fe0: root[0], Fe1: root[1], Fe2: root[2], Fe3: root[3]},
 		Key:  &pb.Fea{Fe0: key[0], Fe1: key[1], Fe2: key[2], Fe3: key[3]},
@@ -253,6 +288,9 @@ func (tree *StateTree) get(ctx context.Context, root, key []uint64) (*Proof, err
 	if err != nil {
 		return nil, err
 	}
+	if tree.cache != nil {
+		tree.cache.Add(cacheKey, append([]uint64{}, value...))
+	}
 	return &Proof{
 		Root:  []uint64{root[0], root[1], root[2], root[3]},
 		Key:   key,
@@ -261,12 +299,25 @@ func (tree *StateTree) get(ctx context.Context, root, key []uint64) (*Proof, err
 }
 
 func (tree *StateTree) getProgram(ctx context.Context, key []uint64) (*ProgramProof, error) {
+	var cacheKey programCacheKey
+	if tree.cache != nil {
+		copy(cacheKey[:], key)
+		if data, ok := tree.cache.Get(cacheKey); ok {
+			return &ProgramProof{
+				Data: data.([]byte),
+			}, nil
+		}
+	}
+
 	result, err := tree.grpcClient.GetProgram(ctx, &pb.GetProgramRequest{
 		Key: &pb.Fea{Fe0: key[0], Fe1: key[1], Fe2: key[2], Fe3: key[3]},
 	})
 	if err != nil {
 		return nil, err
 	}
+	if tree.cache != nil {
+		tree.cache.Add(cacheKey, result.Data)
+	}
 
 	return &ProgramProof{
 		Data: result.Data,
@@ -0,0 +1,57 @@
+package merkletree
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree/pb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// countingStateDBClient counts the reads sent to the StateDB
+type countingStateDBClient struct {
+	*LocalStateDBClient
+	gets, getPrograms int
+}
+
+func (c *countingStateDBClient) Get(ctx context.Context, in *pb.GetRequest, opts ...grpc.CallOption) (*pb.GetResponse, error) {
+	c.gets++
+	return c.LocalStateDBClient.Get(ctx, in, opts...)
+}
+
+func (c *countingStateDBClient) GetProgram(ctx context.Context, in *pb.GetProgramRequest, opts ...grpc.CallOption) (*pb.GetProgramResponse, error) {
+	c.getPrograms++
+	return c.LocalStateDBClient.GetProgram(ctx, in, opts...)
+}
+
+func TestStateTreeWithCache(t *testing.T) {
+	ctx := context.Background()
+	client := &countingStateDBClient{LocalStateDBClient: NewLocalStateDBClient(NewMemoryTree())}
+	tree := NewStateTreeWithCache(client, 10)
+	address := common.HexToAddress("0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D")
+
+	root1, _, err := tree.SetBalance(ctx, address, big.NewInt(1000), nil)
+	require.NoError(t, err)
+	root2, _, err := tree.SetBalance(ctx, address, big.NewInt(2000), root1)
+	require.NoError(t, err)
+	root2, _, err = tree.SetCode(ctx, address, common.Hex2Bytes("6080604052"), root2)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		balance, err := tree.GetBalance(ctx, address, root1)
+		require.NoError(t, err)
+		assert.Equal(t, "1000", balance.String())
+		balance, err = tree.GetBalance(ctx, address, root2)
+		require.NoError(t, err)
+		assert.Equal(t, "2000", balance.String())
+		code, err := tree.GetCode(ctx, address, root2)
+		require.NoError(t, err)
+		assert.Equal(t, common.Hex2Bytes("6080604052"), code)
+	}
+	assert.Equal(t, 3, client.gets)
+	assert.Equal(t, 1, client.getPrograms)
+}
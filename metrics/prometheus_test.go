@@ -15,6 +15,11 @@ var (
 	gaugeName             = "gaugeName"
 	gaugeOpts             = prometheus.GaugeOpts{Name: gaugeName}
 	gauge                 prometheus.Gauge
+	gaugeVecName          = "gaugeVecName"
+	gaugeVecLabelName     = "gaugeVecLabelName"
+	gaugeVecLabelVal      = "gaugeVecLabelVal"
+	gaugeVecOpts          = GaugeVecOpts{prometheus.GaugeOpts{Name: gaugeVecName}, []string{gaugeVecLabelName}}
+	gaugeVec              *prometheus.GaugeVec
 	counterName           = "counterName"
 	counterOpts           = prometheus.CounterOpts{Name: counterName}
 	counter               prometheus.Counter
@@ -39,6 +44,7 @@ var (
 func setup() {
 	Init()
 	gauge = prometheus.NewGauge(gaugeOpts)
+	gaugeVec = prometheus.NewGaugeVec(gaugeVecOpts.GaugeOpts, gaugeVecOpts.Labels)
 	counter = prometheus.NewCounter(counterOpts)
 	counterVec = prometheus.NewCounterVec(counterVecOpts.CounterOpts, counterVecOpts.Labels)
 	histogram = prometheus.NewHistogram(histogramOpts)
@@ -131,6 +137,50 @@ func TestUnregisterGauges(t *testing.T) {
 	assert.Len(t, gauges, 0)
 }
 
+func TestRegisterGaugeVecs(t *testing.T) {
+	setup()
+	defer cleanup()
+
+	RegisterGaugeVecs(gaugeVecOpts)
+
+	assert.Len(t, gaugeVecs, 1)
+}
+
+func TestGaugeVec(t *testing.T) {
+	setup()
+	defer cleanup()
+	gaugeVecs[gaugeVecName] = gaugeVec
+
+	actual, exist := GaugeVec(gaugeVecName)
+
+	assert.True(t, exist)
+	assert.Equal(t, gaugeVec, actual)
+}
+
+func TestGaugeVecSet(t *testing.T) {
+	setup()
+	defer cleanup()
+	gaugeVecs[gaugeVecName] = gaugeVec
+	expected := float64(2)
+
+	GaugeVecSet(gaugeVecName, gaugeVecLabelVal, expected)
+	currGaugeVec, err := gaugeVec.GetMetricWithLabelValues(gaugeVecLabelVal)
+	require.NoError(t, err)
+	actual := testutil.ToFloat64(currGaugeVec)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestUnregisterGaugeVecs(t *testing.T) {
+	setup()
+	defer cleanup()
+	RegisterGaugeVecs(gaugeVecOpts)
+
+	UnregisterGaugeVecs(gaugeVecName)
+
+	assert.Len(t, gaugeVecs, 0)
+}
+
 func TestRegisterCounters(t *testing.T) {
 	setup()
 	defer cleanup()
@@ -23,7 +23,7 @@ type storage interface {
 	IsTxPending(ctx context.Context, hash common.Hash) (bool, error)
 	SetGasPrice(ctx context.Context, gasPrice uint64) error
 	UpdateTxsStatus(ctx context.Context, hashes []string, newStatus TxStatus) error
-	UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus TxStatus) error
+	UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus TxStatus, reason string) error
 	GetTxs(ctx context.Context, filterStatus TxStatus, isClaims bool, minGasPrice, limit uint64) ([]*Transaction, error)
 	GetTxFromAddressFromByHash(ctx context.Context, hash common.Hash) (common.Address, uint64, error)
 	GetTxByHash(ctx context.Context, hash common.Hash) (*Transaction, error)
@@ -31,6 +31,8 @@ type storage interface {
 	GetTxZkCountersByHash(ctx context.Context, hash common.Hash) (*state.ZKCounters, error)
 	DeleteTransactionByHash(ctx context.Context, hash common.Hash) error
 	MarkWIPTxsAsPending(ctx context.Context) error
+	AddTxEvent(ctx context.Context, hash common.Hash, event TxEvent) error
+	GetTxEvents(ctx context.Context, hash common.Hash) ([]TxEvent, error)
 }
 
 type stateInterface interface {
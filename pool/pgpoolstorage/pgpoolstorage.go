@@ -58,6 +58,7 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 	gasPrice := tx.GasPrice().Uint64()
 	nonce := tx.Nonce()
 	sql := `
+		WITH tx AS (
 		INSERT INTO pool.transaction 
 		(
 			hash,
@@ -80,6 +81,10 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 		) 
 		VALUES 
 			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING hash, status, received_at
+		)
+		INSERT INTO pool.transaction_event (hash, status, created_at)
+		SELECT hash, status, received_at FROM tx
 	`
 
 	// Get FromAddress from the JSON data
@@ -307,24 +312,69 @@ func (p *PostgresPoolStorage) CountTransactionsByStatus(ctx context.Context, sta
 }
 
 // UpdateTxStatus updates a transaction status accordingly to the
-// provided status and hash
-func (p *PostgresPoolStorage) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus) error {
-	sql := "UPDATE pool.transaction SET status = $1 WHERE hash = $2"
-	if _, err := p.db.Exec(ctx, sql, newStatus, hash.Hex()); err != nil {
+// provided status and hash and records the change in the tx events
+func (p *PostgresPoolStorage) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus, reason string) error {
+	sql := `
+		WITH tx AS (
+			UPDATE pool.transaction SET status = $1 WHERE hash = $2 RETURNING hash
+		)
+		INSERT INTO pool.transaction_event (hash, status, reason, created_at)
+		SELECT hash, $1, $3::VARCHAR, $4::TIMESTAMP WITH TIME ZONE FROM tx`
+	if _, err := p.db.Exec(ctx, sql, newStatus, hash.Hex(), reason, time.Now().UTC()); err != nil {
 		return err
 	}
 	return nil
 }
 
 // UpdateTxsStatus updates transactions status accordingly to the provided status and hashes
+// and records the changes in the tx events
 func (p *PostgresPoolStorage) UpdateTxsStatus(ctx context.Context, hashes []string, newStatus pool.TxStatus) error {
-	sql := "UPDATE pool.transaction SET status = $1 WHERE hash = ANY ($2)"
-	if _, err := p.db.Exec(ctx, sql, newStatus, hashes); err != nil {
+	sql := `
+		WITH tx AS (
+			UPDATE pool.transaction SET status = $1 WHERE hash = ANY ($2) RETURNING hash
+		)
+		INSERT INTO pool.transaction_event (hash, status, created_at)
+		SELECT hash, $1, $3::TIMESTAMP WITH TIME ZONE FROM tx`
+	if _, err := p.db.Exec(ctx, sql, newStatus, hashes, time.Now().UTC()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddTxEvent records an event in the lifecycle of a transaction
+// that does not change its status
+func (p *PostgresPoolStorage) AddTxEvent(ctx context.Context, hash common.Hash, event pool.TxEvent) error {
+	sql := "INSERT INTO pool.transaction_event (hash, status, reason, created_at) VALUES ($1, $2, $3, $4)"
+	if _, err := p.db.Exec(ctx, sql, hash.Hex(), event.Status, event.Reason, event.Timestamp.UTC()); err != nil {
 		return err
 	}
 	return nil
 }
 
+// GetTxEvents returns the events of a transaction in the order they happened
+func (p *PostgresPoolStorage) GetTxEvents(ctx context.Context, hash common.Hash) ([]pool.TxEvent, error) {
+	sql := "SELECT status, reason, created_at FROM pool.transaction_event WHERE hash = $1 ORDER BY id"
+	rows, err := p.db.Query(ctx, sql, hash.Hex())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]pool.TxEvent, 0)
+	for rows.Next() {
+		var (
+			event  pool.TxEvent
+			status string
+		)
+		if err := rows.Scan(&status, &event.Reason, &event.Timestamp); err != nil {
+			return nil, err
+		}
+		event.Status = pool.TxStatus(status)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
 // DeleteTxsByHashes deletes txs by their hashes
 func (p *PostgresPoolStorage) DeleteTxsByHashes(ctx context.Context, hashes []common.Hash) error {
 	hh := make([]string, 0, len(hashes))
@@ -563,8 +613,13 @@ func (p *PostgresPoolStorage) GetTxZkCountersByHash(ctx context.Context, hash co
 
 // MarkWIPTxsAsPending updates WIP txs status to pending
 func (p *PostgresPoolStorage) MarkWIPTxsAsPending(ctx context.Context) error {
-	const query = `UPDATE pool.transaction SET status = $1 WHERE status = $2`
-	if _, err := p.db.Exec(ctx, query, pool.TxStatusPending, pool.TxStatusWIP); err != nil {
+	const query = `
+		WITH tx AS (
+			UPDATE pool.transaction SET status = $1 WHERE status = $2 RETURNING hash
+		)
+		INSERT INTO pool.transaction_event (hash, status, created_at)
+		SELECT hash, $1, $3::TIMESTAMP WITH TIME ZONE FROM tx`
+	if _, err := p.db.Exec(ctx, query, pool.TxStatusPending, pool.TxStatusWIP, time.Now().UTC()); err != nil {
 		return err
 	}
 	return nil
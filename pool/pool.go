@@ -97,9 +97,10 @@ func (p *Pool) GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]
 }
 
 // UpdateTxStatus updates a transaction state accordingly to the
-// provided state and hash
-func (p *Pool) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus TxStatus) error {
-	return p.storage.UpdateTxStatus(ctx, hash, newStatus)
+// provided state and hash, the reason of the change is stored in
+// the tx history
+func (p *Pool) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus TxStatus, reason string) error {
+	return p.storage.UpdateTxStatus(ctx, hash, newStatus, reason)
 }
 
 // SetGasPrice allows an external component to define the gas price
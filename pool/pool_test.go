@@ -616,7 +616,7 @@ func Test_UpdateTxStatus(t *testing.T) {
 		t.Error(err)
 	}
 
-	err = p.UpdateTxStatus(ctx, signedTx.Hash(), pool.TxStatusInvalid)
+	err = p.UpdateTxStatus(ctx, signedTx.Hash(), pool.TxStatusInvalid, "OOCS")
 	if err != nil {
 		t.Error(err)
 	}
@@ -634,6 +634,14 @@ func Test_UpdateTxStatus(t *testing.T) {
 	}
 
 	assert.Equal(t, pool.TxStatusInvalid, pool.TxStatus(state))
+
+	events, err := p.GetTxEvents(ctx, signedTx.Hash())
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, pool.TxStatusPending, events[0].Status)
+	assert.Equal(t, "", events[0].Reason)
+	assert.Equal(t, pool.TxStatusInvalid, events[1].Status)
+	assert.Equal(t, "OOCS", events[1].Reason)
 }
 
 func Test_SetAndGetGasPrice(t *testing.T) {
@@ -40,6 +40,16 @@ type Transaction struct {
 	ReceivedAt    time.Time
 }
 
+// TxEvent represents a change in the lifecycle of a pool tx
+type TxEvent struct {
+	// Status is the status of the tx after the event
+	Status TxStatus
+	// Reason is the error that caused the event, if any
+	Reason string
+	// Timestamp is the time the event happened
+	Timestamp time.Time
+}
+
 // IsClaimTx checks, if tx is a claim tx
 func (tx *Transaction) IsClaimTx(l2BridgeAddr common.Address, freeClaimGasLimit uint64) bool {
 	if tx.To() == nil {
@@ -0,0 +1,23 @@
+package pruner
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+const (
+	// ModeArchive keeps all the history
+	ModeArchive = "archive"
+	// ModeFull keeps the logs, receipts and transaction bodies of the last
+	// RetainedBatches batches only
+	ModeFull = "full"
+)
+
+// Config represents the configuration of the pruning of the state history
+type Config struct {
+	// Mode is the retention mode of the node, archive or full
+	Mode string `mapstructure:"Mode"`
+	// RetainedBatches is the number of batches whose history is kept in full mode
+	RetainedBatches uint64 `mapstructure:"RetainedBatches"`
+	// Interval is the time between the runs of the pruning
+	Interval types.Duration `mapstructure:"Interval"`
+	// ChunkSize is the number of batches pruned in each database transaction
+	ChunkSize uint64 `mapstructure:"ChunkSize"`
+}
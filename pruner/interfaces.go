@@ -0,0 +1,17 @@
+package pruner
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/jackc/pgx/v4"
+)
+
+// stateInterface gathers the methods required to interact with the state.
+type stateInterface interface {
+	BeginStateTransaction(ctx context.Context) (pgx.Tx, error)
+	GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	GetLastVerifiedBatch(ctx context.Context, dbTx pgx.Tx) (*state.VerifiedBatch, error)
+	GetPrunedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	PruneBatches(ctx context.Context, fromBatchNumber, toBatchNumber uint64, dbTx pgx.Tx) error
+}
@@ -0,0 +1,109 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// Pruner removes periodically the logs, the receipts and the transaction
+// bodies of the batches older than the retention window. The batches are
+// pruned in chunks, each one in its own database transaction, so the
+// synchronizer is never blocked for long
+type Pruner struct {
+	cfg   Config
+	state stateInterface
+}
+
+// New creates a new Pruner
+func New(cfg Config, state stateInterface) (*Pruner, error) {
+	if cfg.Mode != ModeFull {
+		return nil, fmt.Errorf("the pruner can't run in %q mode", cfg.Mode)
+	}
+	if cfg.RetainedBatches == 0 {
+		return nil, fmt.Errorf("the number of retained batches must be greater than 0")
+	}
+	if cfg.ChunkSize == 0 {
+		return nil, fmt.Errorf("the chunk size must be greater than 0")
+	}
+	return &Pruner{cfg: cfg, state: state}, nil
+}
+
+// Start runs the pruning until the context is cancelled
+func (p *Pruner) Start(ctx context.Context) {
+	log.Infof("pruning the history older than %d batches", p.cfg.RetainedBatches)
+	for {
+		if err := p.prune(ctx); err != nil {
+			log.Errorf("error pruning the history: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			log.Info("Finishing pruner...")
+			return
+		case <-time.After(p.cfg.Interval.Duration):
+		}
+	}
+}
+
+// prune removes the history of the batches out of the retention window that
+// were not pruned yet
+func (p *Pruner) prune(ctx context.Context) error {
+	lastBatchNumber, err := p.state.GetLastBatchNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if lastBatchNumber <= p.cfg.RetainedBatches {
+		return nil
+	}
+	target := lastBatchNumber - p.cfg.RetainedBatches
+
+	// the history of the batches that can still be reorged is never pruned
+	lastVerifiedBatch, err := p.state.GetLastVerifiedBatch(ctx, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if lastVerifiedBatch.BatchNumber < target {
+		target = lastVerifiedBatch.BatchNumber
+	}
+
+	prunedBatchNumber, err := p.state.GetPrunedBatchNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for prunedBatchNumber < target {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		from := prunedBatchNumber + 1
+		to := prunedBatchNumber + p.cfg.ChunkSize
+		if to > target {
+			to = target
+		}
+		if err := p.pruneChunk(ctx, from, to); err != nil {
+			return fmt.Errorf("error pruning batches %d to %d: %w", from, to, err)
+		}
+		log.Debugf("history of batches %d to %d pruned", from, to)
+		prunedBatchNumber = to
+	}
+	return nil
+}
+
+func (p *Pruner) pruneChunk(ctx context.Context, from, to uint64) error {
+	dbTx, err := p.state.BeginStateTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	if err := p.state.PruneBatches(ctx, from, to, dbTx); err != nil {
+		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+			log.Errorf("error rolling back the pruning of batches %d to %d: %v", from, to, rollbackErr)
+		}
+		return err
+	}
+	return dbTx.Commit(ctx)
+}
@@ -0,0 +1,138 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cfg           Config
+		expectedError string
+	}{
+		{
+			name:          "archive mode",
+			cfg:           Config{Mode: ModeArchive, RetainedBatches: 10, ChunkSize: 5},
+			expectedError: `the pruner can't run in "archive" mode`,
+		},
+		{
+			name:          "no retained batches",
+			cfg:           Config{Mode: ModeFull, ChunkSize: 5},
+			expectedError: "the number of retained batches must be greater than 0",
+		},
+		{
+			name:          "no chunk size",
+			cfg:           Config{Mode: ModeFull, RetainedBatches: 10},
+			expectedError: "the chunk size must be greater than 0",
+		},
+		{
+			name: "full mode",
+			cfg:  Config{Mode: ModeFull, RetainedBatches: 10, ChunkSize: 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := New(tc.cfg, newStateMock(t))
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, p)
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	cfg := Config{Mode: ModeFull, RetainedBatches: 10, ChunkSize: 5}
+
+	t.Run("prune in chunks", func(t *testing.T) {
+		st := newStateMock(t)
+		dbTx := newDbTxMock(t)
+		st.On("GetLastBatchNumber", ctx, nil).Return(uint64(25), nil).Once()
+		st.On("GetLastVerifiedBatch", ctx, nil).Return(&state.VerifiedBatch{BatchNumber: 20}, nil).Once()
+		st.On("GetPrunedBatchNumber", ctx, nil).Return(uint64(3), nil).Once()
+		for _, chunk := range [][2]uint64{{4, 8}, {9, 13}, {14, 15}} {
+			st.On("BeginStateTransaction", ctx).Return(dbTx, nil).Once()
+			st.On("PruneBatches", ctx, chunk[0], chunk[1], dbTx).Return(nil).Once()
+			dbTx.On("Commit", ctx).Return(nil).Once()
+		}
+
+		p, err := New(cfg, st)
+		require.NoError(t, err)
+		require.NoError(t, p.prune(ctx))
+	})
+
+	t.Run("capped by the last verified batch", func(t *testing.T) {
+		st := newStateMock(t)
+		dbTx := newDbTxMock(t)
+		st.On("GetLastBatchNumber", ctx, nil).Return(uint64(25), nil).Once()
+		st.On("GetLastVerifiedBatch", ctx, nil).Return(&state.VerifiedBatch{BatchNumber: 6}, nil).Once()
+		st.On("GetPrunedBatchNumber", ctx, nil).Return(uint64(3), nil).Once()
+		st.On("BeginStateTransaction", ctx).Return(dbTx, nil).Once()
+		st.On("PruneBatches", ctx, uint64(4), uint64(6), dbTx).Return(nil).Once()
+		dbTx.On("Commit", ctx).Return(nil).Once()
+
+		p, err := New(cfg, st)
+		require.NoError(t, err)
+		require.NoError(t, p.prune(ctx))
+	})
+
+	t.Run("no verified batches", func(t *testing.T) {
+		st := newStateMock(t)
+		st.On("GetLastBatchNumber", ctx, nil).Return(uint64(25), nil).Once()
+		st.On("GetLastVerifiedBatch", ctx, nil).Return(nil, state.ErrNotFound).Once()
+
+		p, err := New(cfg, st)
+		require.NoError(t, err)
+		require.NoError(t, p.prune(ctx))
+	})
+
+	t.Run("nothing to prune", func(t *testing.T) {
+		st := newStateMock(t)
+		st.On("GetLastBatchNumber", ctx, nil).Return(uint64(25), nil).Once()
+		st.On("GetLastVerifiedBatch", ctx, nil).Return(&state.VerifiedBatch{BatchNumber: 20}, nil).Once()
+		st.On("GetPrunedBatchNumber", ctx, nil).Return(uint64(15), nil).Once()
+
+		p, err := New(cfg, st)
+		require.NoError(t, err)
+		require.NoError(t, p.prune(ctx))
+	})
+
+	t.Run("less batches than the retention window", func(t *testing.T) {
+		st := newStateMock(t)
+		st.On("GetLastBatchNumber", ctx, nil).Return(uint64(8), nil).Once()
+
+		p, err := New(cfg, st)
+		require.NoError(t, err)
+		require.NoError(t, p.prune(ctx))
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		st := newStateMock(t)
+		dbTx := newDbTxMock(t)
+		pruneErr := errors.New("failed to prune")
+		st.On("GetLastBatchNumber", ctx, nil).Return(uint64(25), nil).Once()
+		st.On("GetLastVerifiedBatch", ctx, nil).Return(&state.VerifiedBatch{BatchNumber: 20}, nil).Once()
+		st.On("GetPrunedBatchNumber", ctx, nil).Return(uint64(3), nil).Once()
+		st.On("BeginStateTransaction", ctx).Return(dbTx, nil).Once()
+		st.On("PruneBatches", ctx, uint64(4), uint64(8), dbTx).Return(pruneErr).Once()
+		dbTx.On("Rollback", ctx).Return(nil).Once()
+
+		p, err := New(cfg, st)
+		require.NoError(t, err)
+		err = p.prune(ctx)
+		require.ErrorIs(t, err, pruneErr)
+		assert.Equal(t, "error pruning batches 4 to 8: failed to prune", err.Error())
+		st.AssertNotCalled(t, "PruneBatches", ctx, uint64(9), uint64(13), mock.Anything)
+	})
+}
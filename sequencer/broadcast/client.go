@@ -2,29 +2,229 @@ package broadcast
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
-// NewClient creates a grpc client to communicates with the Broadcast server
-func NewClient(ctx context.Context, serverAddress string) (pb.BroadcastServiceClient, *grpc.ClientConn, context.CancelFunc) {
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = time.Minute
+	dialTimeout           = 30 * time.Second
+)
+
+// Client is a long-lived client of the Broadcast server, it keeps the
+// connection open between calls and reconnects with an exponential backoff
+// when it is lost.
+type Client struct {
+	cfg           ClientConfig
+	serverAddress string
+
+	mu     sync.Mutex
+	conn   *grpc.ClientConn
+	client pb.BroadcastServiceClient
+}
+
+// NewLongLivedClient creates a Client of the Broadcast server running at
+// serverAddress, the connection is established on the first call.
+func NewLongLivedClient(cfg ClientConfig, serverAddress string) *Client {
+	return &Client{
+		cfg:           cfg,
+		serverAddress: serverAddress,
+	}
+}
+
+// ServerAddress returns the address of the Broadcast server
+func (c *Client) ServerAddress() string {
+	return c.serverAddress
+}
+
+// GetLastBatch returns the last batch of the trusted state
+func (c *Client) GetLastBatch(ctx context.Context) (*pb.GetBatchResponse, error) {
+	client, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetLastBatch(ctx, &emptypb.Empty{})
+}
+
+// GetBatch returns the batch of the trusted state with the given number
+func (c *Client) GetBatch(ctx context.Context, batchNumber uint64) (*pb.GetBatchResponse, error) {
+	client, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetBatch(ctx, &pb.GetBatchRequest{BatchNumber: batchNumber})
+}
+
+// StreamBatches calls handle with the closed batches of the trusted state from
+// fromBatchNumber, in order and as they are closed. When the stream is broken
+// it is opened again from the next batch after waiting for the backoff. It
+// returns when the context is done or handle returns an error.
+func (c *Client) StreamBatches(ctx context.Context, fromBatchNumber uint64, handle func(*pb.GetBatchResponse) error) error {
+	next := fromBatchNumber
+	backoff := c.initialBackoff()
+	for {
+		received, err := c.streamBatches(ctx, next, handle)
+		if received > 0 {
+			next += received
+			backoff = c.initialBackoff()
+		}
+		var handleErr *handleError
+		if errors.As(err, &handleErr) {
+			return handleErr.err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Warnf("broadcast stream from batch %d broken, reopening it in %v. Error: %v", next, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = c.nextBackoff(backoff)
+	}
+}
+
+// handleError wraps the errors returned by the handler of the streamed
+// batches, so they are told apart from the errors of the stream
+type handleError struct {
+	err error
+}
+
+func (e *handleError) Error() string {
+	return e.err.Error()
+}
+
+// streamBatches opens a stream from fromBatchNumber and handles the batches
+// received until it is broken, it returns the number of batches handled
+func (c *Client) streamBatches(ctx context.Context, fromBatchNumber uint64, handle func(*pb.GetBatchResponse) error) (uint64, error) {
+	client, err := c.connect(ctx)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.StreamBatches(ctx, &pb.StreamBatchesRequest{FromBatchNumber: fromBatchNumber})
+	if err != nil {
+		return 0, err
+	}
+
+	var received uint64
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			return received, fmt.Errorf("stream closed by the server")
+		} else if err != nil {
+			return received, err
+		}
+		if batch.BatchNumber != fromBatchNumber+received {
+			return received, fmt.Errorf("unexpected batch %d streamed, expected %d", batch.BatchNumber, fromBatchNumber+received)
+		}
+		if err := handle(batch); err != nil {
+			return received, &handleError{err: err}
+		}
+		received++
+	}
+}
+
+// connect returns the client of the open connection, dialing the Broadcast
+// server with an exponential backoff when there is none
+func (c *Client) connect(ctx context.Context) (pb.BroadcastServiceClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	creds, err := c.transportCredentials()
+	if err != nil {
+		return nil, err
+	}
 	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(creds),
 		grpc.WithBlock(),
 	}
-	const maxWaitSeconds = 120
-	ctx, cancel := context.WithTimeout(ctx, maxWaitSeconds*time.Second)
-	log.Infof("connecting to broadcast service: %v", serverAddress)
-	conn, err := grpc.DialContext(ctx, serverAddress, opts...)
+
+	backoff := c.initialBackoff()
+	for {
+		log.Infof("connecting to broadcast service: %v", c.serverAddress)
+		dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+		conn, err := grpc.DialContext(dialCtx, c.serverAddress, opts...)
+		cancel()
+		if err == nil {
+			log.Info("connected to broadcast service")
+			c.conn = conn
+			c.client = pb.NewBroadcastServiceClient(conn)
+			return c.client, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		log.Warnf("failed to connect to broadcast service, retrying in %v. Error: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = c.nextBackoff(backoff)
+	}
+}
+
+// Close closes the connection to the Broadcast server
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	c.client = nil
+	return err
+}
+
+func (c *Client) transportCredentials() (credentials.TransportCredentials, error) {
+	if !c.cfg.TLS {
+		return insecure.NewCredentials(), nil
+	}
+	if c.cfg.CACertFile == "" {
+		return credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}), nil
+	}
+	creds, err := credentials.NewClientTLSFromFile(c.cfg.CACertFile, "")
 	if err != nil {
-		log.Fatalf("failed to connect to broadcast service: %v", err)
+		return nil, fmt.Errorf("failed to load CA certificates of the broadcast service: %w", err)
 	}
-	client := pb.NewBroadcastServiceClient(conn)
-	log.Info("connected to broadcast service")
+	return creds, nil
+}
+
+func (c *Client) initialBackoff() time.Duration {
+	if c.cfg.InitialBackoff.Duration <= 0 {
+		return defaultInitialBackoff
+	}
+	return c.cfg.InitialBackoff.Duration
+}
 
-	return client, conn, cancel
+func (c *Client) nextBackoff(backoff time.Duration) time.Duration {
+	maxBackoff := c.cfg.MaxBackoff.Duration
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	backoff *= 2
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
 }
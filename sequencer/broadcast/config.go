@@ -1,7 +1,37 @@
 package broadcast
 
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
 // ServerConfig represents the configuration of the broadcast server.
 type ServerConfig struct {
 	Host string `mapstructure:"Host"`
 	Port int    `mapstructure:"Port"`
+
+	// TLSCertFile and TLSKeyFile are the PEM files of the certificate and key
+	// used to serve over TLS, it is disabled when they are empty
+	TLSCertFile string `mapstructure:"TLSCertFile"`
+	TLSKeyFile  string `mapstructure:"TLSKeyFile"`
+
+	// StreamPollInterval is the interval used to check the state for new
+	// closed batches to stream when neither the sequencer nor the synchronizer
+	// run along with the server, otherwise the state is checked as they close
+	// or store them
+	StreamPollInterval types.Duration `mapstructure:"StreamPollInterval"`
+}
+
+// ClientConfig represents the configuration of the broadcast client.
+type ClientConfig struct {
+	// TLS enables TLS on the connection to the broadcast server
+	TLS bool `mapstructure:"TLS"`
+
+	// CACertFile is the PEM file of the certificates used to verify the
+	// broadcast server, the system ones are used when it is empty
+	CACertFile string `mapstructure:"CACertFile"`
+
+	// InitialBackoff is the delay before reconnecting to the broadcast server,
+	// it is doubled on each failed attempt
+	InitialBackoff types.Duration `mapstructure:"InitialBackoff"`
+
+	// MaxBackoff is the maximum delay before reconnecting to the broadcast server
+	MaxBackoff types.Duration `mapstructure:"MaxBackoff"`
 }
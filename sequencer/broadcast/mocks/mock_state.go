@@ -111,6 +111,29 @@ func (_m *StateMock) GetLastBatch(ctx context.Context, dbTx pgx.Tx) (*state.Batc
 	return r0, r1
 }
 
+// GetLastClosedBatch provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetLastClosedBatch(ctx context.Context, dbTx pgx.Tx) (*state.Batch, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 *state.Batch
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) *state.Batch); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.Batch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewStateMock interface {
 	mock.TestingT
 	Cleanup(func())
@@ -25,6 +25,9 @@ const _ = grpc.SupportPackageIsVersion7
 type BroadcastServiceClient interface {
 	GetLastBatch(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetBatchResponse, error)
 	GetBatch(ctx context.Context, in *GetBatchRequest, opts ...grpc.CallOption) (*GetBatchResponse, error)
+	// StreamBatches sends the closed batches from the requested one and keeps
+	// the stream open sending the next ones as they are closed
+	StreamBatches(ctx context.Context, in *StreamBatchesRequest, opts ...grpc.CallOption) (BroadcastService_StreamBatchesClient, error)
 }
 
 type broadcastServiceClient struct {
@@ -53,12 +56,47 @@ func (c *broadcastServiceClient) GetBatch(ctx context.Context, in *GetBatchReque
 	return out, nil
 }
 
+func (c *broadcastServiceClient) StreamBatches(ctx context.Context, in *StreamBatchesRequest, opts ...grpc.CallOption) (BroadcastService_StreamBatchesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BroadcastService_ServiceDesc.Streams[0], "/broadcast.v1.BroadcastService/StreamBatches", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &broadcastServiceStreamBatchesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BroadcastService_StreamBatchesClient interface {
+	Recv() (*GetBatchResponse, error)
+	grpc.ClientStream
+}
+
+type broadcastServiceStreamBatchesClient struct {
+	grpc.ClientStream
+}
+
+func (x *broadcastServiceStreamBatchesClient) Recv() (*GetBatchResponse, error) {
+	m := new(GetBatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // BroadcastServiceServer is the server API for BroadcastService service.
 // All implementations must embed UnimplementedBroadcastServiceServer
 // for forward compatibility
 type BroadcastServiceServer interface {
 	GetLastBatch(context.Context, *emptypb.Empty) (*GetBatchResponse, error)
 	GetBatch(context.Context, *GetBatchRequest) (*GetBatchResponse, error)
+	// StreamBatches sends the closed batches from the requested one and keeps
+	// the stream open sending the next ones as they are closed
+	StreamBatches(*StreamBatchesRequest, BroadcastService_StreamBatchesServer) error
 	mustEmbedUnimplementedBroadcastServiceServer()
 }
 
@@ -72,6 +110,9 @@ func (UnimplementedBroadcastServiceServer) GetLastBatch(context.Context, *emptyp
 func (UnimplementedBroadcastServiceServer) GetBatch(context.Context, *GetBatchRequest) (*GetBatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBatch not implemented")
 }
+func (UnimplementedBroadcastServiceServer) StreamBatches(*StreamBatchesRequest, BroadcastService_StreamBatchesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBatches not implemented")
+}
 func (UnimplementedBroadcastServiceServer) mustEmbedUnimplementedBroadcastServiceServer() {}
 
 // UnsafeBroadcastServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -121,6 +162,27 @@ func _BroadcastService_GetBatch_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BroadcastService_StreamBatches_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBatchesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BroadcastServiceServer).StreamBatches(m, &broadcastServiceStreamBatchesServer{stream})
+}
+
+type BroadcastService_StreamBatchesServer interface {
+	Send(*GetBatchResponse) error
+	grpc.ServerStream
+}
+
+type broadcastServiceStreamBatchesServer struct {
+	grpc.ServerStream
+}
+
+func (x *broadcastServiceStreamBatchesServer) Send(m *GetBatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // BroadcastService_ServiceDesc is the grpc.ServiceDesc for BroadcastService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -137,6 +199,12 @@ var BroadcastService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _BroadcastService_GetBatch_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBatches",
+			Handler:       _BroadcastService_StreamBatches_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "broadcast.proto",
 }
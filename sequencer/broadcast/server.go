@@ -2,17 +2,23 @@ package broadcast
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"sync"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast/pb"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+const defaultStreamPollInterval = time.Second
+
 // Server provides the functionality of the Broadcast service.
 type Server struct {
 	cfg *ServerConfig
@@ -20,13 +26,20 @@ type Server struct {
 	srv *grpc.Server
 	pb.UnimplementedBroadcastServiceServer
 	state stateInterface
+
+	// batchClosed is closed and replaced each time a batch is closed, the
+	// streams wait on it once batchClosedEvents is set
+	batchClosed       chan struct{}
+	batchClosedEvents bool
+	batchClosedMux    sync.Mutex
 }
 
 // NewServer is the Broadcast server constructor.
 func NewServer(cfg *ServerConfig, state stateInterface) *Server {
 	return &Server{
-		cfg:   cfg,
-		state: state,
+		cfg:         cfg,
+		state:       state,
+		batchClosed: make(chan struct{}),
 	}
 }
 
@@ -35,6 +48,42 @@ func (s *Server) SetState(st stateInterface) {
 	s.state = st
 }
 
+// BatchClosedHandler returns the handler of the batches closed by the
+// sequencer running along with the server and of the closed batches stored by
+// the synchronizer, e.g. the forced batches included. Once it is requested the
+// streams are driven by the batches closed instead of polling the state, so
+// every component storing closed batches must notify them.
+func (s *Server) BatchClosedHandler() func(batchNumber uint64) {
+	s.batchClosedMux.Lock()
+	defer s.batchClosedMux.Unlock()
+	s.batchClosedEvents = true
+	return s.onBatchClosed
+}
+
+// onBatchClosed wakes up the streams waiting for a batch to be closed
+func (s *Server) onBatchClosed(batchNumber uint64) {
+	s.batchClosedMux.Lock()
+	defer s.batchClosedMux.Unlock()
+	close(s.batchClosed)
+	s.batchClosed = make(chan struct{})
+}
+
+// nextBatchClosed returns a channel closed once the next batch is closed, and
+// the poll interval to check the state instead when there are no events of the
+// batches closed, 0 otherwise
+func (s *Server) nextBatchClosed() (<-chan struct{}, time.Duration) {
+	s.batchClosedMux.Lock()
+	defer s.batchClosedMux.Unlock()
+	if s.batchClosedEvents {
+		return s.batchClosed, 0
+	}
+	pollInterval := s.cfg.StreamPollInterval.Duration
+	if pollInterval <= 0 {
+		pollInterval = defaultStreamPollInterval
+	}
+	return s.batchClosed, pollInterval
+}
+
 // Start sets up the server to process requests.
 func (s *Server) Start() {
 	address := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
@@ -43,7 +92,16 @@ func (s *Server) Start() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	s.srv = grpc.NewServer()
+	var opts []grpc.ServerOption
+	if s.cfg.TLSCertFile != "" || s.cfg.TLSKeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+		if err != nil {
+			log.Fatalf("failed to load TLS credentials: %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	s.srv = grpc.NewServer(opts...)
 	pb.RegisterBroadcastServiceServer(s.srv, s)
 
 	healthService := newHealthChecker()
@@ -80,6 +138,55 @@ func (s *Server) GetLastBatch(ctx context.Context, empty *emptypb.Empty) (*pb.Ge
 	return s.genericGetBatch(ctx, batch)
 }
 
+// StreamBatches sends the closed batches from the requested one and keeps the
+// stream open sending the next ones as they are closed. The state is checked
+// each time a batch is closed by the sequencer or stored by the synchronizer
+// running along with the server or, if none runs along with it, each
+// StreamPollInterval.
+func (s *Server) StreamBatches(in *pb.StreamBatchesRequest, stream pb.BroadcastService_StreamBatchesServer) error {
+	ctx := stream.Context()
+	next := in.FromBatchNumber
+	for {
+		// The channel is taken before checking the state, so a batch closed
+		// meanwhile is not missed
+		batchClosed, pollInterval := s.nextBatchClosed()
+
+		lastClosed, err := s.state.GetLastClosedBatch(ctx, nil)
+		if err != nil && !errors.Is(err, state.ErrStateNotSynchronized) {
+			return err
+		}
+		for ; lastClosed != nil && next <= lastClosed.BatchNumber; next++ {
+			batch, err := s.state.GetBatchByNumber(ctx, next, nil)
+			if err != nil {
+				return err
+			}
+			res, err := s.genericGetBatch(ctx, batch)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(res); err != nil {
+				return err
+			}
+		}
+
+		var poll *time.Timer
+		var polled <-chan time.Time
+		if pollInterval > 0 {
+			poll = time.NewTimer(pollInterval)
+			polled = poll.C
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-batchClosed:
+		case <-polled:
+		}
+		if poll != nil {
+			poll.Stop()
+		}
+	}
+}
+
 func (s *Server) genericGetBatch(ctx context.Context, batch *state.Batch) (*pb.GetBatchResponse, error) {
 	txs, err := s.state.GetEncodedTransactionsByBatchNumber(ctx, batch.BatchNumber, nil)
 	if err != nil {
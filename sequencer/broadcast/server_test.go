@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast/mocks"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast/pb"
@@ -60,12 +61,14 @@ func initialize() {
 	broadcastSrv = initBroadcastServer()
 	go broadcastSrv.Start()
 
-	conn, cancel, err = initConn()
+	// The connection is opened once the server listens, otherwise the first
+	// calls fail while it waits to reconnect
+	err = operations.WaitGRPCHealthy(address)
 	if err != nil {
 		panic(err)
 	}
 
-	err = operations.WaitGRPCHealthy(address)
+	conn, cancel, err = initConn()
 	if err != nil {
 		panic(err)
 	}
@@ -240,3 +243,85 @@ func TestBroadcastServerGetLastBatch(t *testing.T) {
 		})
 	}
 }
+
+func TestBroadcastServerStreamBatches(t *testing.T) {
+	st := new(mocks.StateMock)
+	lastClosed := &state.Batch{BatchNumber: 3}
+	st.On("GetLastClosedBatch", mock.Anything, nil).Return(lastClosed, nil)
+	for batchNumber := uint64(2); batchNumber <= lastClosed.BatchNumber; batchNumber++ {
+		batch := &state.Batch{
+			BatchNumber:    batchNumber,
+			GlobalExitRoot: common.HexToHash("a"),
+			Timestamp:      time.Now(),
+		}
+		st.On("GetBatchByNumber", mock.Anything, batchNumber, nil).Return(batch, nil)
+		st.On("GetEncodedTransactionsByBatchNumber", mock.Anything, batchNumber, nil).Return([]string{"tx1"}, nil)
+	}
+	st.On("GetExitRootByGlobalExitRoot", mock.Anything, common.HexToHash("a"), nil).Return(&state.GlobalExitRoot{}, nil)
+	broadcastSrv.SetState(st)
+
+	client := broadcast.NewLongLivedClient(broadcast.ClientConfig{}, address)
+	defer func() { require.NoError(t, client.Close()) }()
+
+	streamCtx, streamCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer streamCancel()
+	var received []uint64
+	errStop := errors.New("stop")
+	err := client.StreamBatches(streamCtx, 2, func(batch *pb.GetBatchResponse) error {
+		received = append(received, batch.BatchNumber)
+		require.Equal(t, "tx1", batch.Transactions[0].Encoded)
+		if batch.BatchNumber == lastClosed.BatchNumber {
+			return errStop
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []uint64{2, 3}, received)
+}
+
+func TestBroadcastServerStreamBatchesOnBatchClosed(t *testing.T) {
+	// The state is only checked again when a batch is closed
+	cfg := &broadcast.ServerConfig{
+		Host:               host,
+		Port:               port + 1,
+		StreamPollInterval: types.Duration{Duration: time.Hour},
+	}
+	st := new(mocks.StateMock)
+	srv := broadcast.NewServer(cfg, st)
+	batchClosed := srv.BatchClosedHandler()
+	go srv.Start()
+	defer srv.Stop()
+	serverAddress := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	require.NoError(t, operations.WaitGRPCHealthy(serverAddress))
+
+	st.On("GetLastClosedBatch", mock.Anything, nil).Return(&state.Batch{BatchNumber: 2}, nil).Once()
+	st.On("GetLastClosedBatch", mock.Anything, nil).Return(&state.Batch{BatchNumber: 3}, nil)
+	for batchNumber := uint64(2); batchNumber <= 3; batchNumber++ {
+		batch := &state.Batch{
+			BatchNumber:    batchNumber,
+			GlobalExitRoot: common.HexToHash("a"),
+			Timestamp:      time.Now(),
+		}
+		st.On("GetBatchByNumber", mock.Anything, batchNumber, nil).Return(batch, nil)
+		st.On("GetEncodedTransactionsByBatchNumber", mock.Anything, batchNumber, nil).Return([]string{"tx1"}, nil)
+	}
+	st.On("GetExitRootByGlobalExitRoot", mock.Anything, common.HexToHash("a"), nil).Return(&state.GlobalExitRoot{}, nil)
+
+	client := broadcast.NewLongLivedClient(broadcast.ClientConfig{}, serverAddress)
+	defer func() { require.NoError(t, client.Close()) }()
+
+	streamCtx, streamCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer streamCancel()
+	var received []uint64
+	errStop := errors.New("stop")
+	err := client.StreamBatches(streamCtx, 2, func(batch *pb.GetBatchResponse) error {
+		received = append(received, batch.BatchNumber)
+		if batch.BatchNumber == 3 {
+			return errStop
+		}
+		batchClosed(3)
+		return nil
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []uint64{2, 3}, received)
+}
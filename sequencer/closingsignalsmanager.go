@@ -9,19 +9,20 @@ import (
 )
 
 type closingSignalsManager struct {
-	ctx                    context.Context
-	dbManager              dbManagerInterface
-	closingSignalCh        ClosingSignalCh
-	cfg                    FinalizerCfg
-	lastForcedBatchNumSent uint64
+	ctx             context.Context
+	dbManager       dbManagerInterface
+	closingSignalCh ClosingSignalCh
+	cfg             FinalizerCfg
 }
 
 func newClosingSignalsManager(ctx context.Context, dbManager dbManagerInterface, closingSignalCh ClosingSignalCh, cfg FinalizerCfg) *closingSignalsManager {
 	return &closingSignalsManager{ctx: ctx, dbManager: dbManager, closingSignalCh: closingSignalCh, cfg: cfg}
 }
 
+// Start starts checking the closing signals. The forced batches are not
+// signaled, they are included by the synchronizer between the batches derived
+// from HotShot blocks once they time out
 func (c *closingSignalsManager) Start() {
-	go c.checkForcedBatches()
 	go c.checkGERUpdate()
 	go c.checkSendToL1Timeout()
 }
@@ -63,32 +64,3 @@ func (c *closingSignalsManager) checkGERUpdate() {
 		}
 	}
 }
-
-func (c *closingSignalsManager) checkForcedBatches() {
-	for {
-		time.Sleep(c.cfg.ClosingSignalsManagerWaitForL1OperationsInSec.Duration)
-		log.Debug(time.Now())
-
-		if c.lastForcedBatchNumSent == 0 { // TODO: reset c.lastForcedBatchNumSent = 0 on L2 Reorg
-			lastTrustedForcedBatchNum, err := c.dbManager.GetLastTrustedForcedBatchNumber(c.ctx, nil)
-			if err != nil {
-				log.Errorf("error getting last trusted forced batch number: %v", err)
-				continue
-			}
-			if lastTrustedForcedBatchNum > 0 {
-				c.lastForcedBatchNumSent = lastTrustedForcedBatchNum
-			}
-		}
-
-		forcedBatches, err := c.dbManager.GetForcedBatchesSince(c.ctx, c.lastForcedBatchNumSent, nil)
-		if err != nil {
-			log.Errorf("error checking forced batches: %v", err)
-			continue
-		}
-
-		for _, forcedBatch := range forcedBatches {
-			c.closingSignalCh.ForcedBatchCh <- *forcedBatch
-			c.lastForcedBatchNumSent = forcedBatch.ForcedBatchNumber
-		}
-	}
-}
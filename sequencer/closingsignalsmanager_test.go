@@ -48,7 +48,7 @@ func setupTest(t *testing.T) {
 	stateTree = merkletree.NewStateTree(mtDBServiceClient)
 	testState = state.NewState(stateCfg, state.NewPostgresStorage(stateDb), executorClient, stateTree)
 
-	batchConstraints := batchConstraints{
+	batchConstraints := state.BatchConstraintsCfg{
 		MaxTxsPerBatch:       150,
 		MaxBatchBytesSize:    150000,
 		MaxCumulativeGasUsed: 30000000,
@@ -61,7 +61,7 @@ func setupTest(t *testing.T) {
 		MaxSteps:             8388608,
 	}
 
-	testDbManager = newDBManager(ctx, nil, testState, nil, closingSignalCh, txsStore, batchConstraints)
+	testDbManager = newDBManager(ctx, nil, testState, nil, closingSignalCh, txsStore, batchConstraints, time.Second)
 
 	// Set genesis batch
 	dbTx, err := testState.BeginStateTransaction(ctx)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	base "github.com/0xPolygonHermez/zkevm-node/encoding"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 )
 
 // Config represents the configuration of a sequencer
@@ -27,35 +28,12 @@ type Config struct {
 	// FrequencyToCheckTxsForDelete is frequency with which txs will be checked for deleting
 	FrequencyToCheckTxsForDelete types.Duration `mapstructure:"FrequencyToCheckTxsForDelete"`
 
-	// MaxTxsPerBatch is the maximum amount of transactions in the batch
-	MaxTxsPerBatch uint64 `mapstructure:"MaxTxsPerBatch"`
+	// FrequencyToCheckReorgs is frequency with which the state will be checked
+	// for new reorgs, to put back in the pool the txs dropped by them
+	FrequencyToCheckReorgs types.Duration `mapstructure:"FrequencyToCheckReorgs"`
 
-	// MaxBatchBytesSize is the maximum batch size in bytes
-	MaxBatchBytesSize uint64 `mapstructure:"MaxBatchBytesSize"`
-
-	// MaxCumulativeGasUsed is max gas amount used by batch
-	MaxCumulativeGasUsed uint64 `mapstructure:"MaxCumulativeGasUsed"`
-
-	// MaxKeccakHashes is max keccak hashes used by batch
-	MaxKeccakHashes uint32 `mapstructure:"MaxKeccakHashes"`
-
-	// MaxPoseidonHashes is max poseidon hashes batch can handle
-	MaxPoseidonHashes uint32 `mapstructure:"MaxPoseidonHashes"`
-
-	// MaxPoseidonPaddings is max poseidon paddings batch can handle
-	MaxPoseidonPaddings uint32 `mapstructure:"MaxPoseidonPaddings"`
-
-	// MaxMemAligns is max mem aligns batch can handle
-	MaxMemAligns uint32 `mapstructure:"MaxMemAligns"`
-
-	// MaxArithmetics is max arithmetics batch can handle
-	MaxArithmetics uint32 `mapstructure:"MaxArithmetics"`
-
-	// MaxBinaries is max binaries batch can handle
-	MaxBinaries uint32 `mapstructure:"MaxBinaries"`
-
-	// MaxSteps is max steps batch can handle
-	MaxSteps uint32 `mapstructure:"MaxSteps"`
+	// BatchConstraintsCfg are the limits of the resources of a batch
+	state.BatchConstraintsCfg `mapstructure:",squash"`
 
 	// Maximum size, in gas size, a sequence can reach
 	MaxSequenceSize MaxSequenceSize `mapstructure:"MaxSequenceSize"`
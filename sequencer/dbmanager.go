@@ -28,7 +28,9 @@ type dbManager struct {
 	txsStore         TxsStore
 	l2ReorgCh        chan L2ReorgEvent
 	ctx              context.Context
-	batchConstraints batchConstraints
+	batchConstraints state.BatchConstraintsCfg
+
+	frequencyToCheckReorgs time.Duration
 }
 
 func (d *dbManager) GetBatchByNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Batch, error) {
@@ -44,14 +46,15 @@ type ClosingBatchParameters struct {
 	Txs           []types.Transaction
 }
 
-func newDBManager(ctx context.Context, txPool txPool, state dbManagerStateInterface, worker *Worker, closingSignalCh ClosingSignalCh, txsStore TxsStore, batchConstraints batchConstraints) *dbManager {
-	return &dbManager{ctx: ctx, txPool: txPool, state: state, worker: worker, txsStore: txsStore, l2ReorgCh: closingSignalCh.L2ReorgCh, batchConstraints: batchConstraints}
+func newDBManager(ctx context.Context, txPool txPool, state dbManagerStateInterface, worker *Worker, closingSignalCh ClosingSignalCh, txsStore TxsStore, batchConstraints state.BatchConstraintsCfg, frequencyToCheckReorgs time.Duration) *dbManager {
+	return &dbManager{ctx: ctx, txPool: txPool, state: state, worker: worker, txsStore: txsStore, l2ReorgCh: closingSignalCh.L2ReorgCh, batchConstraints: batchConstraints, frequencyToCheckReorgs: frequencyToCheckReorgs}
 }
 
 // Start stars the dbManager routines
 func (d *dbManager) Start() {
 	go d.loadFromPool()
 	go d.storeProcessedTxAndDeleteFromPool()
+	go d.processReorgs()
 }
 
 // GetLastBatchNumber get the latest batch number from state
@@ -127,13 +130,97 @@ func (d *dbManager) loadFromPool() {
 	}
 }
 
+// processReorgs keeps putting back in the pool the txs dropped by the reorgs
+// recorded in the state. The last reorg processed is persisted, so the reorgs
+// recorded while the sequencer was stopped are processed once it starts
+func (d *dbManager) processReorgs() {
+	var (
+		lastReorgID uint64
+		loaded      bool
+	)
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-time.After(d.frequencyToCheckReorgs):
+		}
+
+		if !loaded {
+			var err error
+			lastReorgID, err = d.loadProcessedReorgID()
+			if err != nil {
+				log.Errorf("load last processed reorg from state: %v", err)
+				continue
+			}
+			loaded = true
+		}
+
+		reorgs, err := d.state.GetReorgsAfterID(d.ctx, lastReorgID, nil)
+		if err != nil {
+			log.Errorf("load reorgs from state: %v", err)
+			continue
+		}
+		for _, reorg := range reorgs {
+			d.processReorgedTxs(reorg)
+			lastReorgID = reorg.ID
+			if err := d.state.SetProcessedReorgID(d.ctx, lastReorgID, nil); err != nil {
+				log.Errorf("error storing the last processed reorg %d: %v", lastReorgID, err)
+			}
+		}
+	}
+}
+
+// loadProcessedReorgID returns the last reorg processed, the reorgs recorded
+// before the first start of the sequencer are skipped
+func (d *dbManager) loadProcessedReorgID() (uint64, error) {
+	reorgID, err := d.state.GetProcessedReorgID(d.ctx, nil)
+	if !errors.Is(err, state.ErrNotFound) {
+		return reorgID, err
+	}
+	reorgID, err = d.state.GetLastReorgID(d.ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return reorgID, d.state.SetProcessedReorgID(d.ctx, reorgID, nil)
+}
+
+// processReorgedTxs resubmits to the pool the txs dropped by a reorg. The txs
+// still selectable are marked as pending again and the ones that are not in
+// the pool are added to it, the txs already discarded by the pool are only
+// flagged with the reorg in their events
+func (d *dbManager) processReorgedTxs(reorg state.Reorg) {
+	reason := fmt.Sprintf("dropped by %s reorg %d from batch %d", reorg.Kind, reorg.ID, reorg.FirstBatchNumber)
+	for _, tx := range reorg.DroppedTxs {
+		poolTx, err := d.txPool.GetTxByHash(d.ctx, tx.Hash())
+		if errors.Is(err, pgpoolstorage.ErrNotFound) {
+			if err := d.txPool.AddTx(d.ctx, tx); err != nil {
+				log.Warnf("reorged tx %s could not be resubmitted to the pool: %v", tx.Hash(), err)
+			}
+			continue
+		} else if err != nil {
+			log.Errorf("error loading reorged tx %s from the pool: %v", tx.Hash(), err)
+			continue
+		}
+
+		switch poolTx.Status {
+		case pool.TxStatusInvalid, pool.TxStatusFailed:
+			err = d.txPool.AddTxEvent(d.ctx, tx.Hash(), pool.TxEvent{Status: poolTx.Status, Reason: reason, Timestamp: time.Now()})
+		default:
+			err = d.txPool.UpdateTxStatus(d.ctx, tx.Hash(), pool.TxStatusPending, reason)
+		}
+		if err != nil {
+			log.Errorf("error updating reorged tx %s in the pool: %v", tx.Hash(), err)
+		}
+	}
+}
+
 func (d *dbManager) addTxToWorker(tx pool.Transaction, isClaim bool) error {
 	txTracker, err := d.worker.NewTxTracker(tx.Transaction, isClaim, tx.ZKCounters)
 	if err != nil {
 		return err
 	}
 	d.worker.AddTx(d.ctx, txTracker)
-	return d.txPool.UpdateTxStatus(d.ctx, tx.Hash(), pool.TxStatusWIP)
+	return d.txPool.UpdateTxStatus(d.ctx, tx.Hash(), pool.TxStatusWIP, "")
 }
 
 // BeginStateTransaction starts a db transaction in the state
@@ -225,7 +312,7 @@ func (d *dbManager) storeProcessedTxAndDeleteFromPool() {
 		}
 
 		// Change Tx status to selected
-		err = d.txPool.UpdateTxStatus(d.ctx, txToStore.txResponse.TxHash, pool.TxStatusSelected)
+		err = d.txPool.UpdateTxStatus(d.ctx, txToStore.txResponse.TxHash, pool.TxStatusSelected, "")
 		if err != nil {
 			err = dbTx.Rollback(d.ctx)
 			if err != nil {
@@ -578,8 +665,14 @@ func (d *dbManager) GetTransactionsByBatchNumber(ctx context.Context, batchNumbe
 	return d.state.GetTransactionsByBatchNumber(ctx, batchNumber, nil)
 }
 
-func (d *dbManager) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus) error {
-	return d.txPool.UpdateTxStatus(ctx, hash, newStatus)
+// UpdateTxStatus updates the status of a tx in the pool, storing the reason in its history
+func (d *dbManager) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus, reason string) error {
+	return d.txPool.UpdateTxStatus(ctx, hash, newStatus, reason)
+}
+
+// AddTxEvent stores an event in the history of a tx in the pool
+func (d *dbManager) AddTxEvent(ctx context.Context, hash common.Hash, event pool.TxEvent) error {
+	return d.txPool.AddTxEvent(ctx, hash, event)
 }
 
 // GetLatestVirtualBatchTimestamp gets last virtual batch timestamp
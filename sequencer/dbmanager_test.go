@@ -3,6 +3,7 @@ package sequencer
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"sync"
 	"testing"
 	"time"
@@ -11,12 +12,15 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/merkletree"
 	mtDBclientpb "github.com/0xPolygonHermez/zkevm-node/merkletree/pb"
+	"github.com/0xPolygonHermez/zkevm-node/pool/pgpoolstorage"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	executorclientpb "github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
 	"github.com/0xPolygonHermez/zkevm-node/test/dbutils"
 	"github.com/0xPolygonHermez/zkevm-node/test/testutils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 )
@@ -74,7 +78,7 @@ func setupDBManager() {
 		Wg: new(sync.WaitGroup),
 	}
 
-	batchConstraints := batchConstraints{
+	batchConstraints := state.BatchConstraintsCfg{
 		MaxTxsPerBatch:       150,
 		MaxBatchBytesSize:    150000,
 		MaxCumulativeGasUsed: 30000000,
@@ -87,7 +91,7 @@ func setupDBManager() {
 		MaxSteps:             8388608,
 	}
 
-	testDbManager = newDBManager(ctx, nil, testState, nil, closingSignalCh, txsStore, batchConstraints)
+	testDbManager = newDBManager(ctx, nil, testState, nil, closingSignalCh, txsStore, batchConstraints, time.Second)
 }
 
 func initOrResetDB() {
@@ -160,3 +164,65 @@ func TestCreateFirstBatch(t *testing.T) {
 	processingContext := testDbManager.CreateFirstBatch(ctx, common.Address{})
 	require.Equal(t, uint64(1), processingContext.BatchNumber)
 }
+
+func TestDBManagerProcessReorgs(t *testing.T) {
+	droppedTx := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	reorg := state.Reorg{ID: 3, Kind: state.ReorgKindL1, DroppedTxs: []types.Transaction{*droppedTx}}
+	testCases := []struct {
+		name        string
+		setupState  func(st *StateMock)
+		lastReorgID uint64
+	}{
+		{
+			name: "the reorgs after the persisted one are processed",
+			setupState: func(st *StateMock) {
+				st.On("GetProcessedReorgID", mock.Anything, nil).Return(uint64(2), nil).Once()
+			},
+			lastReorgID: 2,
+		},
+		{
+			name: "the reorgs before the first start are skipped",
+			setupState: func(st *StateMock) {
+				st.On("GetProcessedReorgID", mock.Anything, nil).Return(uint64(0), state.ErrNotFound).Once()
+				st.On("GetLastReorgID", mock.Anything, nil).Return(uint64(2), nil).Once()
+				st.On("SetProcessedReorgID", mock.Anything, uint64(2), nil).Return(nil).Once()
+			},
+			lastReorgID: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			st := NewStateMock(t)
+			txPool := NewPoolMock(t)
+			tc.setupState(st)
+
+			st.On("GetReorgsAfterID", mock.Anything, tc.lastReorgID, nil).Return([]state.Reorg{reorg}, nil).Once()
+			txPool.On("GetTxByHash", mock.Anything, droppedTx.Hash()).Return(nil, pgpoolstorage.ErrNotFound).Once()
+			txPool.On("AddTx", mock.Anything, *droppedTx).Return(nil).Once()
+			st.On("SetProcessedReorgID", mock.Anything, reorg.ID, nil).Return(nil).Once()
+			// the next reorgs are loaded after the processed one
+			processed := make(chan struct{})
+			var processedOnce sync.Once
+			st.On("GetReorgsAfterID", mock.Anything, reorg.ID, nil).
+				Run(func(args mock.Arguments) { processedOnce.Do(func() { close(processed) }) }).
+				Return([]state.Reorg{}, nil)
+
+			d := newDBManager(ctx, txPool, st, nil, ClosingSignalCh{}, TxsStore{}, state.BatchConstraintsCfg{}, time.Millisecond)
+			stopped := make(chan struct{})
+			go func() {
+				d.processReorgs()
+				close(stopped)
+			}()
+
+			select {
+			case <-processed:
+			case <-time.After(5 * time.Second):
+				t.Fatal("reorg not processed")
+			}
+			cancel()
+			<-stopped
+		})
+	}
+}
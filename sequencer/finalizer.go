@@ -34,7 +34,7 @@ type finalizer struct {
 	dbManager          dbManagerInterface
 	executor           stateInterface
 	batch              *WipBatch
-	batchConstraints   batchConstraints
+	batchConstraints   state.BatchConstraintsCfg
 	processRequest     state.ProcessRequest
 	sharedResourcesMux *sync.RWMutex
 	lastGERHash        common.Hash
@@ -48,6 +48,8 @@ type finalizer struct {
 	nextSendingToL1Deadline   int64
 	nextSendingToL1TimeoutMux *sync.RWMutex
 	handlingL2Reorg           bool
+	// onBatchClosed is called with the number of each batch closed, if set
+	onBatchClosed func(batchNumber uint64)
 }
 
 // WipBatch represents a work-in-progress batch.
@@ -77,7 +79,7 @@ func newFinalizer(
 	isSynced func(ctx context.Context) bool,
 	closingSignalCh ClosingSignalCh,
 	txsStore TxsStore,
-	batchConstraints batchConstraints,
+	batchConstraints state.BatchConstraintsCfg,
 ) *finalizer {
 	return &finalizer{
 		cfg:                cfg,
@@ -396,6 +398,10 @@ func (f *finalizer) storeProcessedTx(previousL2BlockStateRoot common.Hash, tx *T
 
 	f.txsStore.Wg.Wait()
 	txResponse := result.Responses[0]
+	// the tx is processed alone, so the counters of the batch are the ones
+	// used by the tx
+	zkCounters := result.UsedZkCounters
+	txResponse.ZKCounters = &zkCounters
 	f.txsStore.Wg.Add(1)
 	f.txsStore.Ch <- &txToStore{
 		batchNumber:              f.batch.batchNumber,
@@ -416,12 +422,16 @@ func (f *finalizer) handleTransactionError(ctx context.Context, result *state.Pr
 	addressInfo := result.ReadWriteAddresses[tx.From]
 
 	if executor.IsROMOutOfCountersError(errorCode) {
-		log.Errorf("ROM out of counters error, marking tx with Hash: %s as INVALID, errorCode: %s", tx.Hash.String(), errorCode.String())
+		reason := txResponse.RomError.Error()
+		if usage, ok := f.batchConstraints.CounterUsage(state.OutOfCountersName(errorCode), result.UsedZkCounters); ok {
+			reason = fmt.Sprintf("%s, %s", reason, usage)
+		}
+		log.Errorf("ROM out of counters error, marking tx with Hash: %s as INVALID, errorCode: %s, %s", tx.Hash.String(), errorCode.String(), reason)
 		start := time.Now()
 		f.worker.DeleteTx(tx.Hash, tx.From)
 		metrics.WorkerProcessingTime(time.Since(start))
 		go func() {
-			err := f.dbManager.UpdateTxStatus(ctx, tx.Hash, pool.TxStatusInvalid)
+			err := f.dbManager.UpdateTxStatus(ctx, tx.Hash, pool.TxStatusInvalid, reason)
 			if err != nil {
 				log.Errorf("failed to update tx status, err: %s", err)
 			}
@@ -439,6 +449,13 @@ func (f *finalizer) handleTransactionError(ctx context.Context, result *state.Pr
 		start := time.Now()
 		f.worker.MoveTxToNotReady(tx.Hash, tx.From, nonce, balance)
 		metrics.WorkerProcessingTime(time.Since(start))
+		go func() {
+			event := pool.TxEvent{Status: pool.TxStatusWIP, Reason: txResponse.RomError.Error(), Timestamp: time.Now()}
+			err := f.dbManager.AddTxEvent(ctx, tx.Hash, event)
+			if err != nil {
+				log.Errorf("failed to add tx event, err: %s", err)
+			}
+		}()
 	}
 }
 
@@ -550,6 +567,7 @@ func (f *finalizer) processForcedBatch(lastBatchNumberInState uint64, stateRoot
 	} else {
 		stateRoot = response.NewStateRoot
 		lastBatchNumberInState += 1
+		f.batchClosed(lastBatchNumberInState)
 	}
 	return lastBatchNumberInState, stateRoot
 }
@@ -613,7 +631,18 @@ func (f *finalizer) closeBatch(ctx context.Context) error {
 		LocalExitRoot: f.batch.localExitRoot,
 		Txs:           transactions,
 	}
-	return f.dbManager.CloseBatch(ctx, receipt)
+	if err := f.dbManager.CloseBatch(ctx, receipt); err != nil {
+		return err
+	}
+	f.batchClosed(f.batch.batchNumber)
+	return nil
+}
+
+// batchClosed notifies that the batch was closed
+func (f *finalizer) batchClosed(batchNumber uint64) {
+	if f.onBatchClosed != nil {
+		f.onBatchClosed(batchNumber)
+	}
 }
 
 // openBatch opens a new batch in the state
@@ -26,7 +26,7 @@ var (
 	executorMock  = new(StateMock)
 	workerMock    = new(WorkerMock)
 	dbTxMock      = new(DbTxMock)
-	bc            = batchConstraints{
+	bc            = state.BatchConstraintsCfg{
 		MaxTxsPerBatch:       150,
 		MaxBatchBytesSize:    150000,
 		MaxCumulativeGasUsed: 30000000,
@@ -230,11 +230,13 @@ func TestFinalizer_handleTransactionError(t *testing.T) {
 		error              pb.RomError
 		expectedDeleteCall bool
 		expectedMoveCall   bool
+		expectedReason     string
 	}{
 		{
 			name:               "OutOfCountersError",
 			error:              pb.RomError(executor.ROM_ERROR_OUT_OF_COUNTERS_STEP),
 			expectedDeleteCall: true,
+			expectedReason:     executor.RomErr(pb.RomError(executor.ROM_ERROR_OUT_OF_COUNTERS_STEP)).Error() + ", steps used 0 of 8388608 (0.00%)",
 		},
 		{
 			name:             "IntrinsicError",
@@ -247,10 +249,13 @@ func TestFinalizer_handleTransactionError(t *testing.T) {
 			// arrange
 			if tc.expectedDeleteCall {
 				workerMock.On("DeleteTx", oldHash, sender).Return().Once()
-				dbManagerMock.On("UpdateTxStatus", ctx, oldHash, pool.TxStatusInvalid).Return(nil).Once()
+				dbManagerMock.On("UpdateTxStatus", ctx, oldHash, pool.TxStatusInvalid, tc.expectedReason).Return(nil).Once()
 			}
 			if tc.expectedMoveCall {
 				workerMock.On("MoveTxToNotReady", oldHash, sender, &nonce, big.NewInt(0)).Return().Once()
+				dbManagerMock.On("AddTxEvent", ctx, oldHash, mock.MatchedBy(func(event pool.TxEvent) bool {
+					return event.Status == pool.TxStatusWIP && event.Reason == executor.RomErr(tc.error).Error()
+				})).Return(nil).Once()
 			}
 
 			result := &state.ProcessBatchResponse{
@@ -622,14 +627,16 @@ func TestFinalizer_closeBatch(t *testing.T) {
 	}
 	managerErr := fmt.Errorf("some error")
 	testCases := []struct {
-		name        string
-		managerErr  error
-		expectedErr error
+		name           string
+		managerErr     error
+		expectedErr    error
+		expectedClosed []uint64
 	}{
 		{
-			name:        "Success",
-			managerErr:  nil,
-			expectedErr: nil,
+			name:           "Success",
+			managerErr:     nil,
+			expectedErr:    nil,
+			expectedClosed: []uint64{f.batch.batchNumber},
 		},
 		{
 			name:        "Manager Error",
@@ -641,6 +648,11 @@ func TestFinalizer_closeBatch(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// arrange
+			var closed []uint64
+			f.onBatchClosed = func(batchNumber uint64) {
+				closed = append(closed, batchNumber)
+			}
+			defer func() { f.onBatchClosed = nil }()
 			dbManagerMock.Mock.On("CloseBatch", ctx, receipt).Return(tc.managerErr).Once()
 			dbManagerMock.Mock.On("GetTransactionsByBatchNumber", ctx, receipt.BatchNumber).Return(txs, tc.managerErr).Once()
 
@@ -655,6 +667,7 @@ func TestFinalizer_closeBatch(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.Equal(t, tc.expectedClosed, closed)
 		})
 	}
 }
@@ -23,8 +23,11 @@ type txPool interface {
 	MarkReorgedTxsAsPending(ctx context.Context) error
 	MarkWIPTxsAsPending(ctx context.Context) error
 	GetPendingTxs(ctx context.Context, isClaims bool, limit uint64) ([]pool.Transaction, error)
-	UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus) error
+	UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus, reason string) error
+	AddTxEvent(ctx context.Context, hash common.Hash, event pool.TxEvent) error
 	GetTxZkCountersByHash(ctx context.Context, hash common.Hash) (*state.ZKCounters, error)
+	GetTxByHash(ctx context.Context, hash common.Hash) (*pool.Transaction, error)
+	AddTx(ctx context.Context, tx types.Transaction) error
 }
 
 // etherman contains the methods required to interact with ethereum.
@@ -68,6 +71,10 @@ type stateInterface interface {
 	GetForcedBatchesSince(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) ([]*state.ForcedBatch, error)
 	GetLastTrustedForcedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetLatestVirtualBatchTimestamp(ctx context.Context, dbTx pgx.Tx) (time.Time, error)
+	GetReorgsAfterID(ctx context.Context, reorgID uint64, dbTx pgx.Tx) ([]state.Reorg, error)
+	GetLastReorgID(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	GetProcessedReorgID(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	SetProcessedReorgID(ctx context.Context, reorgID uint64, dbTx pgx.Tx) error
 }
 
 type workerInterface interface {
@@ -106,7 +113,8 @@ type dbManagerInterface interface {
 	GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*state.Block, error)
 	GetLastTrustedForcedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetBalanceByStateRoot(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error)
-	UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus) error
+	UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus, reason string) error
+	AddTxEvent(ctx context.Context, hash common.Hash, event pool.TxEvent) error
 	GetLatestVirtualBatchTimestamp(ctx context.Context, dbTx pgx.Tx) (time.Time, error)
 }
 
@@ -135,6 +143,10 @@ type dbManagerStateInterface interface {
 	GetLastTrustedForcedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetBalanceByStateRoot(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error)
 	GetLatestVirtualBatchTimestamp(ctx context.Context, dbTx pgx.Tx) (time.Time, error)
+	GetReorgsAfterID(ctx context.Context, reorgID uint64, dbTx pgx.Tx) ([]state.Reorg, error)
+	GetLastReorgID(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	GetProcessedReorgID(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	SetProcessedReorgID(ctx context.Context, reorgID uint64, dbTx pgx.Tx) error
 }
 
 type ethTxManager interface {
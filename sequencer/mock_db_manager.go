@@ -26,6 +26,20 @@ type DbManagerMock struct {
 	mock.Mock
 }
 
+// AddTxEvent provides a mock function with given fields: ctx, hash, event
+func (_m *DbManagerMock) AddTxEvent(ctx context.Context, hash common.Hash, event pool.TxEvent) error {
+	ret := _m.Called(ctx, hash, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pool.TxEvent) error); ok {
+		r0 = rf(ctx, hash, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BeginStateTransaction provides a mock function with given fields: ctx
 func (_m *DbManagerMock) BeginStateTransaction(ctx context.Context) (pgx.Tx, error) {
 	ret := _m.Called(ctx)
@@ -489,13 +503,13 @@ func (_m *DbManagerMock) StoreProcessedTransaction(ctx context.Context, batchNum
 	return r0
 }
 
-// UpdateTxStatus provides a mock function with given fields: ctx, hash, newStatus
-func (_m *DbManagerMock) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus) error {
-	ret := _m.Called(ctx, hash, newStatus)
+// UpdateTxStatus provides a mock function with given fields: ctx, hash, newStatus, reason
+func (_m *DbManagerMock) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus, reason string) error {
+	ret := _m.Called(ctx, hash, newStatus, reason)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pool.TxStatus) error); ok {
-		r0 = rf(ctx, hash, newStatus)
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pool.TxStatus, string) error); ok {
+		r0 = rf(ctx, hash, newStatus, reason)
 	} else {
 		r0 = ret.Error(0)
 	}
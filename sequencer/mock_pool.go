@@ -12,6 +12,8 @@ import (
 	pool "github.com/0xPolygonHermez/zkevm-node/pool"
 
 	state "github.com/0xPolygonHermez/zkevm-node/state"
+
+	types "github.com/ethereum/go-ethereum/core/types"
 )
 
 // PoolMock is an autogenerated mock type for the txPool type
@@ -19,6 +21,34 @@ type PoolMock struct {
 	mock.Mock
 }
 
+// AddTx provides a mock function with given fields: ctx, tx
+func (_m *PoolMock) AddTx(ctx context.Context, tx types.Transaction) error {
+	ret := _m.Called(ctx, tx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.Transaction) error); ok {
+		r0 = rf(ctx, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddTxEvent provides a mock function with given fields: ctx, hash, event
+func (_m *PoolMock) AddTxEvent(ctx context.Context, hash common.Hash, event pool.TxEvent) error {
+	ret := _m.Called(ctx, hash, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pool.TxEvent) error); ok {
+		r0 = rf(ctx, hash, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteTransactionByHash provides a mock function with given fields: ctx, hash
 func (_m *PoolMock) DeleteTransactionByHash(ctx context.Context, hash common.Hash) error {
 	ret := _m.Called(ctx, hash)
@@ -70,6 +100,29 @@ func (_m *PoolMock) GetPendingTxs(ctx context.Context, isClaims bool, limit uint
 	return r0, r1
 }
 
+// GetTxByHash provides a mock function with given fields: ctx, hash
+func (_m *PoolMock) GetTxByHash(ctx context.Context, hash common.Hash) (*pool.Transaction, error) {
+	ret := _m.Called(ctx, hash)
+
+	var r0 *pool.Transaction
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash) *pool.Transaction); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pool.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTxZkCountersByHash provides a mock function with given fields: ctx, hash
 func (_m *PoolMock) GetTxZkCountersByHash(ctx context.Context, hash common.Hash) (*state.ZKCounters, error) {
 	ret := _m.Called(ctx, hash)
@@ -121,13 +174,13 @@ func (_m *PoolMock) MarkWIPTxsAsPending(ctx context.Context) error {
 	return r0
 }
 
-// UpdateTxStatus provides a mock function with given fields: ctx, hash, newStatus
-func (_m *PoolMock) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus) error {
-	ret := _m.Called(ctx, hash, newStatus)
+// UpdateTxStatus provides a mock function with given fields: ctx, hash, newStatus, reason
+func (_m *PoolMock) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus, reason string) error {
+	ret := _m.Called(ctx, hash, newStatus, reason)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pool.TxStatus) error); ok {
-		r0 = rf(ctx, hash, newStatus)
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pool.TxStatus, string) error); ok {
+		r0 = rf(ctx, hash, newStatus, reason)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -360,6 +360,27 @@ func (_m *StateMock) GetLastNBatches(ctx context.Context, numBatches uint, dbTx
 	return r0, r1
 }
 
+// GetLastReorgID provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetLastReorgID(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastStateRoot provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) GetLastStateRoot(ctx context.Context, dbTx pgx.Tx) (common.Hash, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -497,6 +518,50 @@ func (_m *StateMock) GetNonceByStateRoot(ctx context.Context, address common.Add
 	return r0, r1
 }
 
+// GetProcessedReorgID provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetProcessedReorgID(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReorgsAfterID provides a mock function with given fields: ctx, reorgID, dbTx
+func (_m *StateMock) GetReorgsAfterID(ctx context.Context, reorgID uint64, dbTx pgx.Tx) ([]state.Reorg, error) {
+	ret := _m.Called(ctx, reorgID, dbTx)
+
+	var r0 []state.Reorg
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []state.Reorg); ok {
+		r0 = rf(ctx, reorgID, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.Reorg)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, reorgID, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTimeForLatestBatchVirtualization provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) GetTimeForLatestBatchVirtualization(ctx context.Context, dbTx pgx.Tx) (time.Time, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -645,6 +710,20 @@ func (_m *StateMock) ProcessSequencerBatch(ctx context.Context, batchNumber uint
 	return r0, r1
 }
 
+// SetProcessedReorgID provides a mock function with given fields: ctx, reorgID, dbTx
+func (_m *StateMock) SetProcessedReorgID(ctx context.Context, reorgID uint64, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, reorgID, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r0 = rf(ctx, reorgID, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StoreTransaction provides a mock function with given fields: ctx, batchNumber, processedTx, coinbase, timestamp, dbTx
 func (_m *StateMock) StoreTransaction(ctx context.Context, batchNumber uint64, processedTx *state.ProcessTransactionResponse, coinbase common.Address, timestamp uint64, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, batchNumber, processedTx, coinbase, timestamp, dbTx)
@@ -24,20 +24,9 @@ type Sequencer struct {
 	etherman     etherman
 
 	address common.Address
-}
 
-// batchConstraints represents the constraints for a batch
-type batchConstraints struct {
-	MaxTxsPerBatch       uint64
-	MaxBatchBytesSize    uint64
-	MaxCumulativeGasUsed uint64
-	MaxKeccakHashes      uint32
-	MaxPoseidonHashes    uint32
-	MaxPoseidonPaddings  uint32
-	MaxMemAligns         uint32
-	MaxArithmetics       uint32
-	MaxBinaries          uint32
-	MaxSteps             uint32
+	batchClosedEventHandlers    []BatchClosedEventHandler
+	batchClosedEventHandlersMux sync.RWMutex
 }
 
 // TODO: Add tests to config_test.go
@@ -58,6 +47,16 @@ type L2ReorgEvent struct {
 	TxHashes []common.Hash
 }
 
+// BatchClosedEvent is the event that is triggered when the finalizer closes a
+// batch, once it is committed to the state
+type BatchClosedEvent struct {
+	BatchNumber uint64
+}
+
+// BatchClosedEventHandler represent a func that will be called by the
+// sequencer when a batch is closed
+type BatchClosedEventHandler func(e BatchClosedEvent)
+
 // ClosingSignalCh is a struct that contains all the channels that are used to receive batch closing signals
 type ClosingSignalCh struct {
 	ForcedBatchCh        chan state.ForcedBatch
@@ -118,18 +117,7 @@ func (s *Sequencer) Start(ctx context.Context) {
 		Wg: new(sync.WaitGroup),
 	}
 
-	batchConstraints := batchConstraints{
-		MaxTxsPerBatch:       s.cfg.MaxTxsPerBatch,
-		MaxBatchBytesSize:    s.cfg.MaxBatchBytesSize,
-		MaxCumulativeGasUsed: s.cfg.MaxCumulativeGasUsed,
-		MaxKeccakHashes:      s.cfg.MaxKeccakHashes,
-		MaxPoseidonHashes:    s.cfg.MaxPoseidonHashes,
-		MaxPoseidonPaddings:  s.cfg.MaxPoseidonPaddings,
-		MaxMemAligns:         s.cfg.MaxMemAligns,
-		MaxArithmetics:       s.cfg.MaxArithmetics,
-		MaxBinaries:          s.cfg.MaxBinaries,
-		MaxSteps:             s.cfg.MaxSteps,
-	}
+	batchConstraints := s.cfg.BatchConstraintsCfg
 	batchResourceWeights := batchResourceWeights{
 		WeightBatchBytesSize:    s.cfg.WeightBatchBytesSize,
 		WeightCumulativeGasUsed: s.cfg.WeightCumulativeGasUsed,
@@ -148,10 +136,11 @@ func (s *Sequencer) Start(ctx context.Context) {
 	}
 
 	worker := NewWorker(s.state, batchConstraints, batchResourceWeights)
-	dbManager := newDBManager(ctx, s.pool, s.state, worker, closingSignalCh, txsStore, batchConstraints)
+	dbManager := newDBManager(ctx, s.pool, s.state, worker, closingSignalCh, txsStore, batchConstraints, s.cfg.FrequencyToCheckReorgs.Duration)
 	go dbManager.Start()
 
 	finalizer := newFinalizer(s.cfg.Finalizer, worker, dbManager, s.state, s.address, s.isSynced, closingSignalCh, txsStore, batchConstraints)
+	finalizer.onBatchClosed = s.onBatchClosed
 	currBatch, processingReq := s.bootstrap(ctx, dbManager, finalizer)
 	go finalizer.Start(ctx, currBatch, processingReq)
 
@@ -170,6 +159,22 @@ func (s *Sequencer) Start(ctx context.Context) {
 	<-ctx.Done()
 }
 
+// RegisterBatchClosedEventHandler registers a handler called with each batch
+// closed by the sequencer, the handlers must not block
+func (s *Sequencer) RegisterBatchClosedEventHandler(h BatchClosedEventHandler) {
+	s.batchClosedEventHandlersMux.Lock()
+	defer s.batchClosedEventHandlersMux.Unlock()
+	s.batchClosedEventHandlers = append(s.batchClosedEventHandlers, h)
+}
+
+func (s *Sequencer) onBatchClosed(batchNumber uint64) {
+	s.batchClosedEventHandlersMux.RLock()
+	defer s.batchClosedEventHandlersMux.RUnlock()
+	for _, h := range s.batchClosedEventHandlers {
+		h(BatchClosedEvent{BatchNumber: batchNumber})
+	}
+}
+
 func (s *Sequencer) bootstrap(ctx context.Context, dbManager *dbManager, finalizer *finalizer) (*WipBatch, *state.ProcessRequest) {
 	var (
 		currBatch      *WipBatch
@@ -273,7 +278,7 @@ func (s *Sequencer) isSynced(ctx context.Context) bool {
 	return true
 }
 
-func getMaxRemainingResources(constraints batchConstraints) batchResources {
+func getMaxRemainingResources(constraints state.BatchConstraintsCfg) batchResources {
 	return batchResources{
 		zKCounters: state.ZKCounters{
 			CumulativeGasUsed:    constraints.MaxCumulativeGasUsed,
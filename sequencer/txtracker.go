@@ -27,7 +27,7 @@ type TxTracker struct {
 }
 
 // newTxTracker creates and inti a TxTracker
-func newTxTracker(tx types.Transaction, isClaim bool, counters state.ZKCounters, constraints batchConstraints, weights batchResourceWeights) (*TxTracker, error) {
+func newTxTracker(tx types.Transaction, isClaim bool, counters state.ZKCounters, constraints state.BatchConstraintsCfg, weights batchResourceWeights) (*TxTracker, error) {
 	addr, err := state.GetSender(tx)
 	if err != nil {
 		return nil, err
@@ -59,13 +59,13 @@ func newTxTracker(tx types.Transaction, isClaim bool, counters state.ZKCounters,
 }
 
 // updateZKCounters updates the counters of the tx and recalculates the tx efficiency
-func (tx *TxTracker) updateZKCounters(counters state.ZKCounters, constraints batchConstraints, weights batchResourceWeights) {
+func (tx *TxTracker) updateZKCounters(counters state.ZKCounters, constraints state.BatchConstraintsCfg, weights batchResourceWeights) {
 	tx.BatchResources.zKCounters = counters
 	tx.calculateEfficiency(constraints, weights)
 }
 
 // calculateEfficiency calculates the tx efficiency
-func (tx *TxTracker) calculateEfficiency(constraints batchConstraints, weights batchResourceWeights) {
+func (tx *TxTracker) calculateEfficiency(constraints state.BatchConstraintsCfg, weights batchResourceWeights) {
 	const perThousand = 1000 // TODO: Add this as config parameter
 
 	totalWeight := float64(weights.WeightArithmetics + weights.WeightBatchBytesSize + weights.WeightBinaries + weights.WeightCumulativeGasUsed +
@@ -31,7 +31,7 @@ func TestTxTrackerEfficiencyCalculation(t *testing.T) {
 	rcWeigth.WeightBatchBytesSize = 2
 
 	// Init ZKEVM resourceCostMax values
-	rcMax := batchConstraints{}
+	rcMax := state.BatchConstraintsCfg{}
 	rcMax.MaxCumulativeGasUsed = 10
 	rcMax.MaxArithmetics = 10
 	rcMax.MaxBinaries = 10
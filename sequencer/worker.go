@@ -19,12 +19,12 @@ type Worker struct {
 	workerMutex    sync.Mutex
 	// dbManager            dbManagerInterface
 	state                stateInterface
-	batchConstraints     batchConstraints
+	batchConstraints     state.BatchConstraintsCfg
 	batchResourceWeights batchResourceWeights
 }
 
 // NewWorker creates an init a worker
-func NewWorker(state stateInterface, constraints batchConstraints, weights batchResourceWeights) *Worker {
+func NewWorker(state stateInterface, constraints state.BatchConstraintsCfg, weights batchResourceWeights) *Worker {
 	w := Worker{
 		pool:                 make(map[string]*addrQueue),
 		efficiencyList:       newEfficiencyList(),
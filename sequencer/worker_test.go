@@ -76,7 +76,7 @@ func TestWorkerAddTx(t *testing.T) {
 	rcWeigth.WeightBatchBytesSize = 2
 
 	// Init ZKEVM resourceCostMax values
-	rcMax := batchConstraints{}
+	rcMax := state.BatchConstraintsCfg{}
 	rcMax.MaxCumulativeGasUsed = 10
 	rcMax.MaxArithmetics = 10
 	rcMax.MaxBinaries = 10
@@ -182,7 +182,7 @@ func TestWorkerGetBestTx(t *testing.T) {
 	rcWeigth.WeightBatchBytesSize = 2
 
 	// Init ZKEVM resourceCostMax values
-	rcMax := batchConstraints{}
+	rcMax := state.BatchConstraintsCfg{}
 	rcMax.MaxCumulativeGasUsed = 10
 	rcMax.MaxArithmetics = 10
 	rcMax.MaxBinaries = 10
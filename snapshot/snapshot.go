@@ -0,0 +1,362 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	// Version is the version of the snapshot format
+	Version = 2
+
+	manifestFile = "manifest.json"
+)
+
+var (
+	// ErrStateNotEmpty is returned when importing a snapshot into a state
+	// database that already has data
+	ErrStateNotEmpty = errors.New("the state database is not empty")
+	// ErrNoVerifiedBatch is returned when exporting a snapshot of a state
+	// without batches verified on L1
+	ErrNoVerifiedBatch = errors.New("there is no verified batch to export")
+	// ErrMigrationMismatch is returned when importing a snapshot exported
+	// from a state database with a different schema
+	ErrMigrationMismatch = errors.New("the snapshot was exported with a different state migration")
+)
+
+// Manifest describes the content of a snapshot
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Migration is the last state migration applied to the exported
+	// database, the snapshot is only imported into a database with the same
+	// schema
+	Migration string `json:"migration"`
+	// BatchNumber is the last batch of the snapshot, it is verified on L1
+	BatchNumber uint64 `json:"batchNumber"`
+	// StateRoot is the state root of the last batch, the snapshot contains all
+	// the nodes of its tree
+	StateRoot common.Hash `json:"stateRoot"`
+	// BlockNumber is the last L1 block of the snapshot, the synchronizer
+	// resumes from it
+	BlockNumber uint64 `json:"blockNumber"`
+	Nodes       uint64 `json:"nodes"`
+	Programs    uint64 `json:"programs"`
+	// Files are the sha256 hashes of the files of the snapshot by name
+	Files map[string]string `json:"files"`
+}
+
+// L1Verifier gives the state roots of the batches verified on L1
+type L1Verifier interface {
+	GetVerifiedBatchStateRoot(batchNum uint64) (common.Hash, error)
+}
+
+// Export writes into the directory a snapshot of the state up to the given
+// verified batch, the last verified batch is used when it is 0. The snapshot
+// only contains the L1 blocks before the sequence of the next batch, so the
+// synchronizer doesn't miss any batch when resuming from the snapshot
+func Export(ctx context.Context, stateDB *pgxpool.Pool, store merkletree.NodeStore, dir string, batchNumber uint64) (*Manifest, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil { //nolint:gomnd
+		return nil, err
+	}
+
+	// a single read only transaction keeps the rows of all the tables consistent
+	dbTx, err := stateDB.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := dbTx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Errorf("error rolling back the snapshot export: %v", err)
+		}
+	}()
+
+	manifest := &Manifest{
+		Version:     Version,
+		CreatedAt:   time.Now().UTC(),
+		BatchNumber: batchNumber,
+		Files:       make(map[string]string),
+	}
+	if manifest.Migration, err = getMigration(ctx, dbTx); err != nil {
+		return nil, err
+	}
+	if manifest.BatchNumber == 0 {
+		const lastVerifiedBatchSQL = "SELECT COALESCE(MAX(batch_num), 0) FROM state.verified_batch"
+		if err := dbTx.QueryRow(ctx, lastVerifiedBatchSQL).Scan(&manifest.BatchNumber); err != nil {
+			return nil, err
+		}
+		if manifest.BatchNumber == 0 {
+			return nil, ErrNoVerifiedBatch
+		}
+	}
+
+	var stateRoot string
+	const getStateRootSQL = `SELECT b.state_root FROM state.batch b
+		INNER JOIN state.verified_batch v ON v.batch_num = b.batch_num
+		WHERE b.batch_num = $1`
+	if err := dbTx.QueryRow(ctx, getStateRootSQL, manifest.BatchNumber).Scan(&stateRoot); errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("batch %d is not verified", manifest.BatchNumber)
+	} else if err != nil {
+		return nil, err
+	}
+	manifest.StateRoot = common.HexToHash(stateRoot)
+
+	const getBlockNumberSQL = `SELECT COALESCE(
+		(SELECT MIN(block_num) - 1 FROM state.virtual_batch WHERE batch_num > $1),
+		(SELECT MAX(block_num) FROM state.block))`
+	if err := dbTx.QueryRow(ctx, getBlockNumberSQL, manifest.BatchNumber).Scan(&manifest.BlockNumber); err != nil {
+		return nil, err
+	}
+
+	for _, t := range tables {
+		name := t.name + ".csv"
+		query := t.exportQuery(manifest.BatchNumber, manifest.BlockNumber)
+		err := writeFile(dir, name, manifest, func(w io.Writer) error {
+			_, err := dbTx.Conn().PgConn().CopyTo(ctx, w, fmt.Sprintf("COPY (%s) TO STDOUT WITH (FORMAT csv)", query))
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error exporting %s: %w", t.name, err)
+		}
+		log.Infof("%s exported", t.name)
+	}
+
+	// the nodes and the programs are walked at once, both files are hashed
+	// once they are complete
+	nodes, err := os.Create(filepath.Join(dir, nodesFile))
+	if err != nil {
+		return nil, err
+	}
+	defer nodes.Close()
+	programs, err := os.Create(filepath.Join(dir, programsFile))
+	if err != nil {
+		return nil, err
+	}
+	defer programs.Close()
+	var root [4]uint64
+	copy(root[:], scalarToh4(manifest.StateRoot))
+	manifest.Nodes, manifest.Programs, err = writeTree(ctx, store, root, nodes, programs)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting the tree: %w", err)
+	}
+	for _, name := range []string{nodesFile, programsFile} {
+		if manifest.Files[name], err = hashFile(filepath.Join(dir, name)); err != nil {
+			return nil, err
+		}
+	}
+	log.Infof("tree exported, %d nodes and %d programs", manifest.Nodes, manifest.Programs)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return manifest, os.WriteFile(filepath.Join(dir, manifestFile), data, 0600) //nolint:gomnd
+}
+
+// Import loads the snapshot of the directory into an empty state database and
+// the node store. The files are checked against the hashes of the manifest
+// and the state root of the snapshot is checked against the one verified on
+// L1 before the rows are committed
+func Import(ctx context.Context, stateDB *pgxpool.Pool, store merkletree.NodeStore, dir string, verifier L1Verifier) (*Manifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.VerifyFiles(dir); err != nil {
+		return nil, err
+	}
+
+	var blocks uint64
+	if err := stateDB.QueryRow(ctx, "SELECT COUNT(*) FROM state.block").Scan(&blocks); err != nil {
+		return nil, err
+	}
+	if blocks > 0 {
+		return nil, ErrStateNotEmpty
+	}
+	conn, err := stateDB.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	migration, err := getMigration(ctx, conn)
+	conn.Release()
+	if err != nil {
+		return nil, err
+	}
+	if migration != manifest.Migration {
+		return nil, fmt.Errorf("%w: snapshot %s, state database %s", ErrMigrationMismatch, manifest.Migration, migration)
+	}
+
+	l1StateRoot, err := verifier.GetVerifiedBatchStateRoot(manifest.BatchNumber)
+	if err != nil {
+		return nil, err
+	}
+	if l1StateRoot != manifest.StateRoot {
+		return nil, fmt.Errorf("state root %s of batch %d doesn't match the one verified on L1 %s", manifest.StateRoot.String(), manifest.BatchNumber, l1StateRoot.String())
+	}
+
+	// the nodes are stored first, they are useless without the rows
+	nodes, err := os.Open(filepath.Join(dir, nodesFile))
+	if err != nil {
+		return nil, err
+	}
+	defer nodes.Close()
+	programs, err := os.Open(filepath.Join(dir, programsFile))
+	if err != nil {
+		return nil, err
+	}
+	defer programs.Close()
+	if err := readTree(ctx, store, nodes, programs); err != nil {
+		return nil, fmt.Errorf("error importing the tree: %w", err)
+	}
+	var root [4]uint64
+	copy(root[:], scalarToh4(manifest.StateRoot))
+	noop := func([4]uint64, [12]uint64) error { return nil }
+	if err := merkletree.WalkTree(ctx, store, root, noop, func([4]uint64, []byte) error { return nil }); err != nil {
+		return nil, fmt.Errorf("the tree of the snapshot is not complete: %w", err)
+	}
+	log.Infof("tree imported, %d nodes and %d programs", manifest.Nodes, manifest.Programs)
+
+	dbTx, err := stateDB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := dbTx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Errorf("error rolling back the snapshot import: %v", err)
+		}
+	}()
+	if _, err := dbTx.Exec(ctx, "DELETE FROM state.sync_info"); err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		if err := copyFrom(ctx, dbTx, dir, t); err != nil {
+			return nil, fmt.Errorf("error importing %s: %w", t.name, err)
+		}
+		if t.sequence != "" {
+			if _, err := dbTx.Exec(ctx, fmt.Sprintf("SELECT setval('%s', COALESCE(MAX(id), 1)) FROM %s", t.sequence, t.name)); err != nil {
+				return nil, err
+			}
+		}
+		log.Infof("%s imported", t.name)
+	}
+
+	var stateRoot string
+	if err := dbTx.QueryRow(ctx, "SELECT state_root FROM state.batch WHERE batch_num = $1", manifest.BatchNumber).Scan(&stateRoot); err != nil {
+		return nil, err
+	}
+	if common.HexToHash(stateRoot) != manifest.StateRoot {
+		return nil, fmt.Errorf("state root %s of the imported batch %d doesn't match the manifest %s", stateRoot, manifest.BatchNumber, manifest.StateRoot.String())
+	}
+	return manifest, dbTx.Commit(ctx)
+}
+
+// ReadManifest reads the manifest of the snapshot in the directory
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Version != Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", manifest.Version, Version)
+	}
+	return manifest, nil
+}
+
+// VerifyFiles checks that all the files of the snapshot are in the directory
+// and match the hashes of the manifest
+func (m *Manifest) VerifyFiles(dir string) error {
+	expected := []string{nodesFile, programsFile}
+	for _, t := range tables {
+		expected = append(expected, t.name+".csv")
+	}
+	for _, name := range expected {
+		if _, ok := m.Files[name]; !ok {
+			return fmt.Errorf("file %s is missing in the manifest", name)
+		}
+	}
+	for name, expectedHash := range m.Files {
+		hash, err := hashFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if hash != expectedHash {
+			return fmt.Errorf("invalid hash of %s, expected %s got %s", name, expectedHash, hash)
+		}
+	}
+	return nil
+}
+
+// writeFile creates the file of the snapshot, adding its hash to the manifest
+func writeFile(dir, name string, manifest *Manifest, write func(w io.Writer) error) error {
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if err := write(io.MultiWriter(f, h)); err != nil {
+		return err
+	}
+	manifest.Files[name] = hex.EncodeToString(h.Sum(nil))
+	return f.Sync()
+}
+
+func copyFrom(ctx context.Context, dbTx pgx.Tx, dir string, t table) error {
+	f, err := os.Open(filepath.Join(dir, t.name+".csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = dbTx.Conn().PgConn().CopyFrom(ctx, f, fmt.Sprintf("COPY %s (%s) FROM STDIN WITH (FORMAT csv)", t.name, t.columnList("")))
+	return err
+}
+
+// getMigration returns the id of the last migration applied to the database
+func getMigration(ctx context.Context, q pgxQuerier) (string, error) {
+	const getMigrationSQL = "SELECT COALESCE(MAX(id), '') FROM gorp_migrations"
+	var migration string
+	err := q.QueryRow(ctx, getMigrationSQL).Scan(&migration)
+	return migration, err
+}
+
+type pgxQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// scalarToh4 splits the hash into the 4 elements used by the tree, the first
+// element is the least significant one
+func scalarToh4(h common.Hash) []uint64 {
+	h4, _ := merkletree.StringToh4(h.String())
+	return h4
+}
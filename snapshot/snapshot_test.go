@@ -0,0 +1,125 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTree(t *testing.T) (*merkletree.MemoryTree, []byte, common.Address) {
+	ctx := context.Background()
+	memTree := merkletree.NewMemoryTree()
+	tree := merkletree.NewStateTree(merkletree.NewLocalStateDBClient(memTree))
+	address := common.HexToAddress("0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D")
+
+	root, _, err := tree.SetBalance(ctx, address, big.NewInt(1000), nil)
+	require.NoError(t, err)
+	root, _, err = tree.SetNonce(ctx, address, big.NewInt(3), root)
+	require.NoError(t, err)
+	root, _, err = tree.SetCode(ctx, address, common.Hex2Bytes("6080604052"), root)
+	require.NoError(t, err)
+	root, _, err = tree.SetStorageAt(ctx, address, big.NewInt(1), big.NewInt(42), root)
+	require.NoError(t, err)
+	return memTree, root, address
+}
+
+func TestWriteReadTree(t *testing.T) {
+	ctx := context.Background()
+	memTree, root, address := newTestTree(t)
+	var root4 [4]uint64
+	copy(root4[:], scalarToh4(common.BytesToHash(root)))
+
+	var nodes, programs bytes.Buffer
+	nodesCount, programsCount, err := writeTree(ctx, merkletree.NewMemoryNodeStore(memTree), root4, &nodes, &programs)
+	require.NoError(t, err)
+	assert.NotZero(t, nodesCount)
+	assert.Equal(t, uint64(1), programsCount)
+
+	imported := merkletree.NewMemoryTree()
+	require.NoError(t, readTree(ctx, merkletree.NewMemoryNodeStore(imported), &nodes, &programs))
+
+	tree := merkletree.NewStateTree(merkletree.NewLocalStateDBClient(imported))
+	balance, err := tree.GetBalance(ctx, address, root)
+	require.NoError(t, err)
+	assert.Equal(t, "1000", balance.String())
+	nonce, err := tree.GetNonce(ctx, address, root)
+	require.NoError(t, err)
+	assert.Equal(t, "3", nonce.String())
+	code, err := tree.GetCode(ctx, address, root)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hex2Bytes("6080604052"), code)
+	value, err := tree.GetStorageAt(ctx, address, big.NewInt(1), root)
+	require.NoError(t, err)
+	assert.Equal(t, "42", value.String())
+}
+
+func TestReadTreeInvalidNode(t *testing.T) {
+	ctx := context.Background()
+	memTree, root, _ := newTestTree(t)
+	var root4 [4]uint64
+	copy(root4[:], scalarToh4(common.BytesToHash(root)))
+
+	var nodes, programs bytes.Buffer
+	_, _, err := writeTree(ctx, merkletree.NewMemoryNodeStore(memTree), root4, &nodes, &programs)
+	require.NoError(t, err)
+
+	// change the last byte of the preimage of the first node
+	data := nodes.Bytes()
+	data[32+95] ^= 1
+	err = readTree(ctx, merkletree.NewMemoryNodeStore(merkletree.NewMemoryTree()), bytes.NewReader(data), &programs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid node")
+}
+
+func TestManifestVerifyFiles(t *testing.T) {
+	dir := t.TempDir()
+	manifest := &Manifest{Version: Version, Files: make(map[string]string)}
+	names := []string{nodesFile, programsFile}
+	for _, table := range tables {
+		names = append(names, table.name+".csv")
+	}
+	for _, name := range names {
+		require.NoError(t, writeFile(dir, name, manifest, func(w io.Writer) error {
+			_, err := w.Write([]byte(name))
+			return err
+		}))
+	}
+	require.NoError(t, manifest.VerifyFiles(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, nodesFile), []byte("tampered"), 0600))
+	err := manifest.VerifyFiles(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid hash of "+nodesFile)
+
+	delete(manifest.Files, programsFile)
+	err = manifest.VerifyFiles(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "file "+programsFile+" is missing")
+}
+
+func TestTablesExportQuery(t *testing.T) {
+	for _, table := range tables {
+		query := table.exportQuery(10, 20)
+		assert.NotContains(t, query, "*", table.name)
+		assert.NotContains(t, query, "%!", table.name)
+	}
+
+	receipts := tables[0]
+	for _, table := range tables {
+		if table.name == "state.receipt" {
+			receipts = table
+		}
+	}
+	assert.Equal(t, `SELECT t.tx_hash, t.type, t.post_state, t.status, t.cumulative_gas_used, t.gas_used, t.block_num, t.tx_index, t.contract_address, t.zk_counters FROM state.receipt t
+			INNER JOIN state.l2block b ON b.block_num = t.block_num
+			WHERE b.batch_num <= 10`, receipts.exportQuery(10, 20))
+}
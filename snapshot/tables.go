@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// table is a table of the state database included in the snapshots. The query
+// selecting the rows is formatted with the batch number and the L1 block number
+// of the snapshot, COPY doesn't accept parameters, and it selects the columns
+// in the order they are listed. The tables are sorted so they can be imported
+// in order without breaking the foreign keys
+type table struct {
+	name    string
+	columns []string
+	query   string
+	// sequence is the sequence of the id of the table, it is moved past the
+	// imported ids
+	sequence string
+}
+
+// columnList returns the columns of the table separated by commas, each one
+// prefixed by the alias of the table in the query
+func (t table) columnList(alias string) string {
+	if alias == "" {
+		return strings.Join(t.columns, ", ")
+	}
+	prefixed := make([]string, 0, len(t.columns))
+	for _, column := range t.columns {
+		prefixed = append(prefixed, alias+"."+column)
+	}
+	return strings.Join(prefixed, ", ")
+}
+
+var tables = []table{
+	{
+		name:    "state.block",
+		columns: []string{"block_num", "block_hash", "parent_hash", "received_at"},
+		query:   "SELECT %[3]s FROM state.block WHERE block_num <= %[2]d",
+	},
+	{
+		name:    "state.forced_batch",
+		columns: []string{"forced_batch_num", "global_exit_root", "timestamp", "raw_txs_data", "coinbase", "block_num"},
+		query:   "SELECT %[3]s FROM state.forced_batch WHERE block_num <= %[2]d",
+	},
+	{
+		name:    "state.batch",
+		columns: []string{"batch_num", "global_exit_root", "local_exit_root", "state_root", "acc_input_hash", "timestamp", "coinbase", "raw_txs_data", "forced_batch_num"},
+		query:   "SELECT %[3]s FROM state.batch WHERE batch_num <= %[1]d",
+	},
+	{
+		name:    "state.virtual_batch",
+		columns: []string{"batch_num", "tx_hash", "coinbase", "block_num", "seen_at", "sequencer_addr", "l1_finality", "last_forced_batch_num"},
+		query:   "SELECT %[3]s FROM state.virtual_batch WHERE batch_num <= %[1]d",
+	},
+	{
+		name:    "state.verified_batch",
+		columns: []string{"batch_num", "tx_hash", "aggregator", "state_root", "block_num", "is_trusted"},
+		query:   "SELECT %[3]s FROM state.verified_batch WHERE batch_num <= %[1]d AND block_num <= %[2]d",
+	},
+	{
+		name:    "state.l2block",
+		columns: []string{"block_num", "block_hash", "header", "uncles", "parent_hash", "state_root", "received_at", "created_at", "batch_num"},
+		query:   "SELECT %[3]s FROM state.l2block WHERE batch_num <= %[1]d",
+	},
+	{
+		name:    "state.transaction",
+		columns: []string{"hash", "encoded", "decoded", "l2_block_num"},
+		query: `SELECT %[4]s FROM state.transaction t
+			INNER JOIN state.l2block b ON b.block_num = t.l2_block_num
+			WHERE b.batch_num <= %[1]d`,
+	},
+	{
+		name:    "state.receipt",
+		columns: []string{"tx_hash", "type", "post_state", "status", "cumulative_gas_used", "gas_used", "block_num", "tx_index", "contract_address", "zk_counters"},
+		query: `SELECT %[4]s FROM state.receipt t
+			INNER JOIN state.l2block b ON b.block_num = t.block_num
+			WHERE b.batch_num <= %[1]d`,
+	},
+	{
+		name:    "state.log",
+		columns: []string{"tx_hash", "log_index", "address", "data", "topic0", "topic1", "topic2", "topic3"},
+		query: `SELECT %[4]s FROM state.log t
+			INNER JOIN state.transaction tx ON tx.hash = t.tx_hash
+			INNER JOIN state.l2block b ON b.block_num = tx.l2_block_num
+			WHERE b.batch_num <= %[1]d`,
+	},
+	{
+		name:     "state.exit_root",
+		columns:  []string{"id", "block_num", "timestamp", "mainnet_exit_root", "rollup_exit_root", "global_exit_root"},
+		query:    "SELECT %[3]s FROM state.exit_root WHERE block_num <= %[2]d",
+		sequence: "state.exit_root_id_seq",
+	},
+	{
+		name:    "state.sequences",
+		columns: []string{"from_batch_num", "to_batch_num"},
+		query:   "SELECT %[3]s FROM state.sequences WHERE to_batch_num <= %[1]d",
+	},
+	{
+		name:    "state.fork_id",
+		columns: []string{"fork_id", "from_batch_num", "version", "block_num"},
+		query:   "SELECT %[3]s FROM state.fork_id WHERE block_num <= %[2]d",
+	},
+	{
+		name:     "state.reorgs",
+		columns:  []string{"id", "kind", "depth", "first_block_num", "first_batch_num", "old_hash", "new_hash", "reason", "dropped_txs", "created_at"},
+		query:    "SELECT %[3]s FROM state.reorgs WHERE first_block_num <= %[2]d",
+		sequence: "state.reorgs_id_seq",
+	},
+	{
+		// the reorgs after the snapshot are not imported, so they can't have
+		// been processed
+		name:    "state.processed_reorg",
+		columns: []string{"reorg_id"},
+		query: `SELECT LEAST(reorg_id, (SELECT COALESCE(MAX(id), 0) FROM state.reorgs WHERE first_block_num <= %[2]d))
+			FROM state.processed_reorg`,
+	},
+	{
+		// a reset to a block of the snapshot is completed by the synchronizer
+		// once the snapshot is imported
+		name:    "state.reset_in_progress",
+		columns: []string{"block_num", "started_at", "batch_num"},
+		query:   "SELECT %[3]s FROM state.reset_in_progress WHERE block_num <= %[2]d",
+	},
+	{
+		name:    "state.sync_info",
+		columns: []string{"last_batch_num_seen", "last_batch_num_consolidated", "init_sync_batch", "pruned_batch_num"},
+		query: `SELECT %[1]d, COALESCE(MAX(batch_num), 0), (SELECT init_sync_batch FROM state.sync_info LIMIT 1),
+			(SELECT LEAST(pruned_batch_num, %[1]d) FROM state.sync_info LIMIT 1)
+			FROM state.verified_batch WHERE batch_num <= %[1]d AND block_num <= %[2]d`,
+	},
+}
+
+// exportQuery returns the query selecting the rows of the table in the snapshot
+func (t table) exportQuery(batchNumber, blockNumber uint64) string {
+	return fmt.Sprintf(t.query, batchNumber, blockNumber, t.columnList(""), t.columnList("t"))
+}
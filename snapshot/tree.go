@@ -0,0 +1,98 @@
+package snapshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+)
+
+const (
+	nodesFile    = "nodes.bin"
+	programsFile = "programs.bin"
+)
+
+// writeTree writes the nodes and the programs of the tree with the given root,
+// each node is written as its hash followed by its preimage and each program as
+// its hash followed by its length and its bytecode, all of them big endian
+func writeTree(ctx context.Context, store merkletree.NodeStore, root [4]uint64, nodes, programs io.Writer) (nodesCount, programsCount uint64, err error) {
+	nodesW := bufio.NewWriter(nodes)
+	programsW := bufio.NewWriter(programs)
+	err = merkletree.WalkTree(ctx, store, root,
+		func(hash [4]uint64, data [12]uint64) error {
+			nodesCount++
+			if err := binary.Write(nodesW, binary.BigEndian, hash); err != nil {
+				return err
+			}
+			return binary.Write(nodesW, binary.BigEndian, data)
+		},
+		func(hash [4]uint64, data []byte) error {
+			programsCount++
+			if err := binary.Write(programsW, binary.BigEndian, hash); err != nil {
+				return err
+			}
+			if err := binary.Write(programsW, binary.BigEndian, uint32(len(data))); err != nil {
+				return err
+			}
+			_, err := programsW.Write(data)
+			return err
+		},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := nodesW.Flush(); err != nil {
+		return 0, 0, err
+	}
+	return nodesCount, programsCount, programsW.Flush()
+}
+
+// readTree stores the nodes and the programs written by writeTree, checking
+// the hash of every node
+func readTree(ctx context.Context, store merkletree.NodeStore, nodes, programs io.Reader) error {
+	nodesR := bufio.NewReader(nodes)
+	for {
+		var hash [4]uint64
+		var data [12]uint64
+		if err := binary.Read(nodesR, binary.BigEndian, &hash); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := binary.Read(nodesR, binary.BigEndian, &data); err != nil {
+			return err
+		}
+		if err := merkletree.CheckNodeHash(hash, data); err != nil {
+			return err
+		}
+		if err := store.SetNode(ctx, hash, data); err != nil {
+			return err
+		}
+	}
+
+	programsR := bufio.NewReader(programs)
+	for {
+		var hash [4]uint64
+		var length uint32
+		if err := binary.Read(programsR, binary.BigEndian, &hash); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := binary.Read(programsR, binary.BigEndian, &length); err != nil {
+			return err
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(programsR, data); err != nil {
+			return fmt.Errorf("error reading program %s: %w", merkletree.H4ToString(hash[:]), err)
+		}
+		if err := store.SetProgram(ctx, hash, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -59,6 +59,41 @@ type VirtualBatch struct {
 	SequencerAddr common.Address
 	BlockNumber   uint64
 	SeenAt        uint64
+	// L1Finality is the finality level reached by the L1 block the batch was
+	// seen at, latest if not set
+	L1Finality L1Finality
+	// LastForcedBatchNum is the number of the last forced batch included up
+	// to this batch, the forced batches are included in order so it is also
+	// the running count of them
+	LastForcedBatchNum uint64
+}
+
+// L1Finality is a finality level of the L1 blocks
+type L1Finality string
+
+const (
+	// L1FinalityPreconfirmed is the level of the batches taken from the
+	// preconfirmations of the sequencer, before its L1 block was synced
+	L1FinalityPreconfirmed L1Finality = "preconfirmed"
+	// L1FinalityLatest is the level of the blocks that can still be reorged
+	L1FinalityLatest L1Finality = "latest"
+	// L1FinalitySafe is the level of the blocks that are unlikely to be reorged
+	L1FinalitySafe L1Finality = "safe"
+	// L1FinalityFinalized is the level of the blocks that can't be reorged
+	L1FinalityFinalized L1Finality = "finalized"
+)
+
+// l1FinalityLevels are the finality levels from the lowest to the highest
+var l1FinalityLevels = []L1Finality{L1FinalityPreconfirmed, L1FinalityLatest, L1FinalitySafe, L1FinalityFinalized}
+
+// Lower returns the finality levels below this one
+func (f L1Finality) Lower() []L1Finality {
+	for i, level := range l1FinalityLevels {
+		if level == f {
+			return l1FinalityLevels[:i]
+		}
+	}
+	return nil
 }
 
 // Sequence represents the sequence interval
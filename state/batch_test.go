@@ -0,0 +1,14 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestL1FinalityLower(t *testing.T) {
+	assert.Empty(t, L1FinalityPreconfirmed.Lower())
+	assert.Equal(t, []L1Finality{L1FinalityPreconfirmed}, L1FinalityLatest.Lower())
+	assert.Equal(t, []L1Finality{L1FinalityPreconfirmed, L1FinalityLatest, L1FinalitySafe}, L1FinalityFinalized.Lower())
+	assert.Empty(t, L1Finality("unknown").Lower())
+}
@@ -0,0 +1,146 @@
+package state
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/cache"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// blockCache keeps the recent L2 blocks, indexed by number and by hash. Only
+// committed blocks are cached: the ones announced by the new L2 block events
+// and the ones read without a dbTx. The blocks removed by a reset or a trusted
+// reorg are removed from the cache along with them, the monitor of new L2
+// blocks removes the ones it finds changed otherwise
+type blockCache struct {
+	byNumber *cache.LRU
+	byHash   *cache.LRU
+}
+
+func newBlockCache(size int) *blockCache {
+	return &blockCache{
+		byNumber: cache.NewLRU("l2block_by_number", size),
+		byHash:   cache.NewLRU("l2block_by_hash", size),
+	}
+}
+
+func (c *blockCache) add(block *types.Block) {
+	c.byNumber.Add(block.NumberU64(), block)
+	c.byHash.Add(block.Hash(), block)
+}
+
+// onNewL2Block caches the new block, removing the cached blocks from its
+// number on, which were replaced by a reorg if there are any
+func (c *blockCache) onNewL2Block(e NewL2BlockEvent) {
+	c.removeFrom(e.Block.NumberU64())
+	c.add(&e.Block)
+}
+
+// removeFrom removes the cached blocks from the given number on
+func (c *blockCache) removeFrom(number uint64) {
+	fromNumber := func(key, value interface{}) bool {
+		return value.(*types.Block).NumberU64() >= number
+	}
+	c.byNumber.RemoveIf(fromNumber)
+	c.byHash.RemoveIf(fromNumber)
+}
+
+// firstReorgedBlock returns the first block replaced by a reorg, knowing that
+// the given block was replaced, by comparing the cached blocks before it with
+// the stored ones
+func (c *blockCache) firstReorgedBlock(number uint64, storedBlock func(number uint64) (*types.Block, error)) (uint64, error) {
+	for number > 1 {
+		cached, ok := c.byNumber.Get(number - 1)
+		if !ok {
+			break
+		}
+		stored, err := storedBlock(number - 1)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return 0, err
+		}
+		if err == nil && stored.Hash() == cached.(*types.Block).Hash() {
+			break
+		}
+		number--
+	}
+	return number, nil
+}
+
+// GetL2BlockByNumber gets a l2 block by its number, from the cache of recent
+// blocks when it is enabled and the block is read without a dbTx
+func (s *State) GetL2BlockByNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (*types.Block, error) {
+	if s.blockCache == nil {
+		return s.PostgresStorage.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+	}
+	s.startMonitoringNewL2Blocks()
+	if dbTx != nil {
+		return s.PostgresStorage.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+	}
+	if block, ok := s.blockCache.byNumber.Get(blockNumber); ok {
+		return block.(*types.Block), nil
+	}
+	block, err := s.PostgresStorage.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+	if err == nil {
+		s.blockCache.add(block)
+	}
+	return block, err
+}
+
+// GetL2BlockByHash gets a l2 block from its hash, from the cache of recent
+// blocks when it is enabled and the block is read without a dbTx
+func (s *State) GetL2BlockByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*types.Block, error) {
+	if s.blockCache == nil {
+		return s.PostgresStorage.GetL2BlockByHash(ctx, hash, dbTx)
+	}
+	s.startMonitoringNewL2Blocks()
+	if dbTx != nil {
+		return s.PostgresStorage.GetL2BlockByHash(ctx, hash, dbTx)
+	}
+	if block, ok := s.blockCache.byHash.Get(hash); ok {
+		return block.(*types.Block), nil
+	}
+	block, err := s.PostgresStorage.GetL2BlockByHash(ctx, hash, dbTx)
+	if err == nil {
+		s.blockCache.add(block)
+	}
+	return block, err
+}
+
+// ResetTrustedState removes the batches with number greater than the given one
+// from the database, along with the cached blocks of their L2 blocks
+func (s *State) ResetTrustedState(ctx context.Context, batchNum uint64, dbTx pgx.Tx) error {
+	if err := s.PostgresStorage.ResetTrustedState(ctx, batchNum, dbTx); err != nil {
+		return err
+	}
+	return s.removeBlocksAfterLast(ctx, dbTx)
+}
+
+// ResetStep removes a chunk of the state above the given block, along with the
+// cached blocks of the L2 blocks removed, see PostgresStorage.ResetStep
+func (s *State) ResetStep(ctx context.Context, blockNumber uint64, chunkSize uint64, dbTx pgx.Tx) (bool, error) {
+	done, err := s.PostgresStorage.ResetStep(ctx, blockNumber, chunkSize, dbTx)
+	if err != nil {
+		return false, err
+	}
+	return done, s.removeBlocksAfterLast(ctx, dbTx)
+}
+
+// removeBlocksAfterLast removes the cached blocks after the last stored L2
+// block, which were removed from the database
+func (s *State) removeBlocksAfterLast(ctx context.Context, dbTx pgx.Tx) error {
+	if s.blockCache == nil {
+		return nil
+	}
+	lastBlockNumber, err := s.PostgresStorage.GetLastL2BlockNumber(ctx, dbTx)
+	if errors.Is(err, ErrStateNotSynchronized) {
+		s.blockCache.removeFrom(0)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	s.blockCache.removeFrom(lastBlockNumber + 1)
+	return nil
+}
@@ -0,0 +1,83 @@
+package state
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockCacheOnNewL2Block(t *testing.T) {
+	newBlock := func(number int64, time uint64) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(number), Time: time})
+	}
+	c := newBlockCache(10)
+	block1, block2, block3 := newBlock(1, 0), newBlock(2, 0), newBlock(3, 0)
+	c.add(block1)
+	c.add(block2)
+	c.add(block3)
+
+	// block 2 is replaced by a reorg
+	reorgedBlock2 := newBlock(2, 1)
+	c.onNewL2Block(NewL2BlockEvent{Block: *reorgedBlock2})
+
+	cached, ok := c.byNumber.Get(uint64(1))
+	assert.True(t, ok)
+	assert.Equal(t, block1.Hash(), cached.(*types.Block).Hash())
+	cached, ok = c.byNumber.Get(uint64(2))
+	assert.True(t, ok)
+	assert.Equal(t, reorgedBlock2.Hash(), cached.(*types.Block).Hash())
+	_, ok = c.byNumber.Get(uint64(3))
+	assert.False(t, ok)
+	_, ok = c.byHash.Get(block2.Hash())
+	assert.False(t, ok)
+	_, ok = c.byHash.Get(block3.Hash())
+	assert.False(t, ok)
+}
+
+func TestBlockCacheFirstReorgedBlock(t *testing.T) {
+	newBlock := func(number int64, time uint64) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(number), Time: time})
+	}
+	c := newBlockCache(10)
+	for i := int64(1); i <= 5; i++ {
+		c.add(newBlock(i, 0))
+	}
+
+	// blocks 3 to 5 are replaced, block 5 is known to be replaced
+	stored := map[uint64]*types.Block{1: newBlock(1, 0), 2: newBlock(2, 0), 3: newBlock(3, 1), 4: newBlock(4, 1)}
+	storedBlock := func(number uint64) (*types.Block, error) {
+		block, ok := stored[number]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		return block, nil
+	}
+	first, err := c.firstReorgedBlock(5, storedBlock)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), first)
+
+	// the stored chain is shorter than the cached one
+	delete(stored, 4)
+	first, err = c.firstReorgedBlock(5, storedBlock)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), first)
+
+	// the blocks that are not cached are not compared
+	c.removeFrom(3)
+	first, err = c.firstReorgedBlock(5, storedBlock)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), first)
+	_, ok := c.byNumber.Get(uint64(2))
+	assert.True(t, ok)
+	_, ok = c.byNumber.Get(uint64(3))
+	assert.False(t, ok)
+
+	storeErr := errors.New("store error")
+	c.add(newBlock(4, 0))
+	_, err = c.firstReorgedBlock(5, func(number uint64) (*types.Block, error) { return nil, storeErr })
+	require.ErrorIs(t, err, storeErr)
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/metrics"
+)
+
+type entry struct {
+	key   interface{}
+	value interface{}
+}
+
+// LRU is a fixed size cache that evicts the least recently used entries. It
+// is safe for concurrent use and reports its hits and misses to the state
+// metrics labeled with its name
+type LRU struct {
+	name  string
+	size  int
+	mutex sync.Mutex
+	items map[interface{}]*list.Element
+	order *list.List
+}
+
+// NewLRU creates a new LRU cache holding up to size entries
+func NewLRU(name string, size int) *LRU {
+	return &LRU{
+		name:  name,
+		size:  size,
+		items: make(map[interface{}]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+// Get returns the value of the key, if cached
+func (c *LRU) Get(key interface{}) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		metrics.CacheMiss(c.name)
+		return nil, false
+	}
+	metrics.CacheHit(c.name)
+	c.order.MoveToFront(elem)
+	return elem.Value.(*entry).value, true
+}
+
+// Add caches the value of the key, evicting the least recently used entry if
+// the cache is full
+func (c *LRU) Add(key, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*entry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry).key)
+	}
+}
+
+// RemoveIf removes the entries for which the given function returns true
+func (c *LRU) RemoveIf(fn func(key, value interface{}) bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key, elem := range c.items {
+		if fn(key, elem.Value.(*entry).value) {
+			c.order.Remove(elem)
+			delete(c.items, key)
+		}
+	}
+}
+
+// Purge removes all the entries
+func (c *LRU) Purge() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.items = make(map[interface{}]*list.Element, c.size)
+	c.order.Init()
+}
+
+// Len returns the number of cached entries
+func (c *LRU) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
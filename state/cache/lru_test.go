@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	c := NewLRU("test", 2)
+	c.Add(1, "one")
+	c.Add(2, "two")
+
+	value, ok := c.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "one", value)
+
+	// 2 is the least recently used entry
+	c.Add(3, "three")
+	_, ok = c.Get(2)
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+
+	c.Add(1, "uno")
+	value, ok = c.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "uno", value)
+
+	c.RemoveIf(func(key, value interface{}) bool { return key.(int) > 2 })
+	_, ok = c.Get(3)
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.Len())
+
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+	_, ok = c.Get(1)
+	assert.False(t, ok)
+}
@@ -13,4 +13,21 @@ type Config struct {
 
 	// ForkIdIntervals is the list of fork id intervals
 	ForkIDIntervals []ForkIDInterval
+
+	// Cache is the configuration of the in-memory caches
+	Cache CacheConfig
+
+	// BatchConstraints are the limits of the resources of a batch
+	BatchConstraints BatchConstraintsCfg
+}
+
+// CacheConfig is the configuration of the in-memory caches of the state
+type CacheConfig struct {
+	// SMTSize is the number of values and programs read from the merkletree
+	// that are cached, 0 disables the cache
+	SMTSize int `mapstructure:"SMTSize"`
+
+	// BlockSize is the number of recent L2 blocks that are cached, 0 disables
+	// the cache
+	BlockSize int `mapstructure:"BlockSize"`
 }
@@ -39,6 +39,9 @@ var (
 	// ErrExistingTxGreaterThanProcessedTx indicates that we have more txs stored
 	// in db than the txs we want to process.
 	ErrExistingTxGreaterThanProcessedTx = errors.New("there are more transactions in the database than in the processed transaction set")
+	// ErrPrunedHistory indicates the requested data was removed by the pruning
+	// of the history older than the retention window
+	ErrPrunedHistory = errors.New("pruned history unavailable")
 	// ErrOutOfOrderProcessedTx indicates the the processed transactions of an
 	// ongoing batch are not in the same order as the transactions stored in the
 	// database for the same batch.
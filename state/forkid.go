@@ -1,11 +1,23 @@
 package state
 
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+)
+
 // ForkIDInterval is a fork id interval
 type ForkIDInterval struct {
 	FromBatchNumber uint64
 	ToBatchNumber   uint64
 	ForkId          uint64
 	Version         string
+	// BlockNumber is the L1 block where the fork id was activated
+	BlockNumber uint64
 }
 
 // GetForkIDByBatchNumber returns the fork id for a given batch number
@@ -17,3 +29,112 @@ func GetForkIDByBatchNumber(intervals []ForkIDInterval, batchNumber uint64) uint
 	}
 	return 1
 }
+
+// buildForkIDIntervals sets the end of every interval to the batch before the
+// start of the next one, the fork ids must be sorted by their first batch. When
+// several fork ids start at the same batch the last one wins
+func buildForkIDIntervals(forkIDs []ForkIDInterval) []ForkIDInterval {
+	intervals := make([]ForkIDInterval, 0, len(forkIDs))
+	for _, forkID := range forkIDs {
+		if n := len(intervals); n > 0 && intervals[n-1].FromBatchNumber == forkID.FromBatchNumber {
+			intervals = intervals[:n-1]
+		}
+		if n := len(intervals); n > 0 {
+			intervals[n-1].ToBatchNumber = forkID.FromBatchNumber - 1
+		}
+		forkID.ToBatchNumber = math.MaxUint64
+		intervals = append(intervals, forkID)
+	}
+	return intervals
+}
+
+// GetForkIdByBatchNumber returns the fork id for the given batch number
+func (s *State) GetForkIdByBatchNumber(batchNumber uint64) uint64 {
+	s.forkIDIntervalsMutex.RLock()
+	defer s.forkIDIntervalsMutex.RUnlock()
+	return GetForkIDByBatchNumber(s.cfg.ForkIDIntervals, batchNumber)
+}
+
+// GetForkIDIntervals returns the fork id intervals in use
+func (s *State) GetForkIDIntervals() []ForkIDInterval {
+	s.forkIDIntervalsMutex.RLock()
+	defer s.forkIDIntervalsMutex.RUnlock()
+	return append([]ForkIDInterval(nil), s.cfg.ForkIDIntervals...)
+}
+
+// UpdateForkIDIntervals replaces the fork id intervals in use
+func (s *State) UpdateForkIDIntervals(intervals []ForkIDInterval) {
+	s.forkIDIntervalsMutex.Lock()
+	defer s.forkIDIntervalsMutex.Unlock()
+	s.cfg.ForkIDIntervals = intervals
+}
+
+// LoadForkIDIntervals reads the fork ids stored in the state db and starts
+// using them. When there are none stored, e.g. on a db that is not synced yet,
+// the intervals in use are kept
+func (s *State) LoadForkIDIntervals(ctx context.Context, dbTx pgx.Tx) error {
+	intervals, err := s.GetForkIDs(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	if len(intervals) == 0 {
+		log.Debug("no fork ids stored, keeping the fork id intervals in use")
+		return nil
+	}
+	log.Debugf("fork id intervals loaded: %+v", intervals)
+	s.UpdateForkIDIntervals(intervals)
+	return nil
+}
+
+// BackfillForkIDs stores the given fork ids read from L1 when there are none
+// stored, e.g. on a db synced before the fork ids were stored. Only the fork
+// ids activated up to the last synced block are stored, linked to the last
+// stored block up to their activation one, the synchronizer stores the rest
+func (s *State) BackfillForkIDs(ctx context.Context, forkIDs []ForkIDInterval, dbTx pgx.Tx) error {
+	stored, err := s.GetForkIDs(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	if len(stored) > 0 {
+		return nil
+	}
+	lastBlock, err := s.GetLastBlock(ctx, dbTx)
+	if errors.Is(err, ErrStateNotSynchronized) {
+		// the synchronizer stores the fork ids along with the genesis
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, forkID := range forkIDs {
+		if forkID.BlockNumber > lastBlock.BlockNumber {
+			continue
+		}
+		forkID.BlockNumber, err = s.GetLastBlockNumberUpTo(ctx, forkID.BlockNumber, dbTx)
+		if err != nil {
+			return err
+		}
+		if err := s.AddForkID(ctx, forkID, dbTx); err != nil {
+			return err
+		}
+		log.Infof("fork id %d backfilled from batch %d", forkID.ForkId, forkID.FromBatchNumber)
+	}
+	return nil
+}
+
+// ReloadForkIDIntervals loads the stored fork ids every interval until the
+// context is done, so the processes that don't run the synchronizer use the
+// fork ids it stores
+func (s *State) ReloadForkIDIntervals(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.LoadForkIDIntervals(ctx, nil); err != nil {
+				log.Errorf("error reloading fork id intervals: %v", err)
+			}
+		}
+	}
+}
@@ -1,6 +1,15 @@
 package state
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/encoding"
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 // Genesis contains the information to populate state on creation
 type Genesis struct {
@@ -18,3 +27,63 @@ type GenesisAction struct {
 	Value           string `json:"value"`
 	Root            string `json:"root"`
 }
+
+// SetGenesisActions sets the values of the genesis actions on an empty tree
+// and returns the resulting root, it doesn't need a database so it can be used
+// against an in-memory tree to compute the root of a genesis
+func SetGenesisActions(ctx context.Context, tree *merkletree.StateTree, actions []*GenesisAction) ([]byte, error) {
+	var newRoot []byte
+	for _, action := range actions {
+		address := common.HexToAddress(action.Address)
+		switch action.Type {
+		case int(merkletree.LeafTypeBalance):
+			balance, err := encoding.DecodeBigIntHexOrDecimal(action.Value)
+			if err != nil {
+				return newRoot, err
+			}
+			newRoot, _, err = tree.SetBalance(ctx, address, balance, newRoot)
+			if err != nil {
+				return newRoot, err
+			}
+		case int(merkletree.LeafTypeNonce):
+			nonce, err := encoding.DecodeBigIntHexOrDecimal(action.Value)
+			if err != nil {
+				return newRoot, err
+			}
+			newRoot, _, err = tree.SetNonce(ctx, address, nonce, newRoot)
+			if err != nil {
+				return newRoot, err
+			}
+		case int(merkletree.LeafTypeCode):
+			code, err := hex.DecodeHex(action.Bytecode)
+			if err != nil {
+				return newRoot, fmt.Errorf("could not decode SC bytecode for address %q: %v", address, err)
+			}
+			newRoot, _, err = tree.SetCode(ctx, address, code, newRoot)
+			if err != nil {
+				return newRoot, err
+			}
+		case int(merkletree.LeafTypeStorage):
+			// Parse position and value
+			positionBI, err := encoding.DecodeBigIntHexOrDecimal(action.StoragePosition)
+			if err != nil {
+				return newRoot, err
+			}
+			valueBI, err := encoding.DecodeBigIntHexOrDecimal(action.Value)
+			if err != nil {
+				return newRoot, err
+			}
+			// Store
+			newRoot, _, err = tree.SetStorageAt(ctx, address, positionBI, valueBI, newRoot)
+			if err != nil {
+				return newRoot, err
+			}
+		case int(merkletree.LeafTypeSCLength):
+			log.Debug("Skipped genesis action of type merkletree.LeafTypeSCLength, these actions will be handled as part of merkletree.LeafTypeCode actions")
+		default:
+			return newRoot, fmt.Errorf("unknown genesis action type %q", action.Type)
+		}
+	}
+
+	return newRoot, nil
+}
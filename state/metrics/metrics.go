@@ -14,6 +14,12 @@ const (
 	ExecutorProcessingTimeName = Prefix + "executor_processing_time"
 	// CallerLabelName is the name of the label for the caller.
 	CallerLabelName = "caller"
+	// CacheHitsName is the name of the metric that counts the hits of the state caches.
+	CacheHitsName = Prefix + "cache_hits"
+	// CacheMissesName is the name of the metric that counts the misses of the state caches.
+	CacheMissesName = Prefix + "cache_misses"
+	// CacheLabelName is the name of the label for the cache.
+	CacheLabelName = "cache"
 )
 
 // Register the metrics for the sequencer package.
@@ -28,7 +34,25 @@ func Register() {
 		},
 	}
 
+	counterVecs := []metrics.CounterVecOpts{
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: CacheHitsName,
+				Help: "[STATE] number of hits of the state caches",
+			},
+			Labels: []string{CacheLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: CacheMissesName,
+				Help: "[STATE] number of misses of the state caches",
+			},
+			Labels: []string{CacheLabelName},
+		},
+	}
+
 	metrics.RegisterHistogramVecs(histogramVecs...)
+	metrics.RegisterCounterVecs(counterVecs...)
 }
 
 // ExecutorProcessingTime observes the last processing time of the executor in the histogram vector by the provided elapsed time
@@ -37,3 +61,13 @@ func ExecutorProcessingTime(caller string, lastExecutionTime time.Duration) {
 	execTimeInSeconds := float64(lastExecutionTime) / float64(time.Second)
 	metrics.HistogramVecObserve(ExecutorProcessingTimeName, string(caller), execTimeInSeconds)
 }
+
+// CacheHit increments the hits of the given cache.
+func CacheHit(cache string) {
+	metrics.CounterVecInc(CacheHitsName, cache)
+}
+
+// CacheMiss increments the misses of the given cache.
+func CacheMiss(cache string) {
+	metrics.CounterVecInc(CacheMissesName, cache)
+}
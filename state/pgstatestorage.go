@@ -27,7 +27,7 @@ const (
 	addBlockSQL                              = "INSERT INTO state.block (block_num, block_hash, parent_hash, received_at) SELECT $1, $2, $3, $4 WHERE NOT EXISTS (SELECT block_num FROM state.block WHERE block_num = $1)"
 	getLastBlockSQL                          = "SELECT block_num, block_hash, parent_hash, received_at FROM state.block ORDER BY block_num DESC LIMIT 1"
 	getPreviousBlockSQL                      = "SELECT block_num, block_hash, parent_hash, received_at FROM state.block ORDER BY block_num DESC LIMIT 1 OFFSET $1"
-	getLastBatchInfoSQL                      = "SELECT v.batch_num, v.block_num, b.timestamp FROM state.batch AS b JOIN state.virtual_batch AS v ON b.batch_num = v.batch_num ORDER BY b.batch_num DESC LIMIT 1"
+	getLastBatchInfoSQL                      = "SELECT v.batch_num, v.block_num, b.timestamp, v.last_forced_batch_num FROM state.batch AS b JOIN state.virtual_batch AS v ON b.batch_num = v.batch_num ORDER BY b.batch_num DESC LIMIT 1"
 	getLastBatchNumberSQL                    = "SELECT batch_num FROM state.batch ORDER BY batch_num DESC LIMIT 1"
 	getLastNBatchesSQL                       = "SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num from state.batch ORDER BY batch_num DESC LIMIT $1"
 	getLastBatchTimeSQL                      = "SELECT timestamp FROM state.batch ORDER BY batch_num DESC LIMIT 1"
@@ -82,39 +82,170 @@ const (
 // PostgresStorage implements the Storage interface
 type PostgresStorage struct {
 	*pgxpool.Pool
+	readReplicas *readReplicas
+	// prunedHistory is set when the history of the old batches may have been
+	// pruned, GetLogs only checks the pruned batches when it is set
+	prunedHistory bool
 }
 
 // NewPostgresStorage creates a new StateDB
 func NewPostgresStorage(db *pgxpool.Pool) *PostgresStorage {
 	return &PostgresStorage{
-		db,
+		Pool: db,
 	}
 }
 
-// getExecQuerier determines which execQuerier to use, dbTx or the main pgxpool
-func (p *PostgresStorage) getExecQuerier(dbTx pgx.Tx) execQuerier {
+// EnablePrunedHistoryCheck makes GetLogs fail with ErrPrunedHistory when any
+// block of the range belongs to a pruned batch, and GetTransactionReceipt when
+// the transaction does. It must be called before the storage is used when the
+// node prunes its history or was pruned before
+func (p *PostgresStorage) EnablePrunedHistoryCheck() {
+	p.prunedHistory = true
+}
+
+// getExecQuerier determines which execQuerier to use, dbTx, a read replica
+// if the context allows it or the main pgxpool
+func (p *PostgresStorage) getExecQuerier(ctx context.Context, dbTx pgx.Tx) execQuerier {
 	if dbTx != nil {
 		return dbTx
 	}
+	if p.readReplicas != nil {
+		if read, ok := ctx.Value(readReplicaKey{}).(readReplicaRead); ok {
+			if replica := p.readReplicas.pick(read); replica != nil {
+				return &readReplicaQuerier{replica: replica.pool, primary: p, scope: read.scope}
+			}
+		}
+	}
 	return p
 }
 
-// Reset resets the state to a block for the given DB tx
-func (p *PostgresStorage) Reset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) error {
-	e := p.getExecQuerier(dbTx)
-	const resetSQL = "DELETE FROM state.block WHERE block_num > $1"
-	if _, err := e.Exec(ctx, resetSQL, blockNumber); err != nil {
+// firstForcedBatchToResetSQL returns the first batch that includes a forced
+// batch forced after the given block, the forced batches can't be removed with
+// their blocks while a batch refers to them
+const firstForcedBatchToResetSQL = "SELECT MIN(b.batch_num) FROM state.batch AS b JOIN state.forced_batch AS f ON b.forced_batch_num = f.forced_batch_num WHERE f.block_num > $1"
+
+// StartReset persists the marker of a reset of the state to a block, so it is
+// resumed on startup if it is not completed. A reset in progress to a later
+// block is replaced by it. The marker records the first batch to remove, the
+// one that includes the first forced batch forced after the block, which
+// ResetStep removes along with the batches after it. The L1 data served by
+// the RPC that come from the blocks after the given one are removed along with
+// the marker
+func (p *PostgresStorage) StartReset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) error {
+	const getResetBatchNumSQL = "SELECT LEAST((SELECT MIN(batch_num) FROM state.reset_in_progress), (" + firstForcedBatchToResetSQL + "))"
+	const deleteResetSQL = "DELETE FROM state.reset_in_progress"
+	const addResetSQL = "INSERT INTO state.reset_in_progress (block_num, started_at, batch_num) VALUES ($1, $2, $3)"
+	const resetVirtualBatchesSQL = "DELETE FROM state.virtual_batch WHERE block_num > $1 OR seen_at > $1"
+	const resetVerifiedBatchesSQL = "DELETE FROM state.verified_batch WHERE block_num > $1"
+	const resetExitRootsSQL = "DELETE FROM state.exit_root WHERE block_num > $1"
+	e := p.getExecQuerier(ctx, dbTx)
+	// the batches of a reset in progress to a later block not removed yet are
+	// still removed
+	var batchNumber *uint64
+	if err := e.QueryRow(ctx, getResetBatchNumSQL, blockNumber).Scan(&batchNumber); err != nil {
 		return err
 	}
-
+	if _, err := e.Exec(ctx, deleteResetSQL); err != nil {
+		return err
+	}
+	if _, err := e.Exec(ctx, addResetSQL, blockNumber, time.Now().UTC(), batchNumber); err != nil {
+		return err
+	}
+	for _, resetSQL := range []string{resetVirtualBatchesSQL, resetVerifiedBatchesSQL, resetExitRootsSQL} {
+		if _, err := e.Exec(ctx, resetSQL, blockNumber); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// GetResetInProgress returns the block the state is being reset to,
+// ErrNotFound if there is no reset in progress
+func (p *PostgresStorage) GetResetInProgress(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	const getResetSQL = "SELECT block_num FROM state.reset_in_progress ORDER BY block_num LIMIT 1"
+	var blockNumber uint64
+	e := p.getExecQuerier(ctx, dbTx)
+	err := e.QueryRow(ctx, getResetSQL).Scan(&blockNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrNotFound
+	} else if err != nil {
+		return 0, err
+	}
+	return blockNumber, nil
+}
+
+// ResetStep removes a chunk of the state above the given block, it returns
+// true once there are no blocks left above it. The steps first remove the
+// batches from the first one recorded by the marker of the reset, the last
+// chunk of at most chunkSize batch numbers each, so the RPC never sees a batch
+// without the ones before it, and clear it from the marker once they are all
+// removed. Then they remove the last chunk of at most chunkSize L1 block
+// numbers along with the data kept with the blocks, like the forced batches and
+// the fork ids. Without a marker the first batch removed is the one that
+// includes the first forced batch forced after the block
+func (p *PostgresStorage) ResetStep(ctx context.Context, blockNumber uint64, chunkSize uint64, dbTx pgx.Tx) (bool, error) {
+	const getResetBatchNumSQL = "SELECT COALESCE((SELECT MIN(batch_num) FROM state.reset_in_progress), (" + firstForcedBatchToResetSQL + "))"
+	const getLastBatchNumSQL = "SELECT COALESCE(MAX(batch_num), 0) FROM state.batch"
+	const resetBatchesSQL = "DELETE FROM state.batch WHERE batch_num >= $1"
+	const updateResetSQL = "UPDATE state.reset_in_progress SET batch_num = NULL"
+	const getLastBlockNumSQL = "SELECT COALESCE(MAX(block_num), 0) FROM state.block"
+	const resetSQL = "DELETE FROM state.block WHERE block_num > $1"
+
+	e := p.getExecQuerier(ctx, dbTx)
+	var batchNumber *uint64
+	if err := e.QueryRow(ctx, getResetBatchNumSQL, blockNumber).Scan(&batchNumber); err != nil {
+		return false, err
+	}
+	if batchNumber != nil {
+		var lastBatchNumber uint64
+		if err := e.QueryRow(ctx, getLastBatchNumSQL).Scan(&lastBatchNumber); err != nil {
+			return false, err
+		}
+		fromBatchNumber := *batchNumber
+		if chunkSize > 0 && lastBatchNumber >= *batchNumber+chunkSize {
+			fromBatchNumber = lastBatchNumber - chunkSize + 1
+		}
+		if _, err := e.Exec(ctx, resetBatchesSQL, fromBatchNumber); err != nil {
+			return false, err
+		}
+		if fromBatchNumber > *batchNumber {
+			return false, nil
+		}
+		if _, err := e.Exec(ctx, updateResetSQL); err != nil {
+			return false, err
+		}
+	}
+
+	var lastBlockNumber uint64
+	if err := e.QueryRow(ctx, getLastBlockNumSQL).Scan(&lastBlockNumber); err != nil {
+		return false, err
+	}
+	if lastBlockNumber <= blockNumber {
+		return true, nil
+	}
+	fromBlockNumber := blockNumber
+	if chunkSize > 0 && lastBlockNumber-blockNumber > chunkSize {
+		fromBlockNumber = lastBlockNumber - chunkSize
+	}
+	if _, err := e.Exec(ctx, resetSQL, fromBlockNumber); err != nil {
+		return false, err
+	}
+	return fromBlockNumber == blockNumber, nil
+}
+
+// FinishReset removes the marker of the reset in progress
+func (p *PostgresStorage) FinishReset(ctx context.Context, dbTx pgx.Tx) error {
+	const deleteResetSQL = "DELETE FROM state.reset_in_progress"
+	e := p.getExecQuerier(ctx, dbTx)
+	_, err := e.Exec(ctx, deleteResetSQL)
+	return err
+}
+
 // ResetTrustedState removes the batches with number greater than the given one
 // from the database.
 func (p *PostgresStorage) ResetTrustedState(ctx context.Context, batchNum uint64, dbTx pgx.Tx) error {
 	const resetTrustedStateSQL = "DELETE FROM state.batch WHERE batch_num > $1"
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	if _, err := e.Exec(ctx, resetTrustedStateSQL, batchNum); err != nil {
 		return err
 	}
@@ -124,7 +255,7 @@ func (p *PostgresStorage) ResetTrustedState(ctx context.Context, batchNum uint64
 
 // AddBlock adds a new block to the State Store
 func (p *PostgresStorage) AddBlock(ctx context.Context, block *Block, dbTx pgx.Tx) error {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 
 	// This operation will both read the state.block table (to check if the block we're adding
 	// already exists) and modify it. These two actions do not happen atomically; without
@@ -141,7 +272,7 @@ func (p *PostgresStorage) AddBlock(ctx context.Context, block *Block, dbTx pgx.T
 // GetTxsOlderThanNL1Blocks get txs hashes to delete from tx pool
 func (p *PostgresStorage) GetTxsOlderThanNL1Blocks(ctx context.Context, nL1Blocks uint64, dbTx pgx.Tx) ([]common.Hash, error) {
 	var batchNum, blockNum uint64
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 
 	err := e.QueryRow(ctx, getLastBlockNumSQL).Scan(&blockNum)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -187,7 +318,7 @@ func (p *PostgresStorage) GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*Block
 		parentHash string
 		block      Block
 	)
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 
 	err := q.QueryRow(ctx, getLastBlockSQL).Scan(&block.BlockNumber, &blockHash, &parentHash, &block.ReceivedAt)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -205,7 +336,7 @@ func (p *PostgresStorage) GetPreviousBlock(ctx context.Context, offset uint64, d
 		parentHash string
 		block      Block
 	)
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 
 	err := q.QueryRow(ctx, getPreviousBlockSQL, offset).Scan(&block.BlockNumber, &blockHash, &parentHash, &block.ReceivedAt)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -218,7 +349,7 @@ func (p *PostgresStorage) GetPreviousBlock(ctx context.Context, offset uint64, d
 
 // AddGlobalExitRoot adds a new ExitRoot to the db
 func (p *PostgresStorage) AddGlobalExitRoot(ctx context.Context, exitRoot *GlobalExitRoot, dbTx pgx.Tx) error {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, addGlobalExitRootSQL, exitRoot.BlockNumber, exitRoot.Timestamp, exitRoot.MainnetExitRoot, exitRoot.RollupExitRoot, exitRoot.GlobalExitRoot)
 	return err
 }
@@ -233,7 +364,7 @@ func (p *PostgresStorage) GetLatestGlobalExitRoot(ctx context.Context, maxBlockN
 		receivedAt time.Time
 	)
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err = e.QueryRow(ctx, getLatestExitRootSQL, maxBlockNumber).Scan(&exitRoot.BlockNumber, &exitRoot.MainnetExitRoot, &exitRoot.RollupExitRoot, &exitRoot.GlobalExitRoot)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -258,7 +389,7 @@ func (p *PostgresStorage) GetNumberOfBlocksSinceLastGERUpdate(ctx context.Contex
 		lastExitRootBlockNum uint64
 		err                  error
 	)
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err = e.QueryRow(ctx, getLastBlockNumSQL).Scan(&lastBlockNum)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return 0, ErrNotFound
@@ -282,7 +413,7 @@ func (p *PostgresStorage) GetBlockNumAndMainnetExitRootByGER(ctx context.Context
 		blockNum        uint64
 		mainnetExitRoot common.Hash
 	)
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	const getMainnetExitRoot = "SELECT block_num, mainnet_exit_root FROM state.exit_root WHERE global_exit_root = $1"
 	err := e.QueryRow(ctx, getMainnetExitRoot, ger.Bytes()).Scan(&blockNum, &mainnetExitRoot)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -301,7 +432,7 @@ func (p *PostgresStorage) GetTimeForLatestBatchVirtualization(ctx context.Contex
 		blockNum  uint64
 		timestamp time.Time
 	)
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, getLastVirtualBatchBlockNumSQL).Scan(&blockNum)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -337,7 +468,7 @@ func (p *PostgresStorage) GetForcedBatch(ctx context.Context, forcedBatchNumber
 		seq            string
 	)
 	const getForcedBatchSQL = "SELECT forced_batch_num, global_exit_root, timestamp, raw_txs_data, coinbase, block_num FROM state.forced_batch WHERE forced_batch_num = $1"
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, getForcedBatchSQL, forcedBatchNumber).Scan(&forcedBatch.ForcedBatchNumber, &globalExitRoot, &forcedBatch.ForcedAt, &rawTxs, &seq, &forcedBatch.BlockNumber)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrNotFound
@@ -356,7 +487,7 @@ func (p *PostgresStorage) GetForcedBatch(ctx context.Context, forcedBatchNumber
 // GetForcedBatchesSince gets L1 forced batches since forcedBatchNumber
 func (p *PostgresStorage) GetForcedBatchesSince(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) ([]*ForcedBatch, error) {
 	const getForcedBatchesSQL = "SELECT forced_batch_num, global_exit_root, timestamp, raw_txs_data, coinbase, block_num FROM state.forced_batch WHERE forced_batch_num > $1"
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	rows, err := q.Query(ctx, getForcedBatchesSQL, forcedBatchNumber)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return []*ForcedBatch{}, nil
@@ -379,9 +510,47 @@ func (p *PostgresStorage) GetForcedBatchesSince(ctx context.Context, forcedBatch
 	return forcesBatches, nil
 }
 
+// GetPendingForcedBatches gets the forced batches after lastForcedBatchNumber
+// that were forced in a block up to maxBlockNumber, ordered by their number
+func (p *PostgresStorage) GetPendingForcedBatches(ctx context.Context, lastForcedBatchNumber uint64, maxBlockNumber uint64, dbTx pgx.Tx) ([]ForcedBatch, error) {
+	const getPendingForcedBatchesSQL = `
+		SELECT forced_batch_num, global_exit_root, timestamp, raw_txs_data, coinbase, block_num
+		FROM state.forced_batch
+		WHERE forced_batch_num > $1 AND block_num <= $2
+		ORDER BY forced_batch_num ASC`
+	q := p.getExecQuerier(ctx, dbTx)
+	rows, err := q.Query(ctx, getPendingForcedBatchesSQL, lastForcedBatchNumber, maxBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var forcedBatches []ForcedBatch
+	for rows.Next() {
+		var (
+			forcedBatch    ForcedBatch
+			globalExitRoot string
+			rawTxs         string
+			seq            string
+		)
+		err := rows.Scan(&forcedBatch.ForcedBatchNumber, &globalExitRoot, &forcedBatch.ForcedAt, &rawTxs, &seq, &forcedBatch.BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		forcedBatch.RawTxsData, err = hex.DecodeString(rawTxs)
+		if err != nil {
+			return nil, err
+		}
+		forcedBatch.Sequencer = common.HexToAddress(seq)
+		forcedBatch.GlobalExitRoot = common.HexToHash(globalExitRoot)
+		forcedBatches = append(forcedBatches, forcedBatch)
+	}
+	return forcedBatches, rows.Err()
+}
+
 // AddVerifiedBatch adds a new VerifiedBatch to the db
 func (p *PostgresStorage) AddVerifiedBatch(ctx context.Context, verifiedBatch *VerifiedBatch, dbTx pgx.Tx) error {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	const addVerifiedBatchSQL = "INSERT INTO state.verified_batch (block_num, batch_num, tx_hash, aggregator, state_root, is_trusted) VALUES ($1, $2, $3, $4, $5, $6)"
 	_, err := e.Exec(ctx, addVerifiedBatchSQL, verifiedBatch.BlockNumber, verifiedBatch.BatchNumber, verifiedBatch.TxHash.String(), verifiedBatch.Aggregator.String(), verifiedBatch.StateRoot.String(), verifiedBatch.IsTrusted)
 	return err
@@ -401,7 +570,7 @@ func (p *PostgresStorage) GetVerifiedBatch(ctx context.Context, batchNumber uint
       FROM state.verified_batch
      WHERE batch_num = $1`
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, getVerifiedBatchSQL, batchNumber).Scan(&verifiedBatch.BlockNumber, &verifiedBatch.BatchNumber, &txHash, &agg, &sr, &verifiedBatch.IsTrusted)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrNotFound
@@ -416,7 +585,7 @@ func (p *PostgresStorage) GetVerifiedBatch(ctx context.Context, batchNumber uint
 
 // GetLastNBatches returns the last numBatches batches.
 func (p *PostgresStorage) GetLastNBatches(ctx context.Context, numBatches uint, dbTx pgx.Tx) ([]*Batch, error) {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	rows, err := e.Query(ctx, getLastNBatchesSQL, numBatches)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrStateNotSynchronized
@@ -471,7 +640,7 @@ func (p *PostgresStorage) GetLastNBatchesByL2BlockNumber(ctx context.Context, l2
          LIMIT $2;`
 
 	var l2BlockStateRoot *common.Hash
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	rows, err := e.Query(ctx, getLastNBatchesByBlockNumberSQL, l2BlockNumber, numBatches)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, common.Hash{}, ErrStateNotSynchronized
@@ -507,9 +676,9 @@ func (p *PostgresStorage) GetLastBatchInfo(ctx context.Context, dbTx pgx.Tx) (L2
 	var info L2BatchInfo
 	var timestamp time.Time
 
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 
-	err := q.QueryRow(ctx, getLastBatchInfoSQL).Scan(&info.Number, &info.L1Block, &timestamp)
+	err := q.QueryRow(ctx, getLastBatchInfoSQL).Scan(&info.Number, &info.L1Block, &timestamp, &info.ForcedBatchNum)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return info, ErrStateNotSynchronized
 	}
@@ -521,7 +690,7 @@ func (p *PostgresStorage) GetLastBatchInfo(ctx context.Context, dbTx pgx.Tx) (L2
 // GetLastBatchNumber get last trusted batch number
 func (p *PostgresStorage) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	var batchNumber uint64
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 
 	err := q.QueryRow(ctx, getLastBatchNumberSQL).Scan(&batchNumber)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -533,7 +702,7 @@ func (p *PostgresStorage) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (
 // GetLastBatchTime gets last trusted batch time
 func (p *PostgresStorage) GetLastBatchTime(ctx context.Context, dbTx pgx.Tx) (time.Time, error) {
 	var timestamp time.Time
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, getLastBatchTimeSQL).Scan(&timestamp)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -547,7 +716,7 @@ func (p *PostgresStorage) GetLastBatchTime(ctx context.Context, dbTx pgx.Tx) (ti
 // GetLastVirtualBatchNum gets last virtual batch num
 func (p *PostgresStorage) GetLastVirtualBatchNum(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	var batchNum uint64
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, getLastVirtualBatchNumSQL).Scan(&batchNum)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -562,7 +731,7 @@ func (p *PostgresStorage) GetLastVirtualBatchNum(ctx context.Context, dbTx pgx.T
 func (p *PostgresStorage) GetLatestVirtualBatchTimestamp(ctx context.Context, dbTx pgx.Tx) (time.Time, error) {
 	const getLastVirtualBatchTimestampSQL = `SELECT COALESCE(MAX(block.received_at), NOW()) FROM state.virtual_batch INNER JOIN state.block ON state.block.block_num = virtual_batch.block_num`
 	var timestamp time.Time
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, getLastVirtualBatchTimestampSQL).Scan(&timestamp)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -577,7 +746,7 @@ func (p *PostgresStorage) GetLatestVirtualBatchTimestamp(ctx context.Context, db
 // the roll-up in order to allow the components to know if the state
 // is synchronized or not
 func (p *PostgresStorage) SetLastBatchNumberSeenOnEthereum(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) error {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, updateLastBatchSeenSQL, batchNumber)
 	return err
 }
@@ -589,7 +758,7 @@ func (p *PostgresStorage) ContainsBlock(ctx context.Context, blockNum uint64, db
 		 WHERE block_num = $1`
 	var count uint64
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	if err := e.QueryRow(ctx, getBlockByNumberSQL, blockNum).Scan(&count); err != nil {
 		return false, err
 	}
@@ -601,7 +770,7 @@ func (p *PostgresStorage) ContainsBlock(ctx context.Context, blockNum uint64, db
 // roll-up in the Ethereum network.
 func (p *PostgresStorage) GetLastBatchNumberSeenOnEthereum(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	var batchNumber uint64
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, getLastBatchSeenSQL).Scan(&batchNumber)
 
 	if err != nil {
@@ -618,7 +787,7 @@ func (p *PostgresStorage) GetBatchByNumber(ctx context.Context, batchNumber uint
 		  FROM state.batch 
 		 WHERE batch_num = $1`
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	row := e.QueryRow(ctx, getBatchByNumberSQL, batchNumber)
 	batch, err := scanBatch(row)
 
@@ -638,7 +807,7 @@ func (p *PostgresStorage) GetBatchByTxHash(ctx context.Context, transactionHash
 		  FROM state.transaction t, state.batch b, state.l2block l 
 		  WHERE t.hash = $1 AND l.block_num = t.l2_block_num AND b.batch_num = l.batch_num`
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	row := e.QueryRow(ctx, getBatchByTxHashSQL, transactionHash.String())
 	batch, err := scanBatch(row)
 
@@ -660,7 +829,7 @@ func (p *PostgresStorage) GetBatchByL2BlockNumber(ctx context.Context, l2BlockNu
 		 WHERE bl.block_num = $1
 		 LIMIT 1;`
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	row := e.QueryRow(ctx, getBatchByL2BlockNumberSQL, l2BlockNumber)
 	batch, err := scanBatch(row)
 
@@ -691,7 +860,7 @@ func (p *PostgresStorage) GetVirtualBatchByNumber(ctx context.Context, batchNumb
 			batch_num = $1 AND
 			EXISTS (SELECT batch_num FROM state.virtual_batch WHERE batch_num = $1)
 		`
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	row := e.QueryRow(ctx, query, batchNumber)
 	batch, err := scanBatch(row)
 
@@ -706,7 +875,7 @@ func (p *PostgresStorage) GetVirtualBatchByNumber(ctx context.Context, batchNumb
 // IsBatchVirtualized checks if batch is virtualized
 func (p *PostgresStorage) IsBatchVirtualized(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (bool, error) {
 	const query = `SELECT EXISTS (SELECT 1 FROM state.virtual_batch WHERE batch_num = $1)`
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	var exists bool
 	err := e.QueryRow(ctx, query, batchNumber).Scan(&exists)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
@@ -718,7 +887,7 @@ func (p *PostgresStorage) IsBatchVirtualized(ctx context.Context, batchNumber ui
 // IsSequencingTXSynced checks if sequencing tx has been synced into the state
 func (p *PostgresStorage) IsSequencingTXSynced(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (bool, error) {
 	const query = `SELECT EXISTS (SELECT 1 FROM state.virtual_batch WHERE tx_hash = $1)`
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	var exists bool
 	err := e.QueryRow(ctx, query, transactionHash.String()).Scan(&exists)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
@@ -729,7 +898,7 @@ func (p *PostgresStorage) IsSequencingTXSynced(ctx context.Context, transactionH
 
 // GetProcessingContext returns the processing context for the given batch.
 func (p *PostgresStorage) GetProcessingContext(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*ProcessingContext, error) {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	row := e.QueryRow(ctx, getProcessingContextSQL, batchNumber)
 	processingContext := ProcessingContext{}
 	var (
@@ -857,7 +1026,7 @@ func scanForcedBatch(row pgx.Row) (ForcedBatch, error) {
 // GetEncodedTransactionsByBatchNumber returns the encoded field of all
 // transactions in the given batch.
 func (p *PostgresStorage) GetEncodedTransactionsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (encoded []string, err error) {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	rows, err := e.Query(ctx, getEncodedTransactionsByBatchNumberSQL, batchNumber)
 	if !errors.Is(err, pgx.ErrNoRows) && err != nil {
 		return nil, err
@@ -885,7 +1054,7 @@ func (p *PostgresStorage) GetTransactionsByBatchNumber(ctx context.Context, batc
 		return nil, err
 	}
 	for i := 0; i < len(encodedTxs); i++ {
-		tx, err := DecodeTx(encodedTxs[i])
+		tx, err := decodeStoredTx(encodedTxs[i])
 		if err != nil {
 			return nil, err
 		}
@@ -897,7 +1066,7 @@ func (p *PostgresStorage) GetTransactionsByBatchNumber(ctx context.Context, batc
 // GetTxsHashesByBatchNumber returns the hashes of the transactions in the
 // given batch.
 func (p *PostgresStorage) GetTxsHashesByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (encoded []common.Hash, err error) {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	rows, err := e.Query(ctx, getTransactionHashesByBatchNumberSQL, batchNumber)
 	if !errors.Is(err, pgx.ErrNoRows) && err != nil {
 		return nil, err
@@ -920,12 +1089,36 @@ func (p *PostgresStorage) GetTxsHashesByBatchNumber(ctx context.Context, batchNu
 
 // AddVirtualBatch adds a new virtual batch to the storage.
 func (p *PostgresStorage) AddVirtualBatch(ctx context.Context, virtualBatch *VirtualBatch, dbTx pgx.Tx) error {
-	const addVirtualBatchSQL = "INSERT INTO state.virtual_batch (batch_num, tx_hash, coinbase, block_num, seen_at, sequencer_addr) VALUES ($1, $2, $3, $4, $5, $6)"
-	e := p.getExecQuerier(dbTx)
-	_, err := e.Exec(ctx, addVirtualBatchSQL, virtualBatch.BatchNumber, virtualBatch.TxHash.String(), virtualBatch.Coinbase.String(), virtualBatch.BlockNumber, virtualBatch.SeenAt, virtualBatch.SequencerAddr.String())
+	const addVirtualBatchSQL = "INSERT INTO state.virtual_batch (batch_num, tx_hash, coinbase, block_num, seen_at, sequencer_addr, l1_finality, last_forced_batch_num) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	l1Finality := virtualBatch.L1Finality
+	if l1Finality == "" {
+		l1Finality = L1FinalityLatest
+	}
+	e := p.getExecQuerier(ctx, dbTx)
+	_, err := e.Exec(ctx, addVirtualBatchSQL, virtualBatch.BatchNumber, virtualBatch.TxHash.String(), virtualBatch.Coinbase.String(), virtualBatch.BlockNumber, virtualBatch.SeenAt, virtualBatch.SequencerAddr.String(), string(l1Finality), virtualBatch.LastForcedBatchNum)
 	return err
 }
 
+// UpdateVirtualBatchesL1Finality raises to the given finality level the
+// virtual batches seen at an L1 block up to the given one that are below it,
+// returning the number of batches updated. The preconfirmed batches keep
+// their level until their L1 block is synced
+func (p *PostgresStorage) UpdateVirtualBatchesL1Finality(ctx context.Context, l1Finality L1Finality, blockNumber uint64, dbTx pgx.Tx) (int64, error) {
+	const updateVirtualBatchesL1FinalitySQL = "UPDATE state.virtual_batch SET l1_finality = $1 WHERE seen_at <= $2 AND l1_finality = ANY($3)"
+	lower := []string{}
+	for _, level := range l1Finality.Lower() {
+		if level != L1FinalityPreconfirmed {
+			lower = append(lower, string(level))
+		}
+	}
+	e := p.getExecQuerier(ctx, dbTx)
+	commandTag, err := e.Exec(ctx, updateVirtualBatchesL1FinalitySQL, string(l1Finality), blockNumber, lower)
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
+
 // GetVirtualBatch get an L1 virtualBatch.
 func (p *PostgresStorage) GetVirtualBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*VirtualBatch, error) {
 	var (
@@ -933,15 +1126,16 @@ func (p *PostgresStorage) GetVirtualBatch(ctx context.Context, batchNumber uint6
 		txHash        string
 		coinbase      string
 		sequencerAddr string
+		l1Finality    string
 	)
 
 	const getVirtualBatchSQL = `
-    SELECT block_num, batch_num, tx_hash, coinbase, sequencer_addr
+    SELECT block_num, batch_num, tx_hash, coinbase, sequencer_addr, seen_at, l1_finality, last_forced_batch_num
       FROM state.virtual_batch
      WHERE batch_num = $1`
 
-	e := p.getExecQuerier(dbTx)
-	err := e.QueryRow(ctx, getVirtualBatchSQL, batchNumber).Scan(&virtualBatch.BlockNumber, &virtualBatch.BatchNumber, &txHash, &coinbase, &sequencerAddr)
+	e := p.getExecQuerier(ctx, dbTx)
+	err := e.QueryRow(ctx, getVirtualBatchSQL, batchNumber).Scan(&virtualBatch.BlockNumber, &virtualBatch.BatchNumber, &txHash, &coinbase, &sequencerAddr, &virtualBatch.SeenAt, &l1Finality, &virtualBatch.LastForcedBatchNum)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrNotFound
 	} else if err != nil {
@@ -950,6 +1144,7 @@ func (p *PostgresStorage) GetVirtualBatch(ctx context.Context, batchNumber uint6
 	virtualBatch.Coinbase = common.HexToAddress(coinbase)
 	virtualBatch.SequencerAddr = common.HexToAddress(sequencerAddr)
 	virtualBatch.TxHash = common.HexToHash(txHash)
+	virtualBatch.L1Finality = L1Finality(l1Finality)
 	return &virtualBatch, nil
 }
 
@@ -957,7 +1152,7 @@ func (p *PostgresStorage) storeGenesisBatch(ctx context.Context, batch Batch, db
 	if batch.BatchNumber != 0 {
 		return fmt.Errorf("%w. Got %d, should be 0", ErrUnexpectedBatch, batch.BatchNumber)
 	}
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(
 		ctx,
 		addGenesisBatchSQL,
@@ -982,7 +1177,7 @@ func (p *PostgresStorage) storeGenesisBatch(ctx context.Context, batch Batch, db
 func (p *PostgresStorage) openBatch(ctx context.Context, batchContext ProcessingContext, dbTx pgx.Tx) error {
 	const openBatchSQL = "INSERT INTO state.batch (batch_num, global_exit_root, timestamp, coinbase, forced_batch_num) VALUES ($1, $2, $3, $4, $5)"
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(
 		ctx, openBatchSQL,
 		batchContext.BatchNumber,
@@ -997,7 +1192,7 @@ func (p *PostgresStorage) openBatch(ctx context.Context, batchContext Processing
 func (p *PostgresStorage) closeBatch(ctx context.Context, receipt ProcessingReceipt, dbTx pgx.Tx) error {
 	const closeBatchSQL = "UPDATE state.batch SET state_root = $1, local_exit_root = $2, acc_input_hash = $3, raw_txs_data = $4 WHERE batch_num = $5"
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, closeBatchSQL, receipt.StateRoot.String(), receipt.LocalExitRoot.String(), receipt.AccInputHash.String(), receipt.BatchL2Data, receipt.BatchNumber)
 	return err
 }
@@ -1012,7 +1207,7 @@ func (p *PostgresStorage) UpdateGERInOpenBatch(ctx context.Context, ger common.H
 		batchNumber   uint64
 		isBatchHasTxs bool
 	)
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, getLastBatchNumberSQL).Scan(&batchNumber)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return ErrStateNotSynchronized
@@ -1040,7 +1235,7 @@ func (p *PostgresStorage) UpdateGERInOpenBatch(ctx context.Context, ger common.H
 
 // IsBatchClosed indicates if the batch referenced by batchNum is closed or not
 func (p *PostgresStorage) IsBatchClosed(ctx context.Context, batchNum uint64, dbTx pgx.Tx) (bool, error) {
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	var isClosed bool
 	err := q.QueryRow(ctx, isBatchClosedSQL, batchNum).Scan(&isClosed)
 	return isClosed, err
@@ -1054,7 +1249,7 @@ func (p *PostgresStorage) GetNextForcedBatches(ctx context.Context, nextForcedBa
 		WHERE forced_batch_num > (Select coalesce(max(forced_batch_num),0) as forced_batch_num from state.batch INNER JOIN state.virtual_batch ON state.virtual_batch.batch_num = state.batch.batch_num)
 		ORDER BY forced_batch_num ASC LIMIT $1;
 	`
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	// Get the next forced batches
 	rows, err := q.Query(ctx, getNextForcedBatchesSQL, nextForcedBatches)
 
@@ -1094,7 +1289,7 @@ func (p *PostgresStorage) GetNextForcedBatches(ctx context.Context, nextForcedBa
 func (p *PostgresStorage) GetBatchNumberOfL2Block(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (uint64, error) {
 	getBatchNumByBlockNum := "SELECT batch_num FROM state.l2block WHERE block_num = $1"
 	batchNumber := uint64(0)
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getBatchNumByBlockNum, blockNumber).
 		Scan(&batchNumber)
 
@@ -1110,7 +1305,7 @@ func (p *PostgresStorage) GetBatchNumberOfL2Block(ctx context.Context, blockNumb
 func (p *PostgresStorage) BatchNumberByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (uint64, error) {
 	getBatchNumByBlockNum := "SELECT batch_num FROM state.l2block WHERE block_num = $1"
 	batchNumber := uint64(0)
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getBatchNumByBlockNum, blockNumber).
 		Scan(&batchNumber)
 
@@ -1127,7 +1322,7 @@ func (p *PostgresStorage) GetL2BlockByNumber(ctx context.Context, blockNumber ui
 	header := &types.Header{}
 	uncles := []*types.Header{}
 	receivedAt := time.Time{}
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getL2BlockByNumberSQL, blockNumber).
 		Scan(&header, &uncles, &receivedAt)
 
@@ -1152,7 +1347,7 @@ func (p *PostgresStorage) GetL2BlockByNumber(ctx context.Context, blockNumber ui
 // GetTransactionByHash gets a transaction accordingly to the provided transaction hash
 func (p *PostgresStorage) GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Transaction, error) {
 	var encoded string
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getTransactionByHashSQL, transactionHash.String()).Scan(&encoded)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1161,7 +1356,7 @@ func (p *PostgresStorage) GetTransactionByHash(ctx context.Context, transactionH
 		return nil, err
 	}
 
-	tx, err := DecodeTx(encoded)
+	tx, err := decodeStoredTx(encoded)
 	if err != nil {
 		return nil, err
 	}
@@ -1195,7 +1390,7 @@ func (p *PostgresStorage) GetTransactionReceipt(ctx context.Context, transaction
 		 WHERE r.tx_hash = $1`
 
 	receipt := types.Receipt{}
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getReceiptSQL, transactionHash.String()).
 		Scan(&receipt.TransactionIndex,
 			&txHash,
@@ -1211,6 +1406,21 @@ func (p *PostgresStorage) GetTransactionReceipt(ctx context.Context, transaction
 		)
 
 	if errors.Is(err, pgx.ErrNoRows) {
+		// the receipts of the pruned batches are removed, but not their
+		// transactions
+		if p.prunedHistory {
+			const isTxPrunedSQL = `
+			  SELECT EXISTS (SELECT 1 FROM state.transaction t
+			   INNER JOIN state.l2block b ON b.block_num = t.l2_block_num, state.sync_info s
+			   WHERE t.hash = $1 AND b.batch_num > 0 AND b.batch_num <= s.pruned_batch_num)`
+			var pruned bool
+			if err := q.QueryRow(ctx, isTxPrunedSQL, transactionHash.String()).Scan(&pruned); err != nil {
+				return nil, err
+			}
+			if pruned {
+				return nil, ErrPrunedHistory
+			}
+		}
 		return nil, ErrNotFound
 	} else if err != nil {
 		return nil, err
@@ -1237,7 +1447,7 @@ func (p *PostgresStorage) GetTransactionReceipt(ctx context.Context, transaction
 // since we only have a single transaction per l2 block, any index different from 0 will return a not found result
 func (p *PostgresStorage) GetTransactionByL2BlockHashAndIndex(ctx context.Context, blockHash common.Hash, index uint64, dbTx pgx.Tx) (*types.Transaction, error) {
 	var encoded string
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getTransactionByL2BlockHashAndIndexSQL, blockHash.String(), index).Scan(&encoded)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrNotFound
@@ -1245,7 +1455,7 @@ func (p *PostgresStorage) GetTransactionByL2BlockHashAndIndex(ctx context.Contex
 		return nil, err
 	}
 
-	tx, err := DecodeTx(encoded)
+	tx, err := decodeStoredTx(encoded)
 	if err != nil {
 		return nil, err
 	}
@@ -1257,7 +1467,7 @@ func (p *PostgresStorage) GetTransactionByL2BlockHashAndIndex(ctx context.Contex
 // since we only have a single transaction per l2 block, any index different from 0 will return a not found result
 func (p *PostgresStorage) GetTransactionByL2BlockNumberAndIndex(ctx context.Context, blockNumber uint64, index uint64, dbTx pgx.Tx) (*types.Transaction, error) {
 	var encoded string
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getTransactionByL2BlockNumberAndIndexSQL, blockNumber, index).Scan(&encoded)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrNotFound
@@ -1265,7 +1475,7 @@ func (p *PostgresStorage) GetTransactionByL2BlockNumberAndIndex(ctx context.Cont
 		return nil, err
 	}
 
-	tx, err := DecodeTx(encoded)
+	tx, err := decodeStoredTx(encoded)
 	if err != nil {
 		return nil, err
 	}
@@ -1276,7 +1486,7 @@ func (p *PostgresStorage) GetTransactionByL2BlockNumberAndIndex(ctx context.Cont
 // GetL2BlockTransactionCountByHash returns the number of transactions related to the provided block hash
 func (p *PostgresStorage) GetL2BlockTransactionCountByHash(ctx context.Context, blockHash common.Hash, dbTx pgx.Tx) (uint64, error) {
 	var count uint64
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getL2BlockTransactionCountByHashSQL, blockHash.String()).Scan(&count)
 	if err != nil {
 		return 0, err
@@ -1287,7 +1497,7 @@ func (p *PostgresStorage) GetL2BlockTransactionCountByHash(ctx context.Context,
 // GetL2BlockTransactionCountByNumber returns the number of transactions related to the provided block number
 func (p *PostgresStorage) GetL2BlockTransactionCountByNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (uint64, error) {
 	var count uint64
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getL2BlockTransactionCountByNumberSQL, blockNumber).Scan(&count)
 	if err != nil {
 		return 0, err
@@ -1297,7 +1507,7 @@ func (p *PostgresStorage) GetL2BlockTransactionCountByNumber(ctx context.Context
 
 // getTransactionLogs returns the logs of a transaction by transaction hash
 func (p *PostgresStorage) getTransactionLogs(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) ([]*types.Log, error) {
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 
 	const getTransactionLogsSQL = `
 	SELECT t.l2_block_num, b.block_hash, l.tx_hash, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3
@@ -1366,7 +1576,7 @@ func scanLogs(rows pgx.Rows) ([]*types.Log, error) {
 
 // AddL2Block adds a new L2 block to the State Store
 func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2Block *types.Block, receipts []*types.Receipt, dbTx pgx.Tx) error {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 
 	const addL2BlockSQL = `
         INSERT INTO state.l2block (block_num, block_hash, header, uncles, parent_hash, state_root, received_at, batch_num, created_at)
@@ -1435,7 +1645,7 @@ func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2
 // GetLastConsolidatedL2BlockNumber gets the last l2 block verified
 func (p *PostgresStorage) GetLastConsolidatedL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	var lastConsolidatedBlockNumber uint64
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getLastConsolidatedBlockNumberSQL).Scan(&lastConsolidatedBlockNumber)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1450,7 +1660,7 @@ func (p *PostgresStorage) GetLastConsolidatedL2BlockNumber(ctx context.Context,
 // GetLastL2BlockNumber gets the last l2 block number
 func (p *PostgresStorage) GetLastL2BlockNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	var lastBlockNumber uint64
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getLastL2BlockNumber).Scan(&lastBlockNumber)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1466,7 +1676,7 @@ func (p *PostgresStorage) GetLastL2BlockNumber(ctx context.Context, dbTx pgx.Tx)
 func (p *PostgresStorage) GetLastL2BlockHeader(ctx context.Context, dbTx pgx.Tx) (*types.Header, error) {
 	const query = "SELECT b.header FROM state.l2block b ORDER BY b.block_num DESC LIMIT 1"
 	header := &types.Header{}
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, query).Scan(&header)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1486,7 +1696,7 @@ func (p *PostgresStorage) GetLastL2Block(ctx context.Context, dbTx pgx.Tx) (*typ
 		unclesStr  string
 		receivedAt time.Time
 	)
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getLastL2BlockSQL).Scan(&headerStr, &unclesStr, &receivedAt)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1523,7 +1733,7 @@ func (p *PostgresStorage) GetLastL2Block(ctx context.Context, dbTx pgx.Tx) (*typ
 func (p *PostgresStorage) GetLastVerifiedBatchNumberSeenOnEthereum(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	const getLastVerifiedBatchSeenSQL = "SELECT last_batch_num_verified FROM state.sync_info LIMIT 1"
 	var batchNumber uint64
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, getLastVerifiedBatchSeenSQL).Scan(&batchNumber)
 	if err != nil {
 		return 0, err
@@ -1538,7 +1748,7 @@ func (p *PostgresStorage) GetLastVerifiedBatch(ctx context.Context, dbTx pgx.Tx)
 		verifiedBatch VerifiedBatch
 		txHash, agg   string
 	)
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, query).Scan(&verifiedBatch.BlockNumber, &verifiedBatch.BatchNumber, &txHash, &agg)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrNotFound
@@ -1554,7 +1764,7 @@ func (p *PostgresStorage) GetLastVerifiedBatch(ctx context.Context, dbTx pgx.Tx)
 func (p *PostgresStorage) GetStateRootByBatchNumber(ctx context.Context, batchNum uint64, dbTx pgx.Tx) (common.Hash, error) {
 	const query = "SELECT state_root FROM state.batch WHERE batch_num = $1"
 	var stateRootStr string
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, query, batchNum).Scan(&stateRootStr)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return common.Hash{}, ErrNotFound
@@ -1568,7 +1778,7 @@ func (p *PostgresStorage) GetStateRootByBatchNumber(ctx context.Context, batchNu
 func (p *PostgresStorage) GetLocalExitRootByBatchNumber(ctx context.Context, batchNum uint64, dbTx pgx.Tx) (common.Hash, error) {
 	const query = "SELECT local_exit_root FROM state.batch WHERE batch_num = $1"
 	var localExitRootStr string
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, query, batchNum).Scan(&localExitRootStr)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return common.Hash{}, ErrNotFound
@@ -1582,7 +1792,7 @@ func (p *PostgresStorage) GetLocalExitRootByBatchNumber(ctx context.Context, bat
 func (p *PostgresStorage) GetBlockNumVirtualBatchByBatchNum(ctx context.Context, batchNum uint64, dbTx pgx.Tx) (uint64, error) {
 	const query = "SELECT block_num FROM state.virtual_batch WHERE batch_num = $1"
 	var blockNum uint64
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err := e.QueryRow(ctx, query, batchNum).Scan(&blockNum)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return 0, ErrNotFound
@@ -1597,7 +1807,7 @@ func (p *PostgresStorage) GetL2BlockByHash(ctx context.Context, hash common.Hash
 	header := &types.Header{}
 	uncles := []*types.Header{}
 	receivedAt := time.Time{}
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getL2BlockByHashSQL, hash.String()).
 		Scan(&header, &uncles, &receivedAt)
 
@@ -1621,7 +1831,7 @@ func (p *PostgresStorage) GetL2BlockByHash(ctx context.Context, hash common.Hash
 
 // GetTxsByBlockNumber returns all the txs in a given block
 func (p *PostgresStorage) GetTxsByBlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error) {
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	rows, err := q.Query(ctx, getTxsByBlockNumSQL, blockNumber)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1639,7 +1849,7 @@ func (p *PostgresStorage) GetTxsByBlockNumber(ctx context.Context, blockNumber u
 			return nil, err
 		}
 
-		tx, err := DecodeTx(encoded)
+		tx, err := decodeStoredTx(encoded)
 		if err != nil {
 			return nil, err
 		}
@@ -1651,7 +1861,7 @@ func (p *PostgresStorage) GetTxsByBlockNumber(ctx context.Context, blockNumber u
 
 // GetTxsByBatchNumber returns all the txs in a given batch
 func (p *PostgresStorage) GetTxsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error) {
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 
 	const getTxsByBatchNumSQL = `
         SELECT encoded
@@ -1677,7 +1887,7 @@ func (p *PostgresStorage) GetTxsByBatchNumber(ctx context.Context, batchNumber u
 			return nil, err
 		}
 
-		tx, err := DecodeTx(encoded)
+		tx, err := decodeStoredTx(encoded)
 		if err != nil {
 			return nil, err
 		}
@@ -1690,7 +1900,7 @@ func (p *PostgresStorage) GetTxsByBatchNumber(ctx context.Context, batchNumber u
 // GetL2BlockHeaderByHash gets the block header by block number
 func (p *PostgresStorage) GetL2BlockHeaderByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*types.Header, error) {
 	header := &types.Header{}
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getL2BlockHeaderByHashSQL, hash.String()).Scan(&header)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1704,7 +1914,7 @@ func (p *PostgresStorage) GetL2BlockHeaderByHash(ctx context.Context, hash commo
 // GetL2BlockHeaderByNumber gets the block header by block number
 func (p *PostgresStorage) GetL2BlockHeaderByNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (*types.Header, error) {
 	header := &types.Header{}
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getL2BlockHeaderByNumberSQL, blockNumber).Scan(&header)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1717,7 +1927,7 @@ func (p *PostgresStorage) GetL2BlockHeaderByNumber(ctx context.Context, blockNum
 
 // GetL2BlockHashesSince gets the block hashes added since the provided date
 func (p *PostgresStorage) GetL2BlockHashesSince(ctx context.Context, since time.Time, dbTx pgx.Tx) ([]common.Hash, error) {
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	rows, err := q.Query(ctx, getL2BlockHashesSinceSQL, since)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return []common.Hash{}, nil
@@ -1743,7 +1953,7 @@ func (p *PostgresStorage) GetL2BlockHashesSince(ctx context.Context, since time.
 
 // IsL2BlockConsolidated checks if the block ID is consolidated
 func (p *PostgresStorage) IsL2BlockConsolidated(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (bool, error) {
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	rows, err := q.Query(ctx, isL2BlockConsolidated, blockNumber)
 	if err != nil {
 		return false, err
@@ -1760,7 +1970,7 @@ func (p *PostgresStorage) IsL2BlockConsolidated(ctx context.Context, blockNumber
 
 // IsL2BlockVirtualized checks if the block  ID is virtualized
 func (p *PostgresStorage) IsL2BlockVirtualized(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (bool, error) {
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	rows, err := q.Query(ctx, isL2BlockVirtualized, blockNumber)
 	if err != nil {
 		return false, err
@@ -1777,6 +1987,9 @@ func (p *PostgresStorage) IsL2BlockVirtualized(ctx context.Context, blockNumber
 
 // GetLogs returns the logs that match the filter
 func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock uint64, addresses []common.Address, topics [][]common.Hash, blockHash *common.Hash, since *time.Time, dbTx pgx.Tx) ([]*types.Log, error) {
+	const isL2BlockPrunedSQL = `
+	  SELECT EXISTS (SELECT 1 FROM state.l2block b, state.sync_info s
+	   WHERE (b.block_num BETWEEN $1 AND $2 OR b.block_hash = $3) AND b.batch_num > 0 AND b.batch_num <= s.pruned_batch_num)`
 	const getLogsByBlockHashSQL = `
 	  SELECT t.l2_block_num, b.block_hash, l.tx_hash, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3
 		FROM state.log l
@@ -1799,7 +2012,29 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 
 	var err error
 	var rows pgx.Rows
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
+
+	// the logs of the range are incomplete if any of its blocks was pruned
+	if p.prunedHistory {
+		var (
+			fromBlockNum, toBlockNum *uint64
+			blockHashStr             *string
+		)
+		if blockHash != nil {
+			h := blockHash.String()
+			blockHashStr = &h
+		} else {
+			fromBlockNum, toBlockNum = &fromBlock, &toBlock
+		}
+		var pruned bool
+		if err := q.QueryRow(ctx, isL2BlockPrunedSQL, fromBlockNum, toBlockNum, blockHashStr).Scan(&pruned); err != nil {
+			return nil, err
+		}
+		if pruned {
+			return nil, ErrPrunedHistory
+		}
+	}
+
 	if blockHash != nil {
 		rows, err = q.Query(ctx, getLogsByBlockHashSQL, blockHash.String())
 	} else {
@@ -1833,7 +2068,7 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 // GetSyncingInfo returns information regarding the syncing status of the node
 func (p *PostgresStorage) GetSyncingInfo(ctx context.Context, dbTx pgx.Tx) (SyncingInfo, error) {
 	var info SyncingInfo
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 	err := q.QueryRow(ctx, getSyncingInfoSQL).
 		Scan(&info.InitialSyncingBlock, &info.LastBlockNumberSeen, &info.LastBlockNumberConsolidated,
 			&info.InitialSyncingBatch, &info.LastBatchNumberSeen, &info.LastBatchNumberConsolidated)
@@ -1878,7 +2113,7 @@ func (p *PostgresStorage) hashesToHex(hashes []common.Hash) []string {
 
 // AddReceipt adds a new receipt to the State Store
 func (p *PostgresStorage) AddReceipt(ctx context.Context, receipt *types.Receipt, dbTx pgx.Tx) error {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	const addReceiptSQL = `
         INSERT INTO state.receipt (tx_hash, type, post_state, status, cumulative_gas_used, gas_used, block_num, tx_index, contract_address)
                            VALUES (     $1,   $2,         $3,     $4,                  $5,       $6,        $7,       $8,               $9)`
@@ -1886,6 +2121,40 @@ func (p *PostgresStorage) AddReceipt(ctx context.Context, receipt *types.Receipt
 	return err
 }
 
+// AddReceiptZKCounters stores the ZK counters used by the tx of a receipt
+func (p *PostgresStorage) AddReceiptZKCounters(ctx context.Context, txHash common.Hash, counters ZKCounters, dbTx pgx.Tx) error {
+	const addReceiptZKCountersSQL = "UPDATE state.receipt SET zk_counters = $2 WHERE tx_hash = $1"
+	countersJSON, err := json.Marshal(counters)
+	if err != nil {
+		return err
+	}
+	e := p.getExecQuerier(ctx, dbTx)
+	_, err = e.Exec(ctx, addReceiptZKCountersSQL, txHash.String(), countersJSON)
+	return err
+}
+
+// GetReceiptZKCounters returns the ZK counters used by the tx of a receipt,
+// ErrNotFound if the receipt is not stored or its counters are not known
+func (p *PostgresStorage) GetReceiptZKCounters(ctx context.Context, txHash common.Hash, dbTx pgx.Tx) (*ZKCounters, error) {
+	const getReceiptZKCountersSQL = "SELECT zk_counters FROM state.receipt WHERE tx_hash = $1"
+	var countersJSON []byte
+	e := p.getExecQuerier(ctx, dbTx)
+	err := e.QueryRow(ctx, getReceiptZKCountersSQL, txHash.String()).Scan(&countersJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if countersJSON == nil {
+		return nil, ErrNotFound
+	}
+	var counters ZKCounters
+	if err := json.Unmarshal(countersJSON, &counters); err != nil {
+		return nil, err
+	}
+	return &counters, nil
+}
+
 // AddLog adds a new log to the State Store
 func (p *PostgresStorage) AddLog(ctx context.Context, l *types.Log, dbTx pgx.Tx) error {
 	const addLogSQL = `INSERT INTO state.log (tx_hash, log_index, address, data, topic0, topic1, topic2, topic3)
@@ -1897,7 +2166,7 @@ func (p *PostgresStorage) AddLog(ctx context.Context, l *types.Log, dbTx pgx.Tx)
 		topicsAsHex[i] = &topicHex
 	}
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, addLogSQL,
 		l.TxHash.String(), l.Index, l.Address.String(), hex.EncodeToHex(l.Data),
 		topicsAsHex[0], topicsAsHex[1], topicsAsHex[2], topicsAsHex[3])
@@ -1914,7 +2183,7 @@ func (p *PostgresStorage) GetExitRootByGlobalExitRoot(ctx context.Context, ger c
 
 	const sql = "SELECT block_num, mainnet_exit_root, rollup_exit_root, global_exit_root FROM state.exit_root WHERE global_exit_root = $1 ORDER BY id DESC LIMIT 1"
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	err = e.QueryRow(ctx, sql, ger).Scan(&exitRoot.BlockNumber, &exitRoot.MainnetExitRoot, &exitRoot.RollupExitRoot, &exitRoot.GlobalExitRoot)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1927,14 +2196,14 @@ func (p *PostgresStorage) GetExitRootByGlobalExitRoot(ctx context.Context, ger c
 
 // AddSequence stores the sequence information to allow the aggregator verify sequences.
 func (p *PostgresStorage) AddSequence(ctx context.Context, sequence Sequence, dbTx pgx.Tx) error {
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, addSequenceSQL, sequence.FromBatchNumber, sequence.ToBatchNumber)
 	return err
 }
 
 // GetSequences get the next sequences higher than an specify batch number
 func (p *PostgresStorage) GetSequences(ctx context.Context, lastVerifiedBatchNumber uint64, dbTx pgx.Tx) ([]Sequence, error) {
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 
 	rows, err := q.Query(ctx, getSequencesSQL, lastVerifiedBatchNumber)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -1984,7 +2253,7 @@ func (p *PostgresStorage) GetVirtualBatchToProve(ctx context.Context, lastVerfie
 			)
 		ORDER BY b.batch_num ASC LIMIT 1
 		`
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	row := e.QueryRow(ctx, query, lastVerfiedBatchNumber)
 	batch, err := scanBatch(row)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -2001,7 +2270,7 @@ func (p *PostgresStorage) CheckProofContainsCompleteSequences(ctx context.Contex
 		SELECT EXISTS (SELECT 1 FROM state.sequences s1 WHERE s1.from_batch_num = $1) AND
 			   EXISTS (SELECT 1 FROM state.sequences s2 WHERE s2.to_batch_num = $2)
 		`
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	var exists bool
 	err := e.QueryRow(ctx, getProofContainsCompleteSequencesSQL, proof.BatchNumber, proof.BatchNumberFinal).Scan(&exists)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
@@ -2032,7 +2301,7 @@ func (p *PostgresStorage) GetProofReadyToVerify(ctx context.Context, lastVerfied
 
 	var proof *Proof = &Proof{}
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	row := e.QueryRow(ctx, getProofReadyToVerifySQL, lastVerfiedBatchNumber+1)
 	err := row.Scan(&proof.BatchNumber, &proof.BatchNumberFinal, &proof.Proof, &proof.ProofID, &proof.InputProver, &proof.Prover, &proof.ProverID, &proof.GeneratingSince, &proof.CreatedAt, &proof.UpdatedAt)
 
@@ -2098,7 +2367,7 @@ func (p *PostgresStorage) GetProofsToAggregate(ctx context.Context, dbTx pgx.Tx)
 		LIMIT 1
 		`
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	row := e.QueryRow(ctx, getProofsToAggregateSQL)
 	err := row.Scan(
 		&proof1.BatchNumber, &proof1.BatchNumberFinal, &proof1.Proof, &proof1.ProofID, &proof1.InputProver, &proof1.Prover, &proof1.ProverID, &proof1.GeneratingSince, &proof1.CreatedAt, &proof1.UpdatedAt,
@@ -2116,7 +2385,7 @@ func (p *PostgresStorage) GetProofsToAggregate(ctx context.Context, dbTx pgx.Tx)
 // AddGeneratedProof adds a generated proof to the storage
 func (p *PostgresStorage) AddGeneratedProof(ctx context.Context, proof *Proof, dbTx pgx.Tx) error {
 	const addGeneratedProofSQL = "INSERT INTO state.proof (batch_num, batch_num_final, proof, proof_id, input_prover, prover, prover_id, generating_since, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)"
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	now := time.Now().UTC().Round(time.Microsecond)
 	_, err := e.Exec(ctx, addGeneratedProofSQL, proof.BatchNumber, proof.BatchNumberFinal, proof.Proof, proof.ProofID, proof.InputProver, proof.Prover, proof.ProverID, proof.GeneratingSince, now, now)
 	return err
@@ -2125,7 +2394,7 @@ func (p *PostgresStorage) AddGeneratedProof(ctx context.Context, proof *Proof, d
 // UpdateGeneratedProof updates a generated proof in the storage
 func (p *PostgresStorage) UpdateGeneratedProof(ctx context.Context, proof *Proof, dbTx pgx.Tx) error {
 	const addGeneratedProofSQL = "UPDATE state.proof SET proof = $3, proof_id = $4, input_prover = $5, prover = $6, prover_id = $7, generating_since = $8, updated_at = $9 WHERE batch_num = $1 AND batch_num_final = $2"
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	now := time.Now().UTC().Round(time.Microsecond)
 	_, err := e.Exec(ctx, addGeneratedProofSQL, proof.BatchNumber, proof.BatchNumberFinal, proof.Proof, proof.ProofID, proof.InputProver, proof.Prover, proof.ProverID, proof.GeneratingSince, now)
 	return err
@@ -2135,7 +2404,7 @@ func (p *PostgresStorage) UpdateGeneratedProof(ctx context.Context, proof *Proof
 // inside the batch numbers range.
 func (p *PostgresStorage) DeleteGeneratedProofs(ctx context.Context, batchNumber uint64, batchNumberFinal uint64, dbTx pgx.Tx) error {
 	const deleteGeneratedProofSQL = "DELETE FROM state.proof WHERE batch_num >= $1 AND batch_num_final <= $2"
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, deleteGeneratedProofSQL, batchNumber, batchNumberFinal)
 	return err
 }
@@ -2144,7 +2413,7 @@ func (p *PostgresStorage) DeleteGeneratedProofs(ctx context.Context, batchNumber
 // the specified batch number included.
 func (p *PostgresStorage) CleanupGeneratedProofs(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) error {
 	const deleteGeneratedProofSQL = "DELETE FROM state.proof WHERE batch_num_final <= $1"
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, deleteGeneratedProofSQL, batchNumber)
 	return err
 }
@@ -2157,7 +2426,7 @@ func (p *PostgresStorage) CleanupLockedProofs(ctx context.Context, duration stri
 		return 0, err
 	}
 	sql := fmt.Sprintf("DELETE FROM state.proof WHERE generating_since < (NOW() - interval '%s')", interval)
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	ct, err := e.Exec(ctx, sql)
 	if err != nil {
 		return 0, err
@@ -2169,7 +2438,7 @@ func (p *PostgresStorage) CleanupLockedProofs(ctx context.Context, duration stri
 // This method is meant to be use during aggregator boot-up sequence
 func (p *PostgresStorage) DeleteUngeneratedProofs(ctx context.Context, dbTx pgx.Tx) error {
 	const deleteUngeneratedProofsSQL = "DELETE FROM state.proof WHERE generating_since IS NOT NULL"
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, deleteUngeneratedProofsSQL)
 	return err
 }
@@ -2183,7 +2452,7 @@ func (p *PostgresStorage) GetLastClosedBatch(ctx context.Context, dbTx pgx.Tx) (
 			ORDER BY bt.batch_num DESC
 			LIMIT 1;`
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	row := e.QueryRow(ctx, getLastClosedBatchSQL)
 	batch, err := scanBatch(row)
 
@@ -2199,7 +2468,7 @@ func (p *PostgresStorage) GetLastClosedBatch(ctx context.Context, dbTx pgx.Tx) (
 func (p *PostgresStorage) UpdateBatchL2Data(ctx context.Context, batchNumber uint64, batchL2Data []byte, dbTx pgx.Tx) error {
 	const updateL2DataSQL = "UPDATE state.batch SET raw_txs_data = $2 WHERE batch_num = $1"
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, updateL2DataSQL, batchNumber, batchL2Data)
 	return err
 }
@@ -2207,7 +2476,7 @@ func (p *PostgresStorage) UpdateBatchL2Data(ctx context.Context, batchNumber uin
 // AddAccumulatedInputHash adds the accumulated input hash
 func (p *PostgresStorage) AddAccumulatedInputHash(ctx context.Context, batchNum uint64, accInputHash common.Hash, dbTx pgx.Tx) error {
 	const addAccInputHashBatchSQL = "UPDATE state.batch SET acc_input_hash = $1 WHERE batch_num = $2"
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, addAccInputHashBatchSQL, accInputHash.String(), batchNum)
 	return err
 }
@@ -2216,7 +2485,7 @@ func (p *PostgresStorage) AddAccumulatedInputHash(ctx context.Context, batchNum
 func (p *PostgresStorage) GetLastTrustedForcedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	const getLastTrustedForcedBatchNumberSQL = "SELECT COALESCE(MAX(forced_batch_num), 0) FROM state.batch"
 	var forcedBatchNumber uint64
-	q := p.getExecQuerier(dbTx)
+	q := p.getExecQuerier(ctx, dbTx)
 
 	err := q.QueryRow(ctx, getLastTrustedForcedBatchNumberSQL).Scan(&forcedBatchNumber)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -2229,7 +2498,327 @@ func (p *PostgresStorage) GetLastTrustedForcedBatchNumber(ctx context.Context, d
 func (p *PostgresStorage) AddDebugInfo(ctx context.Context, info *DebugInfo, dbTx pgx.Tx) error {
 	const insertDebugInfoSQL = "INSERT INTO state.debug (error_type, timestamp, payload) VALUES ($1, $2, $3)"
 
-	e := p.getExecQuerier(dbTx)
+	e := p.getExecQuerier(ctx, dbTx)
 	_, err := e.Exec(ctx, insertDebugInfoSQL, info.ErrorType, info.Timestamp, info.Payload)
 	return err
 }
+
+// AddForkID stores a fork id activated on L1, if the fork id already exists it
+// is updated with the provided activation batch and L1 block
+func (p *PostgresStorage) AddForkID(ctx context.Context, forkID ForkIDInterval, dbTx pgx.Tx) error {
+	const addForkIDSQL = `
+		INSERT INTO state.fork_id (fork_id, from_batch_num, version, block_num) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (fork_id) DO UPDATE SET from_batch_num = $2, version = $3, block_num = $4`
+	e := p.getExecQuerier(ctx, dbTx)
+	_, err := e.Exec(ctx, addForkIDSQL, forkID.ForkId, forkID.FromBatchNumber, forkID.Version, forkID.BlockNumber)
+	return err
+}
+
+// GetForkIDs returns the fork id intervals built from the stored fork ids
+func (p *PostgresStorage) GetForkIDs(ctx context.Context, dbTx pgx.Tx) ([]ForkIDInterval, error) {
+	const getForkIDsSQL = "SELECT fork_id, from_batch_num, version, block_num FROM state.fork_id ORDER BY from_batch_num, fork_id"
+	q := p.getExecQuerier(ctx, dbTx)
+	rows, err := q.Query(ctx, getForkIDsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	forkIDs := make([]ForkIDInterval, 0)
+	for rows.Next() {
+		var forkID ForkIDInterval
+		if err := rows.Scan(&forkID.ForkId, &forkID.FromBatchNumber, &forkID.Version, &forkID.BlockNumber); err != nil {
+			return nil, err
+		}
+		forkIDs = append(forkIDs, forkID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return buildForkIDIntervals(forkIDs), nil
+}
+
+// GetLastBlockNumberUpTo returns the last stored L1 block up to the given one,
+// or the first stored block when all of them are after it
+func (p *PostgresStorage) GetLastBlockNumberUpTo(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (uint64, error) {
+	const getLastBlockNumberUpToSQL = `
+		SELECT COALESCE(
+			(SELECT MAX(block_num) FROM state.block WHERE block_num <= $1),
+			(SELECT MIN(block_num) FROM state.block))`
+	q := p.getExecQuerier(ctx, dbTx)
+	var lastBlockNumber *uint64
+	if err := q.QueryRow(ctx, getLastBlockNumberUpToSQL, blockNumber).Scan(&lastBlockNumber); err != nil {
+		return 0, err
+	}
+	if lastBlockNumber == nil {
+		return 0, ErrStateNotSynchronized
+	}
+	return *lastBlockNumber, nil
+}
+
+// GetPrunedBatchNumber returns the last batch whose logs, receipts and
+// transaction bodies were pruned, 0 when nothing was pruned
+func (p *PostgresStorage) GetPrunedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	const getPrunedBatchNumSQL = "SELECT pruned_batch_num FROM state.sync_info LIMIT 1"
+	var prunedBatchNum uint64
+	q := p.getExecQuerier(ctx, dbTx)
+	if err := q.QueryRow(ctx, getPrunedBatchNumSQL).Scan(&prunedBatchNum); errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return prunedBatchNum, nil
+}
+
+// PruneBatches removes the logs, the receipts, the transaction bodies and the
+// debug info of the batches in the range, recording the last one as pruned.
+// The transaction rows are kept so the lookups by hash keep working
+func (p *PostgresStorage) PruneBatches(ctx context.Context, fromBatchNumber, toBatchNumber uint64, dbTx pgx.Tx) error {
+	const deleteLogsSQL = `
+		DELETE FROM state.log l USING state.transaction t, state.l2block b
+		 WHERE l.tx_hash = t.hash AND t.l2_block_num = b.block_num AND b.batch_num BETWEEN $1 AND $2`
+	const deleteReceiptsSQL = `
+		DELETE FROM state.receipt r USING state.l2block b
+		 WHERE r.block_num = b.block_num AND b.batch_num BETWEEN $1 AND $2`
+	const clearTransactionsSQL = `
+		UPDATE state.transaction t SET encoded = '', decoded = NULL
+		  FROM state.l2block b
+		 WHERE t.l2_block_num = b.block_num AND b.batch_num BETWEEN $1 AND $2 AND t.encoded <> ''`
+	const deleteDebugInfoSQL = `
+		DELETE FROM state.debug
+		 WHERE timestamp < (SELECT timestamp FROM state.batch WHERE batch_num = $1)`
+	const updatePrunedBatchNumSQL = "UPDATE state.sync_info SET pruned_batch_num = $1"
+
+	e := p.getExecQuerier(ctx, dbTx)
+	for _, sql := range []string{deleteLogsSQL, deleteReceiptsSQL, clearTransactionsSQL} {
+		if _, err := e.Exec(ctx, sql, fromBatchNumber, toBatchNumber); err != nil {
+			return err
+		}
+	}
+	if _, err := e.Exec(ctx, deleteDebugInfoSQL, toBatchNumber); err != nil {
+		return err
+	}
+	_, err := e.Exec(ctx, updatePrunedBatchNumSQL, toBatchNumber)
+	return err
+}
+
+// GetVirtualBatchesAfterBlock returns the virtual batches sequenced in the L1
+// blocks with number greater than the given one, ordered by batch number
+func (p *PostgresStorage) GetVirtualBatchesAfterBlock(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]VirtualBatch, error) {
+	const getVirtualBatchesAfterBlockSQL = `
+    SELECT block_num, batch_num, tx_hash, coinbase, sequencer_addr, seen_at, l1_finality, last_forced_batch_num
+      FROM state.virtual_batch
+     WHERE block_num > $1
+     ORDER BY batch_num`
+
+	e := p.getExecQuerier(ctx, dbTx)
+	rows, err := e.Query(ctx, getVirtualBatchesAfterBlockSQL, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	virtualBatches := make([]VirtualBatch, 0)
+	for rows.Next() {
+		var (
+			virtualBatch  VirtualBatch
+			txHash        string
+			coinbase      string
+			sequencerAddr string
+			l1Finality    string
+		)
+		if err := rows.Scan(&virtualBatch.BlockNumber, &virtualBatch.BatchNumber, &txHash, &coinbase, &sequencerAddr, &virtualBatch.SeenAt, &l1Finality, &virtualBatch.LastForcedBatchNum); err != nil {
+			return nil, err
+		}
+		virtualBatch.Coinbase = common.HexToAddress(coinbase)
+		virtualBatch.SequencerAddr = common.HexToAddress(sequencerAddr)
+		virtualBatch.TxHash = common.HexToHash(txHash)
+		virtualBatch.L1Finality = L1Finality(l1Finality)
+		virtualBatches = append(virtualBatches, virtualBatch)
+	}
+	return virtualBatches, rows.Err()
+}
+
+// GetTransactionsFromBatchNumber returns the transactions of the batches with
+// number greater than or equal to the given one, in the order they were added
+func (p *PostgresStorage) GetTransactionsFromBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]types.Transaction, error) {
+	const getTransactionsFromBatchNumberSQL = `
+		SELECT t.encoded
+		  FROM state.transaction t
+		 INNER JOIN state.l2block b ON t.l2_block_num = b.block_num
+		 WHERE b.batch_num >= $1
+		 ORDER BY t.l2_block_num`
+
+	e := p.getExecQuerier(ctx, dbTx)
+	rows, err := e.Query(ctx, getTransactionsFromBatchNumberSQL, batchNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	txs := make([]types.Transaction, 0)
+	for rows.Next() {
+		var encoded string
+		if err := rows.Scan(&encoded); err != nil {
+			return nil, err
+		}
+		tx, err := decodeStoredTx(encoded)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, *tx)
+	}
+	return txs, rows.Err()
+}
+
+// GetFirstL2BlockNumberFromBatchNumber returns the number of the first L2 block
+// of the batches with number greater than or equal to the given one
+func (p *PostgresStorage) GetFirstL2BlockNumberFromBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (uint64, error) {
+	const getFirstL2BlockNumberFromBatchNumberSQL = "SELECT MIN(block_num) FROM state.l2block WHERE batch_num >= $1"
+
+	var blockNumber *uint64
+	e := p.getExecQuerier(ctx, dbTx)
+	if err := e.QueryRow(ctx, getFirstL2BlockNumberFromBatchNumberSQL, batchNumber).Scan(&blockNumber); err != nil {
+		return 0, err
+	}
+	if blockNumber == nil {
+		return 0, ErrNotFound
+	}
+	return *blockNumber, nil
+}
+
+// AddReorg adds a reorg to the reorg history, setting its ID
+func (p *PostgresStorage) AddReorg(ctx context.Context, reorg *Reorg, dbTx pgx.Tx) error {
+	const addReorgSQL = `
+		INSERT INTO state.reorgs (kind, depth, first_block_num, first_batch_num, old_hash, new_hash, reason, dropped_txs, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`
+
+	droppedTxs := make([]string, 0, len(reorg.DroppedTxs))
+	for _, tx := range reorg.DroppedTxs {
+		binary, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		droppedTxs = append(droppedTxs, hex.EncodeToHex(binary))
+	}
+	droppedTxsJSON, err := json.Marshal(droppedTxs)
+	if err != nil {
+		return err
+	}
+
+	e := p.getExecQuerier(ctx, dbTx)
+	return e.QueryRow(ctx, addReorgSQL, string(reorg.Kind), reorg.Depth, reorg.FirstBlockNumber, reorg.FirstBatchNumber,
+		reorg.OldHash.String(), reorg.NewHash.String(), reorg.Reason, droppedTxsJSON, reorg.CreatedAt.UTC()).Scan(&reorg.ID)
+}
+
+// GetReorgs returns the reorgs recorded since the given time, in the order
+// they happened
+func (p *PostgresStorage) GetReorgs(ctx context.Context, fromTime time.Time, dbTx pgx.Tx) ([]Reorg, error) {
+	const getReorgsSQL = `
+		SELECT id, kind, depth, first_block_num, first_batch_num, old_hash, new_hash, reason, dropped_txs, created_at
+		  FROM state.reorgs
+		 WHERE created_at >= $1
+		 ORDER BY id`
+	return p.queryReorgs(ctx, getReorgsSQL, fromTime.UTC(), dbTx)
+}
+
+// GetReorgsAfterID returns the reorgs recorded after the one with the given
+// ID, in the order they happened
+func (p *PostgresStorage) GetReorgsAfterID(ctx context.Context, reorgID uint64, dbTx pgx.Tx) ([]Reorg, error) {
+	const getReorgsAfterIDSQL = `
+		SELECT id, kind, depth, first_block_num, first_batch_num, old_hash, new_hash, reason, dropped_txs, created_at
+		  FROM state.reorgs
+		 WHERE id > $1
+		 ORDER BY id`
+	return p.queryReorgs(ctx, getReorgsAfterIDSQL, reorgID, dbTx)
+}
+
+// GetLastReorgID returns the ID of the last reorg recorded, 0 if there is none
+func (p *PostgresStorage) GetLastReorgID(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	const getLastReorgIDSQL = "SELECT COALESCE(MAX(id), 0) FROM state.reorgs"
+	var reorgID uint64
+	e := p.getExecQuerier(ctx, dbTx)
+	err := e.QueryRow(ctx, getLastReorgIDSQL).Scan(&reorgID)
+	return reorgID, err
+}
+
+// GetProcessedReorgID returns the ID of the last reorg whose dropped txs were
+// resubmitted to the pool, ErrNotFound if none was processed yet
+func (p *PostgresStorage) GetProcessedReorgID(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	const getProcessedReorgIDSQL = "SELECT reorg_id FROM state.processed_reorg LIMIT 1"
+	var reorgID uint64
+	e := p.getExecQuerier(ctx, dbTx)
+	err := e.QueryRow(ctx, getProcessedReorgIDSQL).Scan(&reorgID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrNotFound
+	} else if err != nil {
+		return 0, err
+	}
+	return reorgID, nil
+}
+
+// SetProcessedReorgID persists the ID of the last reorg whose dropped txs were
+// resubmitted to the pool
+func (p *PostgresStorage) SetProcessedReorgID(ctx context.Context, reorgID uint64, dbTx pgx.Tx) error {
+	const deleteProcessedReorgSQL = "DELETE FROM state.processed_reorg"
+	const addProcessedReorgSQL = "INSERT INTO state.processed_reorg (reorg_id) VALUES ($1)"
+	e := p.getExecQuerier(ctx, dbTx)
+	if _, err := e.Exec(ctx, deleteProcessedReorgSQL); err != nil {
+		return err
+	}
+	_, err := e.Exec(ctx, addProcessedReorgSQL, reorgID)
+	return err
+}
+
+// queryReorgs returns the reorgs selected by the query with the given argument
+func (p *PostgresStorage) queryReorgs(ctx context.Context, query string, arg interface{}, dbTx pgx.Tx) ([]Reorg, error) {
+	e := p.getExecQuerier(ctx, dbTx)
+	rows, err := e.Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reorgs := make([]Reorg, 0)
+	for rows.Next() {
+		var (
+			reorg          Reorg
+			kind           string
+			oldHash        string
+			newHash        string
+			droppedTxsJSON []byte
+		)
+		if err := rows.Scan(&reorg.ID, &kind, &reorg.Depth, &reorg.FirstBlockNumber, &reorg.FirstBatchNumber,
+			&oldHash, &newHash, &reorg.Reason, &droppedTxsJSON, &reorg.CreatedAt); err != nil {
+			return nil, err
+		}
+		reorg.Kind = ReorgKind(kind)
+		reorg.OldHash = common.HexToHash(oldHash)
+		reorg.NewHash = common.HexToHash(newHash)
+
+		var droppedTxs []string
+		if err := json.Unmarshal(droppedTxsJSON, &droppedTxs); err != nil {
+			return nil, err
+		}
+		reorg.DroppedTxs = make([]types.Transaction, 0, len(droppedTxs))
+		for _, encoded := range droppedTxs {
+			tx, err := DecodeTx(encoded)
+			if err != nil {
+				return nil, err
+			}
+			reorg.DroppedTxs = append(reorg.DroppedTxs, *tx)
+		}
+		reorgs = append(reorgs, reorg)
+	}
+	return reorgs, rows.Err()
+}
+
+// decodeStoredTx decodes the body of a stored transaction, the bodies of the
+// pruned transactions are empty
+func decodeStoredTx(encoded string) (*types.Transaction, error) {
+	if encoded == "" {
+		return nil, ErrPrunedHistory
+	}
+	return DecodeTx(encoded)
+}
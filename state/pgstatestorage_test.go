@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -324,6 +325,213 @@ func TestForcedBatch(t *testing.T) {
 	assert.Equal(t, forcedBatch.ForcedAt.Unix(), fb.ForcedAt.Unix())
 	assert.Equal(t, forcedBatch.GlobalExitRoot, fb.GlobalExitRoot)
 }
+func TestPendingForcedBatches(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	for i := uint64(1); i <= 3; i++ {
+		err = testState.AddBlock(ctx, &state.Block{BlockNumber: i, BlockHash: common.BigToHash(big.NewInt(int64(i))), ReceivedAt: time.Now()}, dbTx)
+		require.NoError(t, err)
+	}
+	// Forced batches stored out of order are returned by number
+	for _, i := range []uint64{3, 1, 2} {
+		forcedBatch := state.ForcedBatch{
+			BlockNumber:       i,
+			ForcedBatchNumber: i,
+			Sequencer:         common.HexToAddress("0x2536C2745Ac4A584656A830f7bdCd329c94e8F30"),
+			RawTxsData:        []byte{byte(i)},
+			ForcedAt:          time.Now(),
+		}
+		require.NoError(t, testState.AddForcedBatch(ctx, &forcedBatch, dbTx))
+	}
+
+	pending, err := testState.GetPendingForcedBatches(ctx, 0, 2, dbTx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, uint64(1), pending[0].ForcedBatchNumber)
+	assert.Equal(t, []byte{1}, pending[0].RawTxsData)
+	assert.Equal(t, uint64(2), pending[1].ForcedBatchNumber)
+
+	pending, err = testState.GetPendingForcedBatches(ctx, 1, 3, dbTx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, uint64(2), pending[0].ForcedBatchNumber)
+	assert.Equal(t, uint64(3), pending[1].ForcedBatchNumber)
+
+	// Resetting the block of a forced batch removes the batches from the one
+	// that includes it
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num, forced_batch_num) VALUES (1, NULL), (2, 2), (3, NULL)")
+	require.NoError(t, err)
+	done, err := testState.ResetStep(ctx, 1, 0, dbTx)
+	require.NoError(t, err)
+	assert.True(t, done)
+	lastBatchNumber, err := testState.GetLastBatchNumber(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), lastBatchNumber)
+	pending, err = testState.GetPendingForcedBatches(ctx, 0, 3, dbTx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, uint64(1), pending[0].ForcedBatchNumber)
+}
+
+// resetState resets the state to the block in steps of a block, as the
+// synchronizer does
+func resetState(t *testing.T, ctx context.Context, blockNumber uint64, dbTx pgx.Tx) {
+	require.NoError(t, testState.StartReset(ctx, blockNumber, dbTx))
+	for done := false; !done; {
+		var err error
+		done, err = testState.ResetStep(ctx, blockNumber, 1, dbTx)
+		require.NoError(t, err)
+	}
+	require.NoError(t, testState.FinishReset(ctx, dbTx))
+}
+
+func TestResetInSteps(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	addr := common.HexToAddress("0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D")
+	for i := uint64(1); i <= 5; i++ {
+		err = testState.AddBlock(ctx, &state.Block{BlockNumber: i, BlockHash: common.BigToHash(big.NewInt(int64(i))), ReceivedAt: time.Now()}, dbTx)
+		require.NoError(t, err)
+		_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES ($1)", i)
+		require.NoError(t, err)
+		virtualBatch := state.VirtualBatch{BlockNumber: i, BatchNumber: i, Coinbase: addr, SequencerAddr: addr, SeenAt: i, L1Finality: state.L1FinalityLatest}
+		require.NoError(t, testState.AddVirtualBatch(ctx, &virtualBatch, dbTx))
+	}
+
+	// the virtual batches of the reverted blocks are removed with the marker
+	require.NoError(t, testState.StartReset(ctx, 2, dbTx))
+	lastVirtualBatchNum, err := testState.GetLastVirtualBatchNum(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), lastVirtualBatchNum)
+	lastBlock, err := testState.GetLastBlock(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), lastBlock.BlockNumber)
+
+	// the blocks are removed in chunks from the highest one
+	done, err := testState.ResetStep(ctx, 2, 2, dbTx)
+	require.NoError(t, err)
+	assert.False(t, done)
+	lastBlock, err = testState.GetLastBlock(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), lastBlock.BlockNumber)
+
+	done, err = testState.ResetStep(ctx, 2, 2, dbTx)
+	require.NoError(t, err)
+	assert.True(t, done)
+	lastBlock, err = testState.GetLastBlock(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), lastBlock.BlockNumber)
+
+	blockNumber, err := testState.GetResetInProgress(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), blockNumber)
+	require.NoError(t, testState.FinishReset(ctx, dbTx))
+	_, err = testState.GetResetInProgress(ctx, dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+}
+
+func TestResetBatchesInSteps(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	for i := uint64(1); i <= 3; i++ {
+		err = testState.AddBlock(ctx, &state.Block{BlockNumber: i, BlockHash: common.BigToHash(big.NewInt(int64(i))), ReceivedAt: time.Now()}, dbTx)
+		require.NoError(t, err)
+	}
+	forcedBatch := state.ForcedBatch{BlockNumber: 3, ForcedBatchNumber: 1, ForcedAt: time.Now()}
+	require.NoError(t, testState.AddForcedBatch(ctx, &forcedBatch, dbTx))
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num, forced_batch_num) VALUES (1, NULL), (2, NULL), (3, 1), (4, NULL), (5, NULL), (6, NULL)")
+	require.NoError(t, err)
+
+	// the marker records the batch that includes the forced batch of a
+	// reverted block
+	require.NoError(t, testState.StartReset(ctx, 2, dbTx))
+	var batchNumber *uint64
+	require.NoError(t, dbTx.QueryRow(ctx, "SELECT batch_num FROM state.reset_in_progress").Scan(&batchNumber))
+	require.NotNil(t, batchNumber)
+	assert.Equal(t, uint64(3), *batchNumber)
+	lastBatchNumber, err := testState.GetLastBatchNumber(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), lastBatchNumber)
+
+	// the batches are removed in chunks from the last one before the blocks
+	done, err := testState.ResetStep(ctx, 2, 2, dbTx)
+	require.NoError(t, err)
+	assert.False(t, done)
+	lastBatchNumber, err = testState.GetLastBatchNumber(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), lastBatchNumber)
+	lastBlock, err := testState.GetLastBlock(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), lastBlock.BlockNumber)
+
+	done, err = testState.ResetStep(ctx, 2, 2, dbTx)
+	require.NoError(t, err)
+	assert.True(t, done)
+	lastBatchNumber, err = testState.GetLastBatchNumber(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), lastBatchNumber)
+	lastBlock, err = testState.GetLastBlock(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), lastBlock.BlockNumber)
+	require.NoError(t, dbTx.QueryRow(ctx, "SELECT batch_num FROM state.reset_in_progress").Scan(&batchNumber))
+	assert.Nil(t, batchNumber)
+	require.NoError(t, testState.FinishReset(ctx, dbTx))
+}
+
+func TestLastBatchInfo(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	_, err = testState.GetLastBatchInfo(ctx, dbTx)
+	require.ErrorIs(t, err, state.ErrStateNotSynchronized)
+
+	err = testState.AddBlock(ctx, &state.Block{BlockNumber: 1, BlockHash: common.HexToHash("0x1"), ReceivedAt: time.Now()}, dbTx)
+	require.NoError(t, err)
+	forcedBatch := state.ForcedBatch{BlockNumber: 1, ForcedBatchNumber: 1, ForcedAt: time.Now()}
+	require.NoError(t, testState.AddForcedBatch(ctx, &forcedBatch, dbTx))
+	timestamp := time.Unix(100, 0)
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num, timestamp, forced_batch_num) VALUES (1, $1, NULL), (2, $1, 1), (3, $1, NULL)", timestamp)
+	require.NoError(t, err)
+
+	// The last forced batch included is stored along with each virtual batch
+	for i, lastForcedBatchNum := range []uint64{0, 1, 1} {
+		virtualBatch := state.VirtualBatch{
+			BatchNumber:        uint64(i + 1),
+			TxHash:             common.BigToHash(big.NewInt(int64(i + 1))),
+			BlockNumber:        1,
+			SeenAt:             1,
+			LastForcedBatchNum: lastForcedBatchNum,
+		}
+		require.NoError(t, testState.AddVirtualBatch(ctx, &virtualBatch, dbTx))
+	}
+	virtualBatch, err := testState.GetVirtualBatch(ctx, 2, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), virtualBatch.LastForcedBatchNum)
+
+	info, err := testState.GetLastBatchInfo(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, state.L2BatchInfo{Number: 3, L1Block: 1, Timestamp: 100, ForcedBatchNum: 1}, info)
+}
+
 func TestCleanupLockedProofs(t *testing.T) {
 	require := require.New(t)
 	assert := assert.New(t)
@@ -428,6 +636,7 @@ func TestVirtualBatch(t *testing.T) {
 		Coinbase:      addr,
 		SequencerAddr: addr,
 		TxHash:        common.HexToHash("0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f1"),
+		L1Finality:    state.L1FinalityLatest,
 	}
 	err = testState.AddVirtualBatch(ctx, &virtualBatch, dbTx)
 	require.NoError(t, err)
@@ -435,5 +644,298 @@ func TestVirtualBatch(t *testing.T) {
 	actualVirtualBatch, err := testState.GetVirtualBatch(ctx, 1, dbTx)
 	require.NoError(t, err)
 	require.Equal(t, virtualBatch, *actualVirtualBatch)
+
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES (2)")
+	require.NoError(t, err)
+	preconfirmedBatch := state.VirtualBatch{
+		BlockNumber:   1,
+		BatchNumber:   2,
+		Coinbase:      addr,
+		SequencerAddr: addr,
+		TxHash:        common.HexToHash("0x1"),
+		SeenAt:        1,
+		L1Finality:    state.L1FinalityPreconfirmed,
+	}
+	err = testState.AddVirtualBatch(ctx, &preconfirmedBatch, dbTx)
+	require.NoError(t, err)
+
+	// the preconfirmed batch keeps its level until its L1 block is synced
+	updated, err := testState.UpdateVirtualBatchesL1Finality(ctx, state.L1FinalitySafe, 1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+	actualVirtualBatch, err = testState.GetVirtualBatch(ctx, 2, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, state.L1FinalityPreconfirmed, actualVirtualBatch.L1Finality)
+	// a batch is never lowered to a previous finality level
+	updated, err = testState.UpdateVirtualBatchesL1Finality(ctx, state.L1FinalityLatest, 1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), updated)
+
+	actualVirtualBatch, err = testState.GetVirtualBatch(ctx, 1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, state.L1FinalitySafe, actualVirtualBatch.L1Finality)
 	require.NoError(t, dbTx.Commit(ctx))
 }
+
+func TestForkIDs(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	previousIntervals := testState.GetForkIDIntervals()
+	defer testState.UpdateForkIDIntervals(previousIntervals)
+
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	for _, blockNumber := range []uint64{1, 2} {
+		err = testState.AddBlock(ctx, &state.Block{BlockNumber: blockNumber, ReceivedAt: time.Now()}, dbTx)
+		require.NoError(t, err)
+	}
+
+	err = testState.AddForkID(ctx, state.ForkIDInterval{FromBatchNumber: 0, ForkId: 1, Version: "v1", BlockNumber: 1}, dbTx)
+	require.NoError(t, err)
+	err = testState.AddForkID(ctx, state.ForkIDInterval{FromBatchNumber: 100, ForkId: 2, Version: "v2", BlockNumber: 2}, dbTx)
+	require.NoError(t, err)
+
+	forkIDs, err := testState.GetForkIDs(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, []state.ForkIDInterval{
+		{FromBatchNumber: 0, ToBatchNumber: 99, ForkId: 1, Version: "v1", BlockNumber: 1},
+		{FromBatchNumber: 100, ToBatchNumber: math.MaxUint64, ForkId: 2, Version: "v2", BlockNumber: 2},
+	}, forkIDs)
+
+	require.NoError(t, testState.LoadForkIDIntervals(ctx, dbTx))
+	assert.Equal(t, uint64(1), testState.GetForkIdByBatchNumber(99))
+	assert.Equal(t, uint64(2), testState.GetForkIdByBatchNumber(100))
+
+	// the fork id activated in a reorged block is deleted with it
+	resetState(t, ctx, 1, dbTx)
+	require.NoError(t, testState.LoadForkIDIntervals(ctx, dbTx))
+	assert.Equal(t, uint64(1), testState.GetForkIdByBatchNumber(100))
+
+	// the intervals in use are kept when no fork ids are stored
+	inUse := []state.ForkIDInterval{{FromBatchNumber: 0, ToBatchNumber: math.MaxUint64, ForkId: 5}}
+	testState.UpdateForkIDIntervals(inUse)
+	resetState(t, ctx, 0, dbTx)
+	require.NoError(t, testState.LoadForkIDIntervals(ctx, dbTx))
+	assert.Equal(t, inUse, testState.GetForkIDIntervals())
+	assert.Equal(t, uint64(5), testState.GetForkIdByBatchNumber(100))
+
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
+func TestBackfillForkIDs(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	l1ForkIDs := []state.ForkIDInterval{
+		{FromBatchNumber: 0, ToBatchNumber: 99, ForkId: 1, Version: "v1", BlockNumber: 5},
+		{FromBatchNumber: 100, ToBatchNumber: 199, ForkId: 2, Version: "v2", BlockNumber: 15},
+		{FromBatchNumber: 200, ToBatchNumber: math.MaxUint64, ForkId: 3, Version: "v3", BlockNumber: 30},
+	}
+
+	// nothing is backfilled before the genesis is synced
+	require.NoError(t, testState.BackfillForkIDs(ctx, l1ForkIDs, dbTx))
+	forkIDs, err := testState.GetForkIDs(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Empty(t, forkIDs)
+
+	for _, blockNumber := range []uint64{10, 20} {
+		err = testState.AddBlock(ctx, &state.Block{BlockNumber: blockNumber, ReceivedAt: time.Now()}, dbTx)
+		require.NoError(t, err)
+	}
+
+	// the fork ids activated after the last synced block are left to the
+	// synchronizer, the rest are linked to the last block up to theirs
+	require.NoError(t, testState.BackfillForkIDs(ctx, l1ForkIDs, dbTx))
+	forkIDs, err = testState.GetForkIDs(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, []state.ForkIDInterval{
+		{FromBatchNumber: 0, ToBatchNumber: 99, ForkId: 1, Version: "v1", BlockNumber: 10},
+		{FromBatchNumber: 100, ToBatchNumber: math.MaxUint64, ForkId: 2, Version: "v2", BlockNumber: 10},
+	}, forkIDs)
+
+	// the stored fork ids are never overwritten
+	require.NoError(t, testState.BackfillForkIDs(ctx, []state.ForkIDInterval{{ForkId: 4, BlockNumber: 10}}, dbTx))
+	forkIDs, err = testState.GetForkIDs(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Len(t, forkIDs, 2)
+}
+
+func TestReorgs(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+
+	droppedTx := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	createdAt := time.Now().Truncate(time.Second)
+	reorgs := []state.Reorg{
+		{
+			Kind:             state.ReorgKindL1,
+			Depth:            2,
+			FirstBlockNumber: 10,
+			FirstBatchNumber: 5,
+			OldHash:          common.HexToHash("0x1"),
+			NewHash:          common.HexToHash("0x2"),
+			Reason:           "L1 block 10 hash changed",
+			CreatedAt:        createdAt.Add(-time.Hour),
+		},
+		{
+			Kind:             state.ReorgKindTrusted,
+			Depth:            1,
+			FirstBlockNumber: 20,
+			FirstBatchNumber: 7,
+			OldHash:          common.HexToHash("0x3"),
+			NewHash:          common.HexToHash("0x4"),
+			Reason:           "Different field StateRoot",
+			DroppedTxs:       []types.Transaction{*droppedTx},
+			CreatedAt:        createdAt,
+		},
+	}
+	for i := range reorgs {
+		require.NoError(t, testState.AddReorg(ctx, &reorgs[i], dbTx))
+	}
+	assert.Less(t, reorgs[0].ID, reorgs[1].ID)
+
+	actualReorgs, err := testState.GetReorgs(ctx, createdAt.Add(-time.Minute), dbTx)
+	require.NoError(t, err)
+	require.Len(t, actualReorgs, 1)
+	assert.Equal(t, reorgs[1].ID, actualReorgs[0].ID)
+	assert.Equal(t, state.ReorgKindTrusted, actualReorgs[0].Kind)
+	assert.Equal(t, reorgs[1].OldHash, actualReorgs[0].OldHash)
+	assert.Equal(t, reorgs[1].NewHash, actualReorgs[0].NewHash)
+	assert.Equal(t, reorgs[1].Reason, actualReorgs[0].Reason)
+	assert.True(t, createdAt.Equal(actualReorgs[0].CreatedAt))
+	require.Len(t, actualReorgs[0].DroppedTxs, 1)
+	assert.Equal(t, droppedTx.Hash(), actualReorgs[0].DroppedTxs[0].Hash())
+
+	actualReorgs, err = testState.GetReorgs(ctx, createdAt.Add(-2*time.Hour), dbTx)
+	require.NoError(t, err)
+	require.Len(t, actualReorgs, 2)
+	assert.Empty(t, actualReorgs[0].DroppedTxs)
+
+	actualReorgs, err = testState.GetReorgsAfterID(ctx, reorgs[0].ID, dbTx)
+	require.NoError(t, err)
+	require.Len(t, actualReorgs, 1)
+	assert.Equal(t, reorgs[1].ID, actualReorgs[0].ID)
+
+	lastReorgID, err := testState.GetLastReorgID(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, reorgs[1].ID, lastReorgID)
+
+	// the last processed reorg is replaced as the reorgs are processed
+	_, err = testState.GetProcessedReorgID(ctx, dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+	for _, reorg := range reorgs {
+		require.NoError(t, testState.SetProcessedReorgID(ctx, reorg.ID, dbTx))
+	}
+	processedReorgID, err := testState.GetProcessedReorgID(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, reorgs[1].ID, processedReorgID)
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
+func TestReceiptZKCounters(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	require.NoError(t, testState.AddBlock(ctx, block, dbTx))
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES (1)")
+	require.NoError(t, err)
+
+	tx := types.NewTx(&types.LegacyTx{Nonce: 0, Value: new(big.Int), GasPrice: big.NewInt(0)})
+	receipt := &types.Receipt{
+		Type:        uint8(tx.Type()),
+		PostState:   state.ZeroHash.Bytes(),
+		BlockNumber: big.NewInt(1),
+		TxHash:      tx.Hash(),
+		Status:      types.ReceiptStatusSuccessful,
+	}
+	header := &types.Header{Number: big.NewInt(1), ParentHash: state.ZeroHash, Root: state.ZeroHash, GasLimit: 10}
+	l2Block := types.NewBlock(header, []*types.Transaction{tx}, []*types.Header{}, []*types.Receipt{receipt}, &trie.StackTrie{})
+	receipt.BlockHash = l2Block.Hash()
+	require.NoError(t, pgStateStorage.AddL2Block(ctx, 1, l2Block, []*types.Receipt{receipt}, dbTx))
+
+	// the counters of the receipt are not known yet
+	_, err = pgStateStorage.GetReceiptZKCounters(ctx, tx.Hash(), dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+
+	counters := state.ZKCounters{CumulativeGasUsed: 21000, UsedKeccakHashes: 2, UsedPoseidonHashes: 3, UsedSteps: 400}
+	require.NoError(t, pgStateStorage.AddReceiptZKCounters(ctx, tx.Hash(), counters, dbTx))
+	stored, err := pgStateStorage.GetReceiptZKCounters(ctx, tx.Hash(), dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, counters, *stored)
+
+	_, err = pgStateStorage.GetReceiptZKCounters(ctx, common.HexToHash("0x1"), dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+}
+
+func TestPrunedHistory(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	storage := state.NewPostgresStorage(stateDb)
+	storage.EnablePrunedHistoryCheck()
+
+	require.NoError(t, testState.AddBlock(ctx, block, dbTx))
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES (0), (1), (2)")
+	require.NoError(t, err)
+
+	// the genesis block of batch 0 is never pruned
+	parentHash := state.ZeroHash
+	txs := make([]*types.Transaction, 0, 2)
+	for blockNumber := uint64(0); blockNumber <= 2; blockNumber++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(blockNumber), ParentHash: parentHash, Root: state.ZeroHash, GasLimit: 10}
+		var (
+			blockTxs []*types.Transaction
+			receipts []*types.Receipt
+		)
+		if blockNumber > 0 {
+			tx := types.NewTx(&types.LegacyTx{Nonce: blockNumber, Value: new(big.Int), GasPrice: big.NewInt(0)})
+			receipt := &types.Receipt{
+				Type:        uint8(tx.Type()),
+				PostState:   state.ZeroHash.Bytes(),
+				BlockNumber: header.Number,
+				TxHash:      tx.Hash(),
+				Status:      types.ReceiptStatusSuccessful,
+				Logs:        []*types.Log{{Address: common.HexToAddress("0x1"), TxHash: tx.Hash(), BlockNumber: blockNumber}},
+			}
+			blockTxs, receipts = []*types.Transaction{tx}, []*types.Receipt{receipt}
+			txs = append(txs, tx)
+		}
+		l2Block := types.NewBlock(header, blockTxs, []*types.Header{}, receipts, &trie.StackTrie{})
+		for _, receipt := range receipts {
+			receipt.BlockHash = l2Block.Hash()
+		}
+		require.NoError(t, storage.AddL2Block(ctx, blockNumber, l2Block, receipts, dbTx))
+		parentHash = l2Block.Hash()
+	}
+	require.NoError(t, storage.PruneBatches(ctx, 1, 1, dbTx))
+
+	// the receipt of a pruned tx is unavailable, the ones of unknown txs
+	// are not found
+	_, err = storage.GetTransactionReceipt(ctx, txs[0].Hash(), dbTx)
+	require.ErrorIs(t, err, state.ErrPrunedHistory)
+	_, err = storage.GetTransactionReceipt(ctx, txs[1].Hash(), dbTx)
+	require.NoError(t, err)
+	_, err = storage.GetTransactionReceipt(ctx, common.HexToHash("0x1"), dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+
+	// a range is pruned when any of its blocks is, not only the first one
+	_, err = storage.GetLogs(ctx, 0, 2, nil, nil, nil, nil, dbTx)
+	require.ErrorIs(t, err, state.ErrPrunedHistory)
+	logs, err := storage.GetLogs(ctx, 2, 2, nil, nil, nil, nil, dbTx)
+	require.NoError(t, err)
+	assert.Len(t, logs, 1)
+}
@@ -0,0 +1,221 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const getLastL2BlockNumberForLagSQL = "SELECT COALESCE(MAX(block_num), 0) FROM state.l2block"
+
+type readReplicaKey struct{}
+
+// readReplicaRead is stored in the context of the queries that can be
+// served by a read replica
+type readReplicaRead struct {
+	// scope is shared by all the queries of a request
+	scope *readReplicaScope
+	// l2BlockNumber is the highest L2 block the query is about, nil if it is
+	// not known, in that case the replica must be in sync with the primary
+	l2BlockNumber *uint64
+}
+
+// readReplicaScope pins the replica that serves the queries of a request, so
+// all of them read the same state. Once a query of the request goes to the
+// primary, the rest of them go to the primary too
+type readReplicaScope struct {
+	mu      sync.Mutex
+	picked  bool
+	replica *readReplica
+}
+
+// unpin sends the next queries of the request to the primary
+func (s *readReplicaScope) unpin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.picked = true
+	s.replica = nil
+}
+
+// WithReadReplica marks the context of a request so its read only queries run
+// without a dbTx are sent to the same read replica, in sync with the primary
+// when the first one is sent
+func WithReadReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readReplicaKey{}, readReplicaRead{scope: &readReplicaScope{}})
+}
+
+// WithReadReplicaBlock marks the context so the read only queries run without
+// a dbTx are sent to a read replica that has already synced the given L2 block.
+// The replica pinned by WithReadReplica is kept if the context has one
+func WithReadReplicaBlock(ctx context.Context, l2BlockNumber uint64) context.Context {
+	read, ok := ctx.Value(readReplicaKey{}).(readReplicaRead)
+	if !ok {
+		read.scope = &readReplicaScope{}
+	}
+	read.l2BlockNumber = &l2BlockNumber
+	return context.WithValue(ctx, readReplicaKey{}, read)
+}
+
+// readReplica is a read replica of the state database and the last L2 block
+// it had synced the last time its lag was checked
+type readReplica struct {
+	pool *pgxpool.Pool
+	// lastL2BlockNumber is the last L2 block of the replica plus one, zero
+	// while the replica has not been checked or is unreachable
+	lastL2BlockNumber uint64
+}
+
+// readReplicas routes the read only queries to the replicas that are not
+// lagging behind the primary
+type readReplicas struct {
+	replicas []*readReplica
+	maxLag   uint64
+	// primaryL2BlockNumber is the last L2 block of the primary plus one, zero
+	// while the primary has not been checked
+	primaryL2BlockNumber uint64
+	next                 uint64
+}
+
+// pick returns the replica pinned by the request if it is able to serve a
+// query about the given L2 block, nil if the query must go to the primary. The
+// first query of a request pins one of the replicas able to serve it
+func (r *readReplicas) pick(read readReplicaRead) *readReplica {
+	read.scope.mu.Lock()
+	defer read.scope.mu.Unlock()
+	if read.scope.picked {
+		if read.scope.replica != nil && !r.serves(read.scope.replica, read) {
+			read.scope.replica = nil
+		}
+		return read.scope.replica
+	}
+	read.scope.picked = true
+	for i := 0; i < len(r.replicas); i++ {
+		replica := r.replicas[int(atomic.AddUint64(&r.next, 1)%uint64(len(r.replicas)))]
+		if r.serves(replica, read) {
+			read.scope.replica = replica
+			return replica
+		}
+	}
+	return nil
+}
+
+// serves checks if the replica is able to serve a query about the given L2
+// block
+func (r *readReplicas) serves(replica *readReplica, read readReplicaRead) bool {
+	primary := atomic.LoadUint64(&r.primaryL2BlockNumber)
+	if primary == 0 {
+		return false
+	}
+	last := atomic.LoadUint64(&replica.lastL2BlockNumber)
+	if last == 0 || last < primary && primary-last > r.maxLag {
+		return false
+	}
+	if read.l2BlockNumber == nil {
+		return last >= primary
+	}
+	return *read.l2BlockNumber < last
+}
+
+// readReplicaQuerier runs the queries of a request on its pinned replica. A
+// row not found on the replica may have been added to the primary after the
+// last lag check, so it is read from the primary, which serves the rest of
+// the request
+type readReplicaQuerier struct {
+	replica execQuerier
+	primary execQuerier
+	scope   *readReplicaScope
+}
+
+// Exec runs the statement on the replica
+func (q *readReplicaQuerier) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	return q.replica.Exec(ctx, sql, arguments...)
+}
+
+// Query runs the query on the replica
+func (q *readReplicaQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return q.replica.Query(ctx, sql, args...)
+}
+
+// QueryRow runs the query on the replica, and on the primary if the replica
+// doesn't have the row
+func (q *readReplicaQuerier) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return &readReplicaRow{
+		row: q.replica.QueryRow(ctx, sql, args...),
+		fallback: func() pgx.Row {
+			q.scope.unpin()
+			return q.primary.QueryRow(ctx, sql, args...)
+		},
+	}
+}
+
+type readReplicaRow struct {
+	row      pgx.Row
+	fallback func() pgx.Row
+}
+
+// Scan reads the row of the replica, or the row of the primary if the replica
+// doesn't have it
+func (r *readReplicaRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.fallback().Scan(dest...)
+	}
+	return err
+}
+
+// NewPostgresStorageWithReadReplicas creates a new StateDB that sends the read
+// only queries of the contexts marked with WithReadReplica or
+// WithReadReplicaBlock to the given replicas. A replica more than maxLag L2
+// blocks behind the primary is not used until it catches up
+func NewPostgresStorageWithReadReplicas(db *pgxpool.Pool, replicas []*pgxpool.Pool, maxLag uint64) *PostgresStorage {
+	p := NewPostgresStorage(db)
+	p.readReplicas = &readReplicas{maxLag: maxLag}
+	for _, replica := range replicas {
+		p.readReplicas.replicas = append(p.readReplicas.replicas, &readReplica{pool: replica})
+	}
+	return p
+}
+
+// MonitorReadReplicas checks the lag of the read replicas periodically until
+// the context is cancelled
+func (p *PostgresStorage) MonitorReadReplicas(ctx context.Context, interval time.Duration) {
+	if p.readReplicas == nil {
+		return
+	}
+	for {
+		p.checkReadReplicas(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (p *PostgresStorage) checkReadReplicas(ctx context.Context) {
+	var primary uint64
+	if err := p.Pool.QueryRow(ctx, getLastL2BlockNumberForLagSQL).Scan(&primary); err != nil {
+		log.Errorf("error getting the last L2 block of the primary: %v", err)
+		return
+	}
+	for i, replica := range p.readReplicas.replicas {
+		var last uint64
+		if err := replica.pool.QueryRow(ctx, getLastL2BlockNumberForLagSQL).Scan(&last); err != nil {
+			log.Warnf("error getting the last L2 block of the read replica %d, it won't be used: %v", i, err)
+			atomic.StoreUint64(&replica.lastL2BlockNumber, 0)
+			continue
+		}
+		if last < primary {
+			log.Debugf("read replica %d is %d L2 blocks behind the primary", i, primary-last)
+		}
+		atomic.StoreUint64(&replica.lastL2BlockNumber, last+1)
+	}
+	atomic.StoreUint64(&p.readReplicas.primaryL2BlockNumber, primary+1)
+}
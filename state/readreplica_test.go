@@ -0,0 +1,154 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReplicasPick(t *testing.T) {
+	block := func(n uint64) *uint64 { return &n }
+	testCases := []struct {
+		name     string
+		primary  uint64
+		replica  uint64
+		read     readReplicaRead
+		expected bool
+	}{
+		{name: "primary not checked", primary: 0, replica: 11, read: readReplicaRead{scope: &readReplicaScope{}}, expected: false},
+		{name: "replica not checked", primary: 11, replica: 0, read: readReplicaRead{scope: &readReplicaScope{}}, expected: false},
+		{name: "replica in sync", primary: 11, replica: 11, read: readReplicaRead{scope: &readReplicaScope{}}, expected: true},
+		{name: "replica behind and unknown block", primary: 11, replica: 9, read: readReplicaRead{scope: &readReplicaScope{}}, expected: false},
+		{name: "replica behind and old block", primary: 11, replica: 9, read: readReplicaRead{scope: &readReplicaScope{}, l2BlockNumber: block(8)}, expected: true},
+		{name: "replica behind and recent block", primary: 11, replica: 9, read: readReplicaRead{scope: &readReplicaScope{}, l2BlockNumber: block(9)}, expected: false},
+		{name: "replica lagging too much", primary: 20, replica: 9, read: readReplicaRead{scope: &readReplicaScope{}, l2BlockNumber: block(1)}, expected: false},
+		{name: "replica ahead of the primary", primary: 11, replica: 12, read: readReplicaRead{scope: &readReplicaScope{}}, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &readReplicas{
+				replicas:             []*readReplica{{lastL2BlockNumber: tc.replica}},
+				maxLag:               5,
+				primaryL2BlockNumber: tc.primary,
+			}
+			assert.Equal(t, tc.expected, r.pick(tc.read) != nil)
+		})
+	}
+}
+
+func TestReadReplicasPickPinned(t *testing.T) {
+	block := func(n uint64) *uint64 { return &n }
+	r := &readReplicas{
+		replicas:             []*readReplica{{lastL2BlockNumber: 11}, {lastL2BlockNumber: 11}, {lastL2BlockNumber: 9}},
+		maxLag:               5,
+		primaryL2BlockNumber: 11,
+	}
+
+	scope := &readReplicaScope{}
+	replica := r.pick(readReplicaRead{scope: scope})
+	require.NotNil(t, replica)
+	for i := 0; i < len(r.replicas); i++ {
+		assert.Same(t, replica, r.pick(readReplicaRead{scope: scope}))
+		assert.Same(t, replica, r.pick(readReplicaRead{scope: scope, l2BlockNumber: block(5)}))
+	}
+
+	// the request stays on the primary once the pinned replica can't serve it
+	replica.lastL2BlockNumber = 9
+	assert.Nil(t, r.pick(readReplicaRead{scope: scope}))
+	replica.lastL2BlockNumber = 11
+	assert.Nil(t, r.pick(readReplicaRead{scope: scope}))
+
+	// a request served by the primary first is not moved to a replica
+	scope = &readReplicaScope{}
+	r.primaryL2BlockNumber = 0
+	assert.Nil(t, r.pick(readReplicaRead{scope: scope}))
+	r.primaryL2BlockNumber = 11
+	assert.Nil(t, r.pick(readReplicaRead{scope: scope}))
+}
+
+func TestWithReadReplicaBlockKeepsScope(t *testing.T) {
+	ctx := WithReadReplica(context.Background())
+	read := ctx.Value(readReplicaKey{}).(readReplicaRead)
+	blockRead := WithReadReplicaBlock(ctx, 5).Value(readReplicaKey{}).(readReplicaRead)
+	assert.Same(t, read.scope, blockRead.scope)
+	assert.Equal(t, uint64(5), *blockRead.l2BlockNumber)
+	assert.Nil(t, read.l2BlockNumber)
+
+	blockRead = WithReadReplicaBlock(context.Background(), 5).Value(readReplicaKey{}).(readReplicaRead)
+	assert.NotNil(t, blockRead.scope)
+}
+
+func TestGetExecQuerierReadReplicas(t *testing.T) {
+	p := NewPostgresStorageWithReadReplicas(nil, nil, 5)
+	p.readReplicas.replicas = []*readReplica{{lastL2BlockNumber: 11}}
+	p.readReplicas.primaryL2BlockNumber = 11
+
+	assert.Equal(t, p, p.getExecQuerier(context.Background(), nil))
+	q, ok := p.getExecQuerier(WithReadReplica(context.Background()), nil).(*readReplicaQuerier)
+	require.True(t, ok)
+	assert.Equal(t, p.readReplicas.replicas[0].pool, q.replica)
+	assert.Equal(t, p, q.primary)
+	assert.Equal(t, p, p.getExecQuerier(WithReadReplicaBlock(context.Background(), 11), nil))
+}
+
+type rowQuerierMock struct {
+	execQuerier
+	err     error
+	queries int
+}
+
+func (q *rowQuerierMock) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	q.queries++
+	return rowMock{err: q.err}
+}
+
+type rowMock struct {
+	err error
+}
+
+func (r rowMock) Scan(dest ...interface{}) error {
+	return r.err
+}
+
+func TestReadReplicaQuerierFallback(t *testing.T) {
+	ctx := context.Background()
+	scanErr := errors.New("scan error")
+	testCases := []struct {
+		name             string
+		replicaErr       error
+		primaryErr       error
+		expectedErr      error
+		expectedPrimary  int
+		expectedUnpinned bool
+	}{
+		{name: "found on the replica"},
+		{name: "replica error", replicaErr: scanErr, expectedErr: scanErr},
+		{name: "found on the primary", replicaErr: pgx.ErrNoRows, expectedPrimary: 1, expectedUnpinned: true},
+		{name: "not found on the primary", replicaErr: pgx.ErrNoRows, primaryErr: pgx.ErrNoRows, expectedErr: pgx.ErrNoRows, expectedPrimary: 1, expectedUnpinned: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			replica := &rowQuerierMock{err: tc.replicaErr}
+			primary := &rowQuerierMock{err: tc.primaryErr}
+			scope := &readReplicaScope{picked: true, replica: &readReplica{}}
+			q := &readReplicaQuerier{replica: replica, primary: primary, scope: scope}
+
+			var n uint64
+			err := q.QueryRow(ctx, "SELECT 1").Scan(&n)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, 1, replica.queries)
+			assert.Equal(t, tc.expectedPrimary, primary.queries)
+			assert.Equal(t, tc.expectedUnpinned, scope.replica == nil)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package state
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReorgKind is the kind of a reorg of the state
+type ReorgKind string
+
+const (
+	// ReorgKindL1 is a reorg of the L1 blocks synced
+	ReorgKindL1 ReorgKind = "l1"
+	// ReorgKindTrusted is a reorg of the trusted batches received from the
+	// trusted sequencer
+	ReorgKindTrusted ReorgKind = "trusted"
+	// ReorgKindPreconfirmation is a reorg of the L1 blocks that only reverts
+	// batches taken from the preconfirmations
+	ReorgKindPreconfirmation ReorgKind = "preconfirmation"
+)
+
+// Reorg is a reorg of the state recorded in the reorg history
+type Reorg struct {
+	ID   uint64
+	Kind ReorgKind
+	// Depth is the number of L1 blocks reverted for L1 and preconfirmation
+	// reorgs, and the number of batches reverted for trusted reorgs
+	Depth uint64
+	// FirstBlockNumber is the first block reverted, an L1 block for L1 and
+	// preconfirmation reorgs and an L2 block for trusted reorgs
+	FirstBlockNumber uint64
+	// FirstBatchNumber is the first batch reverted
+	FirstBatchNumber uint64
+	// OldHash and NewHash are the hashes of the first block reverted before
+	// and after the reorg for L1 and preconfirmation reorgs, and the state
+	// roots of the first batch reverted for trusted reorgs
+	OldHash common.Hash
+	NewHash common.Hash
+	Reason  string
+	// DroppedTxs are the L2 txs removed from the state by the reorg
+	DroppedTxs []types.Transaction
+	CreatedAt  time.Time
+}
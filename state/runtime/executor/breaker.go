@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"sync"
+	"time"
+)
+
+// breakerState is the state of a circuit breaker
+type breakerState int
+
+const (
+	// breakerClosed lets all the requests through
+	breakerClosed breakerState = iota
+	// breakerOpen rejects all the requests until the cooldown expires
+	breakerOpen
+	// breakerHalfOpen lets a single probe request through to check if the executor recovered
+	breakerHalfOpen
+)
+
+// String returns the name of the breaker state
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// circuitBreaker ejects an executor after a number of consecutive failures,
+// letting a probe request through once the cooldown expires
+type circuitBreaker struct {
+	maxFailures uint32
+	cooldown    time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures uint32
+	openedAt time.Time
+	probing  bool
+
+	now func() time.Time
+}
+
+func newCircuitBreaker(maxFailures uint32, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		maxFailures: maxFailures,
+		cooldown:    cooldown,
+		state:       breakerClosed,
+		now:         time.Now,
+	}
+}
+
+// available checks if a request would be let through, without reserving it
+func (b *circuitBreaker) available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		return b.now().Sub(b.openedAt) >= b.cooldown
+	case breakerHalfOpen:
+		return !b.probing
+	}
+	return true
+}
+
+// allow checks if a request can be sent, when the cooldown of an open breaker
+// expired the request is let through as the probe of the half-open state
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// success records a successful request, closing the breaker
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failure records a failed request, opening the breaker when the max number
+// of consecutive failures is reached or the half-open probe failed
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.state == breakerHalfOpen || b.failures >= b.maxFailures {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// release gives back the probe of a half-open breaker without recording a result
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// getState returns the current state of the breaker
+func (b *circuitBreaker) getState() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,265 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultHealthCheckInterval    = 5 * time.Second
+	defaultMaxConsecutiveFailures = 5
+	defaultEjectionCooldown       = 30 * time.Second
+	healthCheckTimeout            = 5 * time.Second
+)
+
+var (
+	// ErrNoExecutorAvailable is returned when all the executors are unhealthy or ejected
+	ErrNoExecutorAvailable = status.Error(codes.Unavailable, "no executor available")
+	// ErrNoExecutorURI is returned when the config has no executor address
+	ErrNoExecutorURI = errors.New("no executor URI configured")
+)
+
+// executorBackend is a connection to one of the executors of the pool
+type executorBackend struct {
+	uri     string
+	conn    *grpc.ClientConn
+	client  pb.ExecutorServiceClient
+	health  healthpb.HealthClient
+	breaker *circuitBreaker
+
+	inFlight int64
+	healthy  int32
+}
+
+func (b *executorBackend) getInFlight() int64 {
+	return atomic.LoadInt64(&b.inFlight)
+}
+
+func (b *executorBackend) isHealthy() bool {
+	return atomic.LoadInt32(&b.healthy) == 1
+}
+
+func (b *executorBackend) setHealthy(healthy bool) {
+	var value int32
+	if healthy {
+		value = 1
+	}
+	atomic.StoreInt32(&b.healthy, value)
+}
+
+// processBatch sends the request to the executor recording the result in its circuit breaker
+func (b *executorBackend) processBatch(ctx context.Context, in *pb.ProcessBatchRequest, opts ...grpc.CallOption) (*pb.ProcessBatchResponse, error) {
+	atomic.AddInt64(&b.inFlight, 1)
+	defer atomic.AddInt64(&b.inFlight, -1)
+
+	res, err := b.client.ProcessBatch(ctx, in, opts...)
+	switch {
+	case err == nil || !isExecutorFailure(err):
+		b.breaker.success()
+	case ctx.Err() != nil:
+		// the request was canceled by the caller, so it says nothing about the executor
+		b.breaker.release()
+	default:
+		b.breaker.failure()
+	}
+	return res, err
+}
+
+// checkHealth checks the gRPC health service of the executor, the executors
+// not implementing the health service are considered healthy
+func (b *executorBackend) checkHealth(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	res, err := b.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return true
+	} else if err != nil {
+		return false
+	}
+	return res.Status == healthpb.HealthCheckResponse_SERVING
+}
+
+// ClientPool is an executor client that balances the requests between several
+// executors by their number of in-flight requests. The executors are ejected
+// while their gRPC health service is not serving or their circuit breaker is
+// open, and the idempotent requests are retried on another executor
+type ClientPool struct {
+	cfg      Config
+	backends []*executorBackend
+	next     uint32
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewClientPool connects to the configured executors and starts checking their health
+func NewClientPool(ctx context.Context, cfg Config) (*ClientPool, error) {
+	uris := cfg.URIs
+	if len(uris) == 0 && cfg.URI != "" {
+		uris = []string{cfg.URI}
+	}
+	if len(uris) == 0 {
+		return nil, ErrNoExecutorURI
+	}
+	if cfg.HealthCheckInterval.Duration <= 0 {
+		cfg.HealthCheckInterval.Duration = defaultHealthCheckInterval
+	}
+	if cfg.MaxConsecutiveFailures == 0 {
+		cfg.MaxConsecutiveFailures = defaultMaxConsecutiveFailures
+	}
+	if cfg.EjectionCooldown.Duration <= 0 {
+		cfg.EjectionCooldown.Duration = defaultEjectionCooldown
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)),
+	}
+	p := &ClientPool{cfg: cfg}
+	for _, uri := range uris {
+		log.Infof("connecting to executor: %v", uri)
+		conn, err := grpc.DialContext(ctx, uri, opts...)
+		if err != nil {
+			_ = p.closeConns()
+			return nil, fmt.Errorf("failed to connect to executor %v: %w", uri, err)
+		}
+		p.backends = append(p.backends, &executorBackend{
+			uri:     uri,
+			conn:    conn,
+			client:  pb.NewExecutorServiceClient(conn),
+			health:  healthpb.NewHealthClient(conn),
+			breaker: newCircuitBreaker(cfg.MaxConsecutiveFailures, cfg.EjectionCooldown.Duration),
+			healthy: 1,
+		})
+	}
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.wg.Add(1)
+	go p.healthCheckLoop(ctx)
+	return p, nil
+}
+
+// ProcessBatch sends the request to the executor with less in-flight requests,
+// the requests not updating the merkle tree, like the ones of eth_call and
+// eth_estimateGas, are retried on another executor when the executor fails
+func (p *ClientPool) ProcessBatch(ctx context.Context, in *pb.ProcessBatchRequest, opts ...grpc.CallOption) (*pb.ProcessBatchResponse, error) {
+	attempts := 1
+	if in.UpdateMerkleTree == 0 {
+		attempts += int(p.cfg.MaxRetries)
+	}
+
+	tried := make(map[*executorBackend]bool, len(p.backends))
+	err := ErrNoExecutorAvailable
+	for i := 0; i < attempts; i++ {
+		b := p.pick(tried)
+		if b == nil {
+			break
+		}
+		tried[b] = true
+
+		var res *pb.ProcessBatchResponse
+		res, err = b.processBatch(ctx, in, opts...)
+		if err == nil || !isExecutorFailure(err) || ctx.Err() != nil {
+			return res, err
+		}
+		log.Warnf("executor %v failed to process batch, err: %v", b.uri, err)
+	}
+	return nil, err
+}
+
+// Close stops the health checks and closes the connections to the executors
+func (p *ClientPool) Close() error {
+	p.cancel()
+	p.wg.Wait()
+	return p.closeConns()
+}
+
+func (p *ClientPool) closeConns() error {
+	var result error
+	for _, b := range p.backends {
+		if err := b.conn.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// pick returns the available executor not tried yet with less in-flight
+// requests, the ties are broken in a round robin way
+func (p *ClientPool) pick(tried map[*executorBackend]bool) *executorBackend {
+	for {
+		var selected *executorBackend
+		start := int(atomic.AddUint32(&p.next, 1))
+		for i := range p.backends {
+			b := p.backends[(start+i)%len(p.backends)]
+			if tried[b] || !b.isHealthy() || !b.breaker.available() {
+				continue
+			}
+			if selected == nil || b.getInFlight() < selected.getInFlight() {
+				selected = b
+			}
+		}
+		if selected == nil {
+			return nil
+		}
+		if selected.breaker.allow() {
+			return selected
+		}
+		// another request took the probe of the half-open breaker
+		tried[selected] = true
+	}
+}
+
+func (p *ClientPool) healthCheckLoop(ctx context.Context) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.cfg.HealthCheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		p.checkHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *ClientPool) checkHealth(ctx context.Context) {
+	for _, b := range p.backends {
+		healthy := b.checkHealth(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if healthy != b.isHealthy() {
+			if healthy {
+				log.Infof("executor %v is healthy again", b.uri)
+			} else {
+				log.Warnf("executor %v is unhealthy, ejecting it", b.uri)
+			}
+		}
+		b.setHealthy(healthy)
+	}
+}
+
+// isExecutorFailure checks if the error was caused by the executor being
+// unreachable or overloaded rather than by the request
+func isExecutorFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
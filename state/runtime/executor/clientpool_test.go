@@ -0,0 +1,205 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type executorServerMock struct {
+	pb.UnimplementedExecutorServiceServer
+
+	uri      string
+	health   *health.Server
+	requests int64
+	err      error
+	block    chan struct{}
+}
+
+func (s *executorServerMock) ProcessBatch(ctx context.Context, in *pb.ProcessBatchRequest) (*pb.ProcessBatchResponse, error) {
+	atomic.AddInt64(&s.requests, 1)
+	if s.block != nil {
+		<-s.block
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &pb.ProcessBatchResponse{}, nil
+}
+
+func (s *executorServerMock) getRequests() int64 {
+	return atomic.LoadInt64(&s.requests)
+}
+
+func newExecutorServerMock(t *testing.T, err error) *executorServerMock {
+	lis, listenErr := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, listenErr)
+
+	s := &executorServerMock{uri: lis.Addr().String(), health: health.NewServer(), err: err}
+	server := grpc.NewServer()
+	pb.RegisterExecutorServiceServer(server, s)
+	healthpb.RegisterHealthServer(server, s.health)
+	go server.Serve(lis) //nolint:errcheck
+	t.Cleanup(server.Stop)
+	return s
+}
+
+func newTestClientPool(t *testing.T, cfg Config, servers ...*executorServerMock) *ClientPool {
+	for _, s := range servers {
+		cfg.URIs = append(cfg.URIs, s.uri)
+	}
+	p, err := NewClientPool(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func TestClientPoolBalancesByInFlight(t *testing.T) {
+	servers := []*executorServerMock{newExecutorServerMock(t, nil), newExecutorServerMock(t, nil)}
+	for _, s := range servers {
+		s.block = make(chan struct{})
+	}
+	p := newTestClientPool(t, Config{}, servers...)
+
+	// keep a request in-flight on one of the executors
+	done := make(chan error)
+	go func() {
+		_, err := p.ProcessBatch(context.Background(), &pb.ProcessBatchRequest{})
+		done <- err
+	}()
+	var busy, idle int
+	require.Eventually(t, func() bool {
+		for i, b := range p.backends {
+			if b.getInFlight() == 1 {
+				busy, idle = i, 1-i
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+	close(servers[idle].block)
+
+	for i := 0; i < 5; i++ {
+		_, err := p.ProcessBatch(context.Background(), &pb.ProcessBatchRequest{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(1), servers[busy].getRequests())
+	assert.Equal(t, int64(5), servers[idle].getRequests())
+
+	close(servers[busy].block)
+	require.NoError(t, <-done)
+}
+
+func TestClientPoolRetriesIdempotentRequests(t *testing.T) {
+	failing := newExecutorServerMock(t, status.Error(codes.Unavailable, "unavailable"))
+	working := newExecutorServerMock(t, nil)
+	p := newTestClientPool(t, Config{MaxRetries: 1, MaxConsecutiveFailures: 100}, failing, working)
+
+	for i := 0; i < 4; i++ {
+		_, err := p.ProcessBatch(context.Background(), &pb.ProcessBatchRequest{UpdateMerkleTree: 0})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(4), working.getRequests())
+	assert.Greater(t, failing.getRequests(), int64(0))
+
+	failed := 0
+	for i := 0; i < 4; i++ {
+		_, err := p.ProcessBatch(context.Background(), &pb.ProcessBatchRequest{UpdateMerkleTree: 1})
+		if err != nil {
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+			failed++
+		}
+	}
+	assert.Greater(t, failed, 0)
+}
+
+func TestClientPoolDoesNotRetryRequestErrors(t *testing.T) {
+	invalid := newExecutorServerMock(t, status.Error(codes.InvalidArgument, "invalid"))
+	p := newTestClientPool(t, Config{MaxRetries: 3}, invalid, invalid)
+
+	_, err := p.ProcessBatch(context.Background(), &pb.ProcessBatchRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, int64(1), invalid.getRequests())
+	assert.Equal(t, breakerClosed, p.backends[0].breaker.getState())
+}
+
+func TestClientPoolEjectsFailingExecutor(t *testing.T) {
+	failing := newExecutorServerMock(t, status.Error(codes.ResourceExhausted, "busy"))
+	p := newTestClientPool(t, Config{MaxConsecutiveFailures: 2, EjectionCooldown: types.NewDuration(time.Hour)}, failing)
+
+	for i := 0; i < 2; i++ {
+		_, err := p.ProcessBatch(context.Background(), &pb.ProcessBatchRequest{})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	}
+	assert.Equal(t, breakerOpen, p.backends[0].breaker.getState())
+
+	_, err := p.ProcessBatch(context.Background(), &pb.ProcessBatchRequest{})
+	assert.ErrorIs(t, err, ErrNoExecutorAvailable)
+	assert.Equal(t, int64(2), failing.getRequests())
+}
+
+func TestClientPoolEjectsUnhealthyExecutor(t *testing.T) {
+	unhealthy := newExecutorServerMock(t, nil)
+	healthy := newExecutorServerMock(t, nil)
+	p := newTestClientPool(t, Config{HealthCheckInterval: types.NewDuration(10 * time.Millisecond)}, unhealthy, healthy)
+
+	unhealthy.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	require.Eventually(t, func() bool { return !p.backends[0].isHealthy() }, time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		_, err := p.ProcessBatch(context.Background(), &pb.ProcessBatchRequest{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(0), unhealthy.getRequests())
+	assert.Equal(t, int64(4), healthy.getRequests())
+
+	unhealthy.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	require.Eventually(t, func() bool { return p.backends[0].isHealthy() }, time.Second, 10*time.Millisecond)
+}
+
+func TestNewClientPoolWithoutURI(t *testing.T) {
+	_, err := NewClientPool(context.Background(), Config{})
+	assert.ErrorIs(t, err, ErrNoExecutorURI)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.failure()
+	assert.Equal(t, breakerClosed, b.getState())
+	b.success()
+	b.failure()
+	assert.Equal(t, breakerClosed, b.getState())
+	b.failure()
+	assert.Equal(t, breakerOpen, b.getState())
+	assert.False(t, b.allow())
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.available())
+	assert.True(t, b.allow())
+	assert.Equal(t, breakerHalfOpen, b.getState())
+	assert.False(t, b.allow(), "only one probe is let through")
+
+	b.failure()
+	assert.Equal(t, breakerOpen, b.getState())
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow())
+	b.success()
+	assert.Equal(t, breakerClosed, b.getState())
+	assert.True(t, b.allow())
+}
@@ -1,6 +1,25 @@
 package executor
 
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
 // Config represents the configuration of the executor server
 type Config struct {
+	// URI is the address of the executor, it is used when URIs is empty
 	URI string `mapstructure:"URI"`
+	// URIs are the addresses of the executors the requests are balanced between
+	URIs []string `mapstructure:"URIs"`
+	// HealthCheckInterval is the time between the checks of the gRPC health service of the executors
+	HealthCheckInterval types.Duration `mapstructure:"HealthCheckInterval"`
+	// MaxConsecutiveFailures is the number of consecutive failed requests that ejects an executor
+	MaxConsecutiveFailures uint32 `mapstructure:"MaxConsecutiveFailures"`
+	// EjectionCooldown is the time an ejected executor waits before receiving a probe request
+	EjectionCooldown types.Duration `mapstructure:"EjectionCooldown"`
+	// MaxRetries is the number of times an idempotent request is retried on another executor
+	MaxRetries uint32 `mapstructure:"MaxRetries"`
+	// Local processes the batches in-process with the pure Go executor keeping
+	// the state tree in memory instead of using the executor and the StateDB
+	// servers. The ZK counters are not computed, so it is meant for testing
+	// and for nodes that don't sequence. The state tree is not persisted, so
+	// the node only starts with an empty state db and syncs from genesis
+	Local bool `mapstructure:"Local"`
 }
@@ -0,0 +1,57 @@
+package local
+
+import (
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// intrinsicErrorToRomError converts the errors of the geth pre-execution
+// checks into the ROM intrinsic errors
+func intrinsicErrorToRomError(err error) pb.RomError {
+	switch {
+	case errors.Is(err, core.ErrNonceTooLow), errors.Is(err, core.ErrNonceTooHigh), errors.Is(err, core.ErrNonceMax):
+		return pb.RomError_ROM_ERROR_INTRINSIC_INVALID_NONCE
+	case errors.Is(err, core.ErrInsufficientFunds), errors.Is(err, core.ErrInsufficientFundsForTransfer):
+		return pb.RomError_ROM_ERROR_INTRINSIC_INVALID_BALANCE
+	case errors.Is(err, core.ErrSenderNoEOA):
+		return pb.RomError_ROM_ERROR_INTRINSIC_INVALID_SENDER_CODE
+	case errors.Is(err, core.ErrGasUintOverflow):
+		return pb.RomError_ROM_ERROR_INTRINSIC_TX_GAS_OVERFLOW
+	}
+	return pb.RomError_ROM_ERROR_INTRINSIC_INVALID_GAS_LIMIT
+}
+
+// vmErrorToRomError converts the errors of the geth EVM into the ROM errors
+func vmErrorToRomError(err error) pb.RomError {
+	var (
+		stackOverflow  *vm.ErrStackOverflow
+		stackUnderflow *vm.ErrStackUnderflow
+		invalidOpCode  *vm.ErrInvalidOpCode
+	)
+	switch {
+	case err == nil:
+		return pb.RomError_ROM_ERROR_NO_ERROR
+	case errors.Is(err, vm.ErrOutOfGas), errors.Is(err, vm.ErrCodeStoreOutOfGas), errors.Is(err, vm.ErrGasUintOverflow):
+		return pb.RomError_ROM_ERROR_OUT_OF_GAS
+	case errors.As(err, &stackOverflow):
+		return pb.RomError_ROM_ERROR_STACK_OVERFLOW
+	case errors.As(err, &stackUnderflow):
+		return pb.RomError_ROM_ERROR_STACK_UNDERFLOW
+	case errors.Is(err, vm.ErrMaxCodeSizeExceeded):
+		return pb.RomError_ROM_ERROR_MAX_CODE_SIZE_EXCEEDED
+	case errors.Is(err, vm.ErrContractAddressCollision):
+		return pb.RomError_ROM_ERROR_CONTRACT_ADDRESS_COLLISION
+	case errors.Is(err, vm.ErrInvalidJump):
+		return pb.RomError_ROM_ERROR_INVALID_JUMP
+	case errors.As(err, &invalidOpCode):
+		return pb.RomError_ROM_ERROR_INVALID_OPCODE
+	case errors.Is(err, vm.ErrWriteProtection):
+		return pb.RomError_ROM_ERROR_INVALID_STATIC
+	case errors.Is(err, vm.ErrInvalidCode):
+		return pb.RomError_ROM_ERROR_INVALID_BYTECODE_STARTS_EF
+	}
+	return pb.RomError_ROM_ERROR_EXECUTION_REVERTED
+}
@@ -0,0 +1,284 @@
+package local
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"google.golang.org/grpc"
+)
+
+const (
+	// txGasLimit is the max gas limit of a transaction
+	txGasLimit = 30000000
+
+	// lastTxStoragePos is the slot of the system contract storing the number of transactions
+	lastTxStoragePos = 0
+	// stateRootStoragePos is the slot of the system contract mapping each
+	// transaction number to the state root after it
+	stateRootStoragePos = 1
+	// globalExitRootStoragePos is the slot of the L2 global exit root manager
+	// mapping each global exit root to the timestamp it was set
+	globalExitRootStoragePos = 0
+	// localExitRootStoragePos is the slot of the L2 global exit root manager storing the local exit root
+	localExitRootStoragePos = 1
+)
+
+var (
+	// addressSystem is the address of the system contract keeping the state
+	// root after each transaction
+	addressSystem = common.HexToAddress("0x000000000000000000000000000000005ca1ab1e")
+	// addressGlobalExitRootManager is the address of the L2 global exit root manager
+	addressGlobalExitRootManager = common.HexToAddress("0xa40D5f56745a118D0906a34E69aEC8C0Db1cB8fA")
+)
+
+// Executor is an in-process implementation of pb.ExecutorServiceClient that
+// processes the batches with the geth EVM, keeping the state in the same
+// sparse merkle tree as the zkEVM ROM so the state roots match the ones of the
+// executor. The ZK counters are not computed and the execution traces are
+// not generated
+type Executor struct {
+	tree           *merkletree.StateTree
+	defaultChainID uint64
+}
+
+// NewExecutor creates a new Executor keeping the state in the given tree.
+// Besides the chain ID of each request, the transactions signed for the
+// default chain ID are accepted, 0 disables it
+func NewExecutor(tree *merkletree.StateTree, defaultChainID uint64) *Executor {
+	return &Executor{tree: tree, defaultChainID: defaultChainID}
+}
+
+// ProcessBatch processes the transactions of the batch on top of the old state root
+func (e *Executor) ProcessBatch(ctx context.Context, in *pb.ProcessBatchRequest, opts ...grpc.CallOption) (*pb.ProcessBatchResponse, error) {
+	sdb := newStateDB(ctx, e.tree, common.BytesToHash(in.OldStateRoot))
+	chainConfig := newChainConfig(in.ChainId)
+	signer := types.NewEIP155Signer(chainConfig.ChainID)
+	coinbase := common.HexToAddress(in.Coinbase)
+	globalExitRoot := common.BytesToHash(in.GlobalExitRoot)
+
+	if err := setGlobalExitRoot(sdb, globalExitRoot, in.EthTimestamp); err != nil {
+		return nil, err
+	}
+
+	res := &pb.ProcessBatchResponse{
+		NewAccInputHash:    accInputHash(common.BytesToHash(in.OldAccInputHash), in.BatchL2Data, globalExitRoot, in.EthTimestamp, coinbase).Bytes(),
+		NewBatchNum:        in.OldBatchNum + 1,
+		Error:              pb.ExecutorError_EXECUTOR_ERROR_NO_ERROR,
+		ReadWriteAddresses: make(map[string]*pb.InfoReadWrite),
+	}
+
+	txs, err := decodeTxs(in.BatchL2Data)
+	if err != nil {
+		// the transactions of a batch that can't be decoded are not processed
+		log.Debugf("error decoding batch L2 data: %v", err)
+		txs = nil
+	}
+
+	for i := range txs {
+		tx := &txs[i]
+		txSigner := signer
+		if e.defaultChainID != 0 && tx.ChainId().Uint64() == e.defaultChainID {
+			txSigner = types.NewEIP155Signer(new(big.Int).SetUint64(e.defaultChainID))
+		}
+		txRes, err := processTx(sdb, chainConfig, txSigner, coinbase, in, tx)
+		if err != nil {
+			return nil, err
+		}
+		for j, l := range txRes.Logs {
+			l.TxIndex = uint32(i)
+			l.Index = uint32(j)
+		}
+		res.CumulativeGasUsed += txRes.GasUsed
+		res.Responses = append(res.Responses, txRes)
+	}
+
+	res.NewStateRoot = sdb.root.Bytes()
+	res.NewLocalExitRoot = sdb.GetState(addressGlobalExitRootManager, common.BigToHash(big.NewInt(localExitRootStoragePos))).Bytes()
+	if sdb.err != nil {
+		return nil, sdb.err
+	}
+	for addr, acc := range sdb.accounts {
+		res.ReadWriteAddresses[addr.Hex()] = &pb.InfoReadWrite{
+			Nonce:   fmt.Sprint(acc.nonce),
+			Balance: acc.balance.String(),
+		}
+	}
+	return res, nil
+}
+
+// processTx executes the transaction, the changes are committed to the tree
+// unless the transaction fails any of the intrinsic checks
+func processTx(sdb *stateDB, chainConfig *params.ChainConfig, signer types.Signer, coinbase common.Address, in *pb.ProcessBatchRequest, tx *types.Transaction) (*pb.ProcessTransactionResponse, error) {
+	rlpTx, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.ProcessTransactionResponse{
+		TxHash:  tx.Hash().Bytes(),
+		RlpTx:   rlpTx,
+		Type:    uint32(tx.Type()),
+		GasLeft: tx.Gas(),
+	}
+
+	romErr := pb.RomError_ROM_ERROR_NO_ERROR
+	sender, err := types.Sender(signer, tx)
+	if errors.Is(err, types.ErrInvalidChainId) {
+		romErr = pb.RomError_ROM_ERROR_INTRINSIC_INVALID_CHAIN_ID
+	} else if err != nil {
+		romErr = pb.RomError_ROM_ERROR_INTRINSIC_INVALID_SIGNATURE
+	} else if tx.Gas() > txGasLimit {
+		romErr = pb.RomError_ROM_ERROR_INTRINSIC_INVALID_GAS_LIMIT
+	}
+	if romErr != pb.RomError_ROM_ERROR_NO_ERROR {
+		res.Error = romErr
+		res.StateRoot = sdb.root.Bytes()
+		return res, nil
+	}
+
+	msg, err := core.TransactionToMessage(tx, signer, nil)
+	if err != nil {
+		return nil, err
+	}
+	txCount := sdb.GetState(addressSystem, common.BigToHash(big.NewInt(lastTxStoragePos))).Big().Uint64()
+	blockCtx := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash:     func(n uint64) common.Hash { return sdb.GetState(addressSystem, stateRootPos(n)) },
+		Coinbase:    coinbase,
+		GasLimit:    txGasLimit,
+		BlockNumber: new(big.Int).SetUint64(txCount + 1),
+		Time:        in.EthTimestamp,
+		Difficulty:  big.NewInt(0),
+		BaseFee:     big.NewInt(0),
+	}
+	evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), sdb, chainConfig, vm.Config{})
+
+	result, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(txGasLimit))
+	if sdb.err != nil {
+		return nil, sdb.err
+	}
+	if err != nil {
+		sdb.discard()
+		res.Error = intrinsicErrorToRomError(err)
+		res.StateRoot = sdb.root.Bytes()
+		return res, nil
+	}
+
+	logs := sdb.logs
+	if _, err := sdb.commit(); err != nil {
+		return nil, err
+	}
+	root, err := updateSystemStorage(sdb, txCount+1)
+	if err != nil {
+		return nil, err
+	}
+
+	res.ReturnValue = result.ReturnData
+	res.GasUsed = result.UsedGas
+	res.GasLeft = tx.Gas() - result.UsedGas
+	res.Error = vmErrorToRomError(result.Err)
+	res.StateRoot = root.Bytes()
+	if tx.To() == nil {
+		res.CreateAddress = crypto.CreateAddress(sender, tx.Nonce()).Hex()
+	}
+	for _, l := range logs {
+		topics := make([][]byte, 0, len(l.Topics))
+		for _, topic := range l.Topics {
+			topics = append(topics, topic.Bytes())
+		}
+		res.Logs = append(res.Logs, &pb.Log{
+			Address:     l.Address.Hex(),
+			Topics:      topics,
+			Data:        l.Data,
+			BatchNumber: in.OldBatchNum + 1,
+			TxHash:      tx.Hash().Bytes(),
+		})
+	}
+	return res, nil
+}
+
+// setGlobalExitRoot stores the timestamp of the global exit root in the L2
+// global exit root manager, unless it was already set
+func setGlobalExitRoot(sdb *stateDB, globalExitRoot common.Hash, timestamp uint64) error {
+	if globalExitRoot == (common.Hash{}) {
+		return nil
+	}
+	pos := crypto.Keccak256Hash(globalExitRoot.Bytes(), common.BigToHash(big.NewInt(globalExitRootStoragePos)).Bytes())
+	if sdb.GetState(addressGlobalExitRootManager, pos) != (common.Hash{}) {
+		return sdb.err
+	}
+	sdb.SetState(addressGlobalExitRootManager, pos, common.BigToHash(new(big.Int).SetUint64(timestamp)))
+	_, err := sdb.commit()
+	return err
+}
+
+// updateSystemStorage stores the number of transactions and the state root
+// after the transaction in the system contract
+func updateSystemStorage(sdb *stateDB, txCount uint64) (common.Hash, error) {
+	sdb.SetState(addressSystem, common.BigToHash(big.NewInt(lastTxStoragePos)), common.BigToHash(new(big.Int).SetUint64(txCount)))
+	root, err := sdb.commit()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	sdb.SetState(addressSystem, stateRootPos(txCount), root)
+	return sdb.commit()
+}
+
+// stateRootPos returns the slot of the system contract storing the state root
+// after the given transaction
+func stateRootPos(txCount uint64) common.Hash {
+	return crypto.Keccak256Hash(
+		common.BigToHash(new(big.Int).SetUint64(txCount)).Bytes(),
+		common.BigToHash(big.NewInt(stateRootStoragePos)).Bytes(),
+	)
+}
+
+// accInputHash computes the accumulated input hash of the batch
+func accInputHash(oldAccInputHash common.Hash, batchL2Data []byte, globalExitRoot common.Hash, timestamp uint64, sequencer common.Address) common.Hash {
+	ts := make([]byte, 8) //nolint:gomnd
+	binary.BigEndian.PutUint64(ts, timestamp)
+	return crypto.Keccak256Hash(oldAccInputHash.Bytes(), crypto.Keccak256(batchL2Data), globalExitRoot.Bytes(), ts, sequencer.Bytes())
+}
+
+// decodeTxs decodes the batch L2 data, recovering from the panics caused by
+// malformed data
+func decodeTxs(batchL2Data []byte) (txs []types.Transaction, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			txs, err = nil, fmt.Errorf("invalid batch L2 data: %v", r)
+		}
+	}()
+	txs, _, err = state.DecodeTxs(batchL2Data)
+	return txs, err
+}
+
+// newChainConfig returns the geth rules closest to the zkEVM ones
+func newChainConfig(chainID uint64) *params.ChainConfig {
+	zero := big.NewInt(0)
+	return &params.ChainConfig{
+		ChainID:             new(big.Int).SetUint64(chainID),
+		HomesteadBlock:      zero,
+		EIP150Block:         zero,
+		EIP155Block:         zero,
+		EIP158Block:         zero,
+		ByzantiumBlock:      zero,
+		ConstantinopleBlock: zero,
+		PetersburgBlock:     zero,
+		IstanbulBlock:       zero,
+		BerlinBlock:         zero,
+		LondonBlock:         zero,
+	}
+}
@@ -0,0 +1,215 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/encoding"
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"github.com/0xPolygonHermez/zkevm-node/test/vectors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func vectorsDir(t *testing.T) string {
+	_, filename, _, ok := runtime.Caller(0)
+	require.True(t, ok)
+	return filepath.Join(filepath.Dir(filename), "../../../../test/vectors/src/state-transition/no-data")
+}
+
+// txErrors maps the reasons of the invalid transactions of the vectors to the
+// errors returned by the executor
+var txErrors = map[string]pb.RomError{
+	"":                                    pb.RomError_ROM_ERROR_NO_ERROR,
+	"TX INVALID: Chain ID does not match": pb.RomError_ROM_ERROR_INTRINSIC_INVALID_CHAIN_ID,
+	"TX INVALID: Invalid nonce":           pb.RomError_ROM_ERROR_INTRINSIC_INVALID_NONCE,
+	"TX INVALID: Not enough funds to pay total transaction cost": pb.RomError_ROM_ERROR_INTRINSIC_INVALID_BALANCE,
+}
+
+// TestProcessBatchStateTransitionVectors checks the resulting accounts, the
+// errors of the transactions and how they change the state root. The new
+// root must be the one of the expected accounts plus the global exit root and
+// the system storage, so nothing else is written to the tree. The expected
+// new roots of these vectors are not checked, they were generated by a ROM
+// version that writes other system storage: the batch without transactions
+// of general.json #7 turns 0x4a9bfc… into 0x74d734…, while the executor the
+// node runs with turns it into 0x5ce97f…, the exact roots of that executor
+// are checked by TestProcessBatchStateRoot
+func TestProcessBatchStateTransitionVectors(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(vectorsDir(t), "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	ctx := context.Background()
+	for _, file := range files {
+		testCases, err := vectors.LoadStateTransitionTestCases(file)
+		require.NoError(t, err)
+
+		for _, testCase := range testCases {
+			testCase := testCase
+			t.Run(fmt.Sprintf("%s/%d", filepath.Base(file), testCase.ID), func(t *testing.T) {
+				tree := merkletree.NewStateTree(merkletree.NewLocalStateDBClient(merkletree.NewMemoryTree()))
+
+				// set the genesis
+				root := common.Hash{}.Bytes()
+				for _, acc := range testCase.GenesisAccounts {
+					address := common.HexToAddress(acc.Address)
+					root, _, err = tree.SetBalance(ctx, address, &acc.Balance.Int, root)
+					require.NoError(t, err)
+					nonce, ok := new(big.Int).SetString(acc.Nonce, encoding.Base10)
+					require.True(t, ok)
+					root, _, err = tree.SetNonce(ctx, address, nonce, root)
+					require.NoError(t, err)
+				}
+				for _, sc := range testCase.GenesisSmartContracts {
+					code, err := hex.DecodeHex(sc.Code)
+					require.NoError(t, err)
+					root, _, err = tree.SetCode(ctx, common.HexToAddress(sc.Address), code, root)
+					require.NoError(t, err)
+				}
+				require.Equal(t, testCase.ExpectedOldRoot, common.BytesToHash(root).String())
+
+				batchL2Data, err := hex.DecodeHex(testCase.BatchL2Data)
+				require.NoError(t, err)
+				res, err := NewExecutor(tree, testCase.DefaultChainID).ProcessBatch(ctx, &pb.ProcessBatchRequest{
+					OldStateRoot:     root,
+					OldBatchNum:      0,
+					ChainId:          testCase.ChainIDSequencer,
+					BatchL2Data:      batchL2Data,
+					GlobalExitRoot:   common.HexToHash(testCase.GlobalExitRoot).Bytes(),
+					EthTimestamp:     testCase.Timestamp,
+					Coinbase:         testCase.SequencerAddress,
+					UpdateMerkleTree: 1,
+				})
+				require.NoError(t, err)
+				assert.Equal(t, pb.ExecutorError_EXECUTOR_ERROR_NO_ERROR, res.Error)
+				assert.Equal(t, uint64(1), res.NewBatchNum)
+
+				require.Equal(t, len(testCase.Txs), len(res.Responses))
+				for i, tx := range testCase.Txs {
+					expected, ok := txErrors[tx.Reason]
+					require.True(t, ok, "unknown reason %q", tx.Reason)
+					assert.Equal(t, expected, res.Responses[i].Error, "tx %d", tx.ID)
+					// an invalid transaction doesn't change the state, a valid one
+					// at least increases the nonce of the sender
+					if i > 0 {
+						if expected == pb.RomError_ROM_ERROR_NO_ERROR {
+							assert.NotEqual(t, res.Responses[i-1].StateRoot, res.Responses[i].StateRoot, "tx %d", tx.ID)
+						} else {
+							assert.Equal(t, res.Responses[i-1].StateRoot, res.Responses[i].StateRoot, "tx %d", tx.ID)
+						}
+					}
+				}
+				if n := len(res.Responses); n > 0 {
+					assert.Equal(t, res.Responses[n-1].StateRoot, res.NewStateRoot)
+				}
+
+				assert.Equal(t, expectedNewRoot(t, tree, testCase, res).String(), common.BytesToHash(res.NewStateRoot).String())
+
+				for addr, leaf := range testCase.ExpectedNewLeafs {
+					balance, err := tree.GetBalance(ctx, common.HexToAddress(addr), res.NewStateRoot)
+					require.NoError(t, err)
+					assert.Equal(t, leaf.Balance.String(), balance.String(), "balance of %s", addr)
+					nonce, err := tree.GetNonce(ctx, common.HexToAddress(addr), res.NewStateRoot)
+					require.NoError(t, err)
+					assert.Equal(t, leaf.Nonce, nonce.String(), "nonce of %s", addr)
+				}
+			})
+		}
+	}
+}
+
+// expectedNewRoot builds in the tree the root of the genesis of the test case
+// with the expected accounts, the global exit root and the state roots stored
+// by the executor in the system contract after each transaction that changed
+// the state
+func expectedNewRoot(t *testing.T, tree *merkletree.StateTree, testCase vectors.StateTransitionTestCase, res *pb.ProcessBatchResponse) common.Hash {
+	ctx := context.Background()
+	root := common.Hash{}.Bytes()
+	var err error
+	for _, acc := range testCase.GenesisAccounts {
+		balance, nonce := &acc.Balance.Int, acc.Nonce
+		if leaf, ok := testCase.ExpectedNewLeafs[acc.Address]; ok {
+			balance, nonce = &leaf.Balance.Int, leaf.Nonce
+		}
+		address := common.HexToAddress(acc.Address)
+		root, _, err = tree.SetBalance(ctx, address, balance, root)
+		require.NoError(t, err)
+		n, ok := new(big.Int).SetString(nonce, encoding.Base10)
+		require.True(t, ok)
+		root, _, err = tree.SetNonce(ctx, address, n, root)
+		require.NoError(t, err)
+	}
+
+	globalExitRoot := common.HexToHash(testCase.GlobalExitRoot)
+	pos := crypto.Keccak256Hash(globalExitRoot.Bytes(), common.BigToHash(big.NewInt(globalExitRootStoragePos)).Bytes())
+	root, _, err = tree.SetStorageAt(ctx, addressGlobalExitRootManager, pos.Big(), new(big.Int).SetUint64(testCase.Timestamp), root)
+	require.NoError(t, err)
+
+	var txCount uint64
+	for _, txRes := range res.Responses {
+		if !executor.IsIntrinsicError(txRes.Error) {
+			txCount++
+		}
+	}
+	if txCount > 0 {
+		root, _, err = tree.SetStorageAt(ctx, addressSystem, big.NewInt(lastTxStoragePos), new(big.Int).SetUint64(txCount), root)
+		require.NoError(t, err)
+	}
+	for i := uint64(1); i <= txCount; i++ {
+		// the root stored after each transaction is taken from the new root,
+		// as it is the one of the tree before storing it
+		stateRoot, err := tree.GetStorageAt(ctx, addressSystem, stateRootPos(i).Big(), res.NewStateRoot)
+		require.NoError(t, err)
+		require.NotZero(t, stateRoot.Sign())
+		root, _, err = tree.SetStorageAt(ctx, addressSystem, stateRootPos(i).Big(), stateRoot, root)
+		require.NoError(t, err)
+	}
+	return common.BytesToHash(root)
+}
+
+// TestProcessBatchStateRoot checks the state roots against the ones returned
+// by the executor for the same batch, see TestExecutor in the state package
+func TestProcessBatchStateRoot(t *testing.T) {
+	const (
+		expectedOldRoot = "0x2dc4db4293af236cb329700be43f08ace740a05088f8c7654736871709687e90"
+		expectedNewRoot = "0xa2b0ad9cc19e2a4aa9a6d7e14b15e5e951e319ed17b619878bec201b4d064c3e"
+	)
+	ctx := context.Background()
+	tree := merkletree.NewStateTree(merkletree.NewLocalStateDBClient(merkletree.NewMemoryTree()))
+
+	root := common.Hash{}.Bytes()
+	balance, ok := new(big.Int).SetString("1000000000000000000000", encoding.Base10)
+	require.True(t, ok)
+	for _, address := range []string{"0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D", "0x4d5Cf5032B2a844602278b01199ED191A86c93ff"} {
+		var err error
+		root, _, err = tree.SetBalance(ctx, common.HexToAddress(address), balance, root)
+		require.NoError(t, err)
+	}
+	require.Equal(t, expectedOldRoot, common.BytesToHash(root).String())
+
+	res, err := NewExecutor(tree, 0).ProcessBatch(ctx, &pb.ProcessBatchRequest{
+		OldStateRoot:     root,
+		OldBatchNum:      0,
+		ChainId:          1000,
+		BatchL2Data:      common.Hex2Bytes("ee80843b9aca00830186a0944d5cf5032b2a844602278b01199ed191a86c93ff88016345785d8a0000808203e880801cee7e01dc62f69a12c3510c6d64de04ee6346d84b6a017f3e786c7d87f963e75d8cc91fa983cd6d9cf55fff80d73bd26cd333b0f098acc1e58edb1fd484ad731b"),
+		GlobalExitRoot:   common.HexToHash("0x090bcaf734c4f06c93954a827b45a6e8c67b8e0fd1e0a35a1c5982d6961828f9").Bytes(),
+		EthTimestamp:     1944498031,
+		Coinbase:         "0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D",
+		UpdateMerkleTree: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Responses, 1)
+	assert.Equal(t, pb.RomError_ROM_ERROR_NO_ERROR, res.Responses[0].Error)
+	assert.Equal(t, expectedNewRoot, common.BytesToHash(res.Responses[0].StateRoot).String())
+	assert.Equal(t, expectedNewRoot, common.BytesToHash(res.NewStateRoot).String())
+}
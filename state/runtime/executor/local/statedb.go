@@ -0,0 +1,451 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// account is the cached state of an address
+type account struct {
+	balance *big.Int
+	nonce   uint64
+
+	code       []byte
+	codeLoaded bool
+
+	// storage keeps the current values of the slots read or written and
+	// committed their values at the beginning of the transaction
+	storage   map[common.Hash]common.Hash
+	committed map[common.Hash]common.Hash
+
+	dirtyBalance bool
+	dirtyNonce   bool
+	dirtyCode    bool
+	dirtyStorage map[common.Hash]struct{}
+
+	created  bool
+	suicided bool
+}
+
+// stateDB implements vm.StateDB on top of the merkletree. The values read are
+// cached and the changes are kept in memory until they are committed to the
+// tree at the end of each transaction
+type stateDB struct {
+	ctx  context.Context
+	tree *merkletree.StateTree
+	root common.Hash
+	// err keeps the first error accessing the tree, the vm.StateDB interface
+	// doesn't return errors so it is checked once the transaction is executed
+	err error
+
+	accounts map[common.Address]*account
+	journal  []func()
+
+	refund      uint64
+	logs        []*types.Log
+	accessAddrs map[common.Address]bool
+	accessSlots map[common.Address]map[common.Hash]bool
+	transient   map[common.Address]map[common.Hash]common.Hash
+}
+
+func newStateDB(ctx context.Context, tree *merkletree.StateTree, root common.Hash) *stateDB {
+	return &stateDB{
+		ctx:         ctx,
+		tree:        tree,
+		root:        root,
+		accounts:    make(map[common.Address]*account),
+		accessAddrs: make(map[common.Address]bool),
+		accessSlots: make(map[common.Address]map[common.Hash]bool),
+		transient:   make(map[common.Address]map[common.Hash]common.Hash),
+	}
+}
+
+func (s *stateDB) setErr(err error) {
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// getAccount returns the cached account, loading its balance and nonce from the tree
+func (s *stateDB) getAccount(addr common.Address) *account {
+	if acc, ok := s.accounts[addr]; ok {
+		return acc
+	}
+	acc := &account{
+		balance:      big.NewInt(0),
+		storage:      make(map[common.Hash]common.Hash),
+		committed:    make(map[common.Hash]common.Hash),
+		dirtyStorage: make(map[common.Hash]struct{}),
+	}
+	balance, err := s.tree.GetBalance(s.ctx, addr, s.root.Bytes())
+	s.setErr(err)
+	if err == nil {
+		acc.balance = balance
+	}
+	nonce, err := s.tree.GetNonce(s.ctx, addr, s.root.Bytes())
+	s.setErr(err)
+	if err == nil {
+		acc.nonce = nonce.Uint64()
+	}
+	s.accounts[addr] = acc
+	return acc
+}
+
+// loadCode loads the code of the account from the tree the first time it is accessed
+func (s *stateDB) loadCode(addr common.Address, acc *account) {
+	if acc.codeLoaded {
+		return
+	}
+	acc.codeLoaded = true
+	codeHash, err := s.tree.GetCodeHash(s.ctx, addr, s.root.Bytes())
+	if err != nil {
+		s.setErr(err)
+		return
+	}
+	if len(codeHash) == 0 || new(big.Int).SetBytes(codeHash).Sign() == 0 {
+		return
+	}
+	code, err := s.tree.GetCode(s.ctx, addr, s.root.Bytes())
+	s.setErr(err)
+	acc.code = code
+}
+
+// CreateAccount marks the account as created, keeping its balance
+func (s *stateDB) CreateAccount(addr common.Address) {
+	acc := s.getAccount(addr)
+	prev := acc.created
+	acc.created = true
+	s.journal = append(s.journal, func() { acc.created = prev })
+}
+
+// SubBalance subtracts amount from the account balance
+func (s *stateDB) SubBalance(addr common.Address, amount *big.Int) {
+	if amount.Sign() == 0 {
+		return
+	}
+	s.setBalance(addr, new(big.Int).Sub(s.GetBalance(addr), amount))
+}
+
+// AddBalance adds amount to the account balance
+func (s *stateDB) AddBalance(addr common.Address, amount *big.Int) {
+	if amount.Sign() == 0 {
+		return
+	}
+	s.setBalance(addr, new(big.Int).Add(s.GetBalance(addr), amount))
+}
+
+func (s *stateDB) setBalance(addr common.Address, balance *big.Int) {
+	acc := s.getAccount(addr)
+	prev := acc.balance
+	acc.balance = balance
+	acc.dirtyBalance = true
+	s.journal = append(s.journal, func() { acc.balance = prev })
+}
+
+// GetBalance returns the account balance
+func (s *stateDB) GetBalance(addr common.Address) *big.Int {
+	return new(big.Int).Set(s.getAccount(addr).balance)
+}
+
+// GetNonce returns the account nonce
+func (s *stateDB) GetNonce(addr common.Address) uint64 {
+	return s.getAccount(addr).nonce
+}
+
+// SetNonce sets the account nonce
+func (s *stateDB) SetNonce(addr common.Address, nonce uint64) {
+	acc := s.getAccount(addr)
+	prev := acc.nonce
+	acc.nonce = nonce
+	acc.dirtyNonce = true
+	s.journal = append(s.journal, func() { acc.nonce = prev })
+}
+
+// GetCodeHash returns the keccak hash of the account code, or the zero hash
+// if the account doesn't exist
+func (s *stateDB) GetCodeHash(addr common.Address) common.Hash {
+	if s.Empty(addr) {
+		return common.Hash{}
+	}
+	return crypto.Keccak256Hash(s.GetCode(addr))
+}
+
+// GetCode returns the account code
+func (s *stateDB) GetCode(addr common.Address) []byte {
+	acc := s.getAccount(addr)
+	s.loadCode(addr, acc)
+	return acc.code
+}
+
+// SetCode sets the account code
+func (s *stateDB) SetCode(addr common.Address, code []byte) {
+	acc := s.getAccount(addr)
+	s.loadCode(addr, acc)
+	prev := acc.code
+	acc.code = code
+	acc.dirtyCode = true
+	s.journal = append(s.journal, func() { acc.code = prev })
+}
+
+// GetCodeSize returns the size of the account code
+func (s *stateDB) GetCodeSize(addr common.Address) int {
+	return len(s.GetCode(addr))
+}
+
+// AddRefund adds gas to the refund counter
+func (s *stateDB) AddRefund(gas uint64) {
+	prev := s.refund
+	s.refund += gas
+	s.journal = append(s.journal, func() { s.refund = prev })
+}
+
+// SubRefund removes gas from the refund counter
+func (s *stateDB) SubRefund(gas uint64) {
+	prev := s.refund
+	if gas > s.refund {
+		s.refund = 0
+	} else {
+		s.refund -= gas
+	}
+	s.journal = append(s.journal, func() { s.refund = prev })
+}
+
+// GetRefund returns the refund counter
+func (s *stateDB) GetRefund() uint64 {
+	return s.refund
+}
+
+// GetCommittedState returns the value of the slot at the beginning of the transaction
+func (s *stateDB) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	acc := s.getAccount(addr)
+	s.loadSlot(addr, acc, key)
+	return acc.committed[key]
+}
+
+// GetState returns the current value of the slot
+func (s *stateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	acc := s.getAccount(addr)
+	s.loadSlot(addr, acc, key)
+	return acc.storage[key]
+}
+
+// SetState sets the value of the slot
+func (s *stateDB) SetState(addr common.Address, key, value common.Hash) {
+	acc := s.getAccount(addr)
+	s.loadSlot(addr, acc, key)
+	prev := acc.storage[key]
+	acc.storage[key] = value
+	acc.dirtyStorage[key] = struct{}{}
+	s.journal = append(s.journal, func() { acc.storage[key] = prev })
+}
+
+func (s *stateDB) loadSlot(addr common.Address, acc *account, key common.Hash) {
+	if _, ok := acc.committed[key]; ok {
+		return
+	}
+	value, err := s.tree.GetStorageAt(s.ctx, addr, key.Big(), s.root.Bytes())
+	s.setErr(err)
+	var v common.Hash
+	if err == nil {
+		v = common.BigToHash(value)
+	}
+	acc.committed[key] = v
+	if _, ok := acc.storage[key]; !ok {
+		acc.storage[key] = v
+	}
+}
+
+// GetTransientState returns the transient value of the slot
+func (s *stateDB) GetTransientState(addr common.Address, key common.Hash) common.Hash {
+	return s.transient[addr][key]
+}
+
+// SetTransientState sets the transient value of the slot
+func (s *stateDB) SetTransientState(addr common.Address, key, value common.Hash) {
+	if _, ok := s.transient[addr]; !ok {
+		s.transient[addr] = make(map[common.Hash]common.Hash)
+	}
+	prev := s.transient[addr][key]
+	s.transient[addr][key] = value
+	s.journal = append(s.journal, func() { s.transient[addr][key] = prev })
+}
+
+// Suicide clears the account balance. As SELFDESTRUCT works as SENDALL in the
+// zkEVM, the code and storage of the account are kept
+func (s *stateDB) Suicide(addr common.Address) bool {
+	acc := s.getAccount(addr)
+	prev := acc.suicided
+	acc.suicided = true
+	s.journal = append(s.journal, func() { acc.suicided = prev })
+	s.setBalance(addr, big.NewInt(0))
+	return true
+}
+
+// HasSuicided checks if the account self destructed in the current transaction
+func (s *stateDB) HasSuicided(addr common.Address) bool {
+	return s.getAccount(addr).suicided
+}
+
+// Exist checks if the account exists, accounts are only stored in the tree
+// when they have a balance, nonce or code
+func (s *stateDB) Exist(addr common.Address) bool {
+	acc := s.getAccount(addr)
+	return acc.created || acc.suicided || !s.Empty(addr)
+}
+
+// Empty checks if the account has no balance, nonce or code
+func (s *stateDB) Empty(addr common.Address) bool {
+	acc := s.getAccount(addr)
+	return acc.balance.Sign() == 0 && acc.nonce == 0 && s.GetCodeSize(addr) == 0
+}
+
+// AddressInAccessList checks if the address is warm
+func (s *stateDB) AddressInAccessList(addr common.Address) bool {
+	return s.accessAddrs[addr]
+}
+
+// SlotInAccessList checks if the address and the slot are warm
+func (s *stateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool) {
+	return s.accessAddrs[addr], s.accessSlots[addr][slot]
+}
+
+// AddAddressToAccessList warms the address
+func (s *stateDB) AddAddressToAccessList(addr common.Address) {
+	if s.accessAddrs[addr] {
+		return
+	}
+	s.accessAddrs[addr] = true
+	s.journal = append(s.journal, func() { delete(s.accessAddrs, addr) })
+}
+
+// AddSlotToAccessList warms the address and the slot
+func (s *stateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	s.AddAddressToAccessList(addr)
+	if s.accessSlots[addr][slot] {
+		return
+	}
+	if _, ok := s.accessSlots[addr]; !ok {
+		s.accessSlots[addr] = make(map[common.Hash]bool)
+	}
+	s.accessSlots[addr][slot] = true
+	s.journal = append(s.journal, func() { delete(s.accessSlots[addr], slot) })
+}
+
+// Prepare resets the transaction scoped state and warms the addresses of the transaction
+func (s *stateDB) Prepare(rules params.Rules, sender, coinbase common.Address, dest *common.Address, precompiles []common.Address, txAccesses types.AccessList) {
+	s.accessAddrs = make(map[common.Address]bool)
+	s.accessSlots = make(map[common.Address]map[common.Hash]bool)
+	s.transient = make(map[common.Address]map[common.Hash]common.Hash)
+	if !rules.IsBerlin {
+		return
+	}
+	s.AddAddressToAccessList(sender)
+	if dest != nil {
+		s.AddAddressToAccessList(*dest)
+	}
+	for _, addr := range precompiles {
+		s.AddAddressToAccessList(addr)
+	}
+	for _, el := range txAccesses {
+		s.AddAddressToAccessList(el.Address)
+		for _, key := range el.StorageKeys {
+			s.AddSlotToAccessList(el.Address, key)
+		}
+	}
+	if rules.IsShanghai {
+		s.AddAddressToAccessList(coinbase)
+	}
+}
+
+// RevertToSnapshot reverts the changes done since the snapshot was taken
+func (s *stateDB) RevertToSnapshot(id int) {
+	for i := len(s.journal) - 1; i >= id; i-- {
+		s.journal[i]()
+	}
+	s.journal = s.journal[:id]
+}
+
+// Snapshot returns an identifier of the current state
+func (s *stateDB) Snapshot() int {
+	return len(s.journal)
+}
+
+// AddLog adds a log emitted by the transaction
+func (s *stateDB) AddLog(log *types.Log) {
+	s.logs = append(s.logs, log)
+	n := len(s.logs) - 1
+	s.journal = append(s.journal, func() { s.logs = s.logs[:n] })
+}
+
+// AddPreimage is a no-op, the preimages are not stored
+func (s *stateDB) AddPreimage(common.Hash, []byte) {}
+
+// discard drops the transaction scoped state without committing it
+func (s *stateDB) discard() {
+	s.RevertToSnapshot(0)
+	s.refund = 0
+	s.logs = nil
+}
+
+// commit writes the changes to the tree, returning the new root
+func (s *stateDB) commit() (common.Hash, error) {
+	if s.err != nil {
+		return common.Hash{}, s.err
+	}
+
+	addrs := make([]common.Address, 0, len(s.accounts))
+	for addr := range s.accounts {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	root := s.root.Bytes()
+	var err error
+	for _, addr := range addrs {
+		acc := s.accounts[addr]
+		if acc.dirtyBalance {
+			if root, _, err = s.tree.SetBalance(s.ctx, addr, acc.balance, root); err != nil {
+				return common.Hash{}, err
+			}
+		}
+		if acc.dirtyNonce {
+			if root, _, err = s.tree.SetNonce(s.ctx, addr, new(big.Int).SetUint64(acc.nonce), root); err != nil {
+				return common.Hash{}, err
+			}
+		}
+		if acc.dirtyCode && len(acc.code) > 0 {
+			if root, _, err = s.tree.SetCode(s.ctx, addr, acc.code, root); err != nil {
+				return common.Hash{}, err
+			}
+		}
+		keys := make([]common.Hash, 0, len(acc.dirtyStorage))
+		for key := range acc.dirtyStorage {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+		for _, key := range keys {
+			if root, _, err = s.tree.SetStorageAt(s.ctx, addr, key.Big(), acc.storage[key].Big(), root); err != nil {
+				return common.Hash{}, err
+			}
+			acc.committed[key] = acc.storage[key]
+		}
+
+		acc.dirtyBalance, acc.dirtyNonce, acc.dirtyCode = false, false, false
+		acc.dirtyStorage = make(map[common.Hash]struct{})
+		acc.created, acc.suicided = false, false
+	}
+
+	s.root = common.BytesToHash(root)
+	s.journal = nil
+	s.refund = 0
+	s.logs = nil
+	return s.root, nil
+}
@@ -74,6 +74,10 @@ type State struct {
 	lastL2BlockSeen         types.Block
 	newL2BlockEvents        chan NewL2BlockEvent
 	newL2BlockEventHandlers []NewL2BlockEventHandler
+	monitorNewL2BlocksOnce  sync.Once
+	blockCache              *blockCache
+
+	forkIDIntervalsMutex sync.RWMutex
 }
 
 // NewState creates a new State
@@ -90,21 +94,26 @@ func NewState(cfg Config, storage *PostgresStorage, executorClient pb.ExecutorSe
 		newL2BlockEvents:        make(chan NewL2BlockEvent),
 		newL2BlockEventHandlers: []NewL2BlockEventHandler{},
 	}
+	if cfg.Cache.BlockSize > 0 {
+		s.blockCache = newBlockCache(cfg.Cache.BlockSize)
+		s.RegisterNewL2BlockEventHandler(s.blockCache.onNewL2Block)
+	}
 
 	return s
 }
 
 // PrepareWebSocket allows the RPC to prepare ws
 func (s *State) PrepareWebSocket() {
-	lastL2Block, err := s.PostgresStorage.GetLastL2Block(context.Background(), nil)
-	if errors.Is(err, ErrStateNotSynchronized) {
-		lastL2Block = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)})
-	} else if err != nil {
-		log.Fatalf("failed to load the last l2 block: %v", err)
-	}
-	s.lastL2BlockSeen = *lastL2Block
-	go s.monitorNewL2Blocks()
-	go s.handleEvents()
+	s.startMonitoringNewL2Blocks()
+}
+
+// startMonitoringNewL2Blocks starts the monitor of new L2 blocks the first
+// time it is called, it is required by the websocket and the block cache
+func (s *State) startMonitoringNewL2Blocks() {
+	s.monitorNewL2BlocksOnce.Do(func() {
+		go s.monitorNewL2Blocks()
+		go s.handleEvents()
+	})
 }
 
 // BeginStateTransaction starts a state transaction
@@ -278,7 +287,7 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 			Coinbase:         lastBatch.Coinbase.String(),
 			UpdateMerkleTree: cFalse,
 			ChainId:          s.cfg.ChainID,
-			ForkId:           s.cfg.CurrentForkID,
+			ForkId:           s.GetForkIdByBatchNumber(lastBatch.BatchNumber + 1),
 		}
 
 		log.Debugf("EstimateGas[processBatchRequest.OldBatchNum]: %v", processBatchRequest.OldBatchNum)
@@ -479,7 +488,7 @@ func (s *State) ProcessBatch(ctx context.Context, request ProcessRequest) (*Proc
 		EthTimestamp:     request.Timestamp,
 		UpdateMerkleTree: cTrue,
 		ChainId:          s.cfg.ChainID,
-		ForkId:           s.cfg.CurrentForkID,
+		ForkId:           s.GetForkIdByBatchNumber(request.BatchNumber),
 	}
 	res, err := s.sendBatchRequestToExecutor(ctx, processBatchRequest, request.Caller)
 	if err != nil {
@@ -606,7 +615,7 @@ func (s *State) processBatch(
 		EthTimestamp:     uint64(lastBatch.Timestamp.Unix()),
 		UpdateMerkleTree: cTrue,
 		ChainId:          s.cfg.ChainID,
-		ForkId:           s.cfg.CurrentForkID,
+		ForkId:           s.GetForkIdByBatchNumber(batchNumber),
 	}
 
 	res, err := s.sendBatchRequestToExecutor(ctx, processBatchRequest, caller)
@@ -719,6 +728,14 @@ func (s *State) StoreTransactions(ctx context.Context, batchNumber uint64, proce
 		if err := s.AddL2Block(ctx, batchNumber, block, receipts, dbTx); err != nil {
 			return err
 		}
+
+		// the counters are only known when the tx was processed alone, the
+		// ones of the synced txs are computed when they are requested
+		if processedTx.ZKCounters != nil {
+			if err := s.AddReceiptZKCounters(ctx, processedTx.TxHash, *processedTx.ZKCounters, dbTx); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -1158,12 +1175,61 @@ func (s *State) ParseTheTraceUsingTheTracer(env *fakevm.FakeEVM, trace instrumen
 func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) *runtime.ExecutionResult {
 	result := new(runtime.ExecutionResult)
 
-	lastBatches, l2BlockStateRoot, err := s.PostgresStorage.GetLastNBatchesByL2BlockNumber(ctx, l2BlockNumber, two, dbTx)
+	processBatchRequest, err := s.buildUnsignedTransactionRequest(ctx, tx, senderAddress, l2BlockNumber, dbTx)
 	if err != nil {
 		result.Err = err
 		return result
 	}
 
+	if noZKEVMCounters {
+		processBatchRequest.NoCounters = cTrue
+	}
+
+	// Send Batch to the Executor
+	processBatchResponse, err := s.executorClient.ProcessBatch(ctx, processBatchRequest)
+	if err != nil {
+		log.Errorf("error processing unsigned transaction ", err)
+		result.Err = err
+		return result
+	} else if processBatchResponse.Error != executor.EXECUTOR_ERROR_NO_ERROR {
+		err = executor.ExecutorErr(processBatchResponse.Error)
+		s.LogExecutorError(processBatchResponse.Error, processBatchRequest)
+		result.Err = err
+		return result
+	}
+
+	response, err := s.convertToProcessBatchResponse([]types.Transaction{*tx}, processBatchResponse)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	r := response.Responses[0]
+	result.ReturnValue = r.ReturnValue
+	result.GasLeft = r.GasLeft
+	result.GasUsed = r.GasUsed
+	result.CreateAddress = r.CreateAddress
+	result.StateRoot = r.StateRoot.Bytes()
+	if processBatchResponse.Responses[0].Error != pb.RomError(executor.ROM_ERROR_NO_ERROR) {
+		err := executor.RomErr(processBatchResponse.Responses[0].Error)
+		if isEVMRevertError(err) {
+			result.Err = constructErrorFromRevert(err, processBatchResponse.Responses[0].ReturnValue)
+		} else {
+			result.Err = err
+		}
+	}
+
+	return result
+}
+
+// buildUnsignedTransactionRequest builds the request to process the given
+// unsigned transaction alone on top of the given L2 block
+func (s *State) buildUnsignedTransactionRequest(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (*pb.ProcessBatchRequest, error) {
+	lastBatches, l2BlockStateRoot, err := s.PostgresStorage.GetLastNBatchesByL2BlockNumber(ctx, l2BlockNumber, two, dbTx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get latest batch from the database to get globalExitRoot and Timestamp
 	lastBatch := lastBatches[0]
 
@@ -1176,8 +1242,7 @@ func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transa
 	batchL2Data, err := EncodeUnsignedTransaction(*tx, s.cfg.ChainID)
 	if err != nil {
 		log.Errorf("error encoding unsigned transaction ", err)
-		result.Err = err
-		return result
+		return nil, err
 	}
 
 	// Create Batch
@@ -1192,11 +1257,7 @@ func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transa
 		Coinbase:         lastBatch.Coinbase.String(),
 		UpdateMerkleTree: cFalse,
 		ChainId:          s.cfg.ChainID,
-		ForkId:           s.cfg.CurrentForkID,
-	}
-
-	if noZKEVMCounters {
-		processBatchRequest.NoCounters = cTrue
+		ForkId:           s.GetForkIdByBatchNumber(lastBatch.BatchNumber + 1),
 	}
 
 	log.Debugf("ProcessUnsignedTransaction[processBatchRequest.OldBatchNum]: %v", processBatchRequest.OldBatchNum)
@@ -1211,41 +1272,7 @@ func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transa
 	log.Debugf("ProcessUnsignedTransaction[processBatchRequest.ChainId]: %v", processBatchRequest.ChainId)
 	log.Debugf("ProcessUnsignedTransaction[processBatchRequest.ForkId]: %v", processBatchRequest.ForkId)
 
-	// Send Batch to the Executor
-	processBatchResponse, err := s.executorClient.ProcessBatch(ctx, processBatchRequest)
-	if err != nil {
-		log.Errorf("error processing unsigned transaction ", err)
-		result.Err = err
-		return result
-	} else if processBatchResponse.Error != executor.EXECUTOR_ERROR_NO_ERROR {
-		err = executor.ExecutorErr(processBatchResponse.Error)
-		s.LogExecutorError(processBatchResponse.Error, processBatchRequest)
-		result.Err = err
-		return result
-	}
-
-	response, err := s.convertToProcessBatchResponse([]types.Transaction{*tx}, processBatchResponse)
-	if err != nil {
-		result.Err = err
-		return result
-	}
-
-	r := response.Responses[0]
-	result.ReturnValue = r.ReturnValue
-	result.GasLeft = r.GasLeft
-	result.GasUsed = r.GasUsed
-	result.CreateAddress = r.CreateAddress
-	result.StateRoot = r.StateRoot.Bytes()
-	if processBatchResponse.Responses[0].Error != pb.RomError(executor.ROM_ERROR_NO_ERROR) {
-		err := executor.RomErr(processBatchResponse.Responses[0].Error)
-		if isEVMRevertError(err) {
-			result.Err = constructErrorFromRevert(err, processBatchResponse.Responses[0].ReturnValue)
-		} else {
-			result.Err = err
-		}
-	}
-
-	return result
+	return processBatchRequest, nil
 }
 
 // GetTree returns State inner tree
@@ -1264,56 +1291,9 @@ func (s *State) SetGenesis(ctx context.Context, block Block, genesis Genesis, db
 		return newRoot, ErrDBTxNil
 	}
 
-	for _, action := range genesis.Actions {
-		address := common.HexToAddress(action.Address)
-		switch action.Type {
-		case int(merkletree.LeafTypeBalance):
-			balance, err := encoding.DecodeBigIntHexOrDecimal(action.Value)
-			if err != nil {
-				return newRoot, err
-			}
-			newRoot, _, err = s.tree.SetBalance(ctx, address, balance, newRoot)
-			if err != nil {
-				return newRoot, err
-			}
-		case int(merkletree.LeafTypeNonce):
-			nonce, err := encoding.DecodeBigIntHexOrDecimal(action.Value)
-			if err != nil {
-				return newRoot, err
-			}
-			newRoot, _, err = s.tree.SetNonce(ctx, address, nonce, newRoot)
-			if err != nil {
-				return newRoot, err
-			}
-		case int(merkletree.LeafTypeCode):
-			code, err := hex.DecodeHex(action.Bytecode)
-			if err != nil {
-				return newRoot, fmt.Errorf("could not decode SC bytecode for address %q: %v", address, err)
-			}
-			newRoot, _, err = s.tree.SetCode(ctx, address, code, newRoot)
-			if err != nil {
-				return newRoot, err
-			}
-		case int(merkletree.LeafTypeStorage):
-			// Parse position and value
-			positionBI, err := encoding.DecodeBigIntHexOrDecimal(action.StoragePosition)
-			if err != nil {
-				return newRoot, err
-			}
-			valueBI, err := encoding.DecodeBigIntHexOrDecimal(action.Value)
-			if err != nil {
-				return newRoot, err
-			}
-			// Store
-			newRoot, _, err = s.tree.SetStorageAt(ctx, address, positionBI, valueBI, newRoot)
-			if err != nil {
-				return newRoot, err
-			}
-		case int(merkletree.LeafTypeSCLength):
-			log.Debug("Skipped genesis action of type merkletree.LeafTypeSCLength, these actions will be handled as part of merkletree.LeafTypeCode actions")
-		default:
-			return newRoot, fmt.Errorf("unknown genesis action type %q", action.Type)
-		}
+	newRoot, err = SetGenesisActions(ctx, s.tree, genesis.Actions)
+	if err != nil {
+		return newRoot, err
 	}
 
 	root.SetBytes(newRoot)
@@ -1480,13 +1460,26 @@ func (s *State) monitorNewL2Blocks() {
 		time.Sleep(1 * time.Second)
 	}
 
+	for {
+		lastL2Block, err := s.PostgresStorage.GetLastL2Block(context.Background(), nil)
+		if errors.Is(err, ErrStateNotSynchronized) {
+			lastL2Block = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)})
+		} else if err != nil {
+			log.Errorf("failed to load the last l2 block: %v", err)
+			waitNextCycle()
+			continue
+		}
+		s.lastL2BlockSeen = *lastL2Block
+		break
+	}
+
 	for {
 		if len(s.newL2BlockEventHandlers) == 0 {
 			waitNextCycle()
 			continue
 		}
 
-		lastL2Block, err := s.GetLastL2Block(context.Background(), nil)
+		lastL2Block, err := s.PostgresStorage.GetLastL2Block(context.Background(), nil)
 		if errors.Is(err, ErrStateNotSynchronized) {
 			waitNextCycle()
 			continue
@@ -1496,6 +1489,12 @@ func (s *State) monitorNewL2Blocks() {
 			continue
 		}
 
+		if err := s.checkL2BlockReorg(context.Background(), lastL2Block); err != nil {
+			log.Errorf("failed to check l2 reorgs while monitoring new blocks: %v", err)
+			waitNextCycle()
+			continue
+		}
+
 		// not updates until now
 		if lastL2Block == nil || s.lastL2BlockSeen.NumberU64() >= lastL2Block.NumberU64() {
 			waitNextCycle()
@@ -1521,6 +1520,52 @@ func (s *State) monitorNewL2Blocks() {
 	}
 }
 
+// checkL2BlockReorg checks if the last L2 block seen was replaced by a reorg,
+// in that case the replaced blocks are removed from the cache and the monitor
+// goes back to the last block kept, so the new blocks are announced again
+func (s *State) checkL2BlockReorg(ctx context.Context, lastL2Block *types.Block) error {
+	seen := s.lastL2BlockSeen.NumberU64()
+	// the genesis block is never replaced
+	if seen == 0 {
+		return nil
+	}
+	var firstReorged uint64
+	if lastL2Block.NumberU64() < seen {
+		firstReorged = lastL2Block.NumberU64() + 1
+	} else {
+		stored := lastL2Block
+		if lastL2Block.NumberU64() > seen {
+			var err error
+			stored, err = s.PostgresStorage.GetL2BlockByNumber(ctx, seen, nil)
+			if err != nil {
+				return err
+			}
+		}
+		if stored.Hash() == s.lastL2BlockSeen.Hash() {
+			return nil
+		}
+		firstReorged = seen
+	}
+
+	if s.blockCache != nil {
+		storedBlock := func(number uint64) (*types.Block, error) {
+			return s.PostgresStorage.GetL2BlockByNumber(ctx, number, nil)
+		}
+		var err error
+		if firstReorged, err = s.blockCache.firstReorgedBlock(firstReorged, storedBlock); err != nil {
+			return err
+		}
+		s.blockCache.removeFrom(firstReorged)
+	}
+	lastKept, err := s.PostgresStorage.GetL2BlockByNumber(ctx, firstReorged-1, nil)
+	if err != nil {
+		return err
+	}
+	log.Warnf("l2 reorg detected from block %d", firstReorged)
+	s.lastL2BlockSeen = *lastKept
+	return nil
+}
+
 func (s *State) handleEvents() {
 	for newL2BlockEvent := range s.newL2BlockEvents {
 		if len(s.newL2BlockEventHandlers) == 0 {
@@ -1617,6 +1662,11 @@ func (s *State) StoreTransaction(ctx context.Context, batchNumber uint64, proces
 	if err := s.AddL2Block(ctx, batchNumber, block, receipts, dbTx); err != nil {
 		return err
 	}
+	if processedTx.ZKCounters != nil {
+		if err := s.AddReceiptZKCounters(ctx, processedTx.TxHash, *processedTx.ZKCounters, dbTx); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -1654,8 +1704,3 @@ func (s *State) LogExecutorError(responseError pb.ExecutorError, processBatchReq
 		}
 	}
 }
-
-// GetForkIdByBatchNumber returns the fork id for the given batch number
-func (s *State) GetForkIdByBatchNumber(batchNumber uint64) uint64 {
-	return GetForkIDByBatchNumber(s.cfg.ForkIDIntervals, batchNumber)
-}
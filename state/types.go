@@ -60,6 +60,9 @@ type ProcessTransactionResponse struct {
 	Logs []*types.Log
 	// IsProcessed indicates if this tx didn't fit into the batch
 	IsProcessed bool
+	// ZKCounters are the counters used by the tx, only known when the tx is
+	// processed alone
+	ZKCounters *ZKCounters
 	// Tx is the whole transaction object
 	Tx types.Transaction
 	// ExecutionTrace contains the traces produced in the execution
@@ -157,4 +160,14 @@ type L2BatchInfo struct {
 	Number    uint64
 	L1Block   uint64
 	Timestamp uint64
+	// ForcedBatchNum is the number of the last forced batch included up to
+	// this batch. The forced batches are included in order, so it is also the
+	// number of batches that are not derived from a HotShot block
+	ForcedBatchNum uint64
+}
+
+// HotShotBatchNumber returns the number of the batch without the forced
+// batches, the numbering of the batches sequenced in HotShot and on L1
+func (b L2BatchInfo) HotShotBatchNumber() uint64 {
+	return b.Number - b.ForcedBatchNum
 }
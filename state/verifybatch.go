@@ -0,0 +1,150 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// BatchDivergence describes the first difference found between a stored batch
+// and the result of executing it again
+type BatchDivergence struct {
+	BatchNumber uint64
+	// TxIndex is the index of the divergent transaction in the batch, -1 when
+	// the difference is in the batch itself
+	TxIndex  int
+	TxHash   common.Hash
+	Field    string
+	Stored   string
+	Executed string
+}
+
+// String returns a readable description of the divergence
+func (d *BatchDivergence) String() string {
+	if d.TxIndex < 0 {
+		return fmt.Sprintf("batch %d: %s stored %s, executed %s", d.BatchNumber, d.Field, d.Stored, d.Executed)
+	}
+	return fmt.Sprintf("batch %d, tx %d (%s): %s stored %s, executed %s", d.BatchNumber, d.TxIndex, d.TxHash.String(), d.Field, d.Stored, d.Executed)
+}
+
+// VerifyBatch executes again the stored raw data of the batch on top of the
+// state root of the previous batch and compares the result with the stored
+// batch and receipts. It returns nil when no difference is found. The merkle
+// tree is not updated
+func (s *State) VerifyBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*BatchDivergence, error) {
+	batch, err := s.PostgresStorage.GetBatchByNumber(ctx, batchNumber, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	encodedTxs, err := s.PostgresStorage.GetEncodedTransactionsByBatchNumber(ctx, batchNumber, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	receipts := make([]*types.Receipt, 0, len(encodedTxs))
+	for _, encodedTx := range encodedTxs {
+		tx, err := DecodeTx(encodedTx)
+		if err != nil {
+			return nil, err
+		}
+		receipt, err := s.PostgresStorage.GetTransactionReceipt(ctx, tx.Hash(), dbTx)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	response, err := s.ExecuteBatch(ctx, *batch, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != executor.EXECUTOR_ERROR_NO_ERROR {
+		return nil, executor.ExecutorErr(response.Error)
+	}
+	return CompareExecutedBatch(batch, receipts, response), nil
+}
+
+// CompareExecutedBatch returns the first difference between the stored batch and
+// receipts and the response of the executor, nil if there is none. The
+// transactions with an intrinsic error are not stored, so their responses are
+// not compared
+func CompareExecutedBatch(batch *Batch, receipts []*types.Receipt, response *pb.ProcessBatchResponse) *BatchDivergence {
+	divergence := func(txIndex int, field string, stored, executed string) *BatchDivergence {
+		d := &BatchDivergence{
+			BatchNumber: batch.BatchNumber,
+			TxIndex:     txIndex,
+			Field:       field,
+			Stored:      stored,
+			Executed:    executed,
+		}
+		if txIndex >= 0 && txIndex < len(receipts) {
+			d.TxHash = receipts[txIndex].TxHash
+		}
+		return d
+	}
+
+	txResponses := make([]*pb.ProcessTransactionResponse, 0, len(response.Responses))
+	for _, txResponse := range response.Responses {
+		if !executor.IsIntrinsicError(txResponse.Error) {
+			txResponses = append(txResponses, txResponse)
+		}
+	}
+	if len(txResponses) != len(receipts) {
+		return divergence(-1, "transactions", fmt.Sprint(len(receipts)), fmt.Sprint(len(txResponses)))
+	}
+	var gasUsed uint64
+	for i, receipt := range receipts {
+		txResponse := txResponses[i]
+		if stored, executed := common.BytesToHash(receipt.PostState), common.BytesToHash(txResponse.StateRoot); stored != executed {
+			return divergence(i, "state root", stored.String(), executed.String())
+		}
+		status := types.ReceiptStatusSuccessful
+		if txResponse.Error != pb.RomError_ROM_ERROR_NO_ERROR {
+			status = types.ReceiptStatusFailed
+		}
+		if receipt.Status != status {
+			return divergence(i, "status", fmt.Sprint(receipt.Status), fmt.Sprint(status))
+		}
+		if receipt.GasUsed != txResponse.GasUsed {
+			return divergence(i, "gas used", fmt.Sprint(receipt.GasUsed), fmt.Sprint(txResponse.GasUsed))
+		}
+		// every transaction is stored in its own L2 block
+		if receipt.CumulativeGasUsed != txResponse.GasUsed {
+			return divergence(i, "cumulative gas used", fmt.Sprint(receipt.CumulativeGasUsed), fmt.Sprint(txResponse.GasUsed))
+		}
+		gasUsed += receipt.GasUsed
+		if len(receipt.Logs) != len(txResponse.Logs) {
+			return divergence(i, "logs", fmt.Sprint(len(receipt.Logs)), fmt.Sprint(len(txResponse.Logs)))
+		}
+		executedLogs := convertToLog(txResponse.Logs)
+		for j, l := range receipt.Logs {
+			if stored, executed := logString(l), logString(executedLogs[j]); stored != executed {
+				return divergence(i, fmt.Sprintf("log %d", j), stored, executed)
+			}
+		}
+	}
+	if gasUsed != response.CumulativeGasUsed {
+		return divergence(-1, "cumulative gas used", fmt.Sprint(gasUsed), fmt.Sprint(response.CumulativeGasUsed))
+	}
+
+	if executed := common.BytesToHash(response.NewStateRoot); batch.StateRoot != executed {
+		return divergence(-1, "state root", batch.StateRoot.String(), executed.String())
+	}
+	if executed := common.BytesToHash(response.NewLocalExitRoot); batch.LocalExitRoot != executed {
+		return divergence(-1, "local exit root", batch.LocalExitRoot.String(), executed.String())
+	}
+	if executed := common.BytesToHash(response.NewAccInputHash); batch.AccInputHash != executed {
+		return divergence(-1, "acc input hash", batch.AccInputHash.String(), executed.String())
+	}
+	return nil
+}
+
+// logString returns a readable description of the content of a log
+func logString(l *types.Log) string {
+	return fmt.Sprintf("address %s topics %v data %s", l.Address.String(), l.Topics, hex.EncodeToHex(l.Data))
+}
@@ -0,0 +1,147 @@
+package state_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareExecutedBatch(t *testing.T) {
+	stateRoot := common.HexToHash("0x1")
+	txStateRoot := common.HexToHash("0x2")
+	localExitRoot := common.HexToHash("0x3")
+	accInputHash := common.HexToHash("0x4")
+	txHash := common.HexToHash("0x5")
+	logAddress := common.HexToAddress("0x6")
+	logTopic := common.HexToHash("0x7")
+
+	newBatch := func() *state.Batch {
+		return &state.Batch{
+			BatchNumber:   7,
+			StateRoot:     stateRoot,
+			LocalExitRoot: localExitRoot,
+			AccInputHash:  accInputHash,
+		}
+	}
+	newReceipts := func() []*types.Receipt {
+		return []*types.Receipt{{
+			TxHash:            txHash,
+			PostState:         txStateRoot.Bytes(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			GasUsed:           21000,
+			Logs:              []*types.Log{{Address: logAddress, Topics: []common.Hash{logTopic}, Data: []byte{1}}},
+		}}
+	}
+	newResponse := func() *pb.ProcessBatchResponse {
+		return &pb.ProcessBatchResponse{
+			NewStateRoot:      stateRoot.Bytes(),
+			NewLocalExitRoot:  localExitRoot.Bytes(),
+			NewAccInputHash:   accInputHash.Bytes(),
+			CumulativeGasUsed: 21000,
+			Responses: []*pb.ProcessTransactionResponse{{
+				StateRoot: txStateRoot.Bytes(),
+				GasUsed:   21000,
+				Error:     pb.RomError_ROM_ERROR_NO_ERROR,
+				Logs:      []*pb.Log{{Address: logAddress.String(), Topics: [][]byte{logTopic.Bytes()}, Data: []byte{1}}},
+			}},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		modify   func(batch *state.Batch, receipts []*types.Receipt, response *pb.ProcessBatchResponse)
+		expected *state.BatchDivergence
+	}{
+		{
+			name:   "no divergence",
+			modify: func(*state.Batch, []*types.Receipt, *pb.ProcessBatchResponse) {},
+		},
+		{
+			name: "tx state root",
+			modify: func(_ *state.Batch, _ []*types.Receipt, response *pb.ProcessBatchResponse) {
+				response.Responses[0].StateRoot = stateRoot.Bytes()
+				response.NewStateRoot = txStateRoot.Bytes()
+			},
+			expected: &state.BatchDivergence{BatchNumber: 7, TxIndex: 0, TxHash: txHash, Field: "state root", Stored: txStateRoot.String(), Executed: stateRoot.String()},
+		},
+		{
+			name: "tx status",
+			modify: func(_ *state.Batch, _ []*types.Receipt, response *pb.ProcessBatchResponse) {
+				response.Responses[0].Error = pb.RomError_ROM_ERROR_OUT_OF_GAS
+			},
+			expected: &state.BatchDivergence{BatchNumber: 7, TxIndex: 0, TxHash: txHash, Field: "status", Stored: "1", Executed: "0"},
+		},
+		{
+			name: "unprocessed tx",
+			modify: func(_ *state.Batch, _ []*types.Receipt, response *pb.ProcessBatchResponse) {
+				response.Responses = append(response.Responses, &pb.ProcessTransactionResponse{Error: pb.RomError_ROM_ERROR_INTRINSIC_INVALID_NONCE})
+			},
+		},
+		{
+			name: "tx cumulative gas used",
+			modify: func(_ *state.Batch, receipts []*types.Receipt, _ *pb.ProcessBatchResponse) {
+				receipts[0].CumulativeGasUsed = 42000
+			},
+			expected: &state.BatchDivergence{BatchNumber: 7, TxIndex: 0, TxHash: txHash, Field: "cumulative gas used", Stored: "42000", Executed: "21000"},
+		},
+		{
+			name: "batch cumulative gas used",
+			modify: func(_ *state.Batch, _ []*types.Receipt, response *pb.ProcessBatchResponse) {
+				response.CumulativeGasUsed = 42000
+			},
+			expected: &state.BatchDivergence{BatchNumber: 7, TxIndex: -1, Field: "cumulative gas used", Stored: "21000", Executed: "42000"},
+		},
+		{
+			name: "log data",
+			modify: func(_ *state.Batch, _ []*types.Receipt, response *pb.ProcessBatchResponse) {
+				response.Responses[0].Logs[0].Data = []byte{2}
+			},
+			expected: &state.BatchDivergence{
+				BatchNumber: 7, TxIndex: 0, TxHash: txHash, Field: "log 0",
+				Stored:   fmt.Sprintf("address %s topics [%s] data 0x01", logAddress.String(), logTopic.String()),
+				Executed: fmt.Sprintf("address %s topics [%s] data 0x02", logAddress.String(), logTopic.String()),
+			},
+		},
+		{
+			name: "number of transactions",
+			modify: func(_ *state.Batch, _ []*types.Receipt, response *pb.ProcessBatchResponse) {
+				response.Responses = nil
+			},
+			expected: &state.BatchDivergence{BatchNumber: 7, TxIndex: -1, Field: "transactions", Stored: "1", Executed: "0"},
+		},
+		{
+			name: "local exit root",
+			modify: func(batch *state.Batch, _ []*types.Receipt, _ *pb.ProcessBatchResponse) {
+				batch.LocalExitRoot = common.Hash{}
+			},
+			expected: &state.BatchDivergence{BatchNumber: 7, TxIndex: -1, Field: "local exit root", Stored: common.Hash{}.String(), Executed: localExitRoot.String()},
+		},
+		{
+			name: "acc input hash",
+			modify: func(_ *state.Batch, _ []*types.Receipt, response *pb.ProcessBatchResponse) {
+				response.NewAccInputHash = nil
+			},
+			expected: &state.BatchDivergence{BatchNumber: 7, TxIndex: -1, Field: "acc input hash", Stored: accInputHash.String(), Executed: common.Hash{}.String()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			batch, receipts, response := newBatch(), newReceipts(), newResponse()
+			tc.modify(batch, receipts, response)
+			divergence := state.CompareExecutedBatch(batch, receipts, response)
+			if tc.expected == nil {
+				require.Nil(t, divergence)
+				return
+			}
+			assert.Equal(t, tc.expected, divergence)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// Names of the ZK counters
+const (
+	CounterCumulativeGasUsed = "gasUsed"
+	CounterKeccakHashes      = "keccakHashes"
+	CounterPoseidonHashes    = "poseidonHashes"
+	CounterPoseidonPaddings  = "poseidonPaddings"
+	CounterMemAligns         = "memAligns"
+	CounterArithmetics       = "arithmetics"
+	CounterBinaries          = "binaries"
+	CounterSteps             = "steps"
+)
+
+// BatchConstraintsCfg represents the constraints of the resources of a batch,
+// shared by the sequencer and the estimation of the counters of the txs
+type BatchConstraintsCfg struct {
+	// MaxTxsPerBatch is the maximum amount of transactions in the batch
+	MaxTxsPerBatch uint64 `mapstructure:"MaxTxsPerBatch"`
+
+	// MaxBatchBytesSize is the maximum batch size in bytes
+	MaxBatchBytesSize uint64 `mapstructure:"MaxBatchBytesSize"`
+
+	// MaxCumulativeGasUsed is max gas amount used by batch
+	MaxCumulativeGasUsed uint64 `mapstructure:"MaxCumulativeGasUsed"`
+
+	// MaxKeccakHashes is max keccak hashes used by batch
+	MaxKeccakHashes uint32 `mapstructure:"MaxKeccakHashes"`
+
+	// MaxPoseidonHashes is max poseidon hashes batch can handle
+	MaxPoseidonHashes uint32 `mapstructure:"MaxPoseidonHashes"`
+
+	// MaxPoseidonPaddings is max poseidon paddings batch can handle
+	MaxPoseidonPaddings uint32 `mapstructure:"MaxPoseidonPaddings"`
+
+	// MaxMemAligns is max mem aligns batch can handle
+	MaxMemAligns uint32 `mapstructure:"MaxMemAligns"`
+
+	// MaxArithmetics is max arithmetics batch can handle
+	MaxArithmetics uint32 `mapstructure:"MaxArithmetics"`
+
+	// MaxBinaries is max binaries batch can handle
+	MaxBinaries uint32 `mapstructure:"MaxBinaries"`
+
+	// MaxSteps is max steps batch can handle
+	MaxSteps uint32 `mapstructure:"MaxSteps"`
+}
+
+// ZKCounterUsage is the usage of a ZK counter compared to the batch limit
+type ZKCounterUsage struct {
+	Name  string
+	Used  uint64
+	Limit uint64
+	// Percentage is the percentage of the batch limit used
+	Percentage float64
+}
+
+// Exceeded indicates if the counter is above the batch limit
+func (u ZKCounterUsage) Exceeded() bool {
+	return u.Used > u.Limit
+}
+
+func (u ZKCounterUsage) String() string {
+	return fmt.Sprintf("%s used %d of %d (%.2f%%)", u.Name, u.Used, u.Limit, u.Percentage)
+}
+
+// ZKCountersUsage returns the usage of each of the counters compared to the
+// limits of a batch
+func (c BatchConstraintsCfg) ZKCountersUsage(counters ZKCounters) []ZKCounterUsage {
+	usage := []ZKCounterUsage{
+		{Name: CounterCumulativeGasUsed, Used: counters.CumulativeGasUsed, Limit: c.MaxCumulativeGasUsed},
+		{Name: CounterKeccakHashes, Used: uint64(counters.UsedKeccakHashes), Limit: uint64(c.MaxKeccakHashes)},
+		{Name: CounterPoseidonHashes, Used: uint64(counters.UsedPoseidonHashes), Limit: uint64(c.MaxPoseidonHashes)},
+		{Name: CounterPoseidonPaddings, Used: uint64(counters.UsedPoseidonPaddings), Limit: uint64(c.MaxPoseidonPaddings)},
+		{Name: CounterMemAligns, Used: uint64(counters.UsedMemAligns), Limit: uint64(c.MaxMemAligns)},
+		{Name: CounterArithmetics, Used: uint64(counters.UsedArithmetics), Limit: uint64(c.MaxArithmetics)},
+		{Name: CounterBinaries, Used: uint64(counters.UsedBinaries), Limit: uint64(c.MaxBinaries)},
+		{Name: CounterSteps, Used: uint64(counters.UsedSteps), Limit: uint64(c.MaxSteps)},
+	}
+	for i := range usage {
+		if usage[i].Limit > 0 {
+			usage[i].Percentage = float64(usage[i].Used) * 100 / float64(usage[i].Limit)
+		}
+	}
+	return usage
+}
+
+// CounterUsage returns the usage of the counter with the given name compared
+// to the limit of a batch
+func (c BatchConstraintsCfg) CounterUsage(name string, counters ZKCounters) (ZKCounterUsage, bool) {
+	for _, usage := range c.ZKCountersUsage(counters) {
+		if usage.Name == name {
+			return usage, true
+		}
+	}
+	return ZKCounterUsage{}, false
+}
+
+// OutOfCountersName returns the name of the counter of an out of counters
+// error of the ROM, empty if the error is not an out of counters one
+func OutOfCountersName(romError pb.RomError) string {
+	switch int32(romError) {
+	case executor.ROM_ERROR_OUT_OF_COUNTERS_STEP:
+		return CounterSteps
+	case executor.ROM_ERROR_OUT_OF_COUNTERS_KECCAK:
+		return CounterKeccakHashes
+	case executor.ROM_ERROR_OUT_OF_COUNTERS_BINARY:
+		return CounterBinaries
+	case executor.ROM_ERROR_OUT_OF_COUNTERS_MEM:
+		return CounterMemAligns
+	case executor.ROM_ERROR_OUT_OF_COUNTERS_ARITH:
+		return CounterArithmetics
+	case executor.ROM_ERROR_OUT_OF_COUNTERS_PADDING:
+		return CounterPoseidonPaddings
+	case executor.ROM_ERROR_OUT_OF_COUNTERS_POSEIDON:
+		return CounterPoseidonHashes
+	}
+	return ""
+}
+
+// CountersEstimation is the result of executing a transaction to know the ZK
+// counters it uses
+type CountersEstimation struct {
+	// Counters are the ZK counters used by the transaction
+	Counters ZKCounters
+	// Usage is the usage of each counter compared to the batch limits
+	Usage []ZKCounterUsage
+	// OutOfCounters is the name of the counter the transaction ran out of,
+	// empty if it did not run out of counters
+	OutOfCounters string
+	// Err is the error of the execution of the transaction, if any
+	Err error
+}
+
+// EstimateCounters executes the transaction alone on top of the given L2 block,
+// or the last one if nil, and returns the ZK counters it uses
+func (s *State) EstimateCounters(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (*CountersEstimation, error) {
+	processBatchRequest, err := s.buildUnsignedTransactionRequest(ctx, tx, senderAddress, l2BlockNumber, dbTx)
+	if err != nil {
+		return nil, err
+	}
+
+	processBatchResponse, err := s.executorClient.ProcessBatch(ctx, processBatchRequest)
+	if err != nil {
+		return nil, err
+	}
+	if processBatchResponse.Error != executor.EXECUTOR_ERROR_NO_ERROR && !executor.IsExecutorOutOfCountersError(processBatchResponse.Error) {
+		s.LogExecutorError(processBatchResponse.Error, processBatchRequest)
+		return nil, executor.ExecutorErr(processBatchResponse.Error)
+	}
+
+	estimation := &CountersEstimation{
+		Counters: ZKCounters{
+			CumulativeGasUsed:    processBatchResponse.CumulativeGasUsed,
+			UsedKeccakHashes:     processBatchResponse.CntKeccakHashes,
+			UsedPoseidonHashes:   processBatchResponse.CntPoseidonHashes,
+			UsedPoseidonPaddings: processBatchResponse.CntPoseidonPaddings,
+			UsedMemAligns:        processBatchResponse.CntMemAligns,
+			UsedArithmetics:      processBatchResponse.CntArithmetics,
+			UsedBinaries:         processBatchResponse.CntBinaries,
+			UsedSteps:            processBatchResponse.CntSteps,
+		},
+	}
+	estimation.Usage = s.cfg.BatchConstraints.ZKCountersUsage(estimation.Counters)
+	if processBatchResponse.Error != executor.EXECUTOR_ERROR_NO_ERROR {
+		// the executor only tells the batch ran out of counters, the
+		// exceeded one is taken from the usage
+		estimation.Err = executor.ExecutorErr(processBatchResponse.Error)
+		for _, usage := range estimation.Usage {
+			if usage.Exceeded() {
+				estimation.OutOfCounters = usage.Name
+				break
+			}
+		}
+	} else if len(processBatchResponse.Responses) > 0 && processBatchResponse.Responses[0].Error != pb.RomError(executor.ROM_ERROR_NO_ERROR) {
+		estimation.OutOfCounters = OutOfCountersName(processBatchResponse.Responses[0].Error)
+		estimation.Err = executor.RomErr(processBatchResponse.Responses[0].Error)
+	}
+	return estimation, nil
+}
+
+// GetTransactionZKCounters returns the usage of the ZK counters used by a
+// stored transaction compared to the limits of a batch. The counters of the
+// synced txs are not stored, they are known executing the tx again
+func (s *State) GetTransactionZKCounters(ctx context.Context, txHash common.Hash, dbTx pgx.Tx) ([]ZKCounterUsage, error) {
+	counters, err := s.GetReceiptZKCounters(ctx, txHash, dbTx)
+	if errors.Is(err, ErrNotFound) {
+		counters, err = s.executeStoredTxZKCounters(ctx, txHash, dbTx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.cfg.BatchConstraints.ZKCountersUsage(*counters), nil
+}
+
+// executeStoredTxZKCounters executes a stored transaction alone on top of the
+// L2 block before its own with the context of its batch
+func (s *State) executeStoredTxZKCounters(ctx context.Context, txHash common.Hash, dbTx pgx.Tx) (*ZKCounters, error) {
+	receipt, err := s.GetTransactionReceipt(ctx, txHash, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.GetTransactionByHash(ctx, txHash, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	l2BlockNumber := receipt.BlockNumber.Uint64()
+	l2Block, err := s.GetL2BlockByNumber(ctx, l2BlockNumber, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	previousL2Block, err := s.GetL2BlockByNumber(ctx, l2BlockNumber-1, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	batch, err := s.GetBatchByL2BlockNumber(ctx, l2BlockNumber, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	processingContext := &ProcessingContext{
+		BatchNumber:    batch.BatchNumber,
+		Coinbase:       l2Block.Coinbase(),
+		Timestamp:      time.Unix(int64(l2Block.Time()), 0),
+		GlobalExitRoot: batch.GlobalExitRoot,
+	}
+	return s.executeTxZKCounters(ctx, batch.BatchNumber, processingContext, previousL2Block.Root(), *tx)
+}
+
+// executeTxZKCounters executes a transaction of a batch alone on top of the
+// state root of the previous L2 block, without updating the merkletree, to
+// know the ZK counters it uses. The executor only returns the counters of the
+// whole batch, so the synced txs are executed again one by one, the same way
+// the sequencer executes them
+func (s *State) executeTxZKCounters(ctx context.Context, batchNumber uint64, processingContext *ProcessingContext, oldStateRoot common.Hash, tx types.Transaction) (*ZKCounters, error) {
+	batchL2Data, err := EncodeTransactions([]types.Transaction{tx})
+	if err != nil {
+		return nil, err
+	}
+	processBatchRequest := &pb.ProcessBatchRequest{
+		OldBatchNum:      batchNumber - 1,
+		Coinbase:         processingContext.Coinbase.String(),
+		BatchL2Data:      batchL2Data,
+		OldStateRoot:     oldStateRoot.Bytes(),
+		GlobalExitRoot:   processingContext.GlobalExitRoot.Bytes(),
+		EthTimestamp:     uint64(processingContext.Timestamp.Unix()),
+		UpdateMerkleTree: cFalse,
+		ChainId:          s.cfg.ChainID,
+		ForkId:           s.GetForkIdByBatchNumber(batchNumber),
+	}
+	processBatchResponse, err := s.executorClient.ProcessBatch(ctx, processBatchRequest)
+	if err != nil {
+		return nil, err
+	}
+	if processBatchResponse.Error != executor.EXECUTOR_ERROR_NO_ERROR {
+		s.LogExecutorError(processBatchResponse.Error, processBatchRequest)
+		return nil, executor.ExecutorErr(processBatchResponse.Error)
+	}
+	counters := convertToCounters(processBatchResponse)
+	return &counters, nil
+}
@@ -2,6 +2,8 @@ package synchronizer
 
 import (
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/health"
 )
 
 // Config represents the configuration of the synchronizer
@@ -14,7 +16,48 @@ type Config struct {
 	// SyncChunkSize is the number of blocks to sync on each chunk
 	SyncChunkSize uint64 `mapstructure:"SyncChunkSize"`
 
+	// SyncPipelineBufferSize is the number of chunks downloaded ahead of their
+	// execution, 0 disables the pipeline so each chunk is executed before
+	// downloading the next one
+	SyncPipelineBufferSize uint64 `mapstructure:"SyncPipelineBufferSize"`
+
+	// SyncPipelineDownloadWorkers is the number of chunks downloaded in
+	// parallel by the pipeline. The sequences of a chunk are decoded from the
+	// last batch of the previous one, so more than one worker is only used
+	// with preconfirmations, when the sequences are not taken from L1
+	SyncPipelineDownloadWorkers uint64 `mapstructure:"SyncPipelineDownloadWorkers"`
+
+	// ResetChunkSize is the number of batches, and then of L1 blocks, removed
+	// on each step of a reset of the state, each step runs in its own db
+	// transaction. 0 removes all the batches and blocks in a single step
+	ResetChunkSize uint64 `mapstructure:"ResetChunkSize"`
+
+	// ForcedBatchTimeout is the number of L1 blocks after which a forced batch
+	// is included. It is included right before the first batch derived from a
+	// HotShot block whose L1 block is at least ForcedBatchTimeout blocks after
+	// the block where it was forced, 0 disables the inclusion of forced batches.
+	// With preconfirmations it must exceed the lag of the L1 finality, the
+	// batches wait until the L1 blocks that can force them are synced
+	ForcedBatchTimeout uint64 `mapstructure:"ForcedBatchTimeout"`
+
 	GenBlockNumber uint64 `mapstructure:"GenBlockNumber"`
 
 	IgnoreGenBlockNumberCheck bool `mapstructure:"IgnoreGenBlockNumberCheck"`
+
+	// L1Finality is the finality level of the L1 blocks synced: latest, safe
+	// or finalized. The blocks above it are not synced until they reach it, so
+	// they can't be reorged once synced
+	L1Finality string `mapstructure:"L1Finality"`
+
+	// L1Confirmations is the number of blocks the synced L1 blocks are kept
+	// behind the block of the L1Finality level
+	L1Confirmations uint64 `mapstructure:"L1Confirmations"`
+
+	// Health is the configuration of the health server that exposes the
+	// liveness and readiness probes of the synchronizer
+	Health health.Config `mapstructure:"Health"`
+
+	// Broadcast is the configuration of the client of the trusted sequencer
+	// broadcast server used by the non sequencer nodes
+	Broadcast broadcast.ClientConfig `mapstructure:"Broadcast"`
 }
@@ -0,0 +1,134 @@
+package synchronizer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// errForcedBatchesNotSynced is returned when a preconfirmed batch can't be
+// processed yet because the L1 blocks that can force batches before it are
+// not synced
+var errForcedBatchesNotSynced = errors.New("the L1 blocks with the forced batches are not synced yet")
+
+// setLastL1BlockSynced records the last L1 block whose events are synced
+func (s *ClientSynchronizer) setLastL1BlockSynced(blockNumber uint64) {
+	atomic.StoreUint64(&s.lastL1BlockSynced, blockNumber)
+	s.status.SetLastL1Block(blockNumber)
+}
+
+// includeForcedBatches processes, before the batch derived from a HotShot
+// block with the given L1 block and timestamp, the forced batches that timed
+// out at that L1 block. They take the next batch numbers after lastBatch,
+// which is updated with the last one included.
+//
+// The rule only depends on the L1 block assigned by HotShot and the forced
+// batches of the L1 blocks up to it, so every node includes the same forced
+// batches at the same batch numbers.
+func (s *ClientSynchronizer) includeForcedBatches(lastBatch *state.L2BatchInfo, l1Block uint64, timestamp uint64, batchesSeenAtBlock uint64, l1Finality state.L1Finality, dbTx pgx.Tx) error {
+	if s.cfg.ForcedBatchTimeout == 0 || l1Block < s.cfg.ForcedBatchTimeout {
+		return nil
+	}
+	maxBlockNumber := l1Block - s.cfg.ForcedBatchTimeout
+
+	// The preconfirmations are synced ahead of L1, the batch waits until every
+	// forced batch that could be included before it is stored. When the batches
+	// are synced from L1 the forced batches are stored by the previous blocks
+	if l1Finality == state.L1FinalityPreconfirmed && atomic.LoadUint64(&s.lastL1BlockSynced) < maxBlockNumber {
+		err := fmt.Errorf("%w: L1 block %d is required to sync batch %d", errForcedBatchesNotSynced, maxBlockNumber, lastBatch.Number+1)
+		rollbackErr := dbTx.Rollback(s.ctx)
+		if rollbackErr != nil {
+			log.Errorf("error rolling back state. BlockNumber: %d, rollbackErr: %s, error : %w", l1Block, rollbackErr.Error(), err)
+			return rollbackErr
+		}
+		return err
+	}
+
+	pending, err := s.state.GetPendingForcedBatches(s.ctx, lastBatch.ForcedBatchNum, maxBlockNumber, dbTx)
+	if err != nil {
+		log.Errorf("error getting the pending forced batches. BlockNumber: %d, error: %w", l1Block, err)
+		rollbackErr := dbTx.Rollback(s.ctx)
+		if rollbackErr != nil {
+			log.Errorf("error rolling back state. BlockNumber: %d, rollbackErr: %s, error : %w", l1Block, rollbackErr.Error(), err)
+			return rollbackErr
+		}
+		return err
+	}
+
+	for _, forcedBatch := range forcedBatchesToInclude(pending, lastBatch.ForcedBatchNum) {
+		batchNumber := lastBatch.Number + 1
+		forcedBatchNum := forcedBatch.ForcedBatchNumber
+		log.Infof("Including forced batch %d forced at L1 block %d as batch %d", forcedBatchNum, forcedBatch.BlockNumber, batchNumber)
+
+		virtualBatch := state.VirtualBatch{
+			BatchNumber:        batchNumber,
+			TxHash:             common.Hash{},
+			SeenAt:             batchesSeenAtBlock,
+			Coinbase:           forcedBatch.Sequencer,
+			BlockNumber:        l1Block,
+			SequencerAddr:      forcedBatch.Sequencer,
+			L1Finality:         l1Finality,
+			LastForcedBatchNum: forcedBatchNum,
+		}
+		batch := state.Batch{
+			BatchNumber:    batchNumber,
+			GlobalExitRoot: forcedBatch.GlobalExitRoot,
+			Timestamp:      time.Unix(int64(timestamp), 0),
+			Coinbase:       forcedBatch.Sequencer,
+			BatchL2Data:    forcedBatch.RawTxsData,
+			ForcedBatchNum: &forcedBatchNum,
+		}
+		err = s.processSequencedBatch(batch, virtualBatch, dbTx)
+		if err != nil {
+			return err
+		}
+		*lastBatch = state.L2BatchInfo{
+			Number:         batchNumber,
+			L1Block:        l1Block,
+			Timestamp:      timestamp,
+			ForcedBatchNum: forcedBatchNum,
+		}
+	}
+	return nil
+}
+
+// forcedBatchesToInclude returns the forced batches to include after the
+// forced batch lastForcedBatchNum. They are included in the order in which
+// they were forced, so it stops at the first one missing.
+func forcedBatchesToInclude(pending []state.ForcedBatch, lastForcedBatchNum uint64) []state.ForcedBatch {
+	var forcedBatches []state.ForcedBatch
+	next := lastForcedBatchNum + 1
+	for _, forcedBatch := range pending {
+		if forcedBatch.ForcedBatchNumber != next {
+			break
+		}
+		forcedBatches = append(forcedBatches, forcedBatch)
+		next++
+	}
+	return forcedBatches
+}
+
+// equalForcedBatchNum reports whether two batches include the same forced
+// batch, or none
+func equalForcedBatchNum(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// forcedBatchNumString formats the number of the forced batch included in a
+// batch, if any
+func forcedBatchNumString(forcedBatchNum *uint64) string {
+	if forcedBatchNum == nil {
+		return "none"
+	}
+	return strconv.FormatUint(*forcedBatchNum, 10)
+}
@@ -0,0 +1,296 @@
+package synchronizer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testForcedBatchesState stores the batches processed by the synchronizer and
+// serves them through the state mock, so each call continues from the batches
+// stored by the previous ones
+type testForcedBatchesState struct {
+	forcedBatches  []state.ForcedBatch
+	virtualBatches []state.VirtualBatch
+	included       []testIncludedBatch
+}
+
+// testIncludedBatch is a stored batch, forcedBatchNum is the number of the
+// forced batch it includes or 0 if it is derived from a HotShot block
+type testIncludedBatch struct {
+	number             uint64
+	forcedBatchNum     uint64
+	lastForcedBatchNum uint64
+}
+
+func expectForcedBatchesState(m *mocks, forcedBatches []state.ForcedBatch) *testForcedBatchesState {
+	s := &testForcedBatchesState{forcedBatches: forcedBatches}
+	m.State.
+		On("ContainsBlock", mock.Anything, mock.Anything, m.DbTx).
+		Return(true, nil).
+		Maybe()
+	m.State.
+		On("GetLastBatchInfo", mock.Anything, m.DbTx).
+		Return(func(ctx context.Context, dbTx pgx.Tx) state.L2BatchInfo {
+			if len(s.virtualBatches) == 0 {
+				return state.L2BatchInfo{}
+			}
+			last := s.virtualBatches[len(s.virtualBatches)-1]
+			return state.L2BatchInfo{Number: last.BatchNumber, L1Block: last.BlockNumber, ForcedBatchNum: last.LastForcedBatchNum}
+		}, nil).
+		Maybe()
+	m.State.
+		On("GetPendingForcedBatches", mock.Anything, mock.Anything, mock.Anything, m.DbTx).
+		Return(func(ctx context.Context, lastForcedBatchNumber uint64, maxBlockNumber uint64, dbTx pgx.Tx) []state.ForcedBatch {
+			var pending []state.ForcedBatch
+			for _, forcedBatch := range s.forcedBatches {
+				if forcedBatch.ForcedBatchNumber > lastForcedBatchNumber && forcedBatch.BlockNumber <= maxBlockNumber {
+					pending = append(pending, forcedBatch)
+				}
+			}
+			return pending
+		}, nil).
+		Maybe()
+	m.State.
+		On("ExecuteBatch", mock.Anything, mock.Anything, m.DbTx).
+		Run(func(args mock.Arguments) {
+			batch := args.Get(1).(state.Batch)
+			var forcedBatchNum uint64
+			if batch.ForcedBatchNum != nil {
+				forcedBatchNum = *batch.ForcedBatchNum
+			}
+			s.included = append(s.included, testIncludedBatch{number: batch.BatchNumber, forcedBatchNum: forcedBatchNum})
+		}).
+		Return(&pb.ProcessBatchResponse{}, nil).
+		Maybe()
+	m.State.
+		On("GetBatchByNumber", mock.Anything, mock.Anything, m.DbTx).
+		Return(nil, state.ErrNotFound).
+		Maybe()
+	m.State.
+		On("ProcessAndStoreClosedBatch", mock.Anything, mock.Anything, mock.Anything, m.DbTx, state.SynchronizerCallerLabel).
+		Return(nil).
+		Maybe()
+	m.State.
+		On("AddVirtualBatch", mock.Anything, mock.Anything, m.DbTx).
+		Run(func(args mock.Arguments) {
+			virtualBatch := *args.Get(1).(*state.VirtualBatch)
+			s.virtualBatches = append(s.virtualBatches, virtualBatch)
+			s.included[len(s.virtualBatches)-1].lastForcedBatchNum = virtualBatch.LastForcedBatchNum
+		}).
+		Return(nil).
+		Maybe()
+	m.State.
+		On("AddSequence", mock.Anything, mock.Anything, m.DbTx).
+		Return(nil).
+		Maybe()
+	return s
+}
+
+func TestForcedBatchesToInclude(t *testing.T) {
+	forcedBatches := func(nums ...uint64) []state.ForcedBatch {
+		var fbs []state.ForcedBatch
+		for _, num := range nums {
+			fbs = append(fbs, state.ForcedBatch{ForcedBatchNumber: num})
+		}
+		return fbs
+	}
+	numbers := func(fbs []state.ForcedBatch) []uint64 {
+		var nums []uint64
+		for _, fb := range fbs {
+			nums = append(nums, fb.ForcedBatchNumber)
+		}
+		return nums
+	}
+
+	testCases := []struct {
+		name               string
+		pending            []state.ForcedBatch
+		lastForcedBatchNum uint64
+		expected           []uint64
+	}{
+		{
+			name:     "no pending forced batches",
+			expected: nil,
+		},
+		{
+			name:     "all the pending forced batches in order",
+			pending:  forcedBatches(1, 2, 3),
+			expected: []uint64{1, 2, 3},
+		},
+		{
+			name:               "after the last forced batch included",
+			pending:            forcedBatches(3, 4),
+			lastForcedBatchNum: 2,
+			expected:           []uint64{3, 4},
+		},
+		{
+			name:               "stops at the first forced batch missing",
+			pending:            forcedBatches(3, 5, 6),
+			lastForcedBatchNum: 2,
+			expected:           []uint64{3},
+		},
+		{
+			name:               "none when the next forced batch is missing",
+			pending:            forcedBatches(4, 5),
+			lastForcedBatchNum: 2,
+			expected:           nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, numbers(forcedBatchesToInclude(tc.pending, tc.lastForcedBatchNum)))
+		})
+	}
+}
+
+func TestEqualForcedBatchNum(t *testing.T) {
+	one, otherOne, two := uint64(1), uint64(1), uint64(2)
+	assert.True(t, equalForcedBatchNum(nil, nil))
+	assert.True(t, equalForcedBatchNum(&one, &otherOne))
+	assert.False(t, equalForcedBatchNum(&one, nil))
+	assert.False(t, equalForcedBatchNum(nil, &one))
+	assert.False(t, equalForcedBatchNum(&one, &two))
+}
+
+// newTestForcedBatches returns the forced batches numbered from 1 forced at
+// the given L1 blocks
+func newTestForcedBatches(blockNumbers ...uint64) []state.ForcedBatch {
+	var forcedBatches []state.ForcedBatch
+	for i, blockNumber := range blockNumbers {
+		forcedBatches = append(forcedBatches, state.ForcedBatch{
+			BlockNumber:       blockNumber,
+			ForcedBatchNumber: uint64(i + 1),
+			Sequencer:         common.HexToAddress("0x555"),
+			RawTxsData:        []byte{byte(i + 1)},
+		})
+	}
+	return forcedBatches
+}
+
+// newTestSequencedBatches returns the batches derived from HotShot blocks with
+// the given L1 blocks, numbered as downloaded, i.e. without the forced batches
+func newTestSequencedBatches(firstBatchNumber uint64, blockNumbers ...uint64) []etherman.SequencedBatch {
+	var sequencedBatches []etherman.SequencedBatch
+	for i, blockNumber := range blockNumbers {
+		sequencedBatches = append(sequencedBatches, newTestSequencedBatch(firstBatchNumber+uint64(i), blockNumber))
+	}
+	return sequencedBatches
+}
+
+func TestIncludeForcedBatchesTimeout(t *testing.T) {
+	testCases := []struct {
+		name         string
+		blockNumbers []uint64
+		expected     []testIncludedBatch
+	}{
+		{
+			name:         "L1 block before the timeout",
+			blockNumbers: []uint64{9},
+			expected:     []testIncludedBatch{{number: 1}},
+		},
+		{
+			name:         "forced batch not timed out",
+			blockNumbers: []uint64{14},
+			expected:     []testIncludedBatch{{number: 1}},
+		},
+		{
+			name:         "forced batch timed out at the L1 block",
+			blockNumbers: []uint64{15},
+			expected: []testIncludedBatch{
+				{number: 1, forcedBatchNum: 1, lastForcedBatchNum: 1},
+				{number: 2, lastForcedBatchNum: 1},
+			},
+		},
+		{
+			name:         "batches renumbered after the forced batches",
+			blockNumbers: []uint64{14, 15, 16, 16},
+			expected: []testIncludedBatch{
+				{number: 1},
+				{number: 2, forcedBatchNum: 1, lastForcedBatchNum: 1},
+				{number: 3, lastForcedBatchNum: 1},
+				{number: 4, forcedBatchNum: 2, lastForcedBatchNum: 2},
+				{number: 5, lastForcedBatchNum: 2},
+				{number: 6, lastForcedBatchNum: 2},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sync, m := newTestSynchronizer(t, Config{ForcedBatchTimeout: 10})
+			s := expectForcedBatchesState(m, newTestForcedBatches(5, 6))
+
+			err := sync.processSequenceBatches(newTestSequencedBatches(1, tc.blockNumbers...), m.DbTx, 20, state.L1FinalityLatest)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, s.included)
+		})
+	}
+}
+
+func TestIncludeForcedBatchesPreconfirmed(t *testing.T) {
+	sync, m := newTestSynchronizer(t, Config{ForcedBatchTimeout: 10})
+	s := expectForcedBatchesState(m, newTestForcedBatches(5))
+	sequencedBatches := newTestSequencedBatches(1, 15)
+
+	// the forced batches of the L1 block 5 could be missing, so the batch
+	// waits until it is synced
+	sync.setLastL1BlockSynced(4)
+	m.DbTx.
+		On("Rollback", mock.Anything).
+		Return(nil).
+		Once()
+	err := sync.processSequenceBatches(sequencedBatches, m.DbTx, 20, state.L1FinalityPreconfirmed)
+	require.True(t, errors.Is(err, errForcedBatchesNotSynced))
+	assert.Empty(t, s.included)
+
+	sync.setLastL1BlockSynced(5)
+	err = sync.processSequenceBatches(sequencedBatches, m.DbTx, 20, state.L1FinalityPreconfirmed)
+	require.NoError(t, err)
+	assert.Equal(t, []testIncludedBatch{
+		{number: 1, forcedBatchNum: 1, lastForcedBatchNum: 1},
+		{number: 2, lastForcedBatchNum: 1},
+	}, s.included)
+}
+
+func TestIncludeForcedBatchesDeterministic(t *testing.T) {
+	forcedBatches := newTestForcedBatches(3, 5, 5, 7)
+	blockNumbers := []uint64{12, 14, 15, 15, 17, 30}
+	expected := []testIncludedBatch{
+		{number: 1},
+		{number: 2, forcedBatchNum: 1, lastForcedBatchNum: 1},
+		{number: 3, lastForcedBatchNum: 1},
+		{number: 4, forcedBatchNum: 2, lastForcedBatchNum: 2},
+		{number: 5, forcedBatchNum: 3, lastForcedBatchNum: 3},
+		{number: 6, lastForcedBatchNum: 3},
+		{number: 7, lastForcedBatchNum: 3},
+		{number: 8, forcedBatchNum: 4, lastForcedBatchNum: 4},
+		{number: 9, lastForcedBatchNum: 4},
+		{number: 10, lastForcedBatchNum: 4},
+	}
+
+	// the batches are synced from L1 in the sequences they were seen at
+	l1Sync, m := newTestSynchronizer(t, Config{ForcedBatchTimeout: 10})
+	l1State := expectForcedBatchesState(m, forcedBatches)
+	require.NoError(t, l1Sync.processSequenceBatches(newTestSequencedBatches(1, blockNumbers[:3]...), m.DbTx, 20, state.L1FinalityLatest))
+	require.NoError(t, l1Sync.processSequenceBatches(newTestSequencedBatches(4, blockNumbers[3:]...), m.DbTx, 40, state.L1FinalityLatest))
+	assert.Equal(t, expected, l1State.included)
+
+	// the preconfirmations are synced one by one, ahead of L1
+	preconfSync, m := newTestSynchronizer(t, Config{ForcedBatchTimeout: 10})
+	preconfSync.setLastL1BlockSynced(30)
+	preconfState := expectForcedBatchesState(m, forcedBatches)
+	for i, blockNumber := range blockNumbers {
+		require.NoError(t, preconfSync.processSequenceBatches(newTestSequencedBatches(uint64(i+1), blockNumber), m.DbTx, blockNumber, state.L1FinalityPreconfirmed))
+	}
+	assert.Equal(t, expected, preconfState.included)
+}
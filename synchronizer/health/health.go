@@ -0,0 +1,289 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/metrics"
+)
+
+const (
+	// HealthEndpoint is the endpoint of the liveness probe
+	HealthEndpoint = "/health"
+	// ReadyEndpoint is the endpoint of the readiness probe
+	ReadyEndpoint = "/ready"
+
+	readTimeout = 10 * time.Second
+)
+
+// Config represents the configuration of the health server of the synchronizer
+type Config struct {
+	// Enabled defines if the health server is started together with the synchronizer
+	Enabled bool `mapstructure:"Enabled"`
+	// Host is the address the health server binds to
+	Host string `mapstructure:"Host"`
+	// Port is the port of the health server
+	Port int `mapstructure:"Port"`
+
+	// MaxL1BlockLag is the number of L1 blocks the synchronizer can be behind
+	// the last L1 block to sync while ready, 0 disables the check
+	MaxL1BlockLag uint64 `mapstructure:"MaxL1BlockLag"`
+	// MaxHotShotBatchLag is the number of batches the synchronizer can be
+	// behind the last batch sequenced in HotShot while ready, 0 disables the
+	// check
+	MaxHotShotBatchLag uint64 `mapstructure:"MaxHotShotBatchLag"`
+	// MaxSyncAge is the time the synchronizer can go without a successful
+	// sync iteration while healthy, 0 disables the check
+	MaxSyncAge types.Duration `mapstructure:"MaxSyncAge"`
+}
+
+// Status is the sync status of the synchronizer
+type Status struct {
+	// LastL1Block is the last L1 block synced
+	LastL1Block uint64 `json:"lastL1Block"`
+	// L1Head is the last L1 block with the finality level synced
+	L1Head uint64 `json:"l1Head"`
+	// LastBatch is the last batch synced, either from L1 or preconfirmed
+	LastBatch uint64 `json:"lastBatch"`
+	// ForcedBatches is the number of forced batches included up to the last
+	// batch synced, the batches sequenced on L1 and in HotShot are numbered
+	// without them
+	ForcedBatches uint64 `json:"forcedBatches"`
+	// LatestSequencedBatch is the last batch sequenced on L1
+	LatestSequencedBatch uint64 `json:"latestSequencedBatch"`
+	// HotShotLatestBatch is the last batch sequenced in HotShot, nil when the
+	// preconfirmations are not synced
+	HotShotLatestBatch *uint64 `json:"hotShotLatestBatch,omitempty"`
+	// Reorgs is the number of L1 reorgs since the synchronizer started
+	Reorgs uint64 `json:"reorgs"`
+	// StartedAt is the time the synchronizer started
+	StartedAt time.Time `json:"startedAt"`
+	// SyncedAt is the time of the last successful sync iteration
+	SyncedAt time.Time `json:"syncedAt"`
+}
+
+// L1BlockLag returns the number of L1 blocks the synchronizer is behind the
+// last L1 block to sync
+func (s Status) L1BlockLag() uint64 {
+	if s.L1Head <= s.LastL1Block {
+		return 0
+	}
+	return s.L1Head - s.LastL1Block
+}
+
+// HotShotBatchLag returns the number of batches the synchronizer is behind the
+// last batch sequenced in HotShot, the forced batches are not counted
+func (s Status) HotShotBatchLag() uint64 {
+	lastBatch := s.LastBatch - s.ForcedBatches
+	if s.HotShotLatestBatch == nil || *s.HotShotLatestBatch <= lastBatch {
+		return 0
+	}
+	return *s.HotShotLatestBatch - lastBatch
+}
+
+// Tracker keeps the sync status of the synchronizer and its metrics
+type Tracker struct {
+	mu     sync.RWMutex
+	status Status
+}
+
+// NewTracker creates a new Tracker
+func NewTracker() *Tracker {
+	return &Tracker{status: Status{StartedAt: time.Now()}}
+}
+
+// SetLastL1Block sets the last L1 block synced
+func (t *Tracker) SetLastL1Block(blockNumber uint64) {
+	t.mu.Lock()
+	t.status.LastL1Block = blockNumber
+	t.mu.Unlock()
+	metrics.LastL1Block(blockNumber)
+}
+
+// SetL1Head sets the last L1 block with the finality level synced
+func (t *Tracker) SetL1Head(blockNumber uint64) {
+	t.mu.Lock()
+	t.status.L1Head = blockNumber
+	t.mu.Unlock()
+	metrics.L1Head(blockNumber)
+}
+
+// SetBatches sets the last batch synced with the number of forced batches
+// included up to it and the last one sequenced on L1
+func (t *Tracker) SetBatches(lastBatch, forcedBatches, latestSequencedBatch uint64) {
+	t.mu.Lock()
+	t.status.LastBatch = lastBatch
+	t.status.ForcedBatches = forcedBatches
+	t.status.LatestSequencedBatch = latestSequencedBatch
+	t.mu.Unlock()
+	metrics.LastBatch(lastBatch)
+}
+
+// SetLastBatch sets the last batch synced with the number of forced batches
+// included up to it
+func (t *Tracker) SetLastBatch(lastBatch, forcedBatches uint64) {
+	t.mu.Lock()
+	t.status.LastBatch = lastBatch
+	t.status.ForcedBatches = forcedBatches
+	t.mu.Unlock()
+	metrics.LastBatch(lastBatch)
+}
+
+// SetHotShotLatestBatch sets the last batch sequenced in HotShot
+func (t *Tracker) SetHotShotLatestBatch(batchNumber uint64) {
+	t.mu.Lock()
+	t.status.HotShotLatestBatch = &batchNumber
+	t.mu.Unlock()
+	metrics.HotShotLatestBatch(batchNumber)
+}
+
+// Reorg records an L1 reorg that reverted depth blocks
+func (t *Tracker) Reorg(depth uint64) {
+	t.mu.Lock()
+	t.status.Reorgs++
+	t.mu.Unlock()
+	metrics.Reorg(depth)
+}
+
+// Synced records a successful sync iteration
+func (t *Tracker) Synced() {
+	t.mu.Lock()
+	t.status.SyncedAt = time.Now()
+	t.mu.Unlock()
+}
+
+// Status returns the sync status
+func (t *Tracker) Status() Status {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	status := t.status
+	if status.HotShotLatestBatch != nil {
+		hotShotLatestBatch := *status.HotShotLatestBatch
+		status.HotShotLatestBatch = &hotShotLatestBatch
+	}
+	return status
+}
+
+// CheckHealth returns the reasons why the synchronizer is not alive, it is
+// alive while its sync iterations keep succeeding
+func CheckHealth(cfg Config, status Status, now time.Time) []string {
+	var problems []string
+	if cfg.MaxSyncAge.Duration > 0 {
+		lastSync := status.SyncedAt
+		if lastSync.IsZero() {
+			lastSync = status.StartedAt
+		}
+		if age := now.Sub(lastSync); age > cfg.MaxSyncAge.Duration {
+			problems = append(problems, fmt.Sprintf("no successful sync iteration in %s", age.Truncate(time.Second)))
+		}
+	}
+	return problems
+}
+
+// CheckReady returns the reasons why the synchronizer is not ready, it is
+// ready once it has synced and while it is not lagging behind L1 or HotShot
+func CheckReady(cfg Config, status Status, now time.Time) []string {
+	problems := CheckHealth(cfg, status, now)
+	if status.SyncedAt.IsZero() {
+		problems = append(problems, "first sync iteration not completed")
+	}
+	if lag := status.L1BlockLag(); cfg.MaxL1BlockLag > 0 && lag > cfg.MaxL1BlockLag {
+		problems = append(problems, fmt.Sprintf("%d L1 blocks behind, max lag is %d", lag, cfg.MaxL1BlockLag))
+	}
+	if lag := status.HotShotBatchLag(); cfg.MaxHotShotBatchLag > 0 && lag > cfg.MaxHotShotBatchLag {
+		problems = append(problems, fmt.Sprintf("%d batches behind HotShot, max lag is %d", lag, cfg.MaxHotShotBatchLag))
+	}
+	return problems
+}
+
+// response is the body of the health endpoints
+type response struct {
+	Status   Status   `json:"status"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Server serves the liveness and readiness probes of the synchronizer along
+// with its sync status
+type Server struct {
+	cfg     Config
+	tracker *Tracker
+	srv     *http.Server
+}
+
+// NewServer is the health server constructor
+func NewServer(cfg Config, tracker *Tracker) *Server {
+	return &Server{
+		cfg:     cfg,
+		tracker: tracker,
+	}
+}
+
+// Start serves the health endpoints until the server is stopped
+func (s *Server) Start() {
+	address := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("failed to create tcp listener for synchronizer health server: %v", err)
+		return
+	}
+
+	s.srv = &http.Server{
+		Handler:     s.Handler(),
+		ReadTimeout: readTimeout,
+	}
+	log.Infof("synchronizer health server listening in %q", address)
+	if err := s.srv.Serve(lis); err != nil {
+		if err == http.ErrServerClosed {
+			log.Warnf("synchronizer health server stopped")
+			return
+		}
+		log.Errorf("closed http connection for synchronizer health server: %v", err)
+	}
+}
+
+// Stop stops the server
+func (s *Server) Stop() {
+	if s.srv != nil {
+		if err := s.srv.Close(); err != nil {
+			log.Errorf("failed to stop synchronizer health server: %v", err)
+		}
+	}
+}
+
+// Handler returns the handler of the health endpoints, they respond 200 when
+// the check passes and 503 otherwise, along with the sync status
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthEndpoint, s.handle(CheckHealth))
+	mux.HandleFunc(ReadyEndpoint, s.handle(CheckReady))
+	return mux
+}
+
+func (s *Server) handle(check func(Config, Status, time.Time) []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		status := s.tracker.Status()
+		res := response{Status: status, Problems: check(s.cfg, status, time.Now())}
+		body, err := json.Marshal(res)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if len(res.Problems) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if _, err := w.Write(body); err != nil {
+			log.Errorf("failed to write synchronizer health response: %v", err)
+		}
+	}
+}
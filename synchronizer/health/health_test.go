@@ -0,0 +1,178 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecks(t *testing.T) {
+	now := time.Now()
+	cfg := Config{MaxL1BlockLag: 10, MaxHotShotBatchLag: 5, MaxSyncAge: types.NewDuration(time.Minute)}
+	hotShotLatestBatch := uint64(100)
+	synced := Status{
+		LastL1Block:        95,
+		L1Head:             100,
+		LastBatch:          98,
+		HotShotLatestBatch: &hotShotLatestBatch,
+		StartedAt:          now.Add(-time.Hour),
+		SyncedAt:           now.Add(-time.Second),
+	}
+
+	testCases := []struct {
+		name    string
+		cfg     Config
+		status  func(Status) Status
+		healthy bool
+		ready   bool
+	}{
+		{
+			name:    "synced",
+			cfg:     cfg,
+			status:  func(s Status) Status { return s },
+			healthy: true,
+			ready:   true,
+		},
+		{
+			name: "starting",
+			cfg:  cfg,
+			status: func(s Status) Status {
+				s.StartedAt, s.SyncedAt = now, time.Time{}
+				return s
+			},
+			healthy: true,
+			ready:   false,
+		},
+		{
+			name: "stalled",
+			cfg:  cfg,
+			status: func(s Status) Status {
+				s.SyncedAt = now.Add(-2 * time.Minute)
+				return s
+			},
+			healthy: false,
+			ready:   false,
+		},
+		{
+			name: "stalled since start",
+			cfg:  cfg,
+			status: func(s Status) Status {
+				s.SyncedAt = time.Time{}
+				return s
+			},
+			healthy: false,
+			ready:   false,
+		},
+		{
+			name: "behind L1",
+			cfg:  cfg,
+			status: func(s Status) Status {
+				s.LastL1Block = 89
+				return s
+			},
+			healthy: true,
+			ready:   false,
+		},
+		{
+			name: "behind HotShot",
+			cfg:  cfg,
+			status: func(s Status) Status {
+				s.LastBatch = 94
+				return s
+			},
+			healthy: true,
+			ready:   false,
+		},
+		{
+			name: "forced batches not counted",
+			cfg:  cfg,
+			status: func(s Status) Status {
+				s.LastBatch, s.ForcedBatches = 101, 7
+				return s
+			},
+			healthy: true,
+			ready:   false,
+		},
+		{
+			name: "lag checks disabled",
+			cfg:  Config{},
+			status: func(s Status) Status {
+				s.LastL1Block, s.LastBatch, s.SyncedAt = 0, 0, now.Add(-time.Hour)
+				return s
+			},
+			healthy: true,
+			ready:   true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := tc.status(synced)
+			assert.Equal(t, tc.healthy, len(CheckHealth(tc.cfg, status, now)) == 0)
+			assert.Equal(t, tc.ready, len(CheckReady(tc.cfg, status, now)) == 0)
+		})
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	status := tracker.Status()
+	assert.False(t, status.StartedAt.IsZero())
+	assert.True(t, status.SyncedAt.IsZero())
+	assert.Nil(t, status.HotShotLatestBatch)
+
+	tracker.SetL1Head(100)
+	tracker.SetLastL1Block(90)
+	tracker.SetBatches(7, 0, 8)
+	tracker.SetHotShotLatestBatch(12)
+	tracker.SetLastBatch(10, 1)
+	tracker.Reorg(3)
+	tracker.Synced()
+
+	status = tracker.Status()
+	assert.Equal(t, uint64(10), status.L1BlockLag())
+	assert.Equal(t, uint64(10), status.LastBatch)
+	assert.Equal(t, uint64(1), status.ForcedBatches)
+	assert.Equal(t, uint64(8), status.LatestSequencedBatch)
+	assert.Equal(t, uint64(3), status.HotShotBatchLag())
+	assert.Equal(t, uint64(1), status.Reorgs)
+	assert.False(t, status.SyncedAt.IsZero())
+}
+
+func TestHandler(t *testing.T) {
+	tracker := NewTracker()
+	tracker.SetL1Head(100)
+	tracker.SetLastL1Block(50)
+	tracker.Synced()
+	handler := NewServer(Config{MaxL1BlockLag: 10}, tracker).Handler()
+
+	get := func(path string) (int, response) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var res response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return rec.Code, res
+	}
+
+	code, res := get(HealthEndpoint)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, res.Problems)
+	assert.Equal(t, uint64(50), res.Status.LastL1Block)
+
+	code, res = get(ReadyEndpoint)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Len(t, res.Problems, 1)
+
+	tracker.SetLastL1Block(95)
+	code, _ = get(ReadyEndpoint)
+	assert.Equal(t, http.StatusOK, code)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, HealthEndpoint, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -21,7 +21,11 @@ type ethermanInterface interface {
 	GetLatestBatchNumber() (uint64, error)
 	GetTrustedSequencerURL() (string, error)
 	VerifyGenBlockNumber(ctx context.Context, genBlockNumber uint64) (bool, error)
+	VerifyBlockHash(ctx context.Context, blockNumber uint64) (common.Hash, error)
 	GetPreconfirmations(ctx context.Context, prevBatch state.L2BatchInfo) ([]etherman.Block, map[common.Hash][]etherman.Order, error)
+	GetLatestHotShotBatchNumber() (uint64, error)
+	GetForks(ctx context.Context) ([]state.ForkIDInterval, error)
+	ResetL1Cache(blockNumber uint64)
 }
 
 // stateInterface gathers the methods required to interact with the state.
@@ -31,7 +35,10 @@ type stateInterface interface {
 	AddForcedBatch(ctx context.Context, forcedBatch *state.ForcedBatch, dbTx pgx.Tx) error
 	AddBlock(ctx context.Context, block *state.Block, dbTx pgx.Tx) error
 	ContainsBlock(ctx context.Context, blockNum uint64, dbTx pgx.Tx) (bool, error)
-	Reset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) error
+	StartReset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) error
+	ResetStep(ctx context.Context, blockNumber uint64, chunkSize uint64, dbTx pgx.Tx) (bool, error)
+	FinishReset(ctx context.Context, dbTx pgx.Tx) error
+	GetResetInProgress(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetPreviousBlock(ctx context.Context, offset uint64, dbTx pgx.Tx) (*state.Block, error)
 	GetLastBatchInfo(ctx context.Context, dbTx pgx.Tx) (state.L2BatchInfo, error)
 	GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
@@ -41,6 +48,7 @@ type stateInterface interface {
 	AddVirtualBatch(ctx context.Context, virtualBatch *state.VirtualBatch, dbTx pgx.Tx) error
 	// GetNextForcedBatches returns the next forcedBatches in FIFO order
 	GetNextForcedBatches(ctx context.Context, nextForcedBatches int, dbTx pgx.Tx) ([]state.ForcedBatch, error)
+	GetPendingForcedBatches(ctx context.Context, lastForcedBatchNumber uint64, maxBlockNumber uint64, dbTx pgx.Tx) ([]state.ForcedBatch, error)
 	AddVerifiedBatch(ctx context.Context, verifiedBatch *state.VerifiedBatch, dbTx pgx.Tx) error
 	ProcessAndStoreClosedBatch(ctx context.Context, processingCtx state.ProcessingContext, encodedTxs []byte, dbTx pgx.Tx, caller state.CallerLabel) error
 	SetGenesis(ctx context.Context, block state.Block, genesis state.Genesis, dbTx pgx.Tx) ([]byte, error)
@@ -54,6 +62,13 @@ type stateInterface interface {
 	GetLastVirtualBatchNum(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	AddSequence(ctx context.Context, sequence state.Sequence, dbTx pgx.Tx) error
 	AddAccumulatedInputHash(ctx context.Context, batchNum uint64, accInputHash common.Hash, dbTx pgx.Tx) error
+	AddForkID(ctx context.Context, forkID state.ForkIDInterval, dbTx pgx.Tx) error
+	LoadForkIDIntervals(ctx context.Context, dbTx pgx.Tx) error
+	UpdateVirtualBatchesL1Finality(ctx context.Context, l1Finality state.L1Finality, blockNumber uint64, dbTx pgx.Tx) (int64, error)
+	GetVirtualBatchesAfterBlock(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]state.VirtualBatch, error)
+	GetTransactionsFromBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]types.Transaction, error)
+	GetFirstL2BlockNumberFromBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (uint64, error)
+	AddReorg(ctx context.Context, reorg *state.Reorg, dbTx pgx.Tx) error
 
 	BeginStateTransaction(ctx context.Context) (pgx.Tx, error)
 }
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Prefix for the metrics of the synchronizer package.
+	Prefix = "synchronizer_"
+	// LastL1BlockName is the name of the metric that shows the last L1 block synced.
+	LastL1BlockName = Prefix + "last_l1_block"
+	// L1HeadName is the name of the metric that shows the last L1 block with the finality level synced.
+	L1HeadName = Prefix + "l1_head"
+	// LastBatchName is the name of the metric that shows the last batch synced, either from L1 or preconfirmed.
+	LastBatchName = Prefix + "last_batch"
+	// HotShotLatestBatchName is the name of the metric that shows the last batch sequenced in HotShot, that is the
+	// HotShot height in L2 batches.
+	HotShotLatestBatchName = Prefix + "hotshot_latest_batch"
+	// ProcessedBatchesName is the name of the metric that counts the batches processed.
+	ProcessedBatchesName = Prefix + "processed_batches"
+	// BatchExecutionTimeName is the name of the metric that shows the time spent by the executor on each batch.
+	BatchExecutionTimeName = Prefix + "batch_execution_time"
+	// ReorgsName is the name of the metric that counts the L1 reorgs.
+	ReorgsName = Prefix + "reorgs"
+	// ReorgDepthName is the name of the metric that shows the number of L1 blocks reverted by the reorgs.
+	ReorgDepthName = Prefix + "reorg_depth"
+	// PipelinePrefix is the prefix for the metrics of the sync pipeline.
+	PipelinePrefix = Prefix + "pipeline_"
+	// PipelineBufferDepthName is the name of the metric that shows the chunks of L1 blocks downloaded or being
+	// downloaded ahead of their execution.
+	PipelineBufferDepthName = PipelinePrefix + "buffer_depth"
+	// PipelineL1BlocksName is the name of the metric that counts the L1 blocks that went through each stage of
+	// the pipeline.
+	PipelineL1BlocksName = PipelinePrefix + "l1_blocks"
+	// PipelineStageTimeName is the name of the metric that shows the time spent by each stage of the pipeline
+	// on a chunk of L1 blocks.
+	PipelineStageTimeName = PipelinePrefix + "stage_time"
+	// StageLabelName is the name of the label for the stage of the pipeline.
+	StageLabelName = "stage"
+
+	// DownloadStage is the stage of the pipeline that downloads the rollup info from L1.
+	DownloadStage = "download"
+	// ExecutionStage is the stage of the pipeline that executes and stores the downloaded rollup info.
+	ExecutionStage = "execution"
+)
+
+// Register the metrics for the synchronizer package.
+func Register() {
+	gauges := []prometheus.GaugeOpts{
+		{
+			Name: LastL1BlockName,
+			Help: "[SYNCHRONIZER] last L1 block synced",
+		},
+		{
+			Name: L1HeadName,
+			Help: "[SYNCHRONIZER] last L1 block with the finality level synced",
+		},
+		{
+			Name: LastBatchName,
+			Help: "[SYNCHRONIZER] last batch synced, either from L1 or preconfirmed",
+		},
+		{
+			Name: HotShotLatestBatchName,
+			Help: "[SYNCHRONIZER] last batch sequenced in HotShot",
+		},
+		{
+			Name: PipelineBufferDepthName,
+			Help: "[SYNCHRONIZER] number of chunks of L1 blocks downloaded ahead of their execution",
+		},
+	}
+
+	counters := []prometheus.CounterOpts{
+		{
+			Name: ProcessedBatchesName,
+			Help: "[SYNCHRONIZER] number of batches processed, its rate is the batches synced per second",
+		},
+		{
+			Name: ReorgsName,
+			Help: "[SYNCHRONIZER] number of L1 reorgs",
+		},
+	}
+
+	counterVecs := []metrics.CounterVecOpts{
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: PipelineL1BlocksName,
+				Help: "[SYNCHRONIZER] number of L1 blocks that went through the stage of the pipeline",
+			},
+			Labels: []string{StageLabelName},
+		},
+	}
+
+	histograms := []prometheus.HistogramOpts{
+		{
+			Name: BatchExecutionTimeName,
+			Help: "[SYNCHRONIZER] time in seconds spent by the executor on a batch",
+		},
+		{
+			Name:    ReorgDepthName,
+			Help:    "[SYNCHRONIZER] number of L1 blocks reverted by a reorg",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10), //nolint:gomnd
+		},
+	}
+
+	histogramVecs := []metrics.HistogramVecOpts{
+		{
+			HistogramOpts: prometheus.HistogramOpts{
+				Name: PipelineStageTimeName,
+				Help: "[SYNCHRONIZER] time in seconds spent by the stage of the pipeline on a chunk of L1 blocks",
+			},
+			Labels: []string{StageLabelName},
+		},
+	}
+
+	metrics.RegisterGauges(gauges...)
+	metrics.RegisterCounters(counters...)
+	metrics.RegisterCounterVecs(counterVecs...)
+	metrics.RegisterHistograms(histograms...)
+	metrics.RegisterHistogramVecs(histogramVecs...)
+}
+
+// LastL1Block sets the last L1 block synced.
+func LastL1Block(blockNumber uint64) {
+	metrics.GaugeSet(LastL1BlockName, float64(blockNumber))
+}
+
+// L1Head sets the last L1 block with the finality level synced.
+func L1Head(blockNumber uint64) {
+	metrics.GaugeSet(L1HeadName, float64(blockNumber))
+}
+
+// LastBatch sets the last batch synced.
+func LastBatch(batchNumber uint64) {
+	metrics.GaugeSet(LastBatchName, float64(batchNumber))
+}
+
+// HotShotLatestBatch sets the last batch sequenced in HotShot.
+func HotShotLatestBatch(batchNumber uint64) {
+	metrics.GaugeSet(HotShotLatestBatchName, float64(batchNumber))
+}
+
+// ProcessedBatch counts a processed batch and observes the time the executor spent on it.
+func ProcessedBatch(elapsed time.Duration) {
+	metrics.CounterInc(ProcessedBatchesName)
+	metrics.HistogramObserve(BatchExecutionTimeName, elapsed.Seconds())
+}
+
+// Reorg counts an L1 reorg and observes the number of L1 blocks it reverted.
+func Reorg(depth uint64) {
+	metrics.CounterInc(ReorgsName)
+	metrics.HistogramObserve(ReorgDepthName, float64(depth))
+}
+
+// PipelineBufferDepth sets the number of chunks buffered by the pipeline.
+func PipelineBufferDepth(depth int) {
+	metrics.GaugeSet(PipelineBufferDepthName, float64(depth))
+}
+
+// PipelineStage observes the L1 blocks of a chunk that went through the given stage of the pipeline and the time
+// it took.
+func PipelineStage(stage string, l1Blocks uint64, elapsed time.Duration) {
+	metrics.CounterVecAdd(PipelineL1BlocksName, stage, float64(l1Blocks))
+	metrics.HistogramVecObserve(PipelineStageTimeName, stage, elapsed.Seconds())
+}
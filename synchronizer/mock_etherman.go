@@ -12,6 +12,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	state "github.com/0xPolygonHermez/zkevm-node/state"
+
 	types "github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -43,6 +45,29 @@ func (_m *ethermanMock) EthBlockByNumber(ctx context.Context, blockNumber uint64
 	return r0, r1
 }
 
+// GetForks provides a mock function with given fields: ctx
+func (_m *ethermanMock) GetForks(ctx context.Context) ([]state.ForkIDInterval, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []state.ForkIDInterval
+	if rf, ok := ret.Get(0).(func(context.Context) []state.ForkIDInterval); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.ForkIDInterval)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLatestBatchNumber provides a mock function with given fields:
 func (_m *ethermanMock) GetLatestBatchNumber() (uint64, error) {
 	ret := _m.Called()
@@ -64,13 +89,34 @@ func (_m *ethermanMock) GetLatestBatchNumber() (uint64, error) {
 	return r0, r1
 }
 
-// GetRollupInfoByBlockRange provides a mock function with given fields: ctx, fromBlock, toBlock
-func (_m *ethermanMock) GetRollupInfoByBlockRange(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
-	ret := _m.Called(ctx, fromBlock, toBlock)
+// GetLatestHotShotBatchNumber provides a mock function with given fields:
+func (_m *ethermanMock) GetLatestHotShotBatchNumber() (uint64, error) {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPreconfirmations provides a mock function with given fields: ctx, prevBatch
+func (_m *ethermanMock) GetPreconfirmations(ctx context.Context, prevBatch state.L2BatchInfo) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
+	ret := _m.Called(ctx, prevBatch)
 
 	var r0 []etherman.Block
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, *uint64) []etherman.Block); ok {
-		r0 = rf(ctx, fromBlock, toBlock)
+	if rf, ok := ret.Get(0).(func(context.Context, state.L2BatchInfo) []etherman.Block); ok {
+		r0 = rf(ctx, prevBatch)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]etherman.Block)
@@ -78,8 +124,8 @@ func (_m *ethermanMock) GetRollupInfoByBlockRange(ctx context.Context, fromBlock
 	}
 
 	var r1 map[common.Hash][]etherman.Order
-	if rf, ok := ret.Get(1).(func(context.Context, uint64, *uint64) map[common.Hash][]etherman.Order); ok {
-		r1 = rf(ctx, fromBlock, toBlock)
+	if rf, ok := ret.Get(1).(func(context.Context, state.L2BatchInfo) map[common.Hash][]etherman.Order); ok {
+		r1 = rf(ctx, prevBatch)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(map[common.Hash][]etherman.Order)
@@ -87,8 +133,40 @@ func (_m *ethermanMock) GetRollupInfoByBlockRange(ctx context.Context, fromBlock
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, uint64, *uint64) error); ok {
-		r2 = rf(ctx, fromBlock, toBlock)
+	if rf, ok := ret.Get(2).(func(context.Context, state.L2BatchInfo) error); ok {
+		r2 = rf(ctx, prevBatch)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRollupInfoByBlockRange provides a mock function with given fields: ctx, fromBlock, toBlock, prevBatch, usePreconfirmations
+func (_m *ethermanMock) GetRollupInfoByBlockRange(ctx context.Context, fromBlock uint64, toBlock *uint64, prevBatch state.L2BatchInfo, usePreconfirmations bool) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
+	ret := _m.Called(ctx, fromBlock, toBlock, prevBatch, usePreconfirmations)
+
+	var r0 []etherman.Block
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *uint64, state.L2BatchInfo, bool) []etherman.Block); ok {
+		r0 = rf(ctx, fromBlock, toBlock, prevBatch, usePreconfirmations)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]etherman.Block)
+		}
+	}
+
+	var r1 map[common.Hash][]etherman.Order
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, *uint64, state.L2BatchInfo, bool) map[common.Hash][]etherman.Order); ok {
+		r1 = rf(ctx, fromBlock, toBlock, prevBatch, usePreconfirmations)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(map[common.Hash][]etherman.Order)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, *uint64, state.L2BatchInfo, bool) error); ok {
+		r2 = rf(ctx, fromBlock, toBlock, prevBatch, usePreconfirmations)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -140,6 +218,34 @@ func (_m *ethermanMock) HeaderByNumber(ctx context.Context, number *big.Int) (*t
 	return r0, r1
 }
 
+// ResetL1Cache provides a mock function with given fields: blockNumber
+func (_m *ethermanMock) ResetL1Cache(blockNumber uint64) {
+	_m.Called(blockNumber)
+}
+
+// VerifyBlockHash provides a mock function with given fields: ctx, blockNumber
+func (_m *ethermanMock) VerifyBlockHash(ctx context.Context, blockNumber uint64) (common.Hash, error) {
+	ret := _m.Called(ctx, blockNumber)
+
+	var r0 common.Hash
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) common.Hash); ok {
+		r0 = rf(ctx, blockNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, blockNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // VerifyGenBlockNumber provides a mock function with given fields: ctx, genBlockNumber
 func (_m *ethermanMock) VerifyGenBlockNumber(ctx context.Context, genBlockNumber uint64) (bool, error) {
 	ret := _m.Called(ctx, genBlockNumber)
@@ -16,6 +16,8 @@ import (
 	pgx "github.com/jackc/pgx/v4"
 
 	state "github.com/0xPolygonHermez/zkevm-node/state"
+
+	types "github.com/ethereum/go-ethereum/core/types"
 )
 
 // stateMock is an autogenerated mock type for the stateInterface type
@@ -65,6 +67,20 @@ func (_m *stateMock) AddForcedBatch(ctx context.Context, forcedBatch *state.Forc
 	return r0
 }
 
+// AddForkID provides a mock function with given fields: ctx, forkID, dbTx
+func (_m *stateMock) AddForkID(ctx context.Context, forkID state.ForkIDInterval, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, forkID, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, state.ForkIDInterval, pgx.Tx) error); ok {
+		r0 = rf(ctx, forkID, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddGlobalExitRoot provides a mock function with given fields: ctx, exitRoot, dbTx
 func (_m *stateMock) AddGlobalExitRoot(ctx context.Context, exitRoot *state.GlobalExitRoot, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, exitRoot, dbTx)
@@ -79,6 +95,20 @@ func (_m *stateMock) AddGlobalExitRoot(ctx context.Context, exitRoot *state.Glob
 	return r0
 }
 
+// AddReorg provides a mock function with given fields: ctx, reorg, dbTx
+func (_m *stateMock) AddReorg(ctx context.Context, reorg *state.Reorg, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, reorg, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *state.Reorg, pgx.Tx) error); ok {
+		r0 = rf(ctx, reorg, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddSequence provides a mock function with given fields: ctx, sequence, dbTx
 func (_m *stateMock) AddSequence(ctx context.Context, sequence state.Sequence, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, sequence, dbTx)
@@ -158,6 +188,27 @@ func (_m *stateMock) CloseBatch(ctx context.Context, receipt state.ProcessingRec
 	return r0
 }
 
+// ContainsBlock provides a mock function with given fields: ctx, blockNum, dbTx
+func (_m *stateMock) ContainsBlock(ctx context.Context, blockNum uint64, dbTx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, blockNum, dbTx)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) bool); ok {
+		r0 = rf(ctx, blockNum, dbTx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNum, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExecuteBatch provides a mock function with given fields: ctx, batch, dbTx
 func (_m *stateMock) ExecuteBatch(ctx context.Context, batch state.Batch, dbTx pgx.Tx) (*pb.ProcessBatchResponse, error) {
 	ret := _m.Called(ctx, batch, dbTx)
@@ -181,6 +232,20 @@ func (_m *stateMock) ExecuteBatch(ctx context.Context, batch state.Batch, dbTx p
 	return r0, r1
 }
 
+// FinishReset provides a mock function with given fields: ctx, dbTx
+func (_m *stateMock) FinishReset(ctx context.Context, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) error); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetBatchByNumber provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *stateMock) GetBatchByNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Batch, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -204,6 +269,48 @@ func (_m *stateMock) GetBatchByNumber(ctx context.Context, batchNumber uint64, d
 	return r0, r1
 }
 
+// GetFirstL2BlockNumberFromBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *stateMock) GetFirstL2BlockNumberFromBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLastBatchInfo provides a mock function with given fields: ctx, dbTx
+func (_m *stateMock) GetLastBatchInfo(ctx context.Context, dbTx pgx.Tx) (state.L2BatchInfo, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 state.L2BatchInfo
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) state.L2BatchInfo); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(state.L2BatchInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastBatchNumber provides a mock function with given fields: ctx, dbTx
 func (_m *stateMock) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -335,6 +442,29 @@ func (_m *stateMock) GetNextForcedBatches(ctx context.Context, nextForcedBatches
 	return r0, r1
 }
 
+// GetPendingForcedBatches provides a mock function with given fields: ctx, lastForcedBatchNumber, maxBlockNumber, dbTx
+func (_m *stateMock) GetPendingForcedBatches(ctx context.Context, lastForcedBatchNumber uint64, maxBlockNumber uint64, dbTx pgx.Tx) ([]state.ForcedBatch, error) {
+	ret := _m.Called(ctx, lastForcedBatchNumber, maxBlockNumber, dbTx)
+
+	var r0 []state.ForcedBatch
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) []state.ForcedBatch); ok {
+		r0 = rf(ctx, lastForcedBatchNumber, maxBlockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.ForcedBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, lastForcedBatchNumber, maxBlockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPreviousBlock provides a mock function with given fields: ctx, offset, dbTx
 func (_m *stateMock) GetPreviousBlock(ctx context.Context, offset uint64, dbTx pgx.Tx) (*state.Block, error) {
 	ret := _m.Called(ctx, offset, dbTx)
@@ -358,6 +488,27 @@ func (_m *stateMock) GetPreviousBlock(ctx context.Context, offset uint64, dbTx p
 	return r0, r1
 }
 
+// GetResetInProgress provides a mock function with given fields: ctx, dbTx
+func (_m *stateMock) GetResetInProgress(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStateRootByBatchNumber provides a mock function with given fields: ctx, batchNum, dbTx
 func (_m *stateMock) GetStateRootByBatchNumber(ctx context.Context, batchNum uint64, dbTx pgx.Tx) (common.Hash, error) {
 	ret := _m.Called(ctx, batchNum, dbTx)
@@ -381,6 +532,66 @@ func (_m *stateMock) GetStateRootByBatchNumber(ctx context.Context, batchNum uin
 	return r0, r1
 }
 
+// GetTransactionsFromBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *stateMock) GetTransactionsFromBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]types.Transaction, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	var r0 []types.Transaction
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []types.Transaction); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVirtualBatchesAfterBlock provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *stateMock) GetVirtualBatchesAfterBlock(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]state.VirtualBatch, error) {
+	ret := _m.Called(ctx, blockNumber, dbTx)
+
+	var r0 []state.VirtualBatch
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []state.VirtualBatch); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.VirtualBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadForkIDIntervals provides a mock function with given fields: ctx, dbTx
+func (_m *stateMock) LoadForkIDIntervals(ctx context.Context, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) error); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // OpenBatch provides a mock function with given fields: ctx, processingContext, dbTx
 func (_m *stateMock) OpenBatch(ctx context.Context, processingContext state.ProcessingContext, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, processingContext, dbTx)
@@ -432,18 +643,25 @@ func (_m *stateMock) ProcessSequencerBatch(ctx context.Context, batchNumber uint
 	return r0, r1
 }
 
-// Reset provides a mock function with given fields: ctx, blockNumber, dbTx
-func (_m *stateMock) Reset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) error {
-	ret := _m.Called(ctx, blockNumber, dbTx)
+// ResetStep provides a mock function with given fields: ctx, blockNumber, chunkSize, dbTx
+func (_m *stateMock) ResetStep(ctx context.Context, blockNumber uint64, chunkSize uint64, dbTx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, blockNumber, chunkSize, dbTx)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) error); ok {
-		r0 = rf(ctx, blockNumber, dbTx)
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) bool); ok {
+		r0 = rf(ctx, blockNumber, chunkSize, dbTx)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(bool)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, chunkSize, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // ResetTrustedState provides a mock function with given fields: ctx, batchNumber, dbTx
@@ -483,6 +701,20 @@ func (_m *stateMock) SetGenesis(ctx context.Context, block state.Block, genesis
 	return r0, r1
 }
 
+// StartReset provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *stateMock) StartReset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, blockNumber, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StoreTransactions provides a mock function with given fields: ctx, batchNum, processedTxs, dbTx
 func (_m *stateMock) StoreTransactions(ctx context.Context, batchNum uint64, processedTxs []*state.ProcessTransactionResponse, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, batchNum, processedTxs, dbTx)
@@ -497,6 +729,27 @@ func (_m *stateMock) StoreTransactions(ctx context.Context, batchNum uint64, pro
 	return r0
 }
 
+// UpdateVirtualBatchesL1Finality provides a mock function with given fields: ctx, l1Finality, blockNumber, dbTx
+func (_m *stateMock) UpdateVirtualBatchesL1Finality(ctx context.Context, l1Finality state.L1Finality, blockNumber uint64, dbTx pgx.Tx) (int64, error) {
+	ret := _m.Called(ctx, l1Finality, blockNumber, dbTx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, state.L1Finality, uint64, pgx.Tx) int64); ok {
+		r0 = rf(ctx, l1Finality, blockNumber, dbTx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, state.L1Finality, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, l1Finality, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTnewStateMock interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,206 @@
+package synchronizer
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/metrics"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// chunk is a range of L1 blocks downloaded by the sync pipeline along with
+// its rollup info
+type chunk struct {
+	fromBlock uint64
+	toBlock   uint64
+	blocks    []etherman.Block
+	order     map[common.Hash][]etherman.Order
+	err       error
+	// done is closed once the chunk is downloaded
+	done chan struct{}
+}
+
+// usePipeline indicates if the chunks of L1 blocks are downloaded ahead of
+// their execution
+func (s *ClientSynchronizer) usePipeline() bool {
+	return s.cfg.SyncPipelineBufferSize > 0
+}
+
+// downloadWorkers returns the number of chunks downloaded in parallel. The
+// sequences of a chunk are decoded from the last batch of the previous one, so
+// they can only be downloaded in parallel when the sequences are taken from
+// the preconfirmations instead of L1
+func (s *ClientSynchronizer) downloadWorkers() uint64 {
+	if s.cfg.SyncPipelineDownloadWorkers <= 1 || !s.usePreconfirmations() {
+		return 1
+	}
+	return s.cfg.SyncPipelineDownloadWorkers
+}
+
+// syncBlocksPipelined syncs the L1 blocks from fromBlock to lastKnownBlock,
+// downloading the chunks into a bounded buffer while the previous ones are
+// executed. The buffered chunks are dropped if L1 reorgs before executing them
+func (s *ClientSynchronizer) syncBlocksPipelined(lastEthBlockSynced *state.Block, fromBlock, lastKnownBlock uint64) (*state.Block, error) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer func() {
+		cancel()
+		metrics.PipelineBufferDepth(0)
+	}()
+
+	chunks := make(chan *chunk, s.cfg.SyncPipelineBufferSize)
+	go s.downloadChunks(ctx, chunks, fromBlock, lastKnownBlock)
+
+	for c := range chunks {
+		metrics.PipelineBufferDepth(len(chunks))
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return lastEthBlockSynced, ctx.Err()
+		}
+		if c.err != nil {
+			return lastEthBlockSynced, c.err
+		}
+
+		if len(c.blocks) > 0 {
+			// L1 may have reorged while the chunk was waiting in the buffer,
+			// both the stored blocks and the downloaded ones are checked
+			block, reorg, err := s.checkReorg(lastEthBlockSynced)
+			if err != nil {
+				log.Errorf("error checking reorgs. Retrying... Err: %w", err)
+				return lastEthBlockSynced, err
+			}
+			if block != nil {
+				log.Infof("reorg detected, dropping the chunks downloaded from L1 block %d", c.fromBlock)
+				if err = s.resetState(block.BlockNumber, reorg); err != nil {
+					log.Errorf("error resetting the state to a previous block. Retrying... Err: %w", err)
+					return lastEthBlockSynced, err
+				}
+				return block, nil
+			}
+			lastBlock := c.blocks[len(c.blocks)-1]
+			fb, err := s.etherMan.EthBlockByNumber(s.ctx, lastBlock.BlockNumber)
+			if err != nil {
+				return lastEthBlockSynced, err
+			}
+			if fb.Hash() != lastBlock.BlockHash {
+				log.Infof("L1 block %d reorged after being downloaded, dropping the chunks downloaded from L1 block %d", lastBlock.BlockNumber, c.fromBlock)
+				return lastEthBlockSynced, nil
+			}
+		}
+
+		start := time.Now()
+		if err := s.processBlockRange(c.blocks, c.order, s.l1Finality()); err != nil {
+			return lastEthBlockSynced, err
+		}
+		metrics.PipelineStage(metrics.ExecutionStage, c.toBlock-c.fromBlock+1, time.Since(start))
+
+		if len(c.blocks) > 0 {
+			lastBlock := c.blocks[len(c.blocks)-1]
+			lastEthBlockSynced = &state.Block{
+				BlockNumber: lastBlock.BlockNumber,
+				BlockHash:   lastBlock.BlockHash,
+				ParentHash:  lastBlock.ParentHash,
+				ReceivedAt:  lastBlock.ReceivedAt,
+			}
+			s.setLastL1BlockSynced(lastEthBlockSynced.BlockNumber)
+		}
+	}
+
+	waitDuration = s.cfg.SyncInterval.Duration
+	return lastEthBlockSynced, nil
+}
+
+// downloadChunks downloads the chunks of L1 blocks from fromBlock to
+// lastKnownBlock and sends them in order to the channel, blocking while it is
+// full, until all of them are sent, one fails or the context is cancelled
+func (s *ClientSynchronizer) downloadChunks(ctx context.Context, chunks chan<- *chunk, fromBlock, lastKnownBlock uint64) {
+	defer close(chunks)
+
+	prevBatch, err := s.state.GetLastBatchInfo(ctx, nil)
+	if err != nil {
+		log.Warn("error getting latest batch synced. Error: ", err)
+		c := &chunk{fromBlock: fromBlock, err: err, done: make(chan struct{})}
+		close(c.done)
+		chunks <- c
+		return
+	}
+
+	workers := s.downloadWorkers()
+	sem := make(chan struct{}, workers)
+	for from := fromBlock; from <= lastKnownBlock; {
+		to := from + s.cfg.SyncChunkSize
+		if to > lastKnownBlock {
+			to = lastKnownBlock
+		}
+		c := &chunk{fromBlock: from, toBlock: to, done: make(chan struct{})}
+		select {
+		case chunks <- c:
+		case <-ctx.Done():
+			return
+		}
+		metrics.PipelineBufferDepth(len(chunks))
+
+		if workers == 1 {
+			s.downloadChunk(ctx, c, &prevBatch, lastKnownBlock)
+			if c.err != nil {
+				return
+			}
+		} else {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(c *chunk, prevBatch state.L2BatchInfo) {
+				defer func() { <-sem }()
+				s.downloadChunk(ctx, c, &prevBatch, lastKnownBlock)
+			}(c, prevBatch)
+		}
+		from = to + 1
+	}
+}
+
+// downloadChunk downloads the rollup info of the chunk, leaving in prevBatch
+// the last batch decoded
+func (s *ClientSynchronizer) downloadChunk(ctx context.Context, c *chunk, prevBatch *state.L2BatchInfo, lastKnownBlock uint64) {
+	defer close(c.done)
+	start := time.Now()
+
+	log.Infof("Getting rollup info from L1 block %d to block %d", c.fromBlock, c.toBlock)
+	c.blocks, c.order, c.err = s.etherMan.GetRollupInfoByBlockRange(ctx, c.fromBlock, &c.toBlock, *prevBatch, s.usePreconfirmations())
+	if c.err != nil {
+		return
+	}
+	if len(c.blocks) == 0 && c.toBlock < lastKnownBlock {
+		// If there is no events in the checked blocks range, the latest block
+		// of the range is stored so the sync progresses
+		fb, err := s.etherMan.EthBlockByNumber(ctx, c.toBlock)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.blocks = []etherman.Block{{
+			BlockNumber: fb.NumberU64(),
+			BlockHash:   fb.Hash(),
+			ParentHash:  fb.ParentHash(),
+			ReceivedAt:  time.Unix(int64(fb.Time()), 0),
+		}}
+	}
+
+	for _, block := range c.blocks {
+		for _, sequence := range block.SequencedBatches {
+			for _, batch := range sequence {
+				*prevBatch = state.L2BatchInfo{
+					Number:         batch.BatchNumber,
+					L1Block:        batch.BlockNumber,
+					Timestamp:      batch.Timestamp,
+					ForcedBatchNum: prevBatch.ForcedBatchNum,
+				}
+			}
+		}
+	}
+	metrics.PipelineStage(metrics.DownloadStage, c.toBlock-c.fromBlock+1, time.Since(start))
+}
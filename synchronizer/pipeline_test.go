@@ -0,0 +1,309 @@
+package synchronizer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testL1Chain is an L1 chain served by the etherman mock, its blocks can be
+// replaced to simulate a reorg while the pipeline is running
+type testL1Chain struct {
+	mu     sync.Mutex
+	blocks map[uint64]*types.Block
+}
+
+func newTestL1Chain(length uint64) *testL1Chain {
+	c := &testL1Chain{blocks: make(map[uint64]*types.Block)}
+	c.reorg(0, length, nil)
+	return c
+}
+
+// reorg replaces the blocks from fromBlock to toBlock, the extra data makes
+// their hashes different from the previous ones
+func (c *testL1Chain) reorg(fromBlock, toBlock uint64, extra []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for n := fromBlock; n <= toBlock; n++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(n), Extra: extra}
+		if n > 0 {
+			header.ParentHash = c.blocks[n-1].Hash()
+		}
+		c.blocks[n] = types.NewBlockWithHeader(header)
+	}
+}
+
+func (c *testL1Chain) block(n uint64) *types.Block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.blocks[n]
+}
+
+func (c *testL1Chain) stateBlock(n uint64) *state.Block {
+	b := c.block(n)
+	return &state.Block{BlockNumber: n, BlockHash: b.Hash(), ParentHash: b.ParentHash(), ReceivedAt: time.Unix(int64(b.Time()), 0)}
+}
+
+func (c *testL1Chain) ethermanBlock(n uint64) etherman.Block {
+	b := c.block(n)
+	return etherman.Block{BlockNumber: n, BlockHash: b.Hash(), ParentHash: b.ParentHash(), ReceivedAt: time.Unix(int64(b.Time()), 0)}
+}
+
+// expectChunk sets the rollup info of the chunk from fromBlock to toBlock to
+// its last block
+func (c *testL1Chain) expectChunk(m *mocks, fromBlock, toBlock uint64, usePreconfirmations bool) *mock.Call {
+	return m.Etherman.
+		On("GetRollupInfoByBlockRange", mock.Anything, fromBlock, &toBlock, state.L2BatchInfo{}, usePreconfirmations).
+		Return([]etherman.Block{c.ethermanBlock(toBlock)}, map[common.Hash][]etherman.Order{}, nil).
+		Once()
+}
+
+// expectProcessedBlocks records the L1 blocks stored by the synchronizer,
+// onBlock is called with each one if set
+func expectProcessedBlocks(m *mocks, onBlock func(uint64)) *[]uint64 {
+	var mu sync.Mutex
+	var processed []uint64
+	m.State.
+		On("BeginStateTransaction", mock.Anything).
+		Return(m.DbTx, nil)
+	m.State.
+		On("AddBlock", mock.Anything, mock.Anything, m.DbTx).
+		Run(func(args mock.Arguments) {
+			mu.Lock()
+			defer mu.Unlock()
+			n := args.Get(1).(*state.Block).BlockNumber
+			processed = append(processed, n)
+			if onBlock != nil {
+				onBlock(n)
+			}
+		}).
+		Return(nil)
+	m.DbTx.
+		On("Commit", mock.Anything).
+		Return(nil)
+	return &processed
+}
+
+func expectL1Chain(m *mocks, chain *testL1Chain) {
+	m.Etherman.
+		On("EthBlockByNumber", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, n uint64) *types.Block { return chain.block(n) }, nil)
+}
+
+func TestSyncBlocksPipelinedOrder(t *testing.T) {
+	testCases := []struct {
+		name    string
+		workers uint64
+		preconf bool
+	}{
+		{name: "single worker", workers: 1},
+		{name: "parallel workers", workers: 3, preconf: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{SyncPipelineBufferSize: 2, SyncPipelineDownloadWorkers: tc.workers}
+			if tc.preconf {
+				cfg.PreconfirmationsSyncInterval = cfgTypes.Duration{Duration: time.Second}
+			}
+			sync, m := newTestSynchronizer(t, cfg)
+			chain := newTestL1Chain(25)
+			expectL1Chain(m, chain)
+			processed := expectProcessedBlocks(m, nil)
+
+			m.State.
+				On("GetLastBatchInfo", mock.Anything, nil).
+				Return(state.L2BatchInfo{}, nil).
+				Once()
+			// the first chunk is downloaded last, it is executed first anyway
+			chain.expectChunk(m, 1, 11, tc.preconf).After(50 * time.Millisecond)
+			chain.expectChunk(m, 12, 22, tc.preconf)
+			chain.expectChunk(m, 23, 25, tc.preconf)
+
+			lastBlock, err := sync.syncBlocksPipelined(chain.stateBlock(0), 1, 25)
+			require.NoError(t, err)
+			assert.Equal(t, chain.stateBlock(25), lastBlock)
+			assert.Equal(t, []uint64{11, 22, 25}, *processed)
+			assert.Equal(t, uint64(25), sync.lastL1BlockSynced)
+		})
+	}
+}
+
+func TestSyncBlocksPipelinedReorg(t *testing.T) {
+	t.Run("downloaded block reorged", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{SyncPipelineBufferSize: 2})
+		chain := newTestL1Chain(25)
+		expectL1Chain(m, chain)
+		processed := expectProcessedBlocks(m, nil)
+
+		m.State.
+			On("GetLastBatchInfo", mock.Anything, nil).
+			Return(state.L2BatchInfo{}, nil).
+			Once()
+		chain.expectChunk(m, 1, 11, false)
+		// the block 22 is reorged once the second chunk is downloaded
+		chain.expectChunk(m, 12, 22, false).
+			Run(func(args mock.Arguments) { chain.reorg(22, 25, []byte("reorg")) })
+		chain.expectChunk(m, 23, 25, false).Maybe()
+
+		lastBlock, err := sync.syncBlocksPipelined(chain.stateBlock(0), 1, 25)
+		require.NoError(t, err)
+		assert.Equal(t, chain.stateBlock(11), lastBlock)
+		assert.Equal(t, []uint64{11}, *processed)
+	})
+
+	t.Run("stored block reorged", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{SyncPipelineBufferSize: 2})
+		chain := newTestL1Chain(25)
+		expectL1Chain(m, chain)
+		storedBlock := chain.stateBlock(11)
+		// the block 11 is reorged once it is stored, before executing the
+		// second chunk
+		processed := expectProcessedBlocks(m, func(n uint64) {
+			if n == 11 {
+				chain.reorg(11, 25, []byte("reorg"))
+			}
+		})
+
+		m.State.
+			On("GetLastBatchInfo", mock.Anything, nil).
+			Return(state.L2BatchInfo{}, nil).
+			Once()
+		chain.expectChunk(m, 1, 11, false)
+		chain.expectChunk(m, 12, 22, false)
+		chain.expectChunk(m, 23, 25, false).Maybe()
+
+		m.Etherman.
+			On("VerifyBlockHash", mock.Anything, uint64(11)).
+			Return(func(ctx context.Context, n uint64) common.Hash { return chain.block(n).Hash() }, nil).
+			Once()
+		m.State.
+			On("GetPreviousBlock", mock.Anything, uint64(1), m.DbTx).
+			Return(chain.stateBlock(0), nil).
+			Once()
+		m.State.
+			On("GetVirtualBatchesAfterBlock", mock.Anything, uint64(0), m.DbTx).
+			Return([]state.VirtualBatch{}, nil).
+			Once()
+		m.State.
+			On("AddReorg", mock.Anything, mock.MatchedBy(func(reorg *state.Reorg) bool {
+				return reorg.Kind == state.ReorgKindL1 && reorg.FirstBlockNumber == 11 && reorg.OldHash == storedBlock.BlockHash && reorg.Depth == 11
+			}), m.DbTx).
+			Return(nil).
+			Once()
+		m.State.
+			On("StartReset", mock.Anything, uint64(0), m.DbTx).
+			Return(nil).
+			Once()
+		m.EthTxManager.
+			On("Reorg", mock.Anything, uint64(1), m.DbTx).
+			Return(nil).
+			Once()
+		m.State.
+			On("ResetStep", mock.Anything, uint64(0), uint64(0), m.DbTx).
+			Return(true, nil).
+			Once()
+		m.State.
+			On("FinishReset", mock.Anything, m.DbTx).
+			Return(nil).
+			Once()
+		m.Etherman.
+			On("ResetL1Cache", uint64(0)).
+			Return().
+			Once()
+		var nilDbTx pgx.Tx
+		m.State.
+			On("LoadForkIDIntervals", mock.Anything, nilDbTx).
+			Return(nil).
+			Once()
+
+		// the chunks downloaded after the block 11 are dropped
+		lastBlock, err := sync.syncBlocksPipelined(chain.stateBlock(0), 1, 25)
+		require.NoError(t, err)
+		assert.Equal(t, chain.stateBlock(0), lastBlock)
+		assert.Equal(t, []uint64{11}, *processed)
+		assert.Equal(t, uint64(0), sync.lastL1BlockSynced)
+	})
+}
+
+func TestSyncBlocksPipelinedError(t *testing.T) {
+	t.Run("last batch error", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{SyncPipelineBufferSize: 2})
+		chain := newTestL1Chain(25)
+		m.State.
+			On("GetLastBatchInfo", mock.Anything, nil).
+			Return(state.L2BatchInfo{}, errors.New("db error")).
+			Once()
+
+		lastBlock, err := sync.syncBlocksPipelined(chain.stateBlock(0), 1, 25)
+		require.EqualError(t, err, "db error")
+		assert.Equal(t, chain.stateBlock(0), lastBlock)
+	})
+
+	t.Run("download error", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{SyncPipelineBufferSize: 2})
+		chain := newTestL1Chain(25)
+		expectL1Chain(m, chain)
+		processed := expectProcessedBlocks(m, nil)
+
+		m.State.
+			On("GetLastBatchInfo", mock.Anything, nil).
+			Return(state.L2BatchInfo{}, nil).
+			Once()
+		chain.expectChunk(m, 1, 11, false)
+		toBlock := uint64(22)
+		m.Etherman.
+			On("GetRollupInfoByBlockRange", mock.Anything, uint64(12), &toBlock, state.L2BatchInfo{}, false).
+			Return(nil, nil, errors.New("L1 error")).
+			Once()
+
+		// the chunks after the failed one are not downloaded
+		lastBlock, err := sync.syncBlocksPipelined(chain.stateBlock(0), 1, 25)
+		require.EqualError(t, err, "L1 error")
+		assert.Equal(t, chain.stateBlock(11), lastBlock)
+		assert.Equal(t, []uint64{11}, *processed)
+	})
+
+	t.Run("execution error", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{SyncPipelineBufferSize: 2})
+		chain := newTestL1Chain(25)
+		expectL1Chain(m, chain)
+
+		m.State.
+			On("GetLastBatchInfo", mock.Anything, nil).
+			Return(state.L2BatchInfo{}, nil).
+			Once()
+		chain.expectChunk(m, 1, 11, false)
+		chain.expectChunk(m, 12, 22, false).Maybe()
+		chain.expectChunk(m, 23, 25, false).Maybe()
+		m.State.
+			On("BeginStateTransaction", mock.Anything).
+			Return(m.DbTx, nil).
+			Once()
+		m.State.
+			On("AddBlock", mock.Anything, chain.stateBlock(11), m.DbTx).
+			Return(errors.New("db error")).
+			Once()
+		m.DbTx.
+			On("Rollback", mock.Anything).
+			Return(nil).
+			Once()
+
+		lastBlock, err := sync.syncBlocksPipelined(chain.stateBlock(0), 1, 25)
+		require.EqualError(t, err, "db error")
+		assert.Equal(t, chain.stateBlock(0), lastBlock)
+	})
+}
@@ -0,0 +1,603 @@
+package synchronizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/l1client"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// record makes the replay tests record their fixtures again against the
+// simulated L1 and HotShot query service before replaying them:
+//
+//	go test ./synchronizer -run TestSyncReplay -record
+var record = flag.Bool("record", false, "record the fixtures of the replay tests again")
+
+var (
+	testReplayPoEAddr            = common.HexToAddress("0x1000")
+	testReplayGlobalExitRootAddr = common.HexToAddress("0x2000")
+	testReplayHotShotAddr        = common.HexToAddress("0x3000")
+	testReplayMaticAddr          = common.HexToAddress("0x4000")
+	testReplayGlobalExitRoot     = common.HexToHash("0x4e5")
+	testReplayChainID            = big.NewInt(1337)
+
+	lastBatchSequencedSelector    = crypto.Keccak256([]byte("lastBatchSequenced()"))[:4]
+	getLastGlobalExitRootSelector = crypto.Keccak256([]byte("getLastGlobalExitRoot()"))[:4]
+)
+
+// testHotShotBlocks are the blocks served by the simulated HotShot query
+// service, the batch n is derived from the block n
+var testHotShotBlocks = map[uint64]etherman.SequencerBlock{
+	1: {Height: 1, L1Block: 2, Timestamp: 1000, Transactions: "0x"},
+	2: {Height: 2, L1Block: 3, Timestamp: 1012, Transactions: "0x"},
+	3: {Height: 3, L1Block: 5, Timestamp: 1024, Transactions: "0x"},
+}
+
+// testNewBlocks is a NewBlocks event of the HotShot contract, emitted by the
+// tx at the L1 block
+type testNewBlocks struct {
+	blockNumber uint64
+	first       uint64
+	count       uint64
+	tx          *types.Transaction
+}
+
+// testReplayL1 is a simulated L1 chain served over JSON RPC, with the
+// methods used by the synchronizer. The HotShot blocks 1 and 2 are sequenced
+// at the block 3 and the block 3 at the block 6. Once the synchronizer has
+// synced it, the chain is reorged from the block 6 and the last HotShot block
+// is sequenced again at the block 7.
+type testReplayL1 struct {
+	mu        sync.Mutex
+	blocks    []*types.Block
+	newBlocks []testNewBlocks
+	reorged   bool
+}
+
+func newTestReplayL1(t *testing.T) *testReplayL1 {
+	key, err := crypto.HexToECDSA("28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e")
+	require.NoError(t, err)
+	signer := types.NewLondonSigner(testReplayChainID)
+	newTx := func(nonce uint64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   testReplayChainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(1),
+			Gas:       100000,
+			To:        &testReplayHotShotAddr,
+		})
+	}
+
+	l1 := &testReplayL1{
+		newBlocks: []testNewBlocks{
+			{blockNumber: 3, first: 1, count: 2, tx: newTx(0)},
+			{blockNumber: 6, first: 3, count: 1, tx: newTx(1)},
+		},
+	}
+	l1.build(0, 10, nil)
+	return l1
+}
+
+// build replaces the blocks from fromBlock to toBlock, the extra data makes
+// their hashes different from the previous ones
+func (l1 *testReplayL1) build(fromBlock, toBlock uint64, extra []byte) {
+	l1.blocks = l1.blocks[:fromBlock]
+	for n := fromBlock; n <= toBlock; n++ {
+		header := &types.Header{
+			Number:      new(big.Int).SetUint64(n),
+			Time:        1000 + 12*n,
+			Difficulty:  big.NewInt(0),
+			UncleHash:   types.EmptyUncleHash,
+			TxHash:      types.EmptyTxsHash,
+			ReceiptHash: types.EmptyReceiptsHash,
+			Extra:       extra,
+		}
+		if n > 0 {
+			header.ParentHash = l1.blocks[n-1].Hash()
+		}
+		l1.blocks = append(l1.blocks, types.NewBlockWithHeader(header))
+	}
+}
+
+// reorg replaces the blocks from the block 6 and extends the chain up to the
+// block 12, the last NewBlocks event is moved to the block 7
+func (l1 *testReplayL1) reorg() {
+	l1.build(6, 12, []byte("reorg"))
+	l1.newBlocks[1].blockNumber = 7
+	l1.reorged = true
+}
+
+func (l1 *testReplayL1) block(number rpc.BlockNumber) *types.Block {
+	if number < 0 {
+		return l1.blocks[len(l1.blocks)-1]
+	}
+	if int(number) >= len(l1.blocks) {
+		return nil
+	}
+	return l1.blocks[number]
+}
+
+// GetBlockByNumber serves the blocks, without txs as the synchronizer reads
+// them by hash
+func (l1 *testReplayL1) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	l1.mu.Lock()
+	defer l1.mu.Unlock()
+	block := l1.block(number)
+	if block == nil {
+		return nil, nil
+	}
+	fields, err := toJSONFields(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	fields["transactions"] = []interface{}{}
+	fields["uncles"] = []interface{}{}
+	return fields, nil
+}
+
+// testLogFilter is the filter of the eth_getLogs requests
+type testLogFilter struct {
+	FromBlock rpc.BlockNumber  `json:"fromBlock"`
+	ToBlock   rpc.BlockNumber  `json:"toBlock"`
+	Addresses []common.Address `json:"address"`
+	Topics    [][]common.Hash  `json:"topics"`
+}
+
+// GetLogs serves the NewBlocks events
+func (l1 *testReplayL1) GetLogs(filter testLogFilter) ([]types.Log, error) {
+	l1.mu.Lock()
+	defer l1.mu.Unlock()
+	toBlock := l1.block(filter.ToBlock).NumberU64()
+	logs := []types.Log{}
+	for i, newBlocks := range l1.newBlocks {
+		vLog := types.Log{
+			Address:     testReplayHotShotAddr,
+			Topics:      []common.Hash{crypto.Keccak256Hash([]byte("NewBlocks(uint256,uint256)"))},
+			Data:        append(common.BigToHash(new(big.Int).SetUint64(newBlocks.first)).Bytes(), common.BigToHash(new(big.Int).SetUint64(newBlocks.count)).Bytes()...),
+			BlockNumber: newBlocks.blockNumber,
+			TxHash:      newBlocks.tx.Hash(),
+			BlockHash:   l1.blocks[newBlocks.blockNumber].Hash(),
+			Index:       uint(i),
+		}
+		if vLog.BlockNumber >= uint64(filter.FromBlock) && vLog.BlockNumber <= toBlock && matchesLogFilter(vLog, filter) {
+			logs = append(logs, vLog)
+		}
+	}
+	return logs, nil
+}
+
+func matchesLogFilter(vLog types.Log, filter testLogFilter) bool {
+	if len(filter.Addresses) > 0 && !containsAddress(filter.Addresses, vLog.Address) {
+		return false
+	}
+	for i, topics := range filter.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		if i >= len(vLog.Topics) || !containsHash(topics, vLog.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTransactionByHash serves the txs that emitted the NewBlocks events
+func (l1 *testReplayL1) GetTransactionByHash(hash common.Hash) (map[string]interface{}, error) {
+	l1.mu.Lock()
+	defer l1.mu.Unlock()
+	for _, newBlocks := range l1.newBlocks {
+		if newBlocks.tx.Hash() != hash {
+			continue
+		}
+		fields, err := toJSONFields(newBlocks.tx)
+		if err != nil {
+			return nil, err
+		}
+		from, err := types.Sender(types.NewLondonSigner(testReplayChainID), newBlocks.tx)
+		if err != nil {
+			return nil, err
+		}
+		fields["blockNumber"] = hexutil.EncodeUint64(newBlocks.blockNumber)
+		fields["blockHash"] = l1.blocks[newBlocks.blockNumber].Hash()
+		fields["from"] = from
+		return fields, nil
+	}
+	return nil, nil
+}
+
+// GetCode serves the code of the PolygonZkEVM and GlobalExitRootManager
+// contracts, deployed at the block 1
+func (l1 *testReplayL1) GetCode(address common.Address, number rpc.BlockNumber) (hexutil.Bytes, error) {
+	if number == 0 || (address != testReplayPoEAddr && address != testReplayGlobalExitRootAddr) {
+		return hexutil.Bytes{}, nil
+	}
+	return hexutil.Bytes{0x60, 0x80}, nil
+}
+
+// testCallArgs are the args of the eth_call requests
+type testCallArgs struct {
+	To   common.Address `json:"to"`
+	Data hexutil.Bytes  `json:"data"`
+}
+
+// Call serves the last batch sequenced and the last global exit root, the
+// chain is reorged once the synchronizer has asked for the last batch
+// sequenced, that is once it has synced the chain
+func (l1 *testReplayL1) Call(args testCallArgs, number rpc.BlockNumber) (hexutil.Bytes, error) {
+	l1.mu.Lock()
+	defer l1.mu.Unlock()
+	switch {
+	case args.To == testReplayPoEAddr && bytes.Equal(args.Data, lastBatchSequencedSelector):
+		if !l1.reorged {
+			l1.reorg()
+		}
+		return common.BigToHash(big.NewInt(int64(len(testHotShotBlocks)))).Bytes(), nil
+	case args.To == testReplayGlobalExitRootAddr && bytes.Equal(args.Data, getLastGlobalExitRootSelector):
+		return testReplayGlobalExitRoot.Bytes(), nil
+	}
+	return nil, errors.New("execution reverted")
+}
+
+// toJSONFields returns the fields of the JSON encoding of the value
+func toJSONFields(value interface{}) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	return fields, json.Unmarshal(encoded, &fields)
+}
+
+// startTestReplayL1 serves the simulated L1 and HotShot query service,
+// returning their URLs
+func startTestReplayL1(t *testing.T, l1 *testReplayL1) (string, string) {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("eth", l1))
+	l1Srv := httptest.NewServer(srv)
+	t.Cleanup(l1Srv.Close)
+	t.Cleanup(srv.Stop)
+
+	hotShotSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		height, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/availability/block/"), 10, 64)
+		block, ok := testHotShotBlocks[height]
+		if err != nil || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(block)
+	}))
+	t.Cleanup(hotShotSrv.Close)
+	return l1Srv.URL, hotShotSrv.URL
+}
+
+// testReplayState is an in memory state, it stores the blocks, batches and
+// reorgs synced through the state mock
+type testReplayState struct {
+	mu             sync.Mutex
+	blocks         []state.Block
+	batches        []state.ProcessingContext
+	virtualBatches []state.VirtualBatch
+	reorgs         []state.Reorg
+}
+
+func (s *testReplayState) setGenesis(ctx context.Context, block state.Block, genesis state.Genesis, dbTx pgx.Tx) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocks = []state.Block{block}
+	return state.ZeroHash.Bytes()
+}
+
+func (s *testReplayState) addBlock(ctx context.Context, block *state.Block, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocks = append(s.blocks, *block)
+	sort.Slice(s.blocks, func(i, j int) bool { return s.blocks[i].BlockNumber < s.blocks[j].BlockNumber })
+	return nil
+}
+
+func (s *testReplayState) containsBlock(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, block := range s.blocks {
+		if block.BlockNumber == blockNumber {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *testReplayState) previousBlock(ctx context.Context, offset uint64, dbTx pgx.Tx) *state.Block {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if offset >= uint64(len(s.blocks)) {
+		return nil
+	}
+	block := s.blocks[uint64(len(s.blocks))-1-offset]
+	return &block
+}
+
+func (s *testReplayState) previousBlockErr(ctx context.Context, offset uint64, dbTx pgx.Tx) error {
+	if s.previousBlock(ctx, offset, dbTx) == nil {
+		return state.ErrNotFound
+	}
+	return nil
+}
+
+func (s *testReplayState) processAndStoreClosedBatch(ctx context.Context, processingCtx state.ProcessingContext, encodedTxs []byte, dbTx pgx.Tx, caller state.CallerLabel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, processingCtx)
+	return nil
+}
+
+func (s *testReplayState) addVirtualBatch(ctx context.Context, virtualBatch *state.VirtualBatch, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.virtualBatches = append(s.virtualBatches, *virtualBatch)
+	return nil
+}
+
+func (s *testReplayState) lastBatchInfo(ctx context.Context, dbTx pgx.Tx) state.L2BatchInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.virtualBatches) == 0 {
+		return state.L2BatchInfo{}
+	}
+	last := s.virtualBatches[len(s.virtualBatches)-1]
+	return state.L2BatchInfo{
+		Number:         last.BatchNumber,
+		L1Block:        last.BlockNumber,
+		Timestamp:      uint64(s.batches[len(s.batches)-1].Timestamp.Unix()),
+		ForcedBatchNum: last.LastForcedBatchNum,
+	}
+}
+
+func (s *testReplayState) lastBatchNumber(ctx context.Context, dbTx pgx.Tx) uint64 {
+	return s.lastBatchInfo(ctx, dbTx).Number
+}
+
+func (s *testReplayState) virtualBatchesAfterBlock(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) []state.VirtualBatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var virtualBatches []state.VirtualBatch
+	for _, virtualBatch := range s.virtualBatches {
+		if virtualBatch.BlockNumber > blockNumber || virtualBatch.SeenAt > blockNumber {
+			virtualBatches = append(virtualBatches, virtualBatch)
+		}
+	}
+	return virtualBatches
+}
+
+func (s *testReplayState) addReorg(ctx context.Context, reorg *state.Reorg, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reorgs = append(s.reorgs, *reorg)
+	return nil
+}
+
+// startReset removes the batches seen after the block, as the reset of the
+// state does when it starts
+func (s *testReplayState) startReset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, virtualBatch := range s.virtualBatches {
+		if virtualBatch.BlockNumber > blockNumber || virtualBatch.SeenAt > blockNumber {
+			s.virtualBatches = s.virtualBatches[:i]
+			s.batches = s.batches[:i]
+			break
+		}
+	}
+	return nil
+}
+
+// resetStep removes the blocks after the block in a single step
+func (s *testReplayState) resetStep(ctx context.Context, blockNumber uint64, chunkSize uint64, dbTx pgx.Tx) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, block := range s.blocks {
+		if block.BlockNumber > blockNumber {
+			s.blocks = s.blocks[:i]
+			break
+		}
+	}
+	return true
+}
+
+// synced checks if the blocks up to blockNumber, the batches up to
+// batchNumber and the reorgs are synced
+func (s *testReplayState) synced(blockNumber, batchNumber uint64, reorgs int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.blocks) > 0 && s.blocks[len(s.blocks)-1].BlockNumber == blockNumber &&
+		len(s.virtualBatches) > 0 && s.virtualBatches[len(s.virtualBatches)-1].BatchNumber == batchNumber &&
+		len(s.reorgs) == reorgs
+}
+
+func expectReplayState(m *mocks) *testReplayState {
+	s := &testReplayState{}
+	m.State.On("GetResetInProgress", mock.Anything, mock.Anything).Return(uint64(0), state.ErrNotFound).Once()
+	m.State.On("BeginStateTransaction", mock.Anything).Return(m.DbTx, nil).Maybe()
+	m.State.On("GetLastBlock", mock.Anything, mock.Anything).Return(nil, state.ErrStateNotSynchronized).Once()
+	m.State.On("SetGenesis", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(s.setGenesis, nil).Once()
+	m.State.On("LoadForkIDIntervals", mock.Anything, mock.Anything).Return(nil).Maybe()
+	m.State.On("AddBlock", mock.Anything, mock.Anything, mock.Anything).Return(s.addBlock).Maybe()
+	m.State.On("ContainsBlock", mock.Anything, mock.Anything, mock.Anything).Return(s.containsBlock, nil).Maybe()
+	m.State.On("GetPreviousBlock", mock.Anything, mock.Anything, mock.Anything).Return(s.previousBlock, s.previousBlockErr).Maybe()
+	m.State.On("GetLastBatchInfo", mock.Anything, mock.Anything).Return(s.lastBatchInfo, nil).Maybe()
+	m.State.On("GetLastBatchNumber", mock.Anything, mock.Anything).Return(s.lastBatchNumber, nil).Maybe()
+	m.State.On("ExecuteBatch", mock.Anything, mock.Anything, mock.Anything).Return(&pb.ProcessBatchResponse{}, nil).Maybe()
+	m.State.On("GetBatchByNumber", mock.Anything, mock.Anything, mock.Anything).Return(nil, state.ErrNotFound).Maybe()
+	m.State.On("ProcessAndStoreClosedBatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, state.SynchronizerCallerLabel).Return(s.processAndStoreClosedBatch).Maybe()
+	m.State.On("AddVirtualBatch", mock.Anything, mock.Anything, mock.Anything).Return(s.addVirtualBatch).Maybe()
+	m.State.On("AddSequence", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	m.State.On("UpdateVirtualBatchesL1Finality", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(0), nil).Maybe()
+	m.State.On("GetVirtualBatchesAfterBlock", mock.Anything, mock.Anything, mock.Anything).Return(s.virtualBatchesAfterBlock, nil).Maybe()
+	m.State.On("GetTransactionsFromBatchNumber", mock.Anything, mock.Anything, mock.Anything).Return([]types.Transaction{}, nil).Maybe()
+	m.State.On("AddReorg", mock.Anything, mock.Anything, mock.Anything).Return(s.addReorg).Maybe()
+	m.State.On("StartReset", mock.Anything, mock.Anything, mock.Anything).Return(s.startReset).Maybe()
+	m.State.On("ResetStep", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(s.resetStep, nil).Maybe()
+	m.State.On("FinishReset", mock.Anything, mock.Anything).Return(nil).Maybe()
+	m.EthTxManager.On("Reorg", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	m.DbTx.On("Commit", mock.Anything).Return(nil).Maybe()
+	m.DbTx.On("Rollback", mock.Anything).Return(nil).Maybe()
+	return s
+}
+
+func testReplayEthermanConfig() etherman.Config {
+	return etherman.Config{
+		L1ChainID: testReplayChainID.Uint64(),
+		// the log queries are split in adaptive windows, which the replay
+		// requires to be configured as they were while recording
+		LogRange:                  l1client.LogRangeConfig{Adaptive: true, InitialSize: 1, MaxSize: 4, SparseLogs: 1},
+		L1CacheSize:               100,
+		PoEAddr:                   testReplayPoEAddr,
+		MaticAddr:                 testReplayMaticAddr,
+		GlobalExitRootManagerAddr: testReplayGlobalExitRootAddr,
+		HotShotAddr:               testReplayHotShotAddr,
+		// the replayed requests to the query service are matched by path
+		HotShotQueryServiceURL: "http://hotshot.test",
+	}
+}
+
+// syncUntil runs the synchronizer on the etherman until the state is synced
+// up to the L1 block and the batch, with the reorgs given
+func syncUntil(t *testing.T, ethMan ethermanInterface, blockNumber, batchNumber uint64, reorgs int) *testReplayState {
+	m := &mocks{
+		State:        newStateMock(t),
+		EthTxManager: newEthTxManagerMock(t),
+		DbTx:         newDbTxMock(t),
+	}
+	st := expectReplayState(m)
+	cfg := Config{
+		SyncInterval:   cfgTypes.Duration{Duration: 10 * time.Millisecond},
+		SyncChunkSize:  2,
+		ResetChunkSize: 10,
+		GenBlockNumber: 1,
+	}
+	sync, err := NewSynchronizer(true, ethMan, m.State, m.EthTxManager, state.Genesis{}, cfg)
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- sync.Sync()
+	}()
+	for i := 0; i < 500 && !st.synced(blockNumber, batchNumber, reorgs); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	sync.Stop()
+	require.NoError(t, <-done)
+	require.True(t, st.synced(blockNumber, batchNumber, reorgs), "state not synced up to the L1 block %d and the batch %d", blockNumber, batchNumber)
+	return st
+}
+
+// recordTestSync records in the fixture the sync of the simulated L1 and
+// HotShot query service
+func recordTestSync(t *testing.T, fixture string, cfg etherman.Config) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(fixture), 0755)) //nolint:gosec
+	if err := os.Remove(fixture); err != nil && !errors.Is(err, os.ErrNotExist) {
+		require.NoError(t, err)
+	}
+	cfg.URL, cfg.HotShotQueryServiceURL = startTestReplayL1(t, newTestReplayL1(t))
+	cfg.RecordFile = fixture
+	ethMan, err := etherman.NewClient(cfg)
+	require.NoError(t, err)
+	defer ethMan.Close()
+	syncUntil(t, ethMan, 11, 3, 1)
+}
+
+func TestSyncReplay(t *testing.T) {
+	const fixture = "testdata/sync_l1_reorg.jsonl"
+	cfg := testReplayEthermanConfig()
+	if *record {
+		recordTestSync(t, fixture, cfg)
+	}
+
+	// the expected chain is the simulated one after the reorg
+	l1 := newTestReplayL1(t)
+	oldHash := l1.blocks[6].Hash()
+	l1.reorg()
+
+	ethMan, err := etherman.NewReplayClient(cfg, fixture)
+	require.NoError(t, err)
+	st := syncUntil(t, ethMan, 11, 3, 1)
+
+	// the blocks with the batches and the last one of the non empty chunks
+	// are stored, the ones reverted by the reorg are replaced
+	var blocks []string
+	for _, block := range st.blocks {
+		blocks = append(blocks, fmt.Sprintf("%d %s", block.BlockNumber, block.BlockHash))
+	}
+	var expectedBlocks []string
+	for _, n := range []uint64{1, 2, 3, 5, 7, 11} {
+		expectedBlocks = append(expectedBlocks, fmt.Sprintf("%d %s", n, l1.blocks[n].Hash()))
+	}
+	assert.Equal(t, expectedBlocks, blocks)
+
+	// the batch 3 is synced again, seen at the block 7
+	var virtualBatches [][3]uint64
+	for _, virtualBatch := range st.virtualBatches {
+		virtualBatches = append(virtualBatches, [3]uint64{virtualBatch.BatchNumber, virtualBatch.BlockNumber, virtualBatch.SeenAt})
+	}
+	assert.Equal(t, [][3]uint64{{1, 2, 3}, {2, 3, 3}, {3, 5, 7}}, virtualBatches)
+	for _, batch := range st.batches {
+		assert.Equal(t, testReplayGlobalExitRoot, batch.GlobalExitRoot)
+		assert.Equal(t, int64(testHotShotBlocks[batch.BatchNumber].Timestamp), batch.Timestamp.Unix())
+	}
+
+	require.Len(t, st.reorgs, 1)
+	reorg := st.reorgs[0]
+	assert.Equal(t, state.ReorgKindL1, reorg.Kind)
+	assert.Equal(t, uint64(6), reorg.FirstBlockNumber)
+	assert.Equal(t, uint64(1), reorg.Depth)
+	assert.Equal(t, uint64(3), reorg.FirstBatchNumber)
+	assert.Equal(t, oldHash, reorg.OldHash)
+	assert.Equal(t, l1.blocks[6].Hash(), reorg.NewHash)
+}
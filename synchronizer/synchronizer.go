@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/etherman"
@@ -16,18 +18,25 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast/pb"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/health"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/metrics"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/jackc/pgx/v4"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // Synchronizer connects L1 and L2
 type Synchronizer interface {
 	Sync() error
 	Stop()
+	RegisterBatchClosedEventHandler(h BatchClosedEventHandler)
 }
 
+// BatchClosedEventHandler is notified of the number of a closed batch stored
+// by the synchronizer
+type BatchClosedEventHandler func(batchNumber uint64)
+
 // ClientSynchronizer connects L1 and L2
 type ClientSynchronizer struct {
 	isTrustedSequencer bool
@@ -38,6 +47,24 @@ type ClientSynchronizer struct {
 	cancelCtx          context.CancelFunc
 	genesis            state.Genesis
 	cfg                Config
+	status             *health.Tracker
+
+	// broadcastClient is kept open between the syncs of the trusted state
+	// along with the stream of trusted batches
+	broadcastClient *broadcast.Client
+	trustedStream   *trustedBatchStream
+	// trustedMutex is held by the main loop while it syncs, the trusted
+	// batches streamed are processed in the background in between
+	trustedMutex sync.Mutex
+
+	// batchClosedEventHandlers are notified of the closed batches stored by
+	// the synchronizer, e.g. the forced batches included
+	batchClosedEventHandlers    []BatchClosedEventHandler
+	batchClosedEventHandlersMux sync.RWMutex
+
+	// lastL1BlockSynced is the last L1 block whose events are synced, the
+	// preconfirmations wait for the forced batches of the L1 blocks up to it
+	lastL1BlockSynced uint64
 
 	// Channel for synchronizing reorgs with the asynchronous preconf task.
 	// The type of the messages sent on this channel is irrelevant. It is used for synchronization
@@ -53,6 +80,17 @@ func NewSynchronizer(
 	ethTxManager ethTxManager,
 	genesis state.Genesis,
 	cfg Config) (Synchronizer, error) {
+	switch state.L1Finality(cfg.L1Finality) {
+	case "", state.L1FinalityLatest, state.L1FinalitySafe, state.L1FinalityFinalized:
+	default:
+		return nil, fmt.Errorf("unknown L1 finality %q, it must be latest, safe or finalized", cfg.L1Finality)
+	}
+
+	if cfg.SyncPipelineDownloadWorkers > 1 && cfg.PreconfirmationsSyncInterval.Duration == 0 {
+		log.Warn("the chunks of L1 blocks are downloaded by a single worker when the preconfirmations are not used")
+	}
+	metrics.Register()
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &ClientSynchronizer{
@@ -64,6 +102,7 @@ func NewSynchronizer(
 		ethTxManager:       ethTxManager,
 		genesis:            genesis,
 		cfg:                cfg,
+		status:             health.NewTracker(),
 	}, nil
 }
 
@@ -75,6 +114,15 @@ func (s *ClientSynchronizer) Sync() error {
 	// If there is no lastEthereumBlock means that sync from the beginning is necessary. If not, it continues from the retrieved ethereum block
 	// Get the latest synced block. If there is no block on db, use genesis block
 	log.Info("Sync started")
+	if s.cfg.Health.Enabled {
+		healthServer := health.NewServer(s.cfg.Health, s.status)
+		go healthServer.Start()
+		defer healthServer.Stop()
+	}
+	defer s.closeBroadcastClient()
+	if err := s.resumeReset(); err != nil {
+		return err
+	}
 	dbTx, err := s.state.BeginStateTransaction(s.ctx)
 	if err != nil {
 		log.Fatalf("error creating db transaction to get latest block")
@@ -118,6 +166,22 @@ func (s *ClientSynchronizer) Sync() error {
 				log.Fatal("Calculated newRoot should be ", s.genesis.Root, " instead of ", root)
 			}
 			log.Debug("Genesis root matches!")
+			// The synchronizer processes the blocks after the genesis one, so
+			// the fork ids activated up to the genesis block are stored with it
+			forkIDs, err := s.etherMan.GetForks(s.ctx)
+			if err != nil {
+				log.Error("error getting the fork ids. Error: ", err)
+				return err
+			}
+			for _, forkID := range forkIDs {
+				if forkID.BlockNumber > lastEthBlockSynced.BlockNumber {
+					continue
+				}
+				forkID.BlockNumber = lastEthBlockSynced.BlockNumber
+				if err := s.state.AddForkID(s.ctx, forkID, dbTx); err != nil {
+					log.Fatal("error storing the genesis fork ids: ", err)
+				}
+			}
 		} else {
 			log.Fatal("unexpected error getting the latest ethereum block. Error: ", err)
 		}
@@ -131,6 +195,10 @@ func (s *ClientSynchronizer) Sync() error {
 		}
 		log.Fatalf("error committing dbTx, err: %w", err)
 	}
+	if err := s.state.LoadForkIDIntervals(s.ctx, nil); err != nil {
+		log.Error("error loading the fork id intervals. Error: ", err)
+		return err
+	}
 
 	if s.usePreconfirmations() {
 		s.preconfReorg = make(chan interface{})
@@ -162,51 +230,154 @@ func (s *ClientSynchronizer) Sync() error {
 		case <-s.ctx.Done():
 			return nil
 		case <-time.After(waitDuration):
-			//Sync L1Blocks
-			if lastEthBlockSynced, err = s.syncBlocks(lastEthBlockSynced); err != nil {
-				log.Warn("error syncing blocks: ", err)
-				if s.ctx.Err() != nil {
-					continue
-				}
-			}
-			latestSequencedBatchNumber, err := s.etherMan.GetLatestBatchNumber()
-			if err != nil {
-				log.Warn("error getting latest sequenced batch in the rollup. Error: ", err)
-				continue
-			}
-			latestSyncedBatch, err := s.state.GetLastBatchNumber(s.ctx, nil)
-			if err != nil {
-				log.Warn("error getting latest batch synced. Error: ", err)
-				continue
-			}
-			if latestSyncedBatch >= latestSequencedBatchNumber {
-				log.Info("L1 state fully synchronized")
-				err = s.syncTrustedState(latestSyncedBatch)
-				if err != nil {
-					log.Warn("error syncing trusted state. Error: ", err)
-					continue
-				}
-				log.Info("Trusted state fully synchronized")
-				waitDuration = s.cfg.SyncInterval.Duration
-			}
+			// The trusted batches streamed are processed between the syncs
+			s.trustedMutex.Lock()
+			lastEthBlockSynced = s.syncOnce(lastEthBlockSynced)
+			s.trustedMutex.Unlock()
+		}
+	}
+}
+
+// syncOnce syncs the L1 blocks after lastEthBlockSynced and, once the batches
+// sequenced on L1 are synced, the trusted state. It returns the last L1 block
+// synced
+func (s *ClientSynchronizer) syncOnce(lastEthBlockSynced *state.Block) *state.Block {
+	//Sync L1Blocks
+	lastEthBlockSynced, err := s.syncBlocks(lastEthBlockSynced)
+	if err != nil {
+		log.Warn("error syncing blocks: ", err)
+		if s.ctx.Err() != nil {
+			return lastEthBlockSynced
+		}
+	}
+	synced := err == nil
+	s.setLastL1BlockSynced(lastEthBlockSynced.BlockNumber)
+	if err = s.updateL1Finality(); err != nil {
+		log.Warn("error updating the L1 finality of the virtual batches: ", err)
+	}
+	latestSyncedBatch, l1Synced, err := s.checkL1BatchesSynced()
+	if err != nil {
+		return lastEthBlockSynced
+	}
+	if synced {
+		s.status.Synced()
+	}
+	if l1Synced {
+		log.Info("L1 state fully synchronized")
+		err = s.syncTrustedState(latestSyncedBatch.Number)
+		if err != nil {
+			log.Warn("error syncing trusted state. Error: ", err)
+			return lastEthBlockSynced
 		}
+		log.Info("Trusted state fully synchronized")
+		waitDuration = s.cfg.SyncInterval.Duration
 	}
+	return lastEthBlockSynced
+}
+
+// checkL1BatchesSynced returns the last batch synced and if it reached the
+// last batch sequenced on L1, updating the sync status. The batches sequenced
+// on L1 are numbered without the forced batches, so the last batch synced is
+// compared without the ones included up to it
+func (s *ClientSynchronizer) checkL1BatchesSynced() (state.L2BatchInfo, bool, error) {
+	latestSequencedBatchNumber, err := s.etherMan.GetLatestBatchNumber()
+	if err != nil {
+		log.Warn("error getting latest sequenced batch in the rollup. Error: ", err)
+		return state.L2BatchInfo{}, false, err
+	}
+	latestSyncedBatch, err := s.state.GetLastBatchInfo(s.ctx, nil)
+	if err != nil {
+		log.Warn("error getting latest batch synced. Error: ", err)
+		return state.L2BatchInfo{}, false, err
+	}
+	s.status.SetBatches(latestSyncedBatch.Number, latestSyncedBatch.ForcedBatchNum, latestSequencedBatchNumber)
+	return latestSyncedBatch, latestSyncedBatch.HotShotBatchNumber() >= latestSequencedBatchNumber, nil
 }
 
 func (s *ClientSynchronizer) usePreconfirmations() bool {
 	return s.cfg.PreconfirmationsSyncInterval.Duration != 0
 }
 
+// l1Finality returns the finality level of the L1 blocks synced
+func (s *ClientSynchronizer) l1Finality() state.L1Finality {
+	if s.cfg.L1Finality == "" {
+		return state.L1FinalityLatest
+	}
+	return state.L1Finality(s.cfg.L1Finality)
+}
+
+// l1HeaderByFinality returns the header of the last L1 block of the finality
+// level
+func (s *ClientSynchronizer) l1HeaderByFinality(l1Finality state.L1Finality) (*types.Header, error) {
+	var number *big.Int
+	switch l1Finality {
+	case state.L1FinalitySafe:
+		number = big.NewInt(int64(rpc.SafeBlockNumber))
+	case state.L1FinalityFinalized:
+		number = big.NewInt(int64(rpc.FinalizedBlockNumber))
+	}
+	return s.etherMan.HeaderByNumber(s.ctx, number)
+}
+
+// lastL1BlockToSync returns the last L1 block that has reached the finality
+// level and the confirmations configured
+func (s *ClientSynchronizer) lastL1BlockToSync() (uint64, error) {
+	header, err := s.l1HeaderByFinality(s.l1Finality())
+	if err != nil {
+		return 0, err
+	}
+	lastBlock := header.Number.Uint64()
+	if lastBlock < s.cfg.L1Confirmations {
+		return 0, nil
+	}
+	return lastBlock - s.cfg.L1Confirmations, nil
+}
+
+// updateL1Finality raises the finality level of the virtual batches seen at
+// the L1 blocks that have reached a level above the one they were synced at
+func (s *ClientSynchronizer) updateL1Finality() error {
+	for _, l1Finality := range []state.L1Finality{state.L1FinalitySafe, state.L1FinalityFinalized} {
+		// the batches synced from L1 are already at the synced level, the
+		// preconfirmed ones are below any level
+		if !s.usePreconfirmations() && !isL1FinalityLower(s.l1Finality(), l1Finality) {
+			continue
+		}
+		header, err := s.l1HeaderByFinality(l1Finality)
+		if err != nil {
+			// not all the L1 networks support the finality levels
+			log.Debugf("error getting the last %s L1 block: %v", l1Finality, err)
+			continue
+		}
+		updated, err := s.state.UpdateVirtualBatchesL1Finality(s.ctx, l1Finality, header.Number.Uint64(), nil)
+		if err != nil {
+			return err
+		}
+		if updated > 0 {
+			log.Debugf("%d virtual batches reached the %s L1 finality at block %d", updated, l1Finality, header.Number.Uint64())
+		}
+	}
+	return nil
+}
+
+func isL1FinalityLower(l1Finality, than state.L1Finality) bool {
+	for _, lower := range than.Lower() {
+		if lower == l1Finality {
+			return true
+		}
+	}
+	return false
+}
+
 // This function syncs the node from a specific block to the latest
 func (s *ClientSynchronizer) syncBlocks(lastEthBlockSynced *state.Block) (*state.Block, error) {
 	// This function will read events fromBlockNum to latestEthBlock. Check reorg to be sure that everything is ok.
-	block, err := s.checkReorg(lastEthBlockSynced)
+	block, reorg, err := s.checkReorg(lastEthBlockSynced)
 	if err != nil {
 		log.Errorf("error checking reorgs. Retrying... Err: %w", err)
 		return lastEthBlockSynced, fmt.Errorf("error checking reorgs")
 	}
 	if block != nil {
-		err = s.resetState(block.BlockNumber)
+		err = s.resetState(block.BlockNumber, reorg)
 		if err != nil {
 			log.Errorf("error resetting the state to a previous block. Retrying... Err: %w", err)
 			return lastEthBlockSynced, fmt.Errorf("error resetting the state to a previous block")
@@ -214,21 +385,33 @@ func (s *ClientSynchronizer) syncBlocks(lastEthBlockSynced *state.Block) (*state
 		return block, nil
 	}
 
-	// Call the blockchain to retrieve data
-	header, err := s.etherMan.HeaderByNumber(s.ctx, nil)
+	// Call the blockchain to retrieve data, only the blocks that have reached
+	// the finality level are synced
+	lastKnownBlock, err := s.lastL1BlockToSync()
 	if err != nil {
 		return lastEthBlockSynced, err
 	}
-	lastKnownBlock := header.Number
+	s.status.SetL1Head(lastKnownBlock)
 
 	var fromBlock uint64
 	if lastEthBlockSynced.BlockNumber > 0 {
 		fromBlock = lastEthBlockSynced.BlockNumber + 1
 	}
+	if fromBlock > lastKnownBlock {
+		log.Debugf("no L1 blocks with %s finality to sync, last one is %d", s.l1Finality(), lastKnownBlock)
+		waitDuration = s.cfg.SyncInterval.Duration
+		return lastEthBlockSynced, nil
+	}
+	if s.usePipeline() {
+		return s.syncBlocksPipelined(lastEthBlockSynced, fromBlock, lastKnownBlock)
+	}
 
 	for {
 		toBlock := fromBlock + s.cfg.SyncChunkSize
-		log.Infof("Syncing L1 block %d of %d", fromBlock, lastKnownBlock.Uint64())
+		if toBlock > lastKnownBlock {
+			toBlock = lastKnownBlock
+		}
+		log.Infof("Syncing L1 block %d of %d", fromBlock, lastKnownBlock)
 		log.Infof("Getting rollup info from L1 block %d to block %d", fromBlock, toBlock)
 
 		prevBatch, err := s.state.GetLastBatchInfo(s.ctx, nil)
@@ -246,7 +429,7 @@ func (s *ClientSynchronizer) syncBlocks(lastEthBlockSynced *state.Block) (*state
 		if err != nil {
 			return lastEthBlockSynced, err
 		}
-		err = s.processBlockRange(blocks, order)
+		err = s.processBlockRange(blocks, order, s.l1Finality())
 		if err != nil {
 			return lastEthBlockSynced, err
 		}
@@ -260,10 +443,11 @@ func (s *ClientSynchronizer) syncBlocks(lastEthBlockSynced *state.Block) (*state
 			for i := range blocks {
 				log.Debug("Position: ", i, ". BlockNumber: ", blocks[i].BlockNumber, ". BlockHash: ", blocks[i].BlockHash)
 			}
+			s.setLastL1BlockSynced(lastEthBlockSynced.BlockNumber)
 		}
 		fromBlock = toBlock + 1
 
-		if lastKnownBlock.Cmp(new(big.Int).SetUint64(toBlock)) < 1 {
+		if lastKnownBlock <= toBlock {
 			waitDuration = s.cfg.SyncInterval.Duration
 			break
 		}
@@ -279,7 +463,7 @@ func (s *ClientSynchronizer) syncBlocks(lastEthBlockSynced *state.Block) (*state
 				ParentHash:  fb.ParentHash(),
 				ReceivedAt:  time.Unix(int64(fb.Time()), 0),
 			}
-			err = s.processBlockRange([]etherman.Block{b}, order)
+			err = s.processBlockRange([]etherman.Block{b}, order, s.l1Finality())
 			if err != nil {
 				return lastEthBlockSynced, err
 			}
@@ -298,6 +482,12 @@ func (s *ClientSynchronizer) syncBlocks(lastEthBlockSynced *state.Block) (*state
 }
 
 func (s *ClientSynchronizer) syncPreconfirmations() error {
+	hotShotLatestBatch, err := s.etherMan.GetLatestHotShotBatchNumber()
+	if err != nil {
+		log.Debug("error getting the latest batch sequenced in HotShot. Error: ", err)
+	} else {
+		s.status.SetHotShotLatestBatch(hotShotLatestBatch)
+	}
 	for {
 		// Figure out where to start from.
 		latestSyncedBatch, err := s.state.GetLastBatchInfo(s.ctx, nil)
@@ -305,6 +495,7 @@ func (s *ClientSynchronizer) syncPreconfirmations() error {
 			log.Warn("error getting latest batch synced. Error: ", err)
 			return err
 		}
+		s.status.SetLastBatch(latestSyncedBatch.Number, latestSyncedBatch.ForcedBatchNum)
 
 		// Fetch new preconfirmed blocks from the sequencer.
 		blocks, order, err := s.etherMan.GetPreconfirmations(s.ctx, latestSyncedBatch)
@@ -317,78 +508,13 @@ func (s *ClientSynchronizer) syncPreconfirmations() error {
 			return nil
 		}
 
-		err = s.processBlockRange(blocks, order)
+		err = s.processBlockRange(blocks, order, state.L1FinalityPreconfirmed)
 		if err != nil {
 			return err
 		}
 	}
 }
 
-// syncTrustedState synchronizes information from the trusted sequencer
-// related to the trusted state when the node has all the information from
-// l1 synchronized
-func (s *ClientSynchronizer) syncTrustedState(latestSyncedBatch uint64) error {
-	if s.isTrustedSequencer {
-		return nil
-	}
-
-	log.Debug("Getting broadcast URI")
-	broadcastURI, err := s.getBroadcastURI()
-	if err != nil {
-		log.Errorf("error getting broadcast URI. Error: %v", err)
-		return err
-	}
-	log.Debug("broadcastURI ", broadcastURI)
-	broadcastClient, _, _ := broadcast.NewClient(s.ctx, broadcastURI)
-
-	log.Info("Getting trusted state info")
-	lastTrustedStateBatch, err := broadcastClient.GetLastBatch(s.ctx, &emptypb.Empty{})
-	if err != nil {
-		log.Warn("error syncing trusted state. Error: ", err)
-		return err
-	}
-
-	log.Debug("lastTrustedStateBatch.BatchNumber ", lastTrustedStateBatch.BatchNumber)
-	log.Debug("latestSyncedBatch ", latestSyncedBatch)
-	if lastTrustedStateBatch.BatchNumber < latestSyncedBatch {
-		return nil
-	}
-
-	batchNumberToSync := latestSyncedBatch
-	for batchNumberToSync <= lastTrustedStateBatch.BatchNumber {
-		batchToSync, err := broadcastClient.GetBatch(s.ctx, &pb.GetBatchRequest{BatchNumber: batchNumberToSync})
-		if err != nil {
-			log.Warnf("failed to get batch %v from trusted state via broadcast. Error: %v", batchNumberToSync, err)
-			return err
-		}
-
-		dbTx, err := s.state.BeginStateTransaction(s.ctx)
-		if err != nil {
-			log.Errorf("error creating db transaction to sync trusted batch %v: %v", batchNumberToSync, err)
-			return err
-		}
-
-		if err := s.processTrustedBatch(batchToSync, dbTx); err != nil {
-			log.Errorf("error processing trusted batch %v: %v", batchNumberToSync, err)
-			err := dbTx.Rollback(s.ctx)
-			if err != nil {
-				log.Errorf("error rolling back db transaction to sync trusted batch %v: %v", batchNumberToSync, err)
-				return err
-			}
-			break
-		}
-
-		if err := dbTx.Commit(s.ctx); err != nil {
-			log.Errorf("error committing db transaction to sync trusted batch %v: %v", batchNumberToSync, err)
-			return err
-		}
-
-		batchNumberToSync++
-	}
-
-	return nil
-}
-
 // gets the broadcast URI from trusted sequencer JSON RPC server
 func (s *ClientSynchronizer) getBroadcastURI() (string, error) {
 	log.Debug("getting trusted sequencer URL from smc")
@@ -417,9 +543,13 @@ func (s *ClientSynchronizer) getBroadcastURI() (string, error) {
 	return url, nil
 }
 
-func (s *ClientSynchronizer) processBlockRange(blocks []etherman.Block, order map[common.Hash][]etherman.Order) error {
+// processBlockRange stores the blocks and their rollup info, l1Finality is the
+// finality level reached by the blocks
+func (s *ClientSynchronizer) processBlockRange(blocks []etherman.Block, order map[common.Hash][]etherman.Order, l1Finality state.L1Finality) error {
 	// New info has to be included into the db using the state
 	for i := range blocks {
+		forkIDsUpdated := false
+		batchesSequenced := false
 		// Begin db transaction
 		dbTx, err := s.state.BeginStateTransaction(s.ctx)
 		if err != nil {
@@ -446,20 +576,32 @@ func (s *ClientSynchronizer) processBlockRange(blocks []etherman.Block, order ma
 		for _, element := range order[blocks[i].BlockHash] {
 			switch element.Name {
 			case etherman.SequenceBatchesOrder:
-				err = s.processSequenceBatches(blocks[i].SequencedBatches[element.Pos], dbTx, blocks[i].BlockNumber)
+				err = s.processSequenceBatches(blocks[i].SequencedBatches[element.Pos], dbTx, blocks[i].BlockNumber, l1Finality)
 				if err != nil {
 					return err
 				}
+				batchesSequenced = true
 			case etherman.GlobalExitRootsOrder:
 				err = s.processGlobalExitRoot(blocks[i].GlobalExitRoots[element.Pos], dbTx)
 				if err != nil {
 					return err
 				}
+			case etherman.ForcedBatchesOrder:
+				err = s.processForcedBatch(blocks[i].ForcedBatches[element.Pos], dbTx)
+				if err != nil {
+					return err
+				}
 			case etherman.TrustedVerifyBatchOrder:
 				err = s.processTrustedVerifyBatches(blocks[i].VerifiedBatches[element.Pos], dbTx)
 				if err != nil {
 					return err
 				}
+			case etherman.ForkIDsOrder:
+				err = s.processForkID(blocks[i].ForkIDs[element.Pos], blocks[i].BlockNumber, dbTx)
+				if err != nil {
+					return err
+				}
+				forkIDsUpdated = true
 			}
 		}
 		err = dbTx.Commit(s.ctx)
@@ -472,12 +614,27 @@ func (s *ClientSynchronizer) processBlockRange(blocks []etherman.Block, order ma
 			}
 			return err
 		}
+		if batchesSequenced {
+			s.onVirtualBatchesStored()
+		}
+		// The fork id intervals are reloaded once the block is committed, so
+		// the state never uses a fork id that is not stored
+		if forkIDsUpdated {
+			err = s.state.LoadForkIDIntervals(s.ctx, nil)
+			if err != nil {
+				log.Errorf("error loading fork id intervals. BlockNumber: %d, err: %w", blocks[i].BlockNumber, err)
+				return err
+			}
+		}
 	}
 	return nil
 }
 
-// This function allows reset the state until an specific ethereum block
-func (s *ClientSynchronizer) resetState(blockNumber uint64) error {
+// This function allows reset the state until an specific ethereum block, the
+// reorg that caused it is recorded in the reorg history. The reset is started
+// in a db transaction that persists its marker, and completed in steps so a
+// deep reorg doesn't hold a single transaction over the whole state
+func (s *ClientSynchronizer) resetState(blockNumber uint64, reorg *state.Reorg) error {
 	log.Debug("Reverting synchronization to block: ", blockNumber)
 
 	if s.preconfReorg != nil {
@@ -499,14 +656,26 @@ func (s *ClientSynchronizer) resetState(blockNumber uint64) error {
 		log.Error("error starting a db transaction to reset the state. Error: ", err)
 		return err
 	}
-	err = s.state.Reset(s.ctx, blockNumber, dbTx)
+	if reorg != nil {
+		err = s.addL1Reorg(reorg, blockNumber, dbTx)
+		if err != nil {
+			rollbackErr := dbTx.Rollback(s.ctx)
+			if rollbackErr != nil {
+				log.Errorf("error rolling back state to store block. BlockNumber: %d, rollbackErr: %s, error : %w", blockNumber, rollbackErr.Error(), err)
+				return rollbackErr
+			}
+			log.Error("error recording the reorg. Error: ", err)
+			return err
+		}
+	}
+	err = s.state.StartReset(s.ctx, blockNumber, dbTx)
 	if err != nil {
 		rollbackErr := dbTx.Rollback(s.ctx)
 		if rollbackErr != nil {
 			log.Errorf("error rolling back state to store block. BlockNumber: %d, rollbackErr: %s, error : %w", blockNumber, rollbackErr.Error(), err)
 			return rollbackErr
 		}
-		log.Error("error resetting the state. Error: ", err)
+		log.Error("error starting the reset of the state. Error: ", err)
 		return err
 	}
 	err = s.ethTxManager.Reorg(s.ctx, blockNumber+1, dbTx)
@@ -526,36 +695,140 @@ func (s *ClientSynchronizer) resetState(blockNumber uint64) error {
 			log.Errorf("error rolling back state to store block. BlockNumber: %d, rollbackErr: %s, error : %w", blockNumber, rollbackErr.Error(), err)
 			return rollbackErr
 		}
-		log.Error("error committing the resetted state. Error: ", err)
+		log.Error("error committing the start of the reset. Error: ", err)
+		return err
+	}
+
+	return s.completeReset(blockNumber)
+}
+
+// completeReset removes the L1 blocks after blockNumber in chunks, each one in
+// its own db transaction, and then removes the marker of the reset. It is also
+// used to resume on startup a reset that was interrupted
+func (s *ClientSynchronizer) completeReset(blockNumber uint64) error {
+	for done := false; !done; {
+		dbTx, err := s.state.BeginStateTransaction(s.ctx)
+		if err != nil {
+			log.Error("error starting a db transaction to reset the state. Error: ", err)
+			return err
+		}
+		done, err = s.state.ResetStep(s.ctx, blockNumber, s.cfg.ResetChunkSize, dbTx)
+		if err != nil {
+			rollbackErr := dbTx.Rollback(s.ctx)
+			if rollbackErr != nil {
+				log.Errorf("error rolling back state to store block. BlockNumber: %d, rollbackErr: %s, error : %w", blockNumber, rollbackErr.Error(), err)
+				return rollbackErr
+			}
+			log.Error("error resetting the state. Error: ", err)
+			return err
+		}
+		if done {
+			err = s.state.FinishReset(s.ctx, dbTx)
+			if err != nil {
+				rollbackErr := dbTx.Rollback(s.ctx)
+				if rollbackErr != nil {
+					log.Errorf("error rolling back state to store block. BlockNumber: %d, rollbackErr: %s, error : %w", blockNumber, rollbackErr.Error(), err)
+					return rollbackErr
+				}
+				log.Error("error finishing the reset of the state. Error: ", err)
+				return err
+			}
+		}
+		err = dbTx.Commit(s.ctx)
+		if err != nil {
+			rollbackErr := dbTx.Rollback(s.ctx)
+			if rollbackErr != nil {
+				log.Errorf("error rolling back state to store block. BlockNumber: %d, rollbackErr: %s, error : %w", blockNumber, rollbackErr.Error(), err)
+				return rollbackErr
+			}
+			log.Error("error committing the resetted state. Error: ", err)
+			return err
+		}
+	}
+	log.Infof("state reset to block %d", blockNumber)
+	s.etherMan.ResetL1Cache(blockNumber)
+	if atomic.LoadUint64(&s.lastL1BlockSynced) > blockNumber {
+		s.setLastL1BlockSynced(blockNumber)
+	}
+
+	// The fork ids activated in the reverted blocks were deleted with them
+	err := s.state.LoadForkIDIntervals(s.ctx, nil)
+	if err != nil {
+		log.Error("error loading fork id intervals after resetting the state. Error: ", err)
 		return err
 	}
 
 	return nil
 }
 
+// resumeReset completes the reset of the state that was in progress when the
+// node stopped, if any
+func (s *ClientSynchronizer) resumeReset() error {
+	blockNumber, err := s.state.GetResetInProgress(s.ctx, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		log.Error("error getting the reset of the state in progress. Error: ", err)
+		return err
+	}
+	log.Infof("resuming the reset of the state to block %d", blockNumber)
+	return s.completeReset(blockNumber)
+}
+
+// addL1Reorg records a reorg of the L1 blocks after blockNumber, it is a
+// preconfirmation reorg when the batches reverted were only preconfirmed. The
+// txs of the batches reverted are recorded as dropped before the reset
+// removes them
+func (s *ClientSynchronizer) addL1Reorg(reorg *state.Reorg, blockNumber uint64, dbTx pgx.Tx) error {
+	virtualBatches, err := s.state.GetVirtualBatchesAfterBlock(s.ctx, blockNumber, dbTx)
+	if err != nil {
+		return err
+	}
+	reorg.Kind = state.ReorgKindL1
+	if len(virtualBatches) > 0 {
+		reorg.FirstBatchNumber = virtualBatches[0].BatchNumber
+		reorg.Kind = state.ReorgKindPreconfirmation
+		for _, virtualBatch := range virtualBatches {
+			if virtualBatch.L1Finality != state.L1FinalityPreconfirmed {
+				reorg.Kind = state.ReorgKindL1
+				break
+			}
+		}
+		reorg.DroppedTxs, err = s.state.GetTransactionsFromBatchNumber(s.ctx, reorg.FirstBatchNumber, dbTx)
+		if err != nil {
+			return err
+		}
+	}
+	reorg.CreatedAt = time.Now()
+	log.Infof("recording %s reorg from L1 block %d, depth %d", reorg.Kind, reorg.FirstBlockNumber, reorg.Depth)
+	return s.state.AddReorg(s.ctx, reorg, dbTx)
+}
+
 /*
 This function will check if there is a reorg.
 As input param needs the last ethereum block synced. Retrieve the block info from the blockchain
 to compare it with the stored info. If hash and hash parent matches, then no reorg is detected and return a nil.
 If hash or hash parent don't match, reorg detected and the function will return the block until the sync process
 must be reverted. Then, check the previous ethereum block synced, get block info from the blockchain and check
-hash and has parent. This operation has to be done until a match is found.
+hash and has parent. This operation has to be done until a match is found. Along with the block, it returns the
+reorg found to be recorded in the reorg history.
 */
-func (s *ClientSynchronizer) checkReorg(latestBlock *state.Block) (*state.Block, error) {
+func (s *ClientSynchronizer) checkReorg(latestBlock *state.Block) (*state.Block, *state.Reorg, error) {
 	// This function only needs to worry about reorgs if some of the reorganized blocks contained rollup info.
 	latestEthBlockSynced := *latestBlock
 	var depth uint64
+	reorg := &state.Reorg{}
 	for {
 		block, err := s.etherMan.EthBlockByNumber(s.ctx, latestBlock.BlockNumber)
 		if err != nil {
 			log.Errorf("error getting latest block synced from blockchain. Block: %d, error: %w", latestBlock.BlockNumber, err)
-			return nil, err
+			return nil, nil, err
 		}
 		if block.NumberU64() != latestBlock.BlockNumber {
 			err = fmt.Errorf("Wrong ethereum block retrieved from blockchain. Block numbers don't match. BlockNumber stored: %d. BlockNumber retrieved: %d",
 				latestBlock.BlockNumber, block.NumberU64())
 			log.Error("error: ", err)
-			return nil, err
+			return nil, nil, err
 		}
 		// Compare hashes
 		if (block.Hash() != latestBlock.BlockHash || block.ParentHash() != latestBlock.ParentHash) && latestBlock.BlockNumber > s.cfg.GenBlockNumber {
@@ -566,13 +839,32 @@ func (s *ClientSynchronizer) checkReorg(latestBlock *state.Block) (*state.Block,
 			log.Debug("[checkReorg function] => BlockNumber: ", latestBlock.BlockNumber, block.NumberU64())
 			log.Debug("[checkReorg function] => BlockHash: ", block.Hash())
 			log.Debug("[checkReorg function] => BlockHashParent: ", block.ParentHash())
+			if depth == 0 {
+				// A single L1 provider can be behind or on a fork, so the reorg is
+				// only acted on when a majority of them agree on the new hash
+				hash, err := s.etherMan.VerifyBlockHash(s.ctx, latestBlock.BlockNumber)
+				if err != nil {
+					log.Warnf("reorg at height %d not confirmed by the L1 providers. Retrying... Err: %v", latestBlock.BlockNumber, err)
+					return nil, nil, err
+				}
+				if hash != block.Hash() {
+					err = fmt.Errorf("L1 block %d changed while checking the reorg, expected hash %s, got %s", latestBlock.BlockNumber, block.Hash(), hash)
+					log.Warn("error: ", err)
+					return nil, nil, err
+				}
+			}
+			reorg.FirstBlockNumber = latestBlock.BlockNumber
+			reorg.OldHash = latestBlock.BlockHash
+			reorg.NewHash = block.Hash()
+			reorg.Reason = fmt.Sprintf("L1 block %d has hash %s and parent hash %s, stored hash %s and parent hash %s",
+				latestBlock.BlockNumber, block.Hash(), block.ParentHash(), latestBlock.BlockHash, latestBlock.ParentHash)
 			depth++
 			log.Debug("REORG: Looking for the latest correct ethereum block. Depth: ", depth)
 			// Reorg detected. Getting previous block
 			dbTx, err := s.state.BeginStateTransaction(s.ctx)
 			if err != nil {
 				log.Errorf("error creating db transaction to get prevoius blocks")
-				return nil, err
+				return nil, nil, err
 			}
 			latestBlock, err = s.state.GetPreviousBlock(s.ctx, depth, dbTx)
 			errC := dbTx.Commit(s.ctx)
@@ -581,16 +873,18 @@ func (s *ClientSynchronizer) checkReorg(latestBlock *state.Block) (*state.Block,
 				rollbackErr := dbTx.Rollback(s.ctx)
 				if rollbackErr != nil {
 					log.Errorf("error rolling back state. RollbackErr: %w", rollbackErr)
-					return nil, rollbackErr
+					return nil, nil, rollbackErr
 				}
 				log.Errorf("error committing dbTx, err: %w", errC)
-				return nil, errC
+				return nil, nil, errC
 			}
 			if errors.Is(err, state.ErrNotFound) {
 				log.Warn("error checking reorg: previous block not found in db: ", err)
-				return &state.Block{}, nil
+				s.status.Reorg(latestEthBlockSynced.BlockNumber)
+				reorg.Depth = latestEthBlockSynced.BlockNumber
+				return &state.Block{}, reorg, nil
 			} else if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		} else {
 			break
@@ -598,9 +892,11 @@ func (s *ClientSynchronizer) checkReorg(latestBlock *state.Block) (*state.Block,
 	}
 	if latestEthBlockSynced.BlockHash != latestBlock.BlockHash {
 		log.Debug("Reorg detected in block: ", latestEthBlockSynced.BlockNumber)
-		return latestBlock, nil
+		s.status.Reorg(latestEthBlockSynced.BlockNumber - latestBlock.BlockNumber)
+		reorg.Depth = latestEthBlockSynced.BlockNumber - latestBlock.BlockNumber
+		return latestBlock, reorg, nil
 	}
-	return nil, nil
+	return nil, nil, nil
 }
 
 // Stop function stops the synchronizer
@@ -608,6 +904,42 @@ func (s *ClientSynchronizer) Stop() {
 	s.cancelCtx()
 }
 
+// RegisterBatchClosedEventHandler registers a handler notified of the closed
+// batches stored by the synchronizer, the ones closed by the sequencer running
+// along with it are not notified
+func (s *ClientSynchronizer) RegisterBatchClosedEventHandler(h BatchClosedEventHandler) {
+	s.batchClosedEventHandlersMux.Lock()
+	defer s.batchClosedEventHandlersMux.Unlock()
+	s.batchClosedEventHandlers = append(s.batchClosedEventHandlers, h)
+}
+
+// onBatchClosed notifies the handlers that the batch was closed
+func (s *ClientSynchronizer) onBatchClosed(batchNumber uint64) {
+	s.batchClosedEventHandlersMux.RLock()
+	defer s.batchClosedEventHandlersMux.RUnlock()
+	for _, h := range s.batchClosedEventHandlers {
+		h(batchNumber)
+	}
+}
+
+// onVirtualBatchesStored notifies the handlers that the last virtual batch
+// was closed, the sequenced batches are stored closed along with their
+// virtual batches
+func (s *ClientSynchronizer) onVirtualBatchesStored() {
+	s.batchClosedEventHandlersMux.RLock()
+	noHandlers := len(s.batchClosedEventHandlers) == 0
+	s.batchClosedEventHandlersMux.RUnlock()
+	if noHandlers {
+		return
+	}
+	batchNumber, err := s.state.GetLastVirtualBatchNum(s.ctx, nil)
+	if err != nil {
+		log.Warn("error getting the last virtual batch to notify it closed. Error: ", err)
+		return
+	}
+	s.onBatchClosed(batchNumber)
+}
+
 func (s *ClientSynchronizer) checkTrustedState(batch state.Batch, tBatch *state.Batch, newRoot common.Hash, dbTx pgx.Tx) bool {
 	//Compare virtual state with trusted state
 	var reorgReasons strings.Builder
@@ -635,7 +967,7 @@ func (s *ClientSynchronizer) checkTrustedState(batch state.Batch, tBatch *state.
 	return false
 }
 
-func (s *ClientSynchronizer) processSequenceBatches(sequencedBatches []etherman.SequencedBatch, dbTx pgx.Tx, batchesSeenAtBlock uint64) error {
+func (s *ClientSynchronizer) processSequenceBatches(sequencedBatches []etherman.SequencedBatch, dbTx pgx.Tx, batchesSeenAtBlock uint64, l1Finality state.L1Finality) error {
 	if len(sequencedBatches) == 0 {
 		log.Warn("Empty sequencedBatches array detected, ignoring...")
 		return nil
@@ -645,6 +977,18 @@ func (s *ClientSynchronizer) processSequenceBatches(sequencedBatches []etherman.
 		return err
 	}
 
+	lastBatch, err := s.state.GetLastBatchInfo(s.ctx, dbTx)
+	if err != nil {
+		log.Errorf("error getting the last virtual batch. Error: %v", err)
+		rollbackErr := dbTx.Rollback(s.ctx)
+		if rollbackErr != nil {
+			log.Errorf("error rolling back state. rollbackErr: %s, error : %w", rollbackErr.Error(), err)
+			return rollbackErr
+		}
+		return err
+	}
+	seq := state.Sequence{FromBatchNumber: lastBatch.Number + 1}
+
 	for _, sbatch := range sequencedBatches {
 		// Ensure the L1 origin for this batch is in the database. Since the L1 origin assigned by
 		// HotShot is not necessarily the same as an L1 block which we added to the database as a
@@ -654,114 +998,174 @@ func (s *ClientSynchronizer) processSequenceBatches(sequencedBatches []etherman.
 			return err
 		}
 
+		// The forced batches that timed out are included before the batch
+		err = s.includeForcedBatches(&lastBatch, blockNumber, sbatch.Timestamp, batchesSeenAtBlock, l1Finality, dbTx)
+		if err != nil {
+			return err
+		}
+
+		// The batch was numbered when it was downloaded, without the forced
+		// batches included since then
+		batchNumber := lastBatch.Number + 1
+		if sbatch.BatchNumber > batchNumber {
+			err = fmt.Errorf("received batch %d from the future, last batch is %d", sbatch.BatchNumber, lastBatch.Number)
+			log.Error(err)
+			rollbackErr := dbTx.Rollback(s.ctx)
+			if rollbackErr != nil {
+				log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %s, error : %w", sbatch.BatchNumber, blockNumber, rollbackErr.Error(), err)
+				return rollbackErr
+			}
+			return err
+		} else if sbatch.BatchNumber < batchNumber {
+			log.Debugf("batch %d renumbered to %d after including forced batches", sbatch.BatchNumber, batchNumber)
+		}
+
 		virtualBatch := state.VirtualBatch{
-			BatchNumber:   sbatch.BatchNumber,
-			TxHash:        sbatch.TxHash,
-			SeenAt:        batchesSeenAtBlock,
-			Coinbase:      sbatch.Coinbase,
-			BlockNumber:   sbatch.BlockNumber,
-			SequencerAddr: sbatch.SequencerAddr,
+			BatchNumber:        batchNumber,
+			TxHash:             sbatch.TxHash,
+			SeenAt:             batchesSeenAtBlock,
+			Coinbase:           sbatch.Coinbase,
+			BlockNumber:        sbatch.BlockNumber,
+			SequencerAddr:      sbatch.SequencerAddr,
+			L1Finality:         l1Finality,
+			LastForcedBatchNum: lastBatch.ForcedBatchNum,
 		}
 		batch := state.Batch{
-			BatchNumber:    sbatch.BatchNumber,
+			BatchNumber:    batchNumber,
 			GlobalExitRoot: sbatch.GlobalExitRoot,
 			Timestamp:      time.Unix(int64(sbatch.Timestamp), 0),
 			Coinbase:       sbatch.Coinbase,
 			BatchL2Data:    sbatch.Transactions,
 		}
+		err = s.processSequencedBatch(batch, virtualBatch, dbTx)
+		if err != nil {
+			return err
+		}
+		lastBatch = state.L2BatchInfo{
+			Number:         batchNumber,
+			L1Block:        sbatch.BlockNumber,
+			Timestamp:      sbatch.Timestamp,
+			ForcedBatchNum: lastBatch.ForcedBatchNum,
+		}
+	}
+	// Insert the sequence to allow the aggregator verify the sequence batches
+	seq.ToBatchNumber = lastBatch.Number
+	err = s.state.AddSequence(s.ctx, seq, dbTx)
+	if err != nil {
+		log.Errorf("error adding sequence. Sequence: %+v", seq)
+		rollbackErr := dbTx.Rollback(s.ctx)
+		if rollbackErr != nil {
+			log.Errorf("error rolling back state. rollbackErr: %s, error : %w", rollbackErr.Error(), err)
+			return rollbackErr
+		}
+		log.Errorf("error getting adding sequence. error: %w", err)
+		return err
+	}
+	return nil
+}
 
-		// Forced batches no longer supported, don't need to be handled
+// processSequencedBatch executes the batch and stores it along with its
+// virtual batch. A batch already stored by the trusted state is kept, unless
+// it doesn't include the same forced batch
+func (s *ClientSynchronizer) processSequencedBatch(batch state.Batch, virtualBatch state.VirtualBatch, dbTx pgx.Tx) error {
+	blockNumber := virtualBatch.BlockNumber
+	processCtx := state.ProcessingContext{
+		BatchNumber:    batch.BatchNumber,
+		Coinbase:       batch.Coinbase,
+		Timestamp:      batch.Timestamp,
+		GlobalExitRoot: batch.GlobalExitRoot,
+		ForcedBatchNum: batch.ForcedBatchNum,
+	}
 
-		// Now we need to check the batch. ForcedBatches should be already stored in the batch table because this is done by the sequencer
-		processCtx := state.ProcessingContext{
-			BatchNumber:    batch.BatchNumber,
-			Coinbase:       batch.Coinbase,
-			Timestamp:      batch.Timestamp,
-			GlobalExitRoot: batch.GlobalExitRoot,
-			ForcedBatchNum: batch.ForcedBatchNum,
+	// Reprocess batch to compare the stateRoot with tBatch.StateRoot and get accInputHash
+	start := time.Now()
+	p, err := s.state.ExecuteBatch(s.ctx, batch, dbTx)
+	if err != nil {
+		log.Errorf("error executing L1 batch: %+v, error: %w", batch, err)
+		rollbackErr := dbTx.Rollback(s.ctx)
+		if rollbackErr != nil {
+			log.Fatalf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %s, error : %w", batch.BatchNumber, blockNumber, rollbackErr.Error(), err)
 		}
+		log.Fatalf("error executing L1 batch: %+v, error: %w", batch, err)
+	}
+	accumulatedInputHash := common.BytesToHash(p.NewAccInputHash)
 
-		// Reprocess batch to compare the stateRoot with tBatch.StateRoot and get accInputHash
-		p, err := s.state.ExecuteBatch(s.ctx, batch, dbTx)
+	// First get trusted batch from db
+	tBatch, err := s.state.GetBatchByNumber(s.ctx, batch.BatchNumber, dbTx)
+	if err == nil && !equalForcedBatchNum(tBatch.ForcedBatchNum, batch.ForcedBatchNum) {
+		log.Warnf("Trusted Reorg detected for Batch Number: %d. Different forced batch included", batch.BatchNumber)
+		err = s.addForcedBatchReorg(tBatch, batch, common.BytesToHash(p.NewStateRoot), dbTx)
 		if err != nil {
-			log.Errorf("error executing L1 batch: %+v, error: %w", batch, err)
+			log.Errorf("error recording the trusted reorg. BatchNumber: %d, BlockNumber: %d, error: %w", batch.BatchNumber, blockNumber, err)
 			rollbackErr := dbTx.Rollback(s.ctx)
 			if rollbackErr != nil {
-				log.Fatalf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %s, error : %w", batch.BatchNumber, blockNumber, rollbackErr.Error(), err)
+				log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %s, error : %w", batch.BatchNumber, blockNumber, rollbackErr.Error(), err)
+				return rollbackErr
 			}
-			log.Fatalf("error executing L1 batch: %+v, error: %w", batch, err)
+			return err
 		}
-		accumulatedInputHash := common.BytesToHash(p.NewAccInputHash)
-
-		// First get trusted batch from db
-		tBatch, err := s.state.GetBatchByNumber(s.ctx, batch.BatchNumber, dbTx)
-		_ = tBatch
+		err = s.state.ResetTrustedState(s.ctx, batch.BatchNumber-1, dbTx)
 		if err != nil {
-			if errors.Is(err, state.ErrNotFound) || errors.Is(err, state.ErrStateNotSynchronized) {
-				log.Debugf("BatchNumber: %d, not found in trusted state. Storing it...", batch.BatchNumber)
-				// If it is not found, store batch
-				err = s.state.ProcessAndStoreClosedBatch(s.ctx, processCtx, batch.BatchL2Data, dbTx, state.SynchronizerCallerLabel)
-				if err != nil {
-					log.Errorf("error storing trustedBatch. BatchNumber: %d, BlockNumber: %d, error: %w", batch.BatchNumber, blockNumber, err)
-					rollbackErr := dbTx.Rollback(s.ctx)
-					if rollbackErr != nil {
-						log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %s, error : %w", batch.BatchNumber, blockNumber, rollbackErr.Error(), err)
-						return rollbackErr
-					}
-					log.Errorf("error storing batch. BatchNumber: %d, BlockNumber: %d, error: %w", batch.BatchNumber, blockNumber, err)
-					return err
-				}
-				tBatch = &batch
-			} else {
-				log.Error("error checking trusted state: ", err)
-				rollbackErr := dbTx.Rollback(s.ctx)
-				if rollbackErr != nil {
-					log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %w", batch.BatchNumber, blockNumber, rollbackErr)
-					return rollbackErr
-				}
-				return err
+			log.Errorf("error resetting trusted state. BatchNumber: %d, BlockNumber: %d, error: %w", batch.BatchNumber, blockNumber, err)
+			rollbackErr := dbTx.Rollback(s.ctx)
+			if rollbackErr != nil {
+				log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %s, error : %w", batch.BatchNumber, blockNumber, rollbackErr.Error(), err)
+				return rollbackErr
 			}
-		} else {
-			//AddAccumulatedInputHash
-			err = s.state.AddAccumulatedInputHash(s.ctx, batch.BatchNumber, accumulatedInputHash, dbTx)
+			return err
+		}
+		err = state.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, state.ErrNotFound) || errors.Is(err, state.ErrStateNotSynchronized) {
+			log.Debugf("BatchNumber: %d, not found in trusted state. Storing it...", batch.BatchNumber)
+			// If it is not found, store batch
+			err = s.state.ProcessAndStoreClosedBatch(s.ctx, processCtx, batch.BatchL2Data, dbTx, state.SynchronizerCallerLabel)
 			if err != nil {
-				log.Errorf("error adding accumulatedInputHash for batch: %d. Error; %w", batch.BatchNumber, err)
+				log.Errorf("error storing trustedBatch. BatchNumber: %d, BlockNumber: %d, error: %w", batch.BatchNumber, blockNumber, err)
 				rollbackErr := dbTx.Rollback(s.ctx)
 				if rollbackErr != nil {
-					log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %w", batch.BatchNumber, blockNumber, rollbackErr)
+					log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %s, error : %w", batch.BatchNumber, blockNumber, rollbackErr.Error(), err)
 					return rollbackErr
 				}
+				log.Errorf("error storing batch. BatchNumber: %d, BlockNumber: %d, error: %w", batch.BatchNumber, blockNumber, err)
 				return err
 			}
+		} else {
+			log.Error("error checking trusted state: ", err)
+			rollbackErr := dbTx.Rollback(s.ctx)
+			if rollbackErr != nil {
+				log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %w", batch.BatchNumber, blockNumber, rollbackErr)
+				return rollbackErr
+			}
+			return err
 		}
-
-		// Store virtualBatch
-		err = s.state.AddVirtualBatch(s.ctx, &virtualBatch, dbTx)
+	} else {
+		//AddAccumulatedInputHash
+		err = s.state.AddAccumulatedInputHash(s.ctx, batch.BatchNumber, accumulatedInputHash, dbTx)
 		if err != nil {
-			log.Errorf("error storing virtualBatch. BatchNumber: %d, BlockNumber: %d, error: %w", virtualBatch.BatchNumber, blockNumber, err)
+			log.Errorf("error adding accumulatedInputHash for batch: %d. Error; %w", batch.BatchNumber, err)
 			rollbackErr := dbTx.Rollback(s.ctx)
 			if rollbackErr != nil {
-				log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %s, error : %w", virtualBatch.BatchNumber, blockNumber, rollbackErr.Error(), err)
+				log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %w", batch.BatchNumber, blockNumber, rollbackErr)
 				return rollbackErr
 			}
-			log.Errorf("error storing virtualBatch. BatchNumber: %d, BlockNumber: %d, error: %w", virtualBatch.BatchNumber, blockNumber, err)
 			return err
 		}
 	}
-	// Insert the sequence to allow the aggregator verify the sequence batches
-	seq := state.Sequence{
-		FromBatchNumber: sequencedBatches[0].BatchNumber,
-		ToBatchNumber:   sequencedBatches[len(sequencedBatches)-1].BatchNumber,
-	}
-	err := s.state.AddSequence(s.ctx, seq, dbTx)
+
+	metrics.ProcessedBatch(time.Since(start))
+
+	// Store virtualBatch
+	err = s.state.AddVirtualBatch(s.ctx, &virtualBatch, dbTx)
 	if err != nil {
-		log.Errorf("error adding sequence. Sequence: %+v", seq)
+		log.Errorf("error storing virtualBatch. BatchNumber: %d, BlockNumber: %d, error: %w", virtualBatch.BatchNumber, blockNumber, err)
 		rollbackErr := dbTx.Rollback(s.ctx)
 		if rollbackErr != nil {
-			log.Errorf("error rolling back state. rollbackErr: %s, error : %w", rollbackErr.Error(), err)
+			log.Errorf("error rolling back state. BatchNumber: %d, BlockNumber: %d, rollbackErr: %s, error : %w", virtualBatch.BatchNumber, blockNumber, rollbackErr.Error(), err)
 			return rollbackErr
 		}
-		log.Errorf("error getting adding sequence. error: %w", err)
+		log.Errorf("error storing virtualBatch. BatchNumber: %d, BlockNumber: %d, error: %w", virtualBatch.BatchNumber, blockNumber, err)
 		return err
 	}
 	return nil
@@ -854,6 +1258,28 @@ func (s *ClientSynchronizer) processGlobalExitRoot(globalExitRoot etherman.Globa
 	return nil
 }
 
+func (s *ClientSynchronizer) processForkID(forkID etherman.ForkID, blockNumber uint64, dbTx pgx.Tx) error {
+	fID := state.ForkIDInterval{
+		FromBatchNumber: forkID.BatchNumber,
+		ForkId:          forkID.ForkID,
+		Version:         forkID.Version,
+		BlockNumber:     blockNumber,
+	}
+	err := s.state.AddForkID(s.ctx, fID, dbTx)
+	if err != nil {
+		log.Errorf("error storing the forkID in processForkID. BlockNumber: %d", blockNumber)
+		rollbackErr := dbTx.Rollback(s.ctx)
+		if rollbackErr != nil {
+			log.Errorf("error rolling back state. BlockNumber: %d, rollbackErr: %s, error : %w", blockNumber, rollbackErr.Error(), err)
+			return rollbackErr
+		}
+		log.Errorf("error storing the forkID in processForkID. BlockNumber: %d, error: %w", blockNumber, err)
+		return err
+	}
+	log.Infof("fork id %d (%s) activated from batch %d at L1 block %d", forkID.ForkID, forkID.Version, forkID.BatchNumber, blockNumber)
+	return nil
+}
+
 func (s *ClientSynchronizer) processTrustedVerifyBatches(lastVerifiedBatch etherman.VerifiedBatch, dbTx pgx.Tx) error {
 	lastVBatch, err := s.state.GetLastVerifiedBatch(s.ctx, dbTx)
 	if err != nil {
@@ -941,20 +1367,16 @@ func (s *ClientSynchronizer) processTrustedBatch(trustedBatch *pb.GetBatchRespon
 
 	// check if batch needs to be synchronized
 	if batch != nil {
-		matchNumber := batch.BatchNumber == trustedBatch.BatchNumber
-		matchGER := batch.GlobalExitRoot.String() == trustedBatch.GlobalExitRoot
-		matchLER := batch.LocalExitRoot.String() == trustedBatch.LocalExitRoot
-		matchSR := batch.StateRoot.String() == trustedBatch.StateRoot
-		matchCoinbase := batch.Coinbase.String() == trustedBatch.Sequencer
-		matchTimestamp := uint64(batch.Timestamp.Unix()) == trustedBatch.Timestamp
-		matchL2Data := hex.EncodeToString(batch.BatchL2Data) == hex.EncodeToString(trustedBatchL2Data)
-
-		if matchNumber && matchGER && matchLER && matchSR &&
-			matchCoinbase && matchTimestamp && matchL2Data {
+		reason := trustedBatchMismatchReason(batch, trustedBatch, trustedBatchL2Data)
+		if reason == "" {
 			log.Debugf("batch %v already synchronized", trustedBatch.BatchNumber)
 			return nil
 		}
 		log.Infof("batch %v needs to be updated", trustedBatch.BatchNumber)
+		if err := s.addTrustedReorg(batch, common.HexToHash(trustedBatch.StateRoot), txs, reason, dbTx); err != nil {
+			log.Errorf("failed to record the trusted reorg of batch %v: %v", trustedBatch.BatchNumber, err)
+			return err
+		}
 	} else {
 		log.Infof("batch %v needs to be synchronized", trustedBatch.BatchNumber)
 	}
@@ -1010,3 +1432,89 @@ func (s *ClientSynchronizer) processTrustedBatch(trustedBatch *pb.GetBatchRespon
 	log.Infof("batch %v synchronized", trustedBatch.BatchNumber)
 	return nil
 }
+
+// trustedBatchMismatchReason returns the fields of the stored batch that don't
+// match the trusted batch, empty if the batch is already synchronized
+func trustedBatchMismatchReason(batch *state.Batch, trustedBatch *pb.GetBatchResponse, trustedBatchL2Data []byte) string {
+	var reasons strings.Builder
+	if batch.BatchNumber != trustedBatch.BatchNumber {
+		reasons.WriteString(fmt.Sprintf("Different field BatchNumber. Stored: %d, Trusted: %d\n", batch.BatchNumber, trustedBatch.BatchNumber))
+	}
+	if batch.GlobalExitRoot.String() != trustedBatch.GlobalExitRoot {
+		reasons.WriteString(fmt.Sprintf("Different field GlobalExitRoot. Stored: %s, Trusted: %s\n", batch.GlobalExitRoot.String(), trustedBatch.GlobalExitRoot))
+	}
+	if batch.LocalExitRoot.String() != trustedBatch.LocalExitRoot {
+		reasons.WriteString(fmt.Sprintf("Different field LocalExitRoot. Stored: %s, Trusted: %s\n", batch.LocalExitRoot.String(), trustedBatch.LocalExitRoot))
+	}
+	if batch.StateRoot.String() != trustedBatch.StateRoot {
+		reasons.WriteString(fmt.Sprintf("Different field StateRoot. Stored: %s, Trusted: %s\n", batch.StateRoot.String(), trustedBatch.StateRoot))
+	}
+	if batch.Coinbase.String() != trustedBatch.Sequencer {
+		reasons.WriteString(fmt.Sprintf("Different field Coinbase. Stored: %s, Trusted: %s\n", batch.Coinbase.String(), trustedBatch.Sequencer))
+	}
+	if uint64(batch.Timestamp.Unix()) != trustedBatch.Timestamp {
+		reasons.WriteString(fmt.Sprintf("Different field Timestamp. Stored: %d, Trusted: %d\n", batch.Timestamp.Unix(), trustedBatch.Timestamp))
+	}
+	if hex.EncodeToString(batch.BatchL2Data) != hex.EncodeToString(trustedBatchL2Data) {
+		reasons.WriteString(fmt.Sprintf("Different field BatchL2Data. Stored: %s, Trusted: %s\n", hex.EncodeToString(batch.BatchL2Data), hex.EncodeToString(trustedBatchL2Data)))
+	}
+	return reasons.String()
+}
+
+// addForcedBatchReorg records the reorg of the trusted state from the stored
+// batch when the sequenced batch with its number includes another forced batch
+func (s *ClientSynchronizer) addForcedBatchReorg(tBatch *state.Batch, batch state.Batch, newStateRoot common.Hash, dbTx pgx.Tx) error {
+	txs, _, err := state.DecodeTxs(batch.BatchL2Data)
+	if err != nil {
+		return err
+	}
+	reason := fmt.Sprintf("Different field ForcedBatchNum. Trusted: %s, Virtual: %s\n", forcedBatchNumString(tBatch.ForcedBatchNum), forcedBatchNumString(batch.ForcedBatchNum))
+	return s.addTrustedReorg(tBatch, newStateRoot, txs, reason, dbTx)
+}
+
+// addTrustedReorg records the reorg of the trusted state from the stored batch
+// when it is replaced by a new one with the given state root and txs. Updating
+// an open batch with new txs is not a reorg, it is one when the stored batch
+// was closed or some of its txs are dropped
+func (s *ClientSynchronizer) addTrustedReorg(batch *state.Batch, newStateRoot common.Hash, newTxs []types.Transaction, reason string, dbTx pgx.Tx) error {
+	storedTxs, err := s.state.GetTransactionsFromBatchNumber(s.ctx, batch.BatchNumber, dbTx)
+	if err != nil {
+		return err
+	}
+	newTxHashes := make(map[common.Hash]struct{}, len(newTxs))
+	for _, tx := range newTxs {
+		newTxHashes[tx.Hash()] = struct{}{}
+	}
+	droppedTxs := make([]types.Transaction, 0)
+	for _, tx := range storedTxs {
+		if _, ok := newTxHashes[tx.Hash()]; !ok {
+			droppedTxs = append(droppedTxs, tx)
+		}
+	}
+	isBatchClosed := batch.StateRoot != state.ZeroHash
+	if !isBatchClosed && len(droppedTxs) == 0 {
+		return nil
+	}
+
+	lastBatchNumber, err := s.state.GetLastBatchNumber(s.ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	firstBlockNumber, err := s.state.GetFirstL2BlockNumberFromBatchNumber(s.ctx, batch.BatchNumber, dbTx)
+	if err != nil && !errors.Is(err, state.ErrNotFound) {
+		return err
+	}
+	reorg := &state.Reorg{
+		Kind:             state.ReorgKindTrusted,
+		Depth:            lastBatchNumber - batch.BatchNumber + 1,
+		FirstBlockNumber: firstBlockNumber,
+		FirstBatchNumber: batch.BatchNumber,
+		OldHash:          batch.StateRoot,
+		NewHash:          newStateRoot,
+		Reason:           reason,
+		DroppedTxs:       droppedTxs,
+		CreatedAt:        time.Now(),
+	}
+	log.Warnf("Trusted Reorg detected for Batch Number: %d, %d txs dropped.\nReasons: %s", batch.BatchNumber, len(droppedTxs), reason)
+	return s.state.AddReorg(s.ctx, reorg, dbTx)
+}
@@ -1,19 +1,21 @@
 package synchronizer
 
 import (
-	context "context"
+	"errors"
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 
 	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/etherman"
-	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevm"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor/pb"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -25,668 +27,750 @@ type mocks struct {
 	DbTx         *dbTxMock
 }
 
-func TestTrustedStateReorg(t *testing.T) {
-	type testCase struct {
-		Name            string
-		getTrustedBatch func(*mocks, context.Context, etherman.SequencedBatch) *state.Batch
+func newTestSynchronizer(t *testing.T, cfg Config) (*ClientSynchronizer, *mocks) {
+	m := &mocks{
+		Etherman:     newEthermanMock(t),
+		State:        newStateMock(t),
+		EthTxManager: newEthTxManagerMock(t),
+		DbTx:         newDbTxMock(t),
 	}
+	cfg.SyncInterval = cfgTypes.Duration{Duration: 1 * time.Second}
+	cfg.SyncChunkSize = 10
+	sync, err := NewSynchronizer(true, m.Etherman, m.State, m.EthTxManager, state.Genesis{}, cfg)
+	require.NoError(t, err)
+	return sync.(*ClientSynchronizer), m
+}
 
-	setupMocks := func(m *mocks, tc *testCase) Synchronizer {
-		genesis := state.Genesis{}
-		cfg := Config{
-			SyncInterval:   cfgTypes.Duration{Duration: 1 * time.Second},
-			SyncChunkSize:  10,
-			GenBlockNumber: uint64(123456),
-		}
-		sync, err := NewSynchronizer(true, m.Etherman, m.State, m.EthTxManager, genesis, cfg)
-		require.NoError(t, err)
-
-		// state preparation
-		ctxMatchBy := mock.MatchedBy(func(ctx context.Context) bool { return ctx != nil })
-		m.State.
-			On("BeginStateTransaction", ctxMatchBy).
-			Run(func(args mock.Arguments) {
-				ctx := args[0].(context.Context)
-				parentHash := common.HexToHash("0x111")
-				ethHeader := &types.Header{Number: big.NewInt(1), ParentHash: parentHash}
-				ethBlock := types.NewBlockWithHeader(ethHeader)
-				lastBlock := &state.Block{BlockHash: ethBlock.Hash(), BlockNumber: ethBlock.Number().Uint64()}
-
-				m.State.
-					On("GetLastBlock", ctx, m.DbTx).
-					Return(lastBlock, nil).
-					Once()
-
-				m.DbTx.
-					On("Commit", ctx).
-					Return(nil).
-					Once()
-
-				m.Etherman.
-					On("EthBlockByNumber", ctx, lastBlock.BlockNumber).
-					Return(ethBlock, nil).
-					Once()
-
-				var n *big.Int
-				m.Etherman.
-					On("HeaderByNumber", ctx, n).
-					Return(ethHeader, nil).
-					Once()
-
-				t := time.Now()
-				sequencedBatch := etherman.SequencedBatch{
-					BatchNumber: uint64(1),
-					Coinbase:    common.HexToAddress("0x222"),
-					TxHash:      common.HexToHash("0x333"),
-					PolygonZkEVMBatchData: polygonzkevm.PolygonZkEVMBatchData{
-						Transactions:       []byte{},
-						GlobalExitRoot:     [32]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32},
-						Timestamp:          uint64(t.Unix()),
-						MinForcedTimestamp: 0,
-					},
-				}
+func newTestSequencedBatch(batchNumber, blockNumber uint64) etherman.SequencedBatch {
+	return etherman.SequencedBatch{
+		BatchNumber:   batchNumber,
+		BlockNumber:   blockNumber,
+		Coinbase:      common.HexToAddress("0x222"),
+		SequencerAddr: common.HexToAddress("0x00"),
+		TxHash:        common.HexToHash("0x333"),
+		PolygonZkEVMBatchData: etherman.PolygonZkEVMBatchData{
+			Transactions:   []byte{},
+			GlobalExitRoot: [32]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32},
+			Timestamp:      uint64(time.Now().Unix()),
+		},
+	}
+}
 
-				ethermanBlock := etherman.Block{
-					BlockHash:        ethBlock.Hash(),
-					SequencedBatches: [][]etherman.SequencedBatch{{sequencedBatch}},
+func TestTrustedStateReorg(t *testing.T) {
+	forcedBatchNum := uint64(1)
+	testCases := []struct {
+		name         string
+		trustedBatch func(etherman.SequencedBatch) *state.Batch
+		reorged      bool
+	}{
+		{
+			name: "same forced batch keeps the trusted batch",
+			trustedBatch: func(sequencedBatch etherman.SequencedBatch) *state.Batch {
+				return &state.Batch{
+					BatchNumber:    sequencedBatch.BatchNumber,
+					BatchL2Data:    []byte{1},
+					GlobalExitRoot: sequencedBatch.GlobalExitRoot,
+					Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
+					Coinbase:       sequencedBatch.Coinbase,
 				}
-				blocks := []etherman.Block{ethermanBlock}
-				order := map[common.Hash][]etherman.Order{
-					ethBlock.Hash(): {
-						{
-							Name: etherman.SequenceBatchesOrder,
-							Pos:  0,
-						},
-					},
+			},
+		},
+		{
+			name: "different forced batch resets the trusted state",
+			trustedBatch: func(sequencedBatch etherman.SequencedBatch) *state.Batch {
+				return &state.Batch{
+					BatchNumber:    sequencedBatch.BatchNumber,
+					BatchL2Data:    sequencedBatch.Transactions,
+					GlobalExitRoot: sequencedBatch.GlobalExitRoot,
+					Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
+					Coinbase:       sequencedBatch.Coinbase,
+					StateRoot:      common.HexToHash("0x555"),
+					ForcedBatchNum: &forcedBatchNum,
 				}
+			},
+			reorged: true,
+		},
+	}
 
-				fromBlock := ethBlock.NumberU64() + 1
-				toBlock := fromBlock + cfg.SyncChunkSize
-
-				m.Etherman.
-					On("GetRollupInfoByBlockRange", ctx, fromBlock, &toBlock).
-					Return(blocks, order, nil).
-					Once()
-
-				m.State.
-					On("BeginStateTransaction", ctx).
-					Return(m.DbTx, nil).
-					Once()
-
-				stateBlock := &state.Block{
-					BlockNumber: ethermanBlock.BlockNumber,
-					BlockHash:   ethermanBlock.BlockHash,
-					ParentHash:  ethermanBlock.ParentHash,
-					ReceivedAt:  ethermanBlock.ReceivedAt,
-				}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sync, m := newTestSynchronizer(t, Config{})
+			ctx := sync.ctx
 
-				m.State.
-					On("AddBlock", ctx, stateBlock, m.DbTx).
-					Return(nil).
-					Once()
+			sequencedBatch := newTestSequencedBatch(1, 5)
+			m.State.
+				On("ContainsBlock", ctx, uint64(5), m.DbTx).
+				Return(true, nil).
+				Twice()
+			m.State.
+				On("GetLastBatchInfo", ctx, m.DbTx).
+				Return(state.L2BatchInfo{}, nil).
+				Once()
 
-				trustedBatch := tc.getTrustedBatch(m, ctx, sequencedBatch)
+			batch := state.Batch{
+				BatchNumber:    sequencedBatch.BatchNumber,
+				GlobalExitRoot: sequencedBatch.GlobalExitRoot,
+				Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
+				Coinbase:       sequencedBatch.Coinbase,
+				BatchL2Data:    sequencedBatch.Transactions,
+			}
+			accInputHash := common.HexToHash("0x444")
+			newStateRoot := common.HexToHash("0x666")
+			m.State.
+				On("ExecuteBatch", ctx, batch, m.DbTx).
+				Return(&pb.ProcessBatchResponse{NewAccInputHash: accInputHash.Bytes(), NewStateRoot: newStateRoot.Bytes()}, nil).
+				Once()
+			trustedBatch := tc.trustedBatch(sequencedBatch)
+			m.State.
+				On("GetBatchByNumber", ctx, sequencedBatch.BatchNumber, m.DbTx).
+				Return(trustedBatch, nil).
+				Once()
 
+			if tc.reorged {
+				// the txs of the trusted batches from it are dropped
+				droppedTx := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil)
 				m.State.
-					On("GetBatchByNumber", ctx, sequencedBatch.BatchNumber, m.DbTx).
-					Return(trustedBatch, nil).
+					On("GetTransactionsFromBatchNumber", ctx, sequencedBatch.BatchNumber, m.DbTx).
+					Return([]types.Transaction{*droppedTx}, nil).
 					Once()
-
-				sbatch := state.Batch{
-					BatchNumber:    sequencedBatch.BatchNumber,
-					Coinbase:       common.HexToAddress("0x222"),
-					BatchL2Data:    []byte{},
-					Timestamp:      time.Unix(int64(t.Unix()), 0),
-					Transactions:   nil,
-					GlobalExitRoot: [32]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32},
-					ForcedBatchNum: nil,
-				}
 				m.State.
-					On("ExecuteBatch", ctx, sbatch, m.DbTx).
-					Return(&pb.ProcessBatchResponse{NewStateRoot: trustedBatch.StateRoot.Bytes()}, nil).
+					On("GetLastBatchNumber", ctx, m.DbTx).
+					Return(uint64(2), nil).
 					Once()
-
-				seq := state.Sequence{
-					FromBatchNumber: 1,
-					ToBatchNumber:   1,
-				}
 				m.State.
-					On("AddSequence", ctx, seq, m.DbTx).
-					Return(nil).
+					On("GetFirstL2BlockNumberFromBatchNumber", ctx, sequencedBatch.BatchNumber, m.DbTx).
+					Return(uint64(7), nil).
 					Once()
-
 				m.State.
-					On("AddAccumulatedInputHash", ctx, sequencedBatch.BatchNumber, common.Hash{}, m.DbTx).
+					On("AddReorg", ctx, mock.MatchedBy(func(reorg *state.Reorg) bool {
+						return reorg.Kind == state.ReorgKindTrusted && reorg.FirstBatchNumber == 1 && reorg.Depth == 2 &&
+							reorg.FirstBlockNumber == 7 && reorg.OldHash == trustedBatch.StateRoot && reorg.NewHash == newStateRoot &&
+							strings.Contains(reorg.Reason, "ForcedBatchNum") &&
+							len(reorg.DroppedTxs) == 1 && reorg.DroppedTxs[0].Hash() == droppedTx.Hash()
+					}), m.DbTx).
 					Return(nil).
 					Once()
-
 				m.State.
 					On("ResetTrustedState", ctx, sequencedBatch.BatchNumber-1, m.DbTx).
 					Return(nil).
 					Once()
-
 				processingContext := state.ProcessingContext{
 					BatchNumber:    sequencedBatch.BatchNumber,
 					Coinbase:       sequencedBatch.Coinbase,
 					Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
 					GlobalExitRoot: sequencedBatch.GlobalExitRoot,
 				}
-
 				m.State.
 					On("ProcessAndStoreClosedBatch", ctx, processingContext, sequencedBatch.Transactions, m.DbTx, state.SynchronizerCallerLabel).
 					Return(nil).
 					Once()
-
-				virtualBatch := &state.VirtualBatch{
-					BatchNumber: sequencedBatch.BatchNumber,
-					TxHash:      sequencedBatch.TxHash,
-					Coinbase:    sequencedBatch.Coinbase,
-					BlockNumber: ethermanBlock.BlockNumber,
-				}
-
+			} else {
 				m.State.
-					On("AddVirtualBatch", ctx, virtualBatch, m.DbTx).
-					Return(nil).
-					Once()
-
-				m.DbTx.
-					On("Commit", ctx).
-					Run(func(args mock.Arguments) { sync.Stop() }).
+					On("AddAccumulatedInputHash", ctx, sequencedBatch.BatchNumber, accInputHash, m.DbTx).
 					Return(nil).
 					Once()
+			}
 
-				m.Etherman.
-					On("GetLatestBatchNumber").
-					Return(uint64(10), nil).
-					Once()
-
-				var nilDbTx pgx.Tx
-				m.State.
-					On("GetLastBatchNumber", ctx, nilDbTx).
-					Return(uint64(10), nil).
-					Once()
-			}).
-			Return(m.DbTx, nil).
-			Once()
+			virtualBatch := &state.VirtualBatch{
+				BatchNumber:   sequencedBatch.BatchNumber,
+				TxHash:        sequencedBatch.TxHash,
+				SeenAt:        5,
+				Coinbase:      sequencedBatch.Coinbase,
+				BlockNumber:   sequencedBatch.BlockNumber,
+				SequencerAddr: sequencedBatch.SequencerAddr,
+				L1Finality:    state.L1FinalityLatest,
+			}
+			m.State.
+				On("AddVirtualBatch", ctx, virtualBatch, m.DbTx).
+				Return(nil).
+				Once()
+			m.State.
+				On("AddSequence", ctx, state.Sequence{FromBatchNumber: 1, ToBatchNumber: 1}, m.DbTx).
+				Return(nil).
+				Once()
 
-		return sync
+			err := sync.processSequenceBatches([]etherman.SequencedBatch{sequencedBatch}, m.DbTx, 5, state.L1FinalityLatest)
+			require.NoError(t, err)
+		})
 	}
+}
 
-	testCases := []testCase{
-		{
-			Name: "Transactions are different",
-			getTrustedBatch: func(m *mocks, ctx context.Context, sequencedBatch etherman.SequencedBatch) *state.Batch {
-				return &state.Batch{
-					BatchL2Data:    []byte{1},
-					GlobalExitRoot: sequencedBatch.GlobalExitRoot,
-					Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
-					Coinbase:       sequencedBatch.Coinbase,
-				}
-			},
-		},
+func TestAddL1Reorg(t *testing.T) {
+	droppedTx := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	testCases := []struct {
+		name           string
+		virtualBatches []state.VirtualBatch
+		expectedKind   state.ReorgKind
+	}{
 		{
-			Name: "Global Exit Root is different",
-			getTrustedBatch: func(m *mocks, ctx context.Context, sequencedBatch etherman.SequencedBatch) *state.Batch {
-				return &state.Batch{
-					BatchL2Data:    sequencedBatch.Transactions,
-					GlobalExitRoot: common.HexToHash("0x999888777"),
-					Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
-					Coinbase:       sequencedBatch.Coinbase,
-				}
-			},
+			name:         "no batches reverted",
+			expectedKind: state.ReorgKindL1,
 		},
 		{
-			Name: "Timestamp is different",
-			getTrustedBatch: func(m *mocks, ctx context.Context, sequencedBatch etherman.SequencedBatch) *state.Batch {
-				return &state.Batch{
-					BatchL2Data:    sequencedBatch.Transactions,
-					GlobalExitRoot: sequencedBatch.GlobalExitRoot,
-					Timestamp:      time.Unix(int64(0), 0),
-					Coinbase:       sequencedBatch.Coinbase,
-				}
+			name: "preconfirmed batches reverted",
+			virtualBatches: []state.VirtualBatch{
+				{BatchNumber: 3, L1Finality: state.L1FinalityPreconfirmed},
+				{BatchNumber: 4, L1Finality: state.L1FinalityPreconfirmed},
 			},
+			expectedKind: state.ReorgKindPreconfirmation,
 		},
 		{
-			Name: "Coinbase is different",
-			getTrustedBatch: func(m *mocks, ctx context.Context, sequencedBatch etherman.SequencedBatch) *state.Batch {
-				return &state.Batch{
-					BatchL2Data:    sequencedBatch.Transactions,
-					GlobalExitRoot: sequencedBatch.GlobalExitRoot,
-					Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
-					Coinbase:       common.HexToAddress("0x999888777"),
-				}
+			name: "sequenced batches reverted",
+			virtualBatches: []state.VirtualBatch{
+				{BatchNumber: 3, L1Finality: state.L1FinalityLatest},
+				{BatchNumber: 4, L1Finality: state.L1FinalityPreconfirmed},
 			},
+			expectedKind: state.ReorgKindL1,
 		},
 	}
 
-	m := mocks{
-		Etherman:     newEthermanMock(t),
-		State:        newStateMock(t),
-		EthTxManager: newEthTxManagerMock(t),
-		DbTx:         newDbTxMock(t),
-	}
-
-	// start synchronizing
 	for _, tc := range testCases {
-		t.Run(tc.Name, func(t *testing.T) {
-			testCase := tc
-			sync := setupMocks(&m, &testCase)
-			err := sync.Sync()
-			require.NoError(t, err)
-		})
-	}
-}
-
-func TestForcedBatch(t *testing.T) {
-	genesis := state.Genesis{}
-	cfg := Config{
-		SyncInterval:   cfgTypes.Duration{Duration: 1 * time.Second},
-		SyncChunkSize:  10,
-		GenBlockNumber: uint64(123456),
-	}
-
-	m := mocks{
-		Etherman: newEthermanMock(t),
-		State:    newStateMock(t),
-		DbTx:     newDbTxMock(t),
-	}
-
-	sync, err := NewSynchronizer(true, m.Etherman, m.State, m.EthTxManager, genesis, cfg)
-	require.NoError(t, err)
-
-	// state preparation
-	ctxMatchBy := mock.MatchedBy(func(ctx context.Context) bool { return ctx != nil })
-	m.State.
-		On("BeginStateTransaction", ctxMatchBy).
-		Run(func(args mock.Arguments) {
-			ctx := args[0].(context.Context)
-			parentHash := common.HexToHash("0x111")
-			ethHeader := &types.Header{Number: big.NewInt(1), ParentHash: parentHash}
-			ethBlock := types.NewBlockWithHeader(ethHeader)
-			lastBlock := &state.Block{BlockHash: ethBlock.Hash(), BlockNumber: ethBlock.Number().Uint64()}
+		t.Run(tc.name, func(t *testing.T) {
+			sync, m := newTestSynchronizer(t, Config{})
+			ctx := sync.ctx
 
 			m.State.
-				On("GetLastBlock", ctx, m.DbTx).
-				Return(lastBlock, nil).
+				On("GetVirtualBatchesAfterBlock", ctx, uint64(10), m.DbTx).
+				Return(tc.virtualBatches, nil).
 				Once()
-
-			m.DbTx.
-				On("Commit", ctx).
-				Return(nil).
-				Once()
-
-			m.Etherman.
-				On("EthBlockByNumber", ctx, lastBlock.BlockNumber).
-				Return(ethBlock, nil).
-				Once()
-
-			var n *big.Int
-			m.Etherman.
-				On("HeaderByNumber", ctx, n).
-				Return(ethHeader, nil).
-				Once()
-
-			t := time.Now()
-			sequencedBatch := etherman.SequencedBatch{
-				BatchNumber:   uint64(2),
-				Coinbase:      common.HexToAddress("0x222"),
-				SequencerAddr: common.HexToAddress("0x00"),
-				TxHash:        common.HexToHash("0x333"),
-				PolygonZkEVMBatchData: polygonzkevm.PolygonZkEVMBatchData{
-					Transactions:       []byte{},
-					GlobalExitRoot:     [32]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32},
-					Timestamp:          uint64(t.Unix()),
-					MinForcedTimestamp: 1000, //ForcedBatch
-				},
-			}
-
-			forceb := []etherman.ForcedBatch{{
-				BlockNumber:       lastBlock.BlockNumber,
-				ForcedBatchNumber: 1,
-				Sequencer:         sequencedBatch.Coinbase,
-				GlobalExitRoot:    sequencedBatch.GlobalExitRoot,
-				RawTxsData:        sequencedBatch.Transactions,
-				ForcedAt:          time.Unix(int64(sequencedBatch.MinForcedTimestamp), 0),
-			}}
-
-			ethermanBlock := etherman.Block{
-				BlockHash:        ethBlock.Hash(),
-				SequencedBatches: [][]etherman.SequencedBatch{{sequencedBatch}},
-				ForcedBatches:    forceb,
-			}
-			blocks := []etherman.Block{ethermanBlock}
-			order := map[common.Hash][]etherman.Order{
-				ethBlock.Hash(): {
-					{
-						Name: etherman.ForcedBatchesOrder,
-						Pos:  0,
-					},
-					{
-						Name: etherman.SequenceBatchesOrder,
-						Pos:  0,
-					},
-				},
-			}
-
-			fromBlock := ethBlock.NumberU64() + 1
-			toBlock := fromBlock + cfg.SyncChunkSize
-
-			m.Etherman.
-				On("GetRollupInfoByBlockRange", ctx, fromBlock, &toBlock).
-				Return(blocks, order, nil).
-				Once()
-
-			m.State.
-				On("BeginStateTransaction", ctx).
-				Return(m.DbTx, nil).
-				Once()
-
-			stateBlock := &state.Block{
-				BlockNumber: ethermanBlock.BlockNumber,
-				BlockHash:   ethermanBlock.BlockHash,
-				ParentHash:  ethermanBlock.ParentHash,
-				ReceivedAt:  ethermanBlock.ReceivedAt,
-			}
-
-			m.State.
-				On("AddBlock", ctx, stateBlock, m.DbTx).
-				Return(nil).
-				Once()
-
-			fb := []state.ForcedBatch{{
-				BlockNumber:       lastBlock.BlockNumber,
-				ForcedBatchNumber: 1,
-				Sequencer:         sequencedBatch.Coinbase,
-				GlobalExitRoot:    sequencedBatch.GlobalExitRoot,
-				RawTxsData:        sequencedBatch.Transactions,
-				ForcedAt:          time.Unix(int64(sequencedBatch.MinForcedTimestamp), 0),
-			}}
-
-			m.State.
-				On("AddForcedBatch", ctx, &fb[0], m.DbTx).
-				Return(nil).
-				Once()
-
-			m.State.
-				On("GetNextForcedBatches", ctx, 1, m.DbTx).
-				Return(fb, nil).
-				Once()
-
-			trustedBatch := &state.Batch{
-				BatchL2Data:    sequencedBatch.Transactions,
-				GlobalExitRoot: sequencedBatch.GlobalExitRoot,
-				Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
-				Coinbase:       sequencedBatch.Coinbase,
-			}
-
-			m.State.
-				On("GetBatchByNumber", ctx, sequencedBatch.BatchNumber, m.DbTx).
-				Return(trustedBatch, nil).
-				Once()
-
-			var forced uint64 = 1
-			sbatch := state.Batch{
-				BatchNumber:    sequencedBatch.BatchNumber,
-				Coinbase:       common.HexToAddress("0x222"),
-				BatchL2Data:    []byte{},
-				Timestamp:      time.Unix(int64(t.Unix()), 0),
-				Transactions:   nil,
-				GlobalExitRoot: [32]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32},
-				ForcedBatchNum: &forced,
-			}
-			m.State. //ExecuteBatch(s.ctx, batch.BatchNumber, batch.BatchL2Data, dbTx
-					On("ExecuteBatch", ctx, sbatch, m.DbTx).
-					Return(&pb.ProcessBatchResponse{NewStateRoot: trustedBatch.StateRoot.Bytes()}, nil).
+			var expectedDroppedTxs []types.Transaction
+			if len(tc.virtualBatches) > 0 {
+				// the txs are loaded before the reset removes the batches
+				expectedDroppedTxs = []types.Transaction{*droppedTx}
+				m.State.
+					On("GetTransactionsFromBatchNumber", ctx, uint64(3), m.DbTx).
+					Return(expectedDroppedTxs, nil).
 					Once()
-
-			virtualBatch := &state.VirtualBatch{
-				BatchNumber: sequencedBatch.BatchNumber,
-				TxHash:      sequencedBatch.TxHash,
-				Coinbase:    sequencedBatch.Coinbase,
-				BlockNumber: ethermanBlock.BlockNumber,
 			}
-
 			m.State.
-				On("AddVirtualBatch", ctx, virtualBatch, m.DbTx).
+				On("AddReorg", ctx, mock.Anything, m.DbTx).
 				Return(nil).
 				Once()
 
-			seq := state.Sequence{
-				FromBatchNumber: 2,
-				ToBatchNumber:   2,
+			reorg := &state.Reorg{FirstBlockNumber: 11, Depth: 2}
+			require.NoError(t, sync.addL1Reorg(reorg, 10, m.DbTx))
+			assert.Equal(t, tc.expectedKind, reorg.Kind)
+			assert.Equal(t, expectedDroppedTxs, reorg.DroppedTxs)
+			if len(tc.virtualBatches) > 0 {
+				assert.Equal(t, uint64(3), reorg.FirstBatchNumber)
 			}
-			m.State.
-				On("AddSequence", ctx, seq, m.DbTx).
-				Return(nil).
-				Once()
+		})
+	}
+}
 
-			m.State.
-				On("AddAccumulatedInputHash", ctx, sequencedBatch.BatchNumber, common.Hash{}, m.DbTx).
-				Return(nil).
-				Once()
+func TestForcedBatch(t *testing.T) {
+	sync, m := newTestSynchronizer(t, Config{ForcedBatchTimeout: 10})
+	ctx := sync.ctx
 
-			m.DbTx.
-				On("Commit", ctx).
-				Run(func(args mock.Arguments) { sync.Stop() }).
-				Return(nil).
-				Once()
+	// the batch was numbered 1 when it was downloaded, the forced batch
+	// included before it moves it to 2
+	sequencedBatch := newTestSequencedBatch(1, 20)
+	m.State.
+		On("ContainsBlock", ctx, uint64(20), m.DbTx).
+		Return(true, nil).
+		Twice()
+	m.State.
+		On("GetLastBatchInfo", ctx, m.DbTx).
+		Return(state.L2BatchInfo{}, nil).
+		Once()
 
-			m.Etherman.
-				On("GetLatestBatchNumber").
-				Return(uint64(10), nil).
-				Once()
+	forcedBatch := state.ForcedBatch{
+		BlockNumber:       5,
+		ForcedBatchNumber: 1,
+		Sequencer:         common.HexToAddress("0x555"),
+		GlobalExitRoot:    common.HexToHash("0x666"),
+		RawTxsData:        []byte{1, 2, 3},
+		ForcedAt:          time.Unix(1000, 0),
+	}
+	m.State.
+		On("GetPendingForcedBatches", ctx, uint64(0), uint64(10), m.DbTx).
+		Return([]state.ForcedBatch{forcedBatch}, nil).
+		Once()
 
-			var nilDbTx pgx.Tx
-			m.State.
-				On("GetLastBatchNumber", ctx, nilDbTx).
-				Return(uint64(10), nil).
-				Once()
-		}).
-		Return(m.DbTx, nil).
+	forcedBatchNum := forcedBatch.ForcedBatchNumber
+	batches := []state.Batch{
+		{
+			BatchNumber:    1,
+			GlobalExitRoot: forcedBatch.GlobalExitRoot,
+			Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
+			Coinbase:       forcedBatch.Sequencer,
+			BatchL2Data:    forcedBatch.RawTxsData,
+			ForcedBatchNum: &forcedBatchNum,
+		},
+		{
+			BatchNumber:    2,
+			GlobalExitRoot: sequencedBatch.GlobalExitRoot,
+			Timestamp:      time.Unix(int64(sequencedBatch.Timestamp), 0),
+			Coinbase:       sequencedBatch.Coinbase,
+			BatchL2Data:    sequencedBatch.Transactions,
+		},
+	}
+	virtualBatches := []*state.VirtualBatch{
+		{
+			BatchNumber:        1,
+			SeenAt:             20,
+			Coinbase:           forcedBatch.Sequencer,
+			BlockNumber:        20,
+			SequencerAddr:      forcedBatch.Sequencer,
+			L1Finality:         state.L1FinalityLatest,
+			LastForcedBatchNum: 1,
+		},
+		{
+			BatchNumber:        2,
+			TxHash:             sequencedBatch.TxHash,
+			SeenAt:             20,
+			Coinbase:           sequencedBatch.Coinbase,
+			BlockNumber:        20,
+			SequencerAddr:      sequencedBatch.SequencerAddr,
+			L1Finality:         state.L1FinalityLatest,
+			LastForcedBatchNum: 1,
+		},
+	}
+	for i, batch := range batches {
+		m.State.
+			On("ExecuteBatch", ctx, batch, m.DbTx).
+			Return(&pb.ProcessBatchResponse{}, nil).
+			Once()
+		m.State.
+			On("GetBatchByNumber", ctx, batch.BatchNumber, m.DbTx).
+			Return(nil, state.ErrNotFound).
+			Once()
+		processingContext := state.ProcessingContext{
+			BatchNumber:    batch.BatchNumber,
+			Coinbase:       batch.Coinbase,
+			Timestamp:      batch.Timestamp,
+			GlobalExitRoot: batch.GlobalExitRoot,
+			ForcedBatchNum: batch.ForcedBatchNum,
+		}
+		m.State.
+			On("ProcessAndStoreClosedBatch", ctx, processingContext, batch.BatchL2Data, m.DbTx, state.SynchronizerCallerLabel).
+			Return(nil).
+			Once()
+		m.State.
+			On("AddVirtualBatch", ctx, virtualBatches[i], m.DbTx).
+			Return(nil).
+			Once()
+	}
+	m.State.
+		On("AddSequence", ctx, state.Sequence{FromBatchNumber: 1, ToBatchNumber: 2}, m.DbTx).
+		Return(nil).
 		Once()
 
-	err = sync.Sync()
+	err := sync.processSequenceBatches([]etherman.SequencedBatch{sequencedBatch}, m.DbTx, 20, state.L1FinalityLatest)
 	require.NoError(t, err)
 }
 
-func TestSequenceForcedBatch(t *testing.T) {
-	genesis := state.Genesis{}
-	cfg := Config{
-		SyncInterval:   cfgTypes.Duration{Duration: 1 * time.Second},
-		SyncChunkSize:  10,
-		GenBlockNumber: uint64(123456),
+func TestProcessBlockRangeNotifiesBatchClosed(t *testing.T) {
+	block := etherman.Block{
+		BlockNumber:      20,
+		BlockHash:        common.HexToHash("0x20"),
+		SequencedBatches: [][]etherman.SequencedBatch{{}},
 	}
-
-	m := mocks{
-		Etherman: newEthermanMock(t),
-		State:    newStateMock(t),
-		DbTx:     newDbTxMock(t),
+	order := map[common.Hash][]etherman.Order{
+		block.BlockHash: {{Name: etherman.SequenceBatchesOrder, Pos: 0}},
+	}
+	expectBlock := func(m *mocks) {
+		m.State.
+			On("BeginStateTransaction", mock.Anything).
+			Return(m.DbTx, nil).
+			Once()
+		m.State.
+			On("AddBlock", mock.Anything, mock.Anything, m.DbTx).
+			Return(nil).
+			Once()
+		m.DbTx.
+			On("Commit", mock.Anything).
+			Return(nil).
+			Once()
 	}
 
-	sync, err := NewSynchronizer(true, m.Etherman, m.State, m.EthTxManager, genesis, cfg)
-	require.NoError(t, err)
+	t.Run("handler registered", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{})
+		var closed []uint64
+		sync.RegisterBatchClosedEventHandler(func(batchNumber uint64) {
+			closed = append(closed, batchNumber)
+		})
+		expectBlock(m)
+		m.State.
+			On("GetLastVirtualBatchNum", mock.Anything, nil).
+			Return(uint64(7), nil).
+			Once()
 
-	// state preparation
-	ctxMatchBy := mock.MatchedBy(func(ctx context.Context) bool { return ctx != nil })
-	m.State.
-		On("BeginStateTransaction", ctxMatchBy).
-		Run(func(args mock.Arguments) {
-			ctx := args[0].(context.Context)
-			parentHash := common.HexToHash("0x111")
-			ethHeader := &types.Header{Number: big.NewInt(1), ParentHash: parentHash}
-			ethBlock := types.NewBlockWithHeader(ethHeader)
-			lastBlock := &state.Block{BlockHash: ethBlock.Hash(), BlockNumber: ethBlock.Number().Uint64()}
+		require.NoError(t, sync.processBlockRange([]etherman.Block{block}, order, state.L1FinalityLatest))
+		assert.Equal(t, []uint64{7}, closed)
+	})
 
-			m.State.
-				On("GetLastBlock", ctx, m.DbTx).
-				Return(lastBlock, nil).
-				Once()
+	t.Run("no handler", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{})
+		expectBlock(m)
 
-			m.DbTx.
-				On("Commit", ctx).
-				Return(nil).
-				Once()
+		require.NoError(t, sync.processBlockRange([]etherman.Block{block}, order, state.L1FinalityLatest))
+		m.State.AssertNotCalled(t, "GetLastVirtualBatchNum", mock.Anything, mock.Anything)
+	})
+}
 
-			m.Etherman.
-				On("EthBlockByNumber", ctx, lastBlock.BlockNumber).
-				Return(ethBlock, nil).
-				Once()
+func TestLastL1BlockToSync(t *testing.T) {
+	testCases := []struct {
+		name            string
+		l1Finality      string
+		l1Confirmations uint64
+		blockNumber     *big.Int
+		lastBlock       uint64
+		expected        uint64
+	}{
+		{
+			name:      "latest by default",
+			lastBlock: 100,
+			expected:  100,
+		},
+		{
+			name:            "latest with confirmations",
+			l1Finality:      string(state.L1FinalityLatest),
+			l1Confirmations: 5,
+			lastBlock:       100,
+			expected:        95,
+		},
+		{
+			name:        "safe",
+			l1Finality:  string(state.L1FinalitySafe),
+			blockNumber: big.NewInt(int64(rpc.SafeBlockNumber)),
+			lastBlock:   90,
+			expected:    90,
+		},
+		{
+			name:            "finalized with confirmations",
+			l1Finality:      string(state.L1FinalityFinalized),
+			l1Confirmations: 2,
+			blockNumber:     big.NewInt(int64(rpc.FinalizedBlockNumber)),
+			lastBlock:       80,
+			expected:        78,
+		},
+		{
+			name:            "more confirmations than blocks",
+			l1Confirmations: 10,
+			lastBlock:       3,
+			expected:        0,
+		},
+	}
 
-			var n *big.Int
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sync, m := newTestSynchronizer(t, Config{L1Finality: tc.l1Finality, L1Confirmations: tc.l1Confirmations})
 			m.Etherman.
-				On("HeaderByNumber", ctx, n).
-				Return(ethHeader, nil).
+				On("HeaderByNumber", sync.ctx, tc.blockNumber).
+				Return(&types.Header{Number: new(big.Int).SetUint64(tc.lastBlock)}, nil).
 				Once()
 
-			sequencedForceBatch := etherman.SequencedForceBatch{
-				BatchNumber: uint64(2),
-				Coinbase:    common.HexToAddress("0x222"),
-				TxHash:      common.HexToHash("0x333"),
-				PolygonZkEVMForcedBatchData: polygonzkevm.PolygonZkEVMForcedBatchData{
-					Transactions:       []byte{},
-					GlobalExitRoot:     [32]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32},
-					MinForcedTimestamp: 1000, //ForcedBatch
-				},
-			}
+			lastBlock, err := sync.lastL1BlockToSync()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, lastBlock)
+		})
+	}
 
-			forceb := []etherman.ForcedBatch{{
-				BlockNumber:       lastBlock.BlockNumber,
-				ForcedBatchNumber: 1,
-				Sequencer:         sequencedForceBatch.Coinbase,
-				GlobalExitRoot:    sequencedForceBatch.GlobalExitRoot,
-				RawTxsData:        sequencedForceBatch.Transactions,
-				ForcedAt:          time.Unix(int64(sequencedForceBatch.MinForcedTimestamp), 0),
-			}}
-
-			ethermanBlock := etherman.Block{
-				BlockHash:             ethBlock.Hash(),
-				SequencedForceBatches: [][]etherman.SequencedForceBatch{{sequencedForceBatch}},
-				ForcedBatches:         forceb,
-			}
-			blocks := []etherman.Block{ethermanBlock}
-			order := map[common.Hash][]etherman.Order{
-				ethBlock.Hash(): {
-					{
-						Name: etherman.ForcedBatchesOrder,
-						Pos:  0,
-					},
-					{
-						Name: etherman.SequenceForceBatchesOrder,
-						Pos:  0,
-					},
-				},
-			}
+	t.Run("header error", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{})
+		var n *big.Int
+		m.Etherman.
+			On("HeaderByNumber", sync.ctx, n).
+			Return(nil, errors.New("unavailable")).
+			Once()
 
-			fromBlock := ethBlock.NumberU64() + 1
-			toBlock := fromBlock + cfg.SyncChunkSize
+		_, err := sync.lastL1BlockToSync()
+		require.Error(t, err)
+	})
+}
 
+func TestCheckL1BatchesSynced(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		lastBatch            state.L2BatchInfo
+		latestSequencedBatch uint64
+		synced               bool
+	}{
+		{
+			name:                 "synced",
+			lastBatch:            state.L2BatchInfo{Number: 5},
+			latestSequencedBatch: 5,
+			synced:               true,
+		},
+		{
+			name:                 "behind",
+			lastBatch:            state.L2BatchInfo{Number: 4},
+			latestSequencedBatch: 5,
+		},
+		{
+			name:                 "synced with forced batches",
+			lastBatch:            state.L2BatchInfo{Number: 7, ForcedBatchNum: 2},
+			latestSequencedBatch: 5,
+			synced:               true,
+		},
+		{
+			name:                 "behind with forced batches",
+			lastBatch:            state.L2BatchInfo{Number: 6, ForcedBatchNum: 2},
+			latestSequencedBatch: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sync, m := newTestSynchronizer(t, Config{})
 			m.Etherman.
-				On("GetRollupInfoByBlockRange", ctx, fromBlock, &toBlock).
-				Return(blocks, order, nil).
+				On("GetLatestBatchNumber").
+				Return(tc.latestSequencedBatch, nil).
 				Once()
-
 			m.State.
-				On("BeginStateTransaction", ctx).
-				Return(m.DbTx, nil).
+				On("GetLastBatchInfo", sync.ctx, nil).
+				Return(tc.lastBatch, nil).
 				Once()
 
-			stateBlock := &state.Block{
-				BlockNumber: ethermanBlock.BlockNumber,
-				BlockHash:   ethermanBlock.BlockHash,
-				ParentHash:  ethermanBlock.ParentHash,
-				ReceivedAt:  ethermanBlock.ReceivedAt,
-			}
-
-			m.State.
-				On("AddBlock", ctx, stateBlock, m.DbTx).
-				Return(nil).
-				Once()
+			lastBatch, synced, err := sync.checkL1BatchesSynced()
+			require.NoError(t, err)
+			assert.Equal(t, tc.lastBatch, lastBatch)
+			assert.Equal(t, tc.synced, synced)
+			status := sync.status.Status()
+			assert.Equal(t, tc.lastBatch.Number, status.LastBatch)
+			assert.Equal(t, tc.lastBatch.ForcedBatchNum, status.ForcedBatches)
+			assert.Equal(t, tc.latestSequencedBatch, status.LatestSequencedBatch)
+		})
+	}
+}
 
-			fb := []state.ForcedBatch{{
-				BlockNumber:       lastBlock.BlockNumber,
-				ForcedBatchNumber: 1,
-				Sequencer:         sequencedForceBatch.Coinbase,
-				GlobalExitRoot:    sequencedForceBatch.GlobalExitRoot,
-				RawTxsData:        sequencedForceBatch.Transactions,
-				ForcedAt:          time.Unix(int64(sequencedForceBatch.MinForcedTimestamp), 0),
-			}}
+func TestUpdateL1Finality(t *testing.T) {
+	safe := big.NewInt(int64(rpc.SafeBlockNumber))
+	finalized := big.NewInt(int64(rpc.FinalizedBlockNumber))
+
+	testCases := []struct {
+		name       string
+		l1Finality string
+		preconf    bool
+		// headers are the last block of each level updated, nil when the
+		// level is not available on L1
+		headers map[state.L1Finality]*types.Header
+	}{
+		{
+			name:       "latest updates safe and finalized",
+			l1Finality: string(state.L1FinalityLatest),
+			headers: map[state.L1Finality]*types.Header{
+				state.L1FinalitySafe:      {Number: big.NewInt(90)},
+				state.L1FinalityFinalized: {Number: big.NewInt(80)},
+			},
+		},
+		{
+			name:       "safe only updates finalized",
+			l1Finality: string(state.L1FinalitySafe),
+			headers: map[state.L1Finality]*types.Header{
+				state.L1FinalityFinalized: {Number: big.NewInt(80)},
+			},
+		},
+		{
+			name:       "finalized updates nothing",
+			l1Finality: string(state.L1FinalityFinalized),
+		},
+		{
+			name:       "preconfirmations update every level",
+			l1Finality: string(state.L1FinalityFinalized),
+			preconf:    true,
+			headers: map[state.L1Finality]*types.Header{
+				state.L1FinalitySafe:      {Number: big.NewInt(90)},
+				state.L1FinalityFinalized: {Number: big.NewInt(80)},
+			},
+		},
+		{
+			name:       "levels not available on L1 are skipped",
+			l1Finality: string(state.L1FinalityLatest),
+			headers: map[state.L1Finality]*types.Header{
+				state.L1FinalitySafe:      nil,
+				state.L1FinalityFinalized: {Number: big.NewInt(80)},
+			},
+		},
+	}
 
-			m.State.
-				On("AddForcedBatch", ctx, &fb[0], m.DbTx).
-				Return(nil).
-				Once()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{L1Finality: tc.l1Finality}
+			if tc.preconf {
+				cfg.PreconfirmationsSyncInterval = cfgTypes.Duration{Duration: time.Second}
+			}
+			sync, m := newTestSynchronizer(t, cfg)
+			for l1Finality, header := range tc.headers {
+				number := safe
+				if l1Finality == state.L1FinalityFinalized {
+					number = finalized
+				}
+				if header == nil {
+					m.Etherman.
+						On("HeaderByNumber", sync.ctx, number).
+						Return(nil, errors.New("unsupported block tag")).
+						Once()
+					continue
+				}
+				m.Etherman.
+					On("HeaderByNumber", sync.ctx, number).
+					Return(header, nil).
+					Once()
+				var nilDbTx pgx.Tx
+				m.State.
+					On("UpdateVirtualBatchesL1Finality", sync.ctx, l1Finality, header.Number.Uint64(), nilDbTx).
+					Return(int64(1), nil).
+					Once()
+			}
 
-			m.State.
-				On("GetLastVirtualBatchNum", ctx, m.DbTx).
-				Return(uint64(1), nil).
-				Once()
+			err := sync.updateL1Finality()
+			require.NoError(t, err)
+		})
+	}
 
-			m.State.
-				On("ResetTrustedState", ctx, uint64(1), m.DbTx).
-				Return(nil).
-				Once()
+	t.Run("update error", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{})
+		m.Etherman.
+			On("HeaderByNumber", sync.ctx, safe).
+			Return(&types.Header{Number: big.NewInt(90)}, nil).
+			Once()
+		var nilDbTx pgx.Tx
+		m.State.
+			On("UpdateVirtualBatchesL1Finality", sync.ctx, state.L1FinalitySafe, uint64(90), nilDbTx).
+			Return(int64(0), errors.New("db error")).
+			Once()
 
-			m.State.
-				On("GetNextForcedBatches", ctx, 1, m.DbTx).
-				Return(fb, nil).
-				Once()
+		err := sync.updateL1Finality()
+		require.Error(t, err)
+	})
+}
 
-			f := uint64(1)
-			processingContext := state.ProcessingContext{
-				BatchNumber:    sequencedForceBatch.BatchNumber,
-				Coinbase:       sequencedForceBatch.Coinbase,
-				Timestamp:      ethBlock.ReceivedAt,
-				GlobalExitRoot: sequencedForceBatch.GlobalExitRoot,
-				ForcedBatchNum: &f,
-			}
+// expectResetSteps sets the steps of the reset of the state to blockNumber,
+// the last one returns stepErr if set or finishes the reset otherwise
+func expectResetSteps(m *mocks, blockNumber, chunkSize uint64, steps int, stepErr error) {
+	m.State.
+		On("BeginStateTransaction", mock.Anything).
+		Return(m.DbTx, nil).
+		Times(steps)
+	m.State.
+		On("ResetStep", mock.Anything, blockNumber, chunkSize, m.DbTx).
+		Return(false, nil).
+		Times(steps - 1)
+	if stepErr != nil {
+		m.State.
+			On("ResetStep", mock.Anything, blockNumber, chunkSize, m.DbTx).
+			Return(false, stepErr).
+			Once()
+		m.DbTx.
+			On("Rollback", mock.Anything).
+			Return(nil).
+			Once()
+		m.DbTx.
+			On("Commit", mock.Anything).
+			Return(nil).
+			Times(steps - 1)
+		return
+	}
+	m.State.
+		On("ResetStep", mock.Anything, blockNumber, chunkSize, m.DbTx).
+		Return(true, nil).
+		Once()
+	m.State.
+		On("FinishReset", mock.Anything, m.DbTx).
+		Return(nil).
+		Once()
+	m.DbTx.
+		On("Commit", mock.Anything).
+		Return(nil).
+		Times(steps)
+	m.Etherman.
+		On("ResetL1Cache", blockNumber).
+		Return().
+		Once()
+	var nilDbTx pgx.Tx
+	m.State.
+		On("LoadForkIDIntervals", mock.Anything, nilDbTx).
+		Return(nil).
+		Once()
+}
 
-			m.State.
-				On("ProcessAndStoreClosedBatch", ctx, processingContext, sequencedForceBatch.Transactions, m.DbTx, state.SynchronizerCallerLabel).
-				Return(nil).
-				Once()
+// expectStartReset sets the start of the reset of the state to blockNumber,
+// committed in its own db transaction
+func expectStartReset(m *mocks, blockNumber uint64) {
+	m.State.
+		On("BeginStateTransaction", mock.Anything).
+		Return(m.DbTx, nil).
+		Once()
+	m.State.
+		On("StartReset", mock.Anything, blockNumber, m.DbTx).
+		Return(nil).
+		Once()
+	m.EthTxManager.
+		On("Reorg", mock.Anything, blockNumber+1, m.DbTx).
+		Return(nil).
+		Once()
+	m.DbTx.
+		On("Commit", mock.Anything).
+		Return(nil).
+		Once()
+}
 
-			virtualBatch := &state.VirtualBatch{
-				BatchNumber:   sequencedForceBatch.BatchNumber,
-				TxHash:        sequencedForceBatch.TxHash,
-				Coinbase:      sequencedForceBatch.Coinbase,
-				SequencerAddr: sequencedForceBatch.Coinbase,
-				BlockNumber:   ethermanBlock.BlockNumber,
-			}
+func TestResetState(t *testing.T) {
+	t.Run("chunked", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{ResetChunkSize: 2})
+		sync.lastL1BlockSynced = 20
+		expectStartReset(m, 5)
+		// each step removes a chunk of blocks in its own db transaction
+		expectResetSteps(m, 5, 2, 3, nil)
+
+		require.NoError(t, sync.resetState(5, nil))
+		assert.Equal(t, uint64(5), sync.lastL1BlockSynced)
+		m.State.AssertNumberOfCalls(t, "ResetStep", 3)
+		m.DbTx.AssertNumberOfCalls(t, "Commit", 4)
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{ResetChunkSize: 2})
+		sync.lastL1BlockSynced = 20
+		expectStartReset(m, 5)
+		// the second step fails, i.e. the node is stopped in the middle of
+		// the reset, so the reset is not finished
+		expectResetSteps(m, 5, 2, 2, errors.New("db error"))
+
+		require.EqualError(t, sync.resetState(5, nil), "db error")
+		assert.Equal(t, uint64(20), sync.lastL1BlockSynced)
+		m.State.AssertNotCalled(t, "FinishReset", mock.Anything, mock.Anything)
+		m.Etherman.AssertNotCalled(t, "ResetL1Cache", mock.Anything)
+	})
+}
 
-			m.State.
-				On("AddVirtualBatch", ctx, virtualBatch, m.DbTx).
-				Return(nil).
-				Once()
+func TestResumeReset(t *testing.T) {
+	var nilDbTx pgx.Tx
+	t.Run("no reset in progress", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{ResetChunkSize: 2})
+		m.State.
+			On("GetResetInProgress", mock.Anything, nilDbTx).
+			Return(uint64(0), state.ErrNotFound).
+			Once()
 
-			seq := state.Sequence{
-				FromBatchNumber: 2,
-				ToBatchNumber:   2,
-			}
-			m.State.
-				On("AddSequence", ctx, seq, m.DbTx).
-				Return(nil).
-				Once()
+		require.NoError(t, sync.resumeReset())
+	})
 
-			m.DbTx.
-				On("Commit", ctx).
-				Run(func(args mock.Arguments) { sync.Stop() }).
-				Return(nil).
-				Once()
+	t.Run("reset in progress", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{ResetChunkSize: 2})
+		sync.lastL1BlockSynced = 20
+		m.State.
+			On("GetResetInProgress", mock.Anything, nilDbTx).
+			Return(uint64(5), nil).
+			Once()
+		// the reset is resumed from the remaining steps, it is not started
+		// again
+		expectResetSteps(m, 5, 2, 2, nil)
 
-			m.Etherman.
-				On("GetLatestBatchNumber").
-				Return(uint64(10), nil).
-				Once()
+		require.NoError(t, sync.resumeReset())
+		assert.Equal(t, uint64(5), sync.lastL1BlockSynced)
+		m.State.AssertNotCalled(t, "StartReset", mock.Anything, mock.Anything, mock.Anything)
+	})
 
-			var nilDbTx pgx.Tx
-			m.State.
-				On("GetLastBatchNumber", ctx, nilDbTx).
-				Return(uint64(10), nil).
-				Once()
-		}).
-		Return(m.DbTx, nil).
-		Once()
+	t.Run("error", func(t *testing.T) {
+		sync, m := newTestSynchronizer(t, Config{ResetChunkSize: 2})
+		m.State.
+			On("GetResetInProgress", mock.Anything, nilDbTx).
+			Return(uint64(0), errors.New("db error")).
+			Once()
 
-	err = sync.Sync()
-	require.NoError(t, err)
+		require.EqualError(t, sync.resumeReset(), "db error")
+	})
 }
@@ -0,0 +1,219 @@
+package synchronizer
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast/pb"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// trustedBatchesBufferSize is the number of streamed trusted batches buffered
+// until they are processed
+const trustedBatchesBufferSize = 100
+
+// trustedBatchStream receives the closed batches of the trusted state as they
+// are streamed by the broadcast server
+type trustedBatchStream struct {
+	// from is the number of the first batch streamed
+	from uint64
+	// next is the number of the next batch to be received, it is updated while
+	// holding the trustedMutex of the synchronizer
+	next    uint64
+	batches chan *pb.GetBatchResponse
+	cancel  context.CancelFunc
+	// done is closed once the streamed batches are no longer processed
+	done chan struct{}
+}
+
+// stopped returns whether the streamed batches are no longer processed
+func (t *trustedBatchStream) stopped() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// broadcastClientFor returns the broadcast client connected to broadcastURI,
+// the previous client and its stream are closed when the URI changes
+func (s *ClientSynchronizer) broadcastClientFor(broadcastURI string) *broadcast.Client {
+	if s.broadcastClient != nil && s.broadcastClient.ServerAddress() == broadcastURI {
+		return s.broadcastClient
+	}
+	s.closeBroadcastClient()
+	s.broadcastClient = broadcast.NewLongLivedClient(s.cfg.Broadcast, broadcastURI)
+	return s.broadcastClient
+}
+
+// closeBroadcastClient stops the trusted batch stream and closes the
+// connection to the broadcast server
+func (s *ClientSynchronizer) closeBroadcastClient() {
+	s.stopTrustedBatchStream()
+	if s.broadcastClient == nil {
+		return
+	}
+	if err := s.broadcastClient.Close(); err != nil {
+		log.Warnf("error closing the broadcast client. Error: %v", err)
+	}
+	s.broadcastClient = nil
+}
+
+// startTrustedBatchStream starts streaming the closed trusted batches from
+// fromBatchNumber in the background, they are processed as they arrive
+func (s *ClientSynchronizer) startTrustedBatchStream(client *broadcast.Client, fromBatchNumber uint64) {
+	s.stopTrustedBatchStream()
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	stream := &trustedBatchStream{
+		from:    fromBatchNumber,
+		next:    fromBatchNumber,
+		batches: make(chan *pb.GetBatchResponse, trustedBatchesBufferSize),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(stream.batches)
+		err := client.StreamBatches(ctx, fromBatchNumber, func(batch *pb.GetBatchResponse) error {
+			select {
+			case stream.batches <- batch:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Warnf("trusted batch stream stopped. Error: %v", err)
+		}
+	}()
+	go s.processTrustedBatchStream(ctx, stream)
+	s.trustedStream = stream
+}
+
+// processTrustedBatchStream processes the streamed batches as they arrive,
+// each one while the main loop is not syncing. It stops along with the stream
+// or when a batch can't be processed, the stream is restarted by the next sync
+// of the trusted state then
+func (s *ClientSynchronizer) processTrustedBatchStream(ctx context.Context, stream *trustedBatchStream) {
+	defer close(stream.done)
+	defer stream.cancel()
+	for batch := range stream.batches {
+		if !s.processStreamedTrustedBatch(ctx, stream, batch) {
+			return
+		}
+	}
+}
+
+// processStreamedTrustedBatch processes a streamed batch, it returns false if
+// the stream was stopped or the batch couldn't be processed
+func (s *ClientSynchronizer) processStreamedTrustedBatch(ctx context.Context, stream *trustedBatchStream, batch *pb.GetBatchResponse) bool {
+	s.trustedMutex.Lock()
+	defer s.trustedMutex.Unlock()
+	// The stream may be stopped while the main loop synced, i.e. after a reorg
+	if ctx.Err() != nil {
+		return false
+	}
+	if batch.BatchNumber < stream.from {
+		stream.next = batch.BatchNumber + 1
+		return true
+	}
+	// The synced state went back, i.e. after a reorg, the stream is restarted
+	// from it
+	lastBatchNumber, err := s.state.GetLastBatchNumber(s.ctx, nil)
+	if err != nil {
+		log.Warnf("error getting latest batch synced. Error: %v", err)
+		return false
+	}
+	if batch.BatchNumber > lastBatchNumber+1 {
+		return false
+	}
+	stream.next = batch.BatchNumber + 1
+	processed, err := s.syncTrustedBatch(batch)
+	if err != nil {
+		log.Warnf("error syncing streamed trusted batch %d. Error: %v", batch.BatchNumber, err)
+	}
+	return err == nil && processed
+}
+
+// stopTrustedBatchStream stops the trusted batch stream, if any
+func (s *ClientSynchronizer) stopTrustedBatchStream() {
+	if s.trustedStream == nil {
+		return
+	}
+	s.trustedStream.cancel()
+	s.trustedStream = nil
+}
+
+// syncTrustedState synchronizes information from the trusted sequencer
+// related to the trusted state when the node has all the information from
+// l1 synchronized. The closed batches are processed as they are received from
+// a stream kept open between calls, and the open batch is requested to the
+// broadcast server. It must be called holding the trustedMutex
+func (s *ClientSynchronizer) syncTrustedState(latestSyncedBatch uint64) error {
+	if s.isTrustedSequencer {
+		return nil
+	}
+
+	log.Debug("Getting broadcast URI")
+	broadcastURI, err := s.getBroadcastURI()
+	if err != nil {
+		log.Errorf("error getting broadcast URI. Error: %v", err)
+		return err
+	}
+	log.Debug("broadcastURI ", broadcastURI)
+	broadcastClient := s.broadcastClientFor(broadcastURI)
+
+	// The stream is restarted when it stopped or the synced state went back,
+	// i.e. after a reorg
+	if s.trustedStream == nil || s.trustedStream.stopped() || latestSyncedBatch+1 < s.trustedStream.next {
+		s.startTrustedBatchStream(broadcastClient, latestSyncedBatch)
+	}
+
+	log.Info("Getting trusted state info")
+	lastTrustedStateBatch, err := broadcastClient.GetLastBatch(s.ctx)
+	if err != nil {
+		log.Warn("error syncing trusted state. Error: ", err)
+		return err
+	}
+	log.Debug("lastTrustedStateBatch.BatchNumber ", lastTrustedStateBatch.BatchNumber)
+	log.Debug("latestSyncedBatch ", latestSyncedBatch)
+
+	// Only the open batch that follows the streamed ones is synced here, the
+	// closed ones are synced as they are streamed
+	if lastTrustedStateBatch.BatchNumber != s.trustedStream.next || lastTrustedStateBatch.BatchNumber < latestSyncedBatch {
+		return nil
+	}
+	_, err = s.syncTrustedBatch(lastTrustedStateBatch)
+	return err
+}
+
+// syncTrustedBatch processes the trusted batch in its own db transaction, it
+// returns false if the batch couldn't be processed
+func (s *ClientSynchronizer) syncTrustedBatch(batch *pb.GetBatchResponse) (bool, error) {
+	dbTx, err := s.state.BeginStateTransaction(s.ctx)
+	if err != nil {
+		log.Errorf("error creating db transaction to sync trusted batch %v: %v", batch.BatchNumber, err)
+		return false, err
+	}
+
+	if err := s.processTrustedBatch(batch, dbTx); err != nil {
+		log.Errorf("error processing trusted batch %v: %v", batch.BatchNumber, err)
+		err := dbTx.Rollback(s.ctx)
+		if err != nil {
+			log.Errorf("error rolling back db transaction to sync trusted batch %v: %v", batch.BatchNumber, err)
+			return false, err
+		}
+		return false, nil
+	}
+
+	if err := dbTx.Commit(s.ctx); err != nil {
+		log.Errorf("error committing db transaction to sync trusted batch %v: %v", batch.BatchNumber, err)
+		return false, err
+	}
+	if batch.StateRoot != state.ZeroHash.String() {
+		s.onBatchClosed(batch.BatchNumber)
+	}
+	return true, nil
+}
@@ -0,0 +1,299 @@
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast/pb"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testBroadcastServer streams the closed batches up to lastClosed and returns
+// the next one as the open batch, its streams can be made to fail
+type testBroadcastServer struct {
+	pb.UnimplementedBroadcastServiceServer
+
+	lastClosed uint64
+
+	mu sync.Mutex
+	// failStreams is the number of streams that fail before sending a batch
+	failStreams int
+	// breakStreams is the number of streams that fail after sending a batch
+	breakStreams int
+	streams      []uint64
+	streamTimes  []time.Time
+}
+
+func (s *testBroadcastServer) GetLastBatch(ctx context.Context, _ *emptypb.Empty) (*pb.GetBatchResponse, error) {
+	return newTestTrustedBatch(s.lastClosed+1, false), nil
+}
+
+func (s *testBroadcastServer) StreamBatches(req *pb.StreamBatchesRequest, stream pb.BroadcastService_StreamBatchesServer) error {
+	s.mu.Lock()
+	s.streams = append(s.streams, req.FromBatchNumber)
+	s.streamTimes = append(s.streamTimes, time.Now())
+	fail := s.failStreams > 0
+	if fail {
+		s.failStreams--
+	}
+	breakStream := !fail && s.breakStreams > 0
+	if breakStream {
+		s.breakStreams--
+	}
+	s.mu.Unlock()
+
+	if fail {
+		return status.Error(codes.Unavailable, "stream failed")
+	}
+	for n := req.FromBatchNumber; n <= s.lastClosed; n++ {
+		if err := stream.Send(newTestTrustedBatch(n, true)); err != nil {
+			return err
+		}
+		if breakStream {
+			return status.Error(codes.Unavailable, "stream broken")
+		}
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+func (s *testBroadcastServer) openedStreams() ([]uint64, []time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]uint64{}, s.streams...), append([]time.Time{}, s.streamTimes...)
+}
+
+// start serves the broadcast service and the trusted sequencer JSON RPC
+// server returning its URI, it returns the URL of the latter
+func (s *testBroadcastServer) start(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	pb.RegisterBroadcastServiceServer(srv, s)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	rpcSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%q}`, lis.Addr().String())
+	}))
+	t.Cleanup(rpcSrv.Close)
+	return rpcSrv.URL
+}
+
+func newTestTrustedBatch(batchNumber uint64, closed bool) *pb.GetBatchResponse {
+	stateRoot := state.ZeroHash
+	if closed {
+		stateRoot = common.BigToHash(new(big.Int).SetUint64(batchNumber))
+	}
+	return &pb.GetBatchResponse{
+		BatchNumber:    batchNumber,
+		GlobalExitRoot: common.Hash{}.String(),
+		LocalExitRoot:  common.Hash{}.String(),
+		StateRoot:      stateRoot.String(),
+		Sequencer:      common.Address{}.String(),
+	}
+}
+
+// expectSyncedTrustedBatches makes the stored batches match the trusted ones,
+// so they are synced without being processed again, and records the numbers
+// of the batches synced
+func expectSyncedTrustedBatches(t *testing.T, m *mocks, lastClosed uint64) *testSyncedBatches {
+	batchL2Data, err := state.EncodeTransactions([]types.Transaction{})
+	require.NoError(t, err)
+
+	synced := &testSyncedBatches{}
+	var nilDbTx pgx.Tx
+	m.State.
+		On("BeginStateTransaction", mock.Anything).
+		Return(m.DbTx, nil)
+	m.State.
+		On("GetBatchByNumber", mock.Anything, mock.Anything, nilDbTx).
+		Return(func(ctx context.Context, n uint64, dbTx pgx.Tx) *state.Batch {
+			synced.add(n)
+			trustedBatch := newTestTrustedBatch(n, n <= lastClosed)
+			return &state.Batch{
+				BatchNumber:    n,
+				GlobalExitRoot: common.HexToHash(trustedBatch.GlobalExitRoot),
+				LocalExitRoot:  common.HexToHash(trustedBatch.LocalExitRoot),
+				StateRoot:      common.HexToHash(trustedBatch.StateRoot),
+				Coinbase:       common.HexToAddress(trustedBatch.Sequencer),
+				Timestamp:      time.Unix(0, 0),
+				BatchL2Data:    batchL2Data,
+			}
+		}, nil)
+	m.State.
+		On("GetLastBatchNumber", mock.Anything, nilDbTx).
+		Return(func(ctx context.Context, dbTx pgx.Tx) uint64 {
+			return synced.last()
+		}, nil)
+	m.DbTx.
+		On("Commit", mock.Anything).
+		Return(nil)
+	return synced
+}
+
+type testSyncedBatches struct {
+	mu      sync.Mutex
+	numbers []uint64
+}
+
+func (b *testSyncedBatches) add(n uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.numbers = append(b.numbers, n)
+}
+
+func (b *testSyncedBatches) last() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.numbers) == 0 {
+		return 0
+	}
+	return b.numbers[len(b.numbers)-1]
+}
+
+func (b *testSyncedBatches) get() []uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]uint64{}, b.numbers...)
+}
+
+func newTestTrustedSynchronizer(t *testing.T, srv *testBroadcastServer) (*ClientSynchronizer, *testSyncedBatches) {
+	cfg := Config{}
+	cfg.Broadcast.InitialBackoff = cfgTypes.Duration{Duration: 20 * time.Millisecond}
+	cfg.Broadcast.MaxBackoff = cfgTypes.Duration{Duration: time.Second}
+	sync, m := newTestSynchronizer(t, cfg)
+	sync.isTrustedSequencer = false
+	t.Cleanup(func() {
+		sync.Stop()
+		sync.closeBroadcastClient()
+	})
+
+	m.Etherman.
+		On("GetTrustedSequencerURL").
+		Return(srv.start(t), nil)
+	return sync, expectSyncedTrustedBatches(t, m, srv.lastClosed)
+}
+
+// syncTrustedStateUntil syncs the trusted state from latestSyncedBatch, as
+// the synchronizer does on each sync, until the batch next is expected from
+// the stream
+func syncTrustedStateUntil(t *testing.T, sync *ClientSynchronizer, synced *testSyncedBatches, latestSyncedBatch, next uint64) {
+	previouslySynced := len(synced.get())
+	for i := 0; i < 200; i++ {
+		// The streamed batches are processed in the background while the
+		// main loop doesn't hold the mutex
+		sync.trustedMutex.Lock()
+		if numbers := synced.get()[previouslySynced:]; len(numbers) > 0 {
+			latestSyncedBatch = numbers[len(numbers)-1]
+		}
+		require.NoError(t, sync.syncTrustedState(latestSyncedBatch))
+		done := sync.trustedStream != nil && sync.trustedStream.next == next
+		sync.trustedMutex.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("trusted state not synced up to batch %d", next)
+}
+
+func TestSyncTrustedStateStream(t *testing.T) {
+	srv := &testBroadcastServer{lastClosed: 3}
+	sync, synced := newTestTrustedSynchronizer(t, srv)
+
+	// the closed batches are streamed and the open one is requested
+	syncTrustedStateUntil(t, sync, synced, 1, 4)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, synced.get())
+
+	// the stream is kept open between the syncs
+	require.NoError(t, sync.syncTrustedState(4))
+	streams, _ := srv.openedStreams()
+	assert.Equal(t, []uint64{1}, streams)
+}
+
+func TestSyncTrustedStateStreamRestart(t *testing.T) {
+	srv := &testBroadcastServer{lastClosed: 3}
+	sync, synced := newTestTrustedSynchronizer(t, srv)
+	syncTrustedStateUntil(t, sync, synced, 1, 4)
+
+	// the synced state went back to the batch 2, i.e. after a reorg, so the
+	// batches from it are streamed again
+	syncTrustedStateUntil(t, sync, synced, 2, 4)
+	streams, _ := srv.openedStreams()
+	assert.Equal(t, []uint64{1, 2}, streams)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 2, 3, 4}, synced.get())
+}
+
+func TestSyncTrustedStateStreamReconnect(t *testing.T) {
+	// the stream is broken once the batch 1 is sent
+	srv := &testBroadcastServer{lastClosed: 3, breakStreams: 1}
+	sync, synced := newTestTrustedSynchronizer(t, srv)
+
+	// the stream is reopened from the next batch
+	syncTrustedStateUntil(t, sync, synced, 1, 4)
+	streams, _ := srv.openedStreams()
+	assert.Equal(t, []uint64{1, 2}, streams)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, synced.get())
+}
+
+func TestSyncTrustedStateStreamBackoff(t *testing.T) {
+	// the first streams fail, then one is broken once the batch 1 is sent
+	srv := &testBroadcastServer{lastClosed: 3, failStreams: 3, breakStreams: 1}
+	sync, synced := newTestTrustedSynchronizer(t, srv)
+
+	syncTrustedStateUntil(t, sync, synced, 1, 4)
+	streams, times := srv.openedStreams()
+	require.Equal(t, []uint64{1, 1, 1, 1, 2}, streams)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, synced.get())
+
+	// the backoff is doubled on each failure
+	for i, backoff := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond} {
+		assert.GreaterOrEqual(t, times[i+1].Sub(times[i]), backoff)
+	}
+	// and it is reset once a batch is received
+	assert.Less(t, times[4].Sub(times[3]), times[3].Sub(times[2]))
+}
+
+func TestSyncTrustedStateStreamInBackground(t *testing.T) {
+	srv := &testBroadcastServer{lastClosed: 3}
+	sync, synced := newTestTrustedSynchronizer(t, srv)
+	var closed testSyncedBatches
+	sync.RegisterBatchClosedEventHandler(closed.add)
+
+	// the streamed batches are not processed while the main loop syncs
+	sync.trustedMutex.Lock()
+	require.NoError(t, sync.syncTrustedState(1))
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, synced.get())
+	sync.trustedMutex.Unlock()
+
+	// and they are processed as they arrive once it is done, without waiting
+	// for the next sync
+	require.Eventually(t, func() bool {
+		return len(synced.get()) == 3
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []uint64{1, 2, 3}, synced.get())
+	assert.Equal(t, []uint64{1, 2, 3}, closed.get())
+}
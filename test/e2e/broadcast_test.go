@@ -10,7 +10,6 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/merkletree"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast"
-	"github.com/0xPolygonHermez/zkevm-node/sequencer/broadcast/pb"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/0xPolygonHermez/zkevm-node/test/constants"
@@ -20,7 +19,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/require"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 const (
@@ -55,19 +53,19 @@ func TestBroadcast(t *testing.T) {
 
 	require.NoError(t, populateDB(ctx, st))
 
-	client, conn, cancel := broadcast.NewClient(ctx, serverAddress)
+	const maxWait = 2 * time.Minute
+	ctx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+	client := broadcast.NewLongLivedClient(broadcast.ClientConfig{}, serverAddress)
 	defer func() {
-		cancel()
-		require.NoError(t, conn.Close())
+		require.NoError(t, client.Close())
 	}()
 
-	lastBatch, err := client.GetLastBatch(ctx, &emptypb.Empty{})
+	lastBatch, err := client.GetLastBatch(ctx)
 	require.NoError(t, err)
 	require.Equal(t, totalBatches, int(lastBatch.BatchNumber))
 
-	batch, err := client.GetBatch(ctx, &pb.GetBatchRequest{
-		BatchNumber: uint64(totalBatches),
-	})
+	batch, err := client.GetBatch(ctx, totalBatches)
 	require.NoError(t, err)
 	require.Equal(t, totalBatches, int(batch.BatchNumber))
 
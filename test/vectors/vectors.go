@@ -11,6 +11,7 @@ type StateTransitionTestCase struct {
 	ID                  uint   `json:"id"`
 	Description         string `json:"description"`
 	ChainIDSequencer    uint64 `json:"chainIdSequencer"`
+	DefaultChainID      uint64 `json:"defaultChainId"`
 	SequencerAddress    string `json:"sequencerAddress"`
 	SequencerPrivateKey string `json:"sequencerPvtKey"`
 
@@ -22,6 +23,8 @@ type StateTransitionTestCase struct {
 	ExpectedNewLeafs      map[string]Leaf        `json:"expectedNewLeafs"`
 	Receipts              []TestReceipt          `json:"receipts"`
 	GlobalExitRoot        string                 `json:"globalExitRoot"`
+	BatchL2Data           string                 `json:"batchL2Data"`
+	Timestamp             uint64                 `json:"timestamp"`
 }
 
 // GenesisAccount represents the state of an account when the network
@@ -0,0 +1,131 @@
+package genesistool
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AccountDiff is a difference in a value of an account between two genesis
+type AccountDiff struct {
+	Address common.Address
+	// Field is the value that differs: balance, nonce, bytecode or the
+	// storage position
+	Field string
+	// A and B are the values of each genesis, empty if not set
+	A string
+	B string
+}
+
+// Diff compares two genesis account by account, the accounts or values only
+// present in one of them are compared against empty ones, so the differences
+// are the ones that change the tree
+func Diff(a, b *Genesis) ([]AccountDiff, error) {
+	accountsA, err := accountsByAddress(a)
+	if err != nil {
+		return nil, err
+	}
+	accountsB, err := accountsByAddress(b)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := []common.Address{}
+	for address := range accountsA {
+		addresses = append(addresses, address)
+	}
+	for address := range accountsB {
+		if _, ok := accountsA[address]; !ok {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return strings.ToLower(addresses[i].Hex()) < strings.ToLower(addresses[j].Hex())
+	})
+
+	diffs := []AccountDiff{}
+	for _, address := range addresses {
+		diffs = append(diffs, diffAccount(address, accountsA[address], accountsB[address])...)
+	}
+	return diffs, nil
+}
+
+func accountsByAddress(genesis *Genesis) (map[common.Address]*account, error) {
+	accounts := make(map[common.Address]*account, len(genesis.Genesis))
+	for _, acc := range genesis.Genesis {
+		n, err := normalize(acc)
+		if err != nil {
+			return nil, err
+		}
+		accounts[n.address] = n
+	}
+	return accounts, nil
+}
+
+func diffAccount(address common.Address, a, b *account) []AccountDiff {
+	empty := &account{balance: big.NewInt(0), nonce: big.NewInt(0), storage: map[common.Hash]common.Hash{}}
+	if a == nil {
+		a = empty
+	}
+	if b == nil {
+		b = empty
+	}
+
+	diffs := []AccountDiff{}
+	if bigString(a.balance) != bigString(b.balance) {
+		diffs = append(diffs, AccountDiff{Address: address, Field: "balance", A: bigString(a.balance), B: bigString(b.balance)})
+	}
+	if bigString(a.nonce) != bigString(b.nonce) {
+		diffs = append(diffs, AccountDiff{Address: address, Field: "nonce", A: bigString(a.nonce), B: bigString(b.nonce)})
+	}
+	if !bytes.Equal(a.code, b.code) {
+		diffs = append(diffs, AccountDiff{Address: address, Field: "bytecode", A: codeString(a.code), B: codeString(b.code)})
+	}
+
+	positions := []common.Hash{}
+	for position := range a.storage {
+		positions = append(positions, position)
+	}
+	for position := range b.storage {
+		if _, ok := a.storage[position]; !ok {
+			positions = append(positions, position)
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return bytes.Compare(positions[i][:], positions[j][:]) < 0
+	})
+	for _, position := range positions {
+		valueA, okA := a.storage[position]
+		valueB, okB := b.storage[position]
+		if okA && okB && valueA == valueB {
+			continue
+		}
+		diff := AccountDiff{Address: address, Field: "storage " + position.Hex()}
+		if okA {
+			diff.A = valueA.Hex()
+		}
+		if okB {
+			diff.B = valueB.Hex()
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+func bigString(v *big.Int) string {
+	if v.Sign() == 0 {
+		return ""
+	}
+	return v.String()
+}
+
+func codeString(code []byte) string {
+	if len(code) == 0 {
+		return ""
+	}
+	return hex.EncodeToHex(code)
+}
@@ -0,0 +1,259 @@
+package genesistool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/tools/genesis/genesisparser"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AllocAccount is an account of a geth-style allocation
+type AllocAccount struct {
+	Balance      string            `json:"balance"`
+	Nonce        string            `json:"nonce,omitempty"`
+	Code         string            `json:"code,omitempty"`
+	Storage      map[string]string `json:"storage,omitempty"`
+	ContractName string            `json:"contractName,omitempty"`
+}
+
+// Alloc is a geth-style allocation of the accounts of the genesis
+type Alloc map[common.Address]AllocAccount
+
+// Account is an account of a genesis file as loaded by the node
+type Account struct {
+	Balance      string            `json:"balance"`
+	Nonce        string            `json:"nonce"`
+	Address      string            `json:"address"`
+	Bytecode     string            `json:"bytecode,omitempty"`
+	Storage      map[string]string `json:"storage,omitempty"`
+	ContractName string            `json:"contractName,omitempty"`
+}
+
+// Genesis is a genesis file as loaded by the node
+type Genesis struct {
+	Root    string    `json:"root"`
+	Genesis []Account `json:"genesis"`
+}
+
+// ErrRootMismatch is returned when the root of a genesis file is not the one
+// of its accounts
+var ErrRootMismatch = errors.New("genesis root mismatch")
+
+// ReadAlloc reads a geth-style allocation from a JSON file, it accepts either
+// the allocation itself or a geth genesis file with an alloc field
+func ReadAlloc(path string) (Alloc, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	var gethGenesis struct {
+		Alloc Alloc `json:"alloc"`
+	}
+	if err := json.Unmarshal(data, &gethGenesis); err == nil && len(gethGenesis.Alloc) > 0 {
+		return gethGenesis.Alloc, nil
+	}
+	var alloc Alloc
+	if err := json.Unmarshal(data, &alloc); err != nil {
+		return nil, fmt.Errorf("failed to decode the allocation: %w", err)
+	}
+	return alloc, nil
+}
+
+// ReadGenesis reads a genesis file
+func ReadGenesis(path string) (*Genesis, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	var genesis Genesis
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return nil, fmt.Errorf("failed to decode the genesis: %w", err)
+	}
+	return &genesis, nil
+}
+
+// WriteJSON writes a value to a file as indented JSON
+func WriteJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) //nolint:gosec
+}
+
+// Build creates the genesis file of an allocation, computing its root on an
+// in-memory tree. The accounts are sorted by address and their values
+// normalized, so the same allocation always results in the same file
+func Build(ctx context.Context, alloc Alloc) (*Genesis, error) {
+	addresses := make([]common.Address, 0, len(alloc))
+	for address := range alloc {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return strings.ToLower(addresses[i].Hex()) < strings.ToLower(addresses[j].Hex())
+	})
+
+	genesis := &Genesis{Genesis: make([]Account, 0, len(addresses))}
+	for _, address := range addresses {
+		allocAccount := alloc[address]
+		acc, err := normalize(Account{
+			Balance:      allocAccount.Balance,
+			Nonce:        allocAccount.Nonce,
+			Address:      address.Hex(),
+			Bytecode:     allocAccount.Code,
+			Storage:      allocAccount.Storage,
+			ContractName: allocAccount.ContractName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		genesis.Genesis = append(genesis.Genesis, acc.toAccount())
+	}
+
+	root, err := ComputeRoot(ctx, genesis)
+	if err != nil {
+		return nil, err
+	}
+	genesis.Root = root.String()
+	return genesis, nil
+}
+
+// Actions returns the actions the node sets on the tree for the accounts of
+// the genesis
+func Actions(genesis *Genesis) []*state.GenesisAction {
+	accounts := make([]genesisparser.GenesisAccountTest, 0, len(genesis.Genesis))
+	for _, acc := range genesis.Genesis {
+		accounts = append(accounts, genesisparser.GenesisAccountTest{
+			Balance:  acc.Balance,
+			Nonce:    acc.Nonce,
+			Address:  acc.Address,
+			Bytecode: acc.Bytecode,
+			Storage:  acc.Storage,
+		})
+	}
+	return genesisparser.GenesisTest2Actions(accounts)
+}
+
+// ComputeRoot computes the root of the accounts of the genesis setting its
+// actions on an in-memory tree
+func ComputeRoot(ctx context.Context, genesis *Genesis) (common.Hash, error) {
+	tree := merkletree.NewStateTree(merkletree.NewLocalStateDBClient(merkletree.NewMemoryTree()))
+	root, err := state.SetGenesisActions(ctx, tree, Actions(genesis))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(root), nil
+}
+
+// Verify recomputes the root of the genesis and returns it along with
+// ErrRootMismatch if it is not the one of the file
+func Verify(ctx context.Context, genesis *Genesis) (common.Hash, error) {
+	root, err := ComputeRoot(ctx, genesis)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if root != common.HexToHash(genesis.Root) {
+		return root, fmt.Errorf("%w: expected %s, computed %s", ErrRootMismatch, genesis.Root, root.String())
+	}
+	return root, nil
+}
+
+// account is an account of a genesis with its values decoded, the storage
+// slots set to zero are dropped since they don't change the tree
+type account struct {
+	address      common.Address
+	contractName string
+	balance      *big.Int
+	nonce        *big.Int
+	code         []byte
+	storage      map[common.Hash]common.Hash
+}
+
+func normalize(acc Account) (*account, error) {
+	if !common.IsHexAddress(acc.Address) {
+		return nil, fmt.Errorf("invalid address %q", acc.Address)
+	}
+	n := &account{
+		address:      common.HexToAddress(acc.Address),
+		contractName: acc.ContractName,
+		balance:      big.NewInt(0),
+		nonce:        big.NewInt(0),
+		storage:      map[common.Hash]common.Hash{},
+	}
+	var err error
+	if acc.Balance != "" {
+		if n.balance, err = decodeBig(acc.Balance); err != nil {
+			return nil, fmt.Errorf("invalid balance of %s: %w", acc.Address, err)
+		}
+	}
+	if acc.Nonce != "" {
+		if n.nonce, err = decodeBig(acc.Nonce); err != nil {
+			return nil, fmt.Errorf("invalid nonce of %s: %w", acc.Address, err)
+		}
+	}
+	if acc.Bytecode != "" {
+		if n.code, err = hex.DecodeHex(acc.Bytecode); err != nil {
+			return nil, fmt.Errorf("invalid bytecode of %s: %w", acc.Address, err)
+		}
+	}
+	for position, value := range acc.Storage {
+		positionBI, err := decodeBig(position)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage position %q of %s: %w", position, acc.Address, err)
+		}
+		valueBI, err := decodeBig(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage value of position %q of %s: %w", position, acc.Address, err)
+		}
+		if valueBI.Sign() != 0 {
+			n.storage[common.BigToHash(positionBI)] = common.BigToHash(valueBI)
+		}
+	}
+	return n, nil
+}
+
+func (a *account) toAccount() Account {
+	acc := Account{
+		Balance:      a.balance.String(),
+		Nonce:        a.nonce.String(),
+		Address:      a.address.Hex(),
+		ContractName: a.contractName,
+	}
+	if len(a.code) > 0 {
+		acc.Bytecode = hex.EncodeToHex(a.code)
+	}
+	if len(a.storage) > 0 {
+		acc.Storage = make(map[string]string, len(a.storage))
+		for position, value := range a.storage {
+			acc.Storage[position.Hex()] = value.Hex()
+		}
+	}
+	return acc
+}
+
+// decodeBig decodes a decimal or 0x prefixed hex number, unlike the decoding
+// of the genesis actions it doesn't take the odd length hex numbers as zero
+func decodeBig(s string) (*big.Int, error) {
+	base := 10
+	if strings.HasPrefix(s, "0x") {
+		s, base = s[2:], 16
+		if s == "" {
+			return big.NewInt(0), nil
+		}
+	}
+	v, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
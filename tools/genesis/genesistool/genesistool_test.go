@@ -0,0 +1,130 @@
+package genesistool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type genesisTestVector struct {
+	Root     string `json:"expectedRoot"`
+	Accounts []struct {
+		Address  string            `json:"address"`
+		Balance  string            `json:"balance"`
+		Nonce    string            `json:"nonce"`
+		Bytecode string            `json:"bytecode"`
+		Storage  map[string]string `json:"storage"`
+	} `json:"addresses"`
+}
+
+func TestBuildVectors(t *testing.T) {
+	files := []string{
+		"../../../test/vectors/src/merkle-tree/smt-full-genesis.json",
+		"../../../test/vectors/src/merkle-tree/smt-genesis.json",
+	}
+	for _, f := range files {
+		var testVectors []genesisTestVector
+		data, err := os.ReadFile(f)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &testVectors))
+
+		for i, tv := range testVectors {
+			t.Run(fmt.Sprintf("%s %d", filepath.Base(f), i), func(t *testing.T) {
+				alloc := Alloc{}
+				for _, acc := range tv.Accounts {
+					if _, ok := alloc[common.HexToAddress(acc.Address)]; ok {
+						t.Skip("an allocation can't have the same address twice")
+					}
+					alloc[common.HexToAddress(acc.Address)] = AllocAccount{
+						Balance: acc.Balance,
+						Nonce:   acc.Nonce,
+						Code:    acc.Bytecode,
+						Storage: acc.Storage,
+					}
+				}
+				genesis, err := Build(context.Background(), alloc)
+				require.NoError(t, err)
+
+				expectedRoot, ok := new(big.Int).SetString(tv.Root, 10)
+				require.True(t, ok)
+				assert.Equal(t, common.BigToHash(expectedRoot).String(), genesis.Root)
+
+				// the built genesis must verify
+				_, err = Verify(context.Background(), genesis)
+				require.NoError(t, err)
+			})
+		}
+	}
+}
+
+func TestBuildNormalizes(t *testing.T) {
+	address := common.HexToAddress("0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D")
+	a, err := Build(context.Background(), Alloc{
+		address: {Balance: "0x64", Nonce: "0x1", Storage: map[string]string{"0x1": "0x2", "0x3": "0x0"}},
+	})
+	require.NoError(t, err)
+	b, err := Build(context.Background(), Alloc{
+		address: {Balance: "100", Nonce: "1", Storage: map[string]string{"1": "2"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	require.Len(t, a.Genesis, 1)
+	assert.Equal(t, Account{
+		Balance: "100",
+		Nonce:   "1",
+		Address: address.Hex(),
+		Storage: map[string]string{
+			common.BigToHash(big.NewInt(1)).Hex(): common.BigToHash(big.NewInt(2)).Hex(),
+		},
+	}, a.Genesis[0])
+}
+
+func TestVerify(t *testing.T) {
+	genesis, err := ReadGenesis("../../../test/config/test.genesis.config.json")
+	require.NoError(t, err)
+
+	root, err := Verify(context.Background(), genesis)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash(genesis.Root), root)
+
+	genesis.Genesis[0].Balance = "1"
+	_, err = Verify(context.Background(), genesis)
+	assert.ErrorIs(t, err, ErrRootMismatch)
+}
+
+func TestDiff(t *testing.T) {
+	addr1 := common.HexToAddress("0x1")
+	addr2 := common.HexToAddress("0x2")
+	addr3 := common.HexToAddress("0x3")
+	a := &Genesis{Genesis: []Account{
+		{Address: addr1.Hex(), Balance: "10", Nonce: "1", Bytecode: "0x6000"},
+		{Address: addr2.Hex(), Balance: "0x0a", Storage: map[string]string{"0x1": "0x1", "0x2": "0x2"}},
+	}}
+	b := &Genesis{Genesis: []Account{
+		{Address: addr2.Hex(), Balance: "10", Storage: map[string]string{"1": "1", "0x2": "0x3", "0x4": "0x0"}},
+		{Address: addr1.Hex(), Balance: "10", Nonce: "2", Bytecode: "0x6001"},
+		{Address: addr3.Hex(), Balance: "5"},
+	}}
+
+	diffs, err := Diff(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, []AccountDiff{
+		{Address: addr1, Field: "nonce", A: "1", B: "2"},
+		{Address: addr1, Field: "bytecode", A: "0x6000", B: "0x6001"},
+		{Address: addr2, Field: "storage " + common.BigToHash(big.NewInt(2)).Hex(), A: common.BigToHash(big.NewInt(2)).Hex(), B: common.BigToHash(big.NewInt(3)).Hex()},
+		{Address: addr3, Field: "balance", A: "", B: "5"},
+	}, diffs)
+
+	diffs, err = Diff(a, a)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+}